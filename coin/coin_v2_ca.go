@@ -84,11 +84,31 @@ func (c *CoinV2) ValidateAssetTag(sharedSecret *crypto.Point, tokenID *common.Ha
 		return false, err
 	}
 
-	recomputedAssetTag.Add(recomputedAssetTag, new(crypto.Point).ScalarMult(crypto.PedCom.G[PedersenRandomnessIndex], blinder))
-	if crypto.IsPointEqual(recomputedAssetTag, c.GetAssetTag()) {
-		return true, nil
+	return c.VerifyAssetTag(*tokenID, blinder)
+}
+
+// VerifyAssetTag checks if the asset tag of a CoinV2 is the asset tag of the given tokenID blinded with the given
+// blinder (see ComputeAssetTagBlinder), i.e, assetTag = HashToPoint(tokenID) + blinder * G_r. A nil blinder is
+// used for coins with an unblinded asset tag (e.g, burning coins).
+//
+// A CoinV2 without an asset tag is a PRV coin, so it only matches the PRV tokenID.
+func (c *CoinV2) VerifyAssetTag(tokenID common.Hash, blinder *crypto.Scalar) (bool, error) {
+	if c == nil {
+		return false, fmt.Errorf("coin is nil")
+	}
+	if c.GetAssetTag() == nil {
+		return tokenID == common.PRVCoinID, nil
+	}
+
+	recomputedAssetTag := crypto.HashToPoint(tokenID[:])
+	if blinder != nil {
+		if !blinder.ScalarValid() {
+			return false, fmt.Errorf("invalid asset tag blinder")
+		}
+		recomputedAssetTag.Add(recomputedAssetTag, new(crypto.Point).ScalarMult(crypto.PedCom.G[PedersenRandomnessIndex], blinder))
 	}
-	return false, nil
+
+	return crypto.IsPointEqual(recomputedAssetTag, c.GetAssetTag()), nil
 }
 
 // SetPlainTokenID sets the given tokenID as the tokenID of a CoinV2 (in raw value, not blinded).
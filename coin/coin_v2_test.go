@@ -6,6 +6,7 @@ import (
 	"github.com/incognitochain/go-incognito-sdk-v2/crypto"
 	"github.com/incognitochain/go-incognito-sdk-v2/key"
 	"github.com/incognitochain/go-incognito-sdk-v2/wallet"
+	"github.com/stretchr/testify/assert"
 	"log"
 	"testing"
 )
@@ -127,3 +128,79 @@ func TestCoinV2_GetTokenId(t *testing.T) {
 	}
 
 }
+
+func TestCoinV2_VerifyAssetTag(t *testing.T) {
+	tokenIDList, _ := makeTokenID(10)
+
+	for i := 0; i < 100; i++ {
+		shardID := byte(common.RandInt() % common.MaxShardNumber)
+		keyWallet, err := wallet.GenRandomWalletForShardID(shardID)
+		if err != nil {
+			panic(err)
+		}
+		keySet := keyWallet.KeySet
+
+		paymentInfo := key.InitPaymentInfo(keySet.PaymentAddress, common.RandUint64(), nil)
+		tokenID := tokenIDList[1+common.RandInt()%(len(tokenIDList)-1)]
+		otherTokenID := common.HashH(tokenID[:])
+
+		c, _, err := NewCoinCA(NewTransferCoinParams(paymentInfo), tokenID)
+		if err != nil {
+			panic(err)
+		}
+
+		// the receiver recomputes the blinder from its private key
+		sharedSecret, err := c.RecomputeSharedSecret(keySet.PrivateKey)
+		if err != nil {
+			panic(err)
+		}
+		blinder, err := ComputeAssetTagBlinder(sharedSecret)
+		if err != nil {
+			panic(err)
+		}
+
+		isValid, err := c.VerifyAssetTag(*tokenID, blinder)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, true, isValid, fmt.Errorf("asset tag of %v should match", tokenID.String()))
+
+		isValid, err = c.VerifyAssetTag(otherTokenID, blinder)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, false, isValid, fmt.Errorf("asset tag of %v should not match %v", tokenID.String(), otherTokenID.String()))
+
+		isValid, err = c.VerifyAssetTag(common.PRVCoinID, blinder)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, false, isValid)
+
+		// a wrong blinder
+		isValid, err = c.VerifyAssetTag(*tokenID, crypto.RandomScalar())
+		assert.Equal(t, nil, err)
+		assert.Equal(t, false, isValid)
+
+		// unblinded asset tags
+		burnCoin := new(CoinV2).Init()
+		burnCoin.SetAmount(new(crypto.Scalar).FromUint64(1000))
+		burnCoin.SetRandomness(crypto.RandomScalar())
+		err = burnCoin.SetPlainTokenID(tokenID)
+		if err != nil {
+			panic(err)
+		}
+		isValid, err = burnCoin.VerifyAssetTag(*tokenID, nil)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, true, isValid)
+		isValid, err = burnCoin.VerifyAssetTag(otherTokenID, nil)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, false, isValid)
+
+		// PRV coins do not have an asset tag
+		prvCoin, err := NewCoinFromPaymentInfo(NewTransferCoinParams(paymentInfo))
+		if err != nil {
+			panic(err)
+		}
+		isValid, err = prvCoin.VerifyAssetTag(common.PRVCoinID, nil)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, true, isValid)
+		isValid, err = prvCoin.VerifyAssetTag(*tokenID, nil)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, false, isValid)
+	}
+}
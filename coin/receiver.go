@@ -77,15 +77,25 @@ func (receiver *OTAReceiver) FromAddress(addr key.PaymentAddress) error {
 
 // FromCoinParams generates an OTAReceiver from the given CoinParams.
 func (receiver *OTAReceiver) FromCoinParams(p *CoinParams) error {
+	return receiver.FromCoinParamsWithRandomness(p, crypto.RandomScalar(), crypto.RandomScalar())
+}
+
+// FromCoinParamsWithRandomness generates an OTAReceiver from the given CoinParams using the given OTA and conceal
+// randomness instead of random ones. The same inputs always result in the same OTAReceiver.
+//
+// The randomness must be kept secret and never be re-used for another payment address; otherwise, the resulting
+// OTAReceiver can be linked to the payment address.
+func (receiver *OTAReceiver) FromCoinParamsWithRandomness(p *CoinParams, otaRand, concealRand *crypto.Scalar) error {
 	if receiver == nil {
 		return fmt.Errorf("OTAReceiver not initialized")
 	}
+	if otaRand == nil || concealRand == nil {
+		return fmt.Errorf("randomness not initialized")
+	}
 
 	addr := p.PaymentInfo.PaymentAddress
 
 	receiverShardID := common.GetShardIDFromLastByte(addr.Pk[len(addr.Pk)-1])
-	otaRand := crypto.RandomScalar()
-	concealRand := crypto.RandomScalar()
 
 	// Increase index until have the right shardID
 	index := uint32(0)
@@ -1,11 +1,13 @@
 package coin
 
 import (
+	"crypto/rand"
 	"fmt"
 	"github.com/incognitochain/go-incognito-sdk-v2/common"
 	"github.com/incognitochain/go-incognito-sdk-v2/crypto"
 	"github.com/incognitochain/go-incognito-sdk-v2/key"
 	"github.com/incognitochain/go-incognito-sdk-v2/wallet"
+	"io"
 )
 
 const (
@@ -96,7 +98,29 @@ func parseInfoForSetBytes(coinBytes *[]byte, offset *int) ([]byte, error) {
 	return info, nil
 }
 
+// NewCoinFromPaymentInfo creates a new CoinV2 from the given CoinParams, using crypto/rand as the source of randomness.
 func NewCoinFromPaymentInfo(p *CoinParams) (*CoinV2, error) {
+	return NewCoinFromPaymentInfoWithRand(p, rand.Reader)
+}
+
+// NewCoinFromPaymentInfoWithRand is the same as NewCoinFromPaymentInfo, except that the randomness, shared randomness
+// and shared conceal randomness of the coin are read from r. Given the same CoinParams and the same bytes from r,
+// it returns the same coin, which is useful for reproducible tests. It should not be used with a predictable r in
+// production, since it would reveal the amount and the receiver of the coin.
+func NewCoinFromPaymentInfoWithRand(p *CoinParams, r io.Reader) (*CoinV2, error) {
+	randomness, err := crypto.RandomScalarFromReader(r)
+	if err != nil {
+		return nil, err
+	}
+	sharedRandom, err := crypto.RandomScalarFromReader(r)
+	if err != nil {
+		return nil, err
+	}
+	sharedConcealRandom, err := crypto.RandomScalarFromReader(r)
+	if err != nil {
+		return nil, err
+	}
+
 	receiverPublicKey, err := new(crypto.Point).FromBytesS(p.PaymentAddress.Pk)
 	if err != nil {
 		errStr := fmt.Sprintf("Cannot parse outputCoinV2 from PaymentInfo when parseByte PublicKey, error %v ", err)
@@ -108,9 +132,9 @@ func NewCoinFromPaymentInfo(p *CoinParams) (*CoinV2, error) {
 	c := new(CoinV2).Init()
 	// Amount, Randomness, SharedRandom are transparency until we call concealData
 	c.SetAmount(new(crypto.Scalar).FromUint64(p.Amount))
-	c.SetRandomness(crypto.RandomScalar())
-	c.SetSharedRandom(crypto.RandomScalar())        // shared randomness for creating one-time-address
-	c.SetSharedConcealRandom(crypto.RandomScalar()) // shared randomness for concealing amount and blinding asset tag
+	c.SetRandomness(randomness)
+	c.SetSharedRandom(sharedRandom)               // shared randomness for creating one-time-address
+	c.SetSharedConcealRandom(sharedConcealRandom) // shared randomness for concealing amount and blinding asset tag
 	c.SetInfo(p.Message)
 	c.SetCommitment(crypto.PedCom.CommitAtIndex(c.GetAmount(), c.GetRandomness(), crypto.PedersenValueIndex))
 
@@ -1,10 +1,13 @@
 package coin
 
 import (
+	"bytes"
 	"fmt"
 	"github.com/incognitochain/go-incognito-sdk-v2/common"
 	"github.com/incognitochain/go-incognito-sdk-v2/key"
 	"github.com/incognitochain/go-incognito-sdk-v2/wallet"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
 	"testing"
 	"time"
 )
@@ -70,3 +73,29 @@ func TestNewCoinFromPaymentInfo(t *testing.T) {
 		fmt.Printf("%v FINISHED: %v\n\n", prefix, time.Since(start).Seconds())
 	}
 }
+
+func TestNewCoinFromPaymentInfoWithRand(t *testing.T) {
+	w, err := wallet.GenRandomWalletForShardID(byte(common.RandInt() % common.MaxShardNumber))
+	assert.Equal(t, nil, err)
+	paymentInfo := key.InitPaymentInfo(w.KeySet.PaymentAddress, 1000, []byte("message"))
+	coinParam := NewTransferCoinParams(paymentInfo, 0)
+
+	for i := 0; i < numTests; i++ {
+		seed := int64(common.RandInt())
+		c1, err := NewCoinFromPaymentInfoWithRand(coinParam, rand.New(rand.NewSource(seed)))
+		assert.Equal(t, nil, err, fmt.Errorf("NewCoinFromPaymentInfoWithRand error: %v", err))
+		c2, err := NewCoinFromPaymentInfoWithRand(coinParam, rand.New(rand.NewSource(seed)))
+		assert.Equal(t, nil, err, fmt.Errorf("NewCoinFromPaymentInfoWithRand error: %v", err))
+		assert.Equal(t, c1.Bytes(), c2.Bytes())
+
+		c3, err := NewCoinFromPaymentInfoWithRand(coinParam, rand.New(rand.NewSource(seed+1)))
+		assert.Equal(t, nil, err, fmt.Errorf("NewCoinFromPaymentInfoWithRand error: %v", err))
+		assert.NotEqual(t, c1.Bytes(), c3.Bytes())
+
+		isOwned, _ := c1.DoesCoinBelongToKeySet(&w.KeySet)
+		assert.Equal(t, true, isOwned)
+	}
+
+	_, err = NewCoinFromPaymentInfoWithRand(coinParam, bytes.NewReader(make([]byte, 100)))
+	assert.NotEqual(t, nil, err)
+}
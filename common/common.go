@@ -134,5 +134,5 @@ var (
 	ConfidentialAssetID = Hash{5}
 	PDEXCoinID          = Hash{6}
 	MaxShardNumber      = 8 //programmatically config based on networkID
-	AddressVersion      = 1
+	AddressVersion      = 1 // the encoding of keys: 0 for the old one (address version 1), 1 for the new one (address version 2)
 )
@@ -21,9 +21,8 @@ func (hashObj Hash) MarshalText() ([]byte, error) {
 }
 
 // UnmarshalText reverts bytes array to hashObj.
-func (hashObj Hash) UnmarshalText(text []byte) error {
-	copy(hashObj[:], text)
-	return nil
+func (hashObj *Hash) UnmarshalText(text []byte) error {
+	return hashObj.Decode(hashObj, string(text))
 }
 
 // UnmarshalJSON unmarshal json data to hashObj.
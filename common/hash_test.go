@@ -0,0 +1,32 @@
+package common
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHash_UnmarshalText(t *testing.T) {
+	h1 := HashH([]byte("hash-1"))
+	h2 := HashH([]byte("hash-2"))
+
+	// as map keys, hashes are encoded with MarshalText and decoded with UnmarshalText.
+	m := map[Hash]uint64{h1: 1, h2: 2}
+	data, err := json.Marshal(m)
+	assert.Equal(t, nil, err)
+	var decoded map[Hash]uint64
+	err = json.Unmarshal(data, &decoded)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, m, decoded)
+
+	var h Hash
+	err = h.UnmarshalText([]byte(h1.String()))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, h1, h)
+
+	err = h.UnmarshalText([]byte("not a hex string"))
+	assert.NotEqual(t, nil, err)
+	err = h.UnmarshalText(make([]byte, 2*MaxHashStringSize))
+	assert.NotEqual(t, nil, err)
+}
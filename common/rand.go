@@ -2,22 +2,44 @@ package common
 
 import (
 	cRand "crypto/rand"
+	"encoding/binary"
 	"math/big"
 	"math/rand"
+	"sync"
 	"time"
 )
 
 var alphabet = "abcdefghijklmnopqrstvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 
+// lockedRand is a math/rand source shared by the non-cryptographic helpers below. It is seeded once (from
+// crypto/rand) and guarded by a mutex so that concurrent callers never re-seed it or race on its state.
+var lockedRand = struct {
+	mtx *sync.Mutex
+	r   *rand.Rand
+}{mtx: new(sync.Mutex), r: rand.New(rand.NewSource(newRandSeed()))}
+
+func newRandSeed() int64 {
+	seedBytes := make([]byte, 8)
+	_, err := cRand.Read(seedBytes)
+	if err != nil {
+		return time.Now().UnixNano()
+	}
+
+	return int64(binary.LittleEndian.Uint64(seedBytes))
+}
+
 // RandInt returns a random int number using math/rand
 func RandInt() int {
-	rand.Seed(time.Now().UnixNano())
-	return rand.Int()
+	lockedRand.mtx.Lock()
+	defer lockedRand.mtx.Unlock()
+	return lockedRand.r.Int()
 }
 
+// RandUint64 returns a random uint64 number using math/rand
 func RandUint64() uint64 {
-	rand.Seed(time.Now().UnixNano())
-	return rand.Uint64()
+	lockedRand.mtx.Lock()
+	defer lockedRand.mtx.Unlock()
+	return lockedRand.r.Uint64()
 }
 
 // RandIntInterval returns a random int in range [L; R]
@@ -29,8 +51,9 @@ func RandIntInterval(L, R int) int {
 
 // RandInt64 returns a random int64 number using math/rand
 func RandInt64() int64 {
-	rand.Seed(time.Now().UnixNano())
-	return rand.Int63()
+	lockedRand.mtx.Lock()
+	defer lockedRand.mtx.Unlock()
+	return lockedRand.r.Int63()
 }
 
 // RandBigIntMaxRange generates a random big.Int whose value is less than a given max value.
@@ -38,10 +61,10 @@ func RandBigIntMaxRange(max *big.Int) (*big.Int, error) {
 	return cRand.Int(cRand.Reader, max)
 }
 
-// RandBytes generates a random l-byte long slice.
+// RandBytes generates a random l-byte long slice using crypto/rand.
 func RandBytes(l int) []byte {
 	randBytes := make([]byte, l)
-	_, err := rand.Read(randBytes)
+	_, err := cRand.Read(randBytes)
 	if err != nil {
 		return randBytes
 	}
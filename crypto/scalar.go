@@ -3,6 +3,7 @@ package crypto
 import (
 	"crypto/subtle"
 	"fmt"
+	"io"
 	"math/big"
 
 	C25519 "github.com/incognitochain/go-incognito-sdk-v2/crypto/curve25519"
@@ -78,6 +79,19 @@ func RandomScalar() *Scalar {
 	return sc
 }
 
+// RandomScalarFromReader returns a Scalar generated from 64 bytes read from r.
+// It is the same as RandomScalar, except that the source of randomness is given by the caller.
+func RandomScalarFromReader(r io.Reader) (*Scalar, error) {
+	var reduceFrom [2 * Ed25519KeySize]byte
+	if _, err := io.ReadFull(r, reduceFrom[:]); err != nil {
+		return nil, fmt.Errorf("cannot read randomness: %v", err)
+	}
+	key := new(C25519.Key)
+	C25519.ScReduce(key, &reduceFrom)
+
+	return new(Scalar).SetKeyUnsafe(key), nil
+}
+
 // HashToScalar returns the hash of msg in the form of a scalar.
 func HashToScalar(msg []byte) *Scalar {
 	key := C25519.HashToScalar(msg)
@@ -5,7 +5,11 @@ import (
 	"github.com/incognitochain/go-incognito-sdk-v2/coin"
 	"github.com/incognitochain/go-incognito-sdk-v2/common"
 	"github.com/incognitochain/go-incognito-sdk-v2/common/base58"
+	"github.com/incognitochain/go-incognito-sdk-v2/internal/safemath"
 	"github.com/incognitochain/go-incognito-sdk-v2/wallet"
+	"sort"
+	"strings"
+	"sync"
 )
 
 // GetBalance retrieves the current tokenID balance of a private key.
@@ -17,18 +21,106 @@ func (client *IncClient) GetBalance(privateKey, tokenID string) (uint64, error)
 
 	balance := uint64(0)
 	for _, unspentCoin := range unspentCoins {
-		balance += unspentCoin.GetValue()
+		balance, err = safemath.AddUint64(balance, unspentCoin.GetValue())
+		if err != nil {
+			return 0, fmt.Errorf("balance of %v overflows: %v", tokenID, err)
+		}
 	}
 
 	return balance, nil
 }
 
+// DefaultGetBalancesConcurrency is the default maximum number of private keys whose balances are retrieved
+// simultaneously by GetBalances.
+const DefaultGetBalancesConcurrency = 8
+
+// BalancesError holds the per-key errors of a GetBalances call, keyed by the private keys.
+type BalancesError map[string]error
+
+// Error implements the error interface. Private keys are shortened so that they are not leaked into logs.
+func (e BalancesError) Error() string {
+	privateKeys := make([]string, 0, len(e))
+	for privateKey := range e {
+		privateKeys = append(privateKeys, privateKey)
+	}
+	sort.Strings(privateKeys)
+
+	errStrs := make([]string, 0, len(privateKeys))
+	for _, privateKey := range privateKeys {
+		shortKey := privateKey
+		if len(shortKey) > 10 {
+			shortKey = fmt.Sprintf("%v...%v", shortKey[:6], shortKey[len(shortKey)-4:])
+		}
+		errStrs = append(errStrs, fmt.Sprintf("%v: %v", shortKey, e[privateKey]))
+	}
+	return fmt.Sprintf("cannot get balances of %v key(s): %v", len(e), strings.Join(errStrs, "; "))
+}
+
+// GetBalances retrieves the current tokenID balances of a list of private keys, as a mapping from a private key
+// to its balance (see GetBalance).
+//
+// The keys are processed concurrently by a bounded pool of workers. The optional parameter `concurrency` specifies the
+// maximum number of keys processed at a time; if not set (or not positive), it defaults to
+// DefaultGetBalancesConcurrency. NOTE that only the first value of concurrency is used.
+//
+// If some of the keys fail, the balances of the remaining keys are still returned, together with a BalancesError
+// describing the failed ones.
+func (client *IncClient) GetBalances(privateKeys []string, tokenID string, concurrency ...int) (map[string]uint64, error) {
+	numWorkers := DefaultGetBalancesConcurrency
+	if len(concurrency) > 0 && concurrency[0] > 0 {
+		numWorkers = concurrency[0]
+	}
+	if len(privateKeys) < numWorkers {
+		numWorkers = len(privateKeys)
+	}
+
+	mtx := new(sync.Mutex)
+	res := make(map[string]uint64)
+	errs := make(BalancesError)
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for privateKey := range jobs {
+				balance, err := client.GetBalance(privateKey, tokenID)
+
+				mtx.Lock()
+				if err != nil {
+					errs[privateKey] = err
+				} else {
+					res[privateKey] = balance
+				}
+				mtx.Unlock()
+			}
+		}()
+	}
+
+	seen := make(map[string]bool)
+	for _, privateKey := range privateKeys {
+		if seen[privateKey] {
+			continue
+		}
+		seen[privateKey] = true
+		jobs <- privateKey
+	}
+	close(jobs)
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return res, errs
+	}
+	return res, nil
+}
+
 // GetAllBalancesV2 returns all non-zero balances of a private key.
 // This function assumes that all v1 output coins have been converted to v1, and only returns the balances calculated with
 // v2 coins (except for PRV). In case you still have v1 UTXOs, try using the regular `GetBalance` function.
 func (client *IncClient) GetAllBalancesV2(privateKey string) (map[string]uint64, error) {
 	res := make(map[string]uint64)
-	allUTXOs, _, err := client.GetAllUTXOsV2(privateKey)
+	allUTXOs, err := client.ScanAllTokens(privateKey, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -36,7 +128,10 @@ func (client *IncClient) GetAllBalancesV2(privateKey string) (map[string]uint64,
 	for tokenID, utxoList := range allUTXOs {
 		balance := uint64(0)
 		for _, utxo := range utxoList {
-			balance += utxo.GetValue()
+			balance, err = safemath.AddUint64(balance, utxo.GetValue())
+			if err != nil {
+				return nil, fmt.Errorf("balance of %v overflows: %v", tokenID, err)
+			}
 		}
 		if balance > 0 {
 			res[tokenID] = balance
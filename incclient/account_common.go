@@ -33,6 +33,58 @@ func (k KeyInfo) String() string {
 	return string(jsb)
 }
 
+// Equal checks if two KeyInfo objects have exactly the same fields.
+func (k KeyInfo) Equal(other *KeyInfo) bool {
+	if other == nil {
+		return false
+	}
+
+	return len(k.Diff(other)) == 0
+}
+
+// Diff returns the names of the fields that differ between two KeyInfo objects.
+// If other is nil, all fields are returned.
+func (k KeyInfo) Diff(other *KeyInfo) []string {
+	if other == nil {
+		return []string{"PrivateKey", "PublicKey", "PaymentAddressV1", "PaymentAddress", "ReadOnlyKey",
+			"OTAPrivateKey", "MiningKey", "MiningPublicKey", "ValidatorPublicKey", "ShardID"}
+	}
+
+	res := make([]string, 0)
+	if k.PrivateKey != other.PrivateKey {
+		res = append(res, "PrivateKey")
+	}
+	if k.PublicKey != other.PublicKey {
+		res = append(res, "PublicKey")
+	}
+	if k.PaymentAddressV1 != other.PaymentAddressV1 {
+		res = append(res, "PaymentAddressV1")
+	}
+	if k.PaymentAddress != other.PaymentAddress {
+		res = append(res, "PaymentAddress")
+	}
+	if k.ReadOnlyKey != other.ReadOnlyKey {
+		res = append(res, "ReadOnlyKey")
+	}
+	if k.OTAPrivateKey != other.OTAPrivateKey {
+		res = append(res, "OTAPrivateKey")
+	}
+	if k.MiningKey != other.MiningKey {
+		res = append(res, "MiningKey")
+	}
+	if k.MiningPublicKey != other.MiningPublicKey {
+		res = append(res, "MiningPublicKey")
+	}
+	if k.ValidatorPublicKey != other.ValidatorPublicKey {
+		res = append(res, "ValidatorPublicKey")
+	}
+	if k.ShardID != other.ShardID {
+		res = append(res, "ShardID")
+	}
+
+	return res
+}
+
 // GetAccountInfoFromPrivateKey returns all fields related to a private key.
 func GetAccountInfoFromPrivateKey(privateKey string) (*KeyInfo, error) {
 	w, err := wallet.Base58CheckDeserialize(privateKey)
@@ -80,3 +132,74 @@ func GetAccountInfoFromPrivateKey(privateKey string) (*KeyInfo, error) {
 		ShardID:            shardID,
 	}, nil
 }
+
+// PrivateKeyToMnemonic returns a 24-word BIP39 mnemonic (with checksum) encoding the 32-byte seed of a private key.
+//
+// Note that the mnemonic only backs up this single private key. It is NOT an HD-wallet mnemonic; it cannot be used with
+// wallet.NewMasterKeyFromMnemonic to derive child keys, and the HD metadata (depth, child number, chain code) of the
+// original key is not preserved.
+func PrivateKeyToMnemonic(privateKey string) (string, error) {
+	w, err := wallet.Base58CheckDeserialize(privateKey)
+	if err != nil {
+		return "", err
+	}
+	if len(w.KeySet.PrivateKey) != common.PrivateKeySize {
+		return "", fmt.Errorf("privateKey is invalid")
+	}
+
+	return wallet.NewMnemonicFromEntropy(w.KeySet.PrivateKey)
+}
+
+// MnemonicToPrivateKey recovers the base58-encoded private key backed up by PrivateKeyToMnemonic.
+//
+// The returned private key has empty HD metadata (depth, child number, chain code). Therefore, its string representation
+// might differ from the original one (e.g, for a child key), but all derived keys (payment address, OTA key, etc.) are the same.
+func MnemonicToPrivateKey(mnemonic string) (string, error) {
+	entropy, err := wallet.NewEntropyFromMnemonic(mnemonic)
+	if err != nil {
+		return "", err
+	}
+	if len(entropy) != common.PrivateKeySize {
+		return "", fmt.Errorf("expect a 24-word mnemonic, got %v bytes of entropy", len(entropy))
+	}
+
+	keySet := new(key.KeySet)
+	err = keySet.InitFromPrivateKeyByte(entropy)
+	if err != nil {
+		return "", err
+	}
+
+	w := &wallet.KeyWallet{
+		Depth:       0x00,
+		ChildNumber: []byte{0x00, 0x00, 0x00, 0x00},
+		ChainCode:   make([]byte, common.HashSize),
+		KeySet:      *keySet,
+	}
+
+	return w.GetPrivateKey()
+}
+
+// DeriveViewOnlyKeys returns the view-only key pair of a private key, which can be shared (e.g, with an accountant)
+// to watch an account:
+//	- readonlyKey: the readonly key, used to decrypt the values of output coins;
+//	- otaKey: the private OTA key, used to detect the v2 output coins belonging to the account.
+//
+// These keys do NOT grant spend authority: the private key cannot be recovered from them, and they cannot be used to
+// sign transactions. Also note that they do not allow to compute the key images of output coins, so a holder of
+// these keys cannot tell by themselves which coins have been spent.
+func DeriveViewOnlyKeys(privateKey string) (readonlyKey, otaKey string, err error) {
+	w, err := wallet.Base58CheckDeserialize(privateKey)
+	if err != nil {
+		return "", "", err
+	}
+	if len(w.KeySet.PrivateKey) != common.PrivateKeySize {
+		return "", "", fmt.Errorf("privateKey is invalid")
+	}
+
+	err = w.KeySet.InitFromPrivateKey(&w.KeySet.PrivateKey)
+	if err != nil {
+		return "", "", err
+	}
+
+	return w.Base58CheckSerialize(wallet.ReadonlyKeyType), w.Base58CheckSerialize(wallet.OTAKeyType), nil
+}
@@ -3,7 +3,15 @@ package incclient
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/incognitochain/go-incognito-sdk-v2/coin"
 	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"github.com/incognitochain/go-incognito-sdk-v2/key"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/rpc"
+	"github.com/incognitochain/go-incognito-sdk-v2/wallet"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -25,6 +33,64 @@ func TestIncClient_GetBalance(t *testing.T) {
 	fmt.Printf("Balance: %v\n", balance)
 }
 
+func TestIncClient_GetBalances(t *testing.T) {
+	server := newMockCoinServer()
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	numKeys := 2 * DefaultGetBalancesConcurrency
+	privateKeys := make([]string, 0)
+	expectedBalances := make(map[string]uint64)
+	for i := 0; i < numKeys; i++ {
+		w, err := wallet.NewMasterKeyFromSeed(common.RandBytes(32))
+		if err != nil {
+			panic(err)
+		}
+		numCoins := 1 + common.RandInt()%3
+		err = server.addCoins(w.KeySet.PaymentAddress, numCoins)
+		if err != nil {
+			panic(err)
+		}
+
+		privateKey := w.Base58CheckSerialize(wallet.PrivateKeyType)
+		privateKeys = append(privateKeys, privateKey)
+		for j := 0; j < numCoins; j++ {
+			expectedBalances[privateKey] += uint64(1000 + j)
+		}
+	}
+	privateKeys = append(privateKeys, privateKeys[0]) // duplicates are only queried once
+
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+	client.SetCoinStore(NewMemCoinStore())
+
+	for _, concurrency := range [][]int{nil, {1}, {3}, {0}} {
+		balances, err := client.GetBalances(privateKeys, common.PRVIDStr, concurrency...)
+		assert.Equal(t, nil, err, fmt.Errorf("GetBalances error: %v", err))
+		assert.Equal(t, expectedBalances, balances)
+	}
+
+	// partial results
+	invalidKeys := []string{"invalid-key-1", "invalid-key-2"}
+	balances, err := client.GetBalances(append(privateKeys, invalidKeys...), common.PRVIDStr)
+	assert.NotEqual(t, nil, err)
+	errs, ok := err.(BalancesError)
+	assert.Equal(t, true, ok, fmt.Errorf("expected a BalancesError, got %T", err))
+	assert.Equal(t, len(invalidKeys), len(errs))
+	for _, invalidKey := range invalidKeys {
+		assert.NotEqual(t, nil, errs[invalidKey])
+	}
+	assert.Equal(t, expectedBalances, balances)
+
+	// private keys are not leaked in the error message.
+	errs = BalancesError{privateKeys[0]: fmt.Errorf("some error")}
+	assert.Equal(t, false, strings.Contains(errs.Error(), privateKeys[0]))
+
+	// no keys
+	balances, err = client.GetBalances(nil, common.PRVIDStr)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(balances))
+}
+
 func TestIncClient_GetAllNFTs(t *testing.T) {
 	ic, err := NewTestNetClientWithCache()
 	if err != nil {
@@ -98,3 +164,234 @@ func TestIncClient_GetAllBalancesV2(t *testing.T) {
 	Logger.Log.Printf("AllBalances: %v\n", string(jsb))
 	Logger.Log.Printf("TimeElapsed without cache: %v\n", time.Since(start).Seconds())
 }
+
+func TestPrivateKeyToMnemonic(t *testing.T) {
+	// known vector: a private key whose 32-byte seed is 0x7f7f...7f (see the BIP39 test vectors)
+	privateKey := "11111111111111111111111111111111111111188NkDPeHYRr5qPduYb3i5HMAQazPdY6jmm2NfUf6Ujv6RqYLPpstrQmy"
+	expectedMnemonic := "legal winner thank year wave sausage worth useful legal winner thank year wave sausage worth useful legal winner thank year wave sausage worth title"
+
+	mnemonic, err := PrivateKeyToMnemonic(privateKey)
+	assert.Equal(t, nil, err, fmt.Errorf("PrivateKeyToMnemonic error: %v", err))
+	assert.Equal(t, expectedMnemonic, mnemonic)
+
+	recoveredKey, err := MnemonicToPrivateKey(mnemonic)
+	assert.Equal(t, nil, err, fmt.Errorf("MnemonicToPrivateKey error: %v", err))
+	assert.Equal(t, privateKey, recoveredKey)
+
+	for i := 0; i < numTests; i++ {
+		w, err := wallet.GenRandomWalletForShardID(byte(common.RandInt() % common.MaxShardNumber))
+		if err != nil {
+			panic(err)
+		}
+		privateKey, err = w.GetPrivateKey()
+		if err != nil {
+			panic(err)
+		}
+
+		mnemonic, err = PrivateKeyToMnemonic(privateKey)
+		assert.Equal(t, nil, err, fmt.Errorf("PrivateKeyToMnemonic error: %v", err))
+		assert.Equal(t, 24, len(strings.Fields(mnemonic)))
+
+		recoveredKey, err = MnemonicToPrivateKey(mnemonic)
+		assert.Equal(t, nil, err, fmt.Errorf("MnemonicToPrivateKey error: %v", err))
+		assert.Equal(t, PrivateKeyToPaymentAddress(privateKey, -1), PrivateKeyToPaymentAddress(recoveredKey, -1))
+	}
+
+	// a 12-word (HD) mnemonic does not back up a single private key
+	_, err = MnemonicToPrivateKey("legal winner thank year wave sausage worth useful legal winner thank yellow")
+	assert.NotEqual(t, nil, err)
+
+	// bad checksum
+	_, err = MnemonicToPrivateKey("legal winner thank year wave sausage worth useful legal winner thank year wave sausage worth useful legal winner thank year wave sausage worth useful")
+	assert.NotEqual(t, nil, err)
+}
+
+func TestDeriveViewOnlyKeys(t *testing.T) {
+	for i := 0; i < numTests; i++ {
+		w, err := wallet.GenRandomWalletForShardID(byte(common.RandInt() % common.MaxShardNumber))
+		if err != nil {
+			panic(err)
+		}
+		privateKey, err := w.GetPrivateKey()
+		if err != nil {
+			panic(err)
+		}
+
+		readonlyKey, otaKey, err := DeriveViewOnlyKeys(privateKey)
+		assert.Equal(t, nil, err, fmt.Errorf("DeriveViewOnlyKeys error: %v", err))
+		assert.Equal(t, PrivateKeyToReadonlyKey(privateKey), readonlyKey)
+		assert.Equal(t, PrivateKeyToPrivateOTAKey(privateKey), otaKey)
+
+		// the private key is not recoverable from the view-only keys.
+		readonlyWallet, err := wallet.Base58CheckDeserialize(readonlyKey)
+		assert.Equal(t, nil, err, fmt.Errorf("cannot deserialize readonlyKey: %v", err))
+		otaWallet, err := wallet.Base58CheckDeserialize(otaKey)
+		assert.Equal(t, nil, err, fmt.Errorf("cannot deserialize otaKey: %v", err))
+		assert.Equal(t, 0, len(readonlyWallet.KeySet.PrivateKey))
+		assert.Equal(t, 0, len(otaWallet.KeySet.PrivateKey))
+		_, err = readonlyWallet.GetPrivateKey()
+		assert.NotEqual(t, nil, err)
+		_, err = otaWallet.GetPrivateKey()
+		assert.NotEqual(t, nil, err)
+
+		// the view-only keys can detect and decrypt output coins.
+		viewKeySet := &key.KeySet{ReadonlyKey: readonlyWallet.KeySet.ReadonlyKey, OTAKey: otaWallet.KeySet.OTAKey}
+		amount := common.RandUint64()%1e12 + 1
+		paymentInfo := key.InitPaymentInfo(w.KeySet.PaymentAddress, amount, []byte{})
+		outCoin, err := coin.NewCoinFromPaymentInfo(coin.NewTransferCoinParams(paymentInfo))
+		if err != nil {
+			panic(err)
+		}
+		err = outCoin.ConcealOutputCoin(w.KeySet.PaymentAddress.GetPublicView())
+		if err != nil {
+			panic(err)
+		}
+		assert.Equal(t, true, outCoin.IsEncrypted())
+
+		belongs, _ := outCoin.DoesCoinBelongToKeySet(viewKeySet)
+		assert.Equal(t, true, belongs)
+		decryptedCoin, err := outCoin.Decrypt(viewKeySet)
+		assert.Equal(t, nil, err, fmt.Errorf("cannot decrypt coin with view-only keys: %v", err))
+		assert.Equal(t, amount, decryptedCoin.GetValue())
+		keyImage := decryptedCoin.GetKeyImage()
+		assert.Equal(t, true, keyImage == nil || keyImage.IsIdentity(), "view-only keys must not compute key images")
+
+		// view-only keys cannot be used in place of a private key.
+		_, _, err = DeriveViewOnlyKeys(readonlyKey)
+		assert.NotEqual(t, nil, err)
+		_, _, err = DeriveViewOnlyKeys(otaKey)
+		assert.NotEqual(t, nil, err)
+	}
+}
+
+func TestKeyInfo_Diff(t *testing.T) {
+	for i := 0; i < numTests; i++ {
+		w, err := wallet.GenRandomWalletForShardID(byte(common.RandInt() % common.MaxShardNumber))
+		if err != nil {
+			panic(err)
+		}
+		privateKey, err := w.GetPrivateKey()
+		if err != nil {
+			panic(err)
+		}
+
+		keyInfo, err := GetAccountInfoFromPrivateKey(privateKey)
+		assert.Equal(t, nil, err, fmt.Errorf("GetAccountInfoFromPrivateKey error: %v", err))
+
+		// re-deriving the keys yields the same KeyInfo
+		reDerived, err := GetAccountInfoFromPrivateKey(privateKey)
+		assert.Equal(t, nil, err, fmt.Errorf("GetAccountInfoFromPrivateKey error: %v", err))
+		assert.Equal(t, true, keyInfo.Equal(keyInfo))
+		assert.Equal(t, true, keyInfo.Equal(reDerived))
+		assert.Equal(t, 0, len(keyInfo.Diff(reDerived)))
+
+		// a changed payment address is detected
+		changed := *reDerived
+		changed.PaymentAddress = PrivateKeyToPaymentAddress(privateKey, 0)
+		assert.Equal(t, false, keyInfo.Equal(&changed))
+		assert.Equal(t, []string{"PaymentAddress"}, keyInfo.Diff(&changed))
+
+		assert.Equal(t, false, keyInfo.Equal(nil))
+		assert.Equal(t, 10, len(keyInfo.Diff(nil)))
+	}
+}
+
+func TestPrivateKeyToPaymentAddressVersion(t *testing.T) {
+	oldAddressVersion := common.AddressVersion
+	defer func() {
+		common.AddressVersion = oldAddressVersion
+	}()
+
+	privateKeys := make([]string, 0)
+	expectedV1 := make(map[string]string)
+	expectedV2 := make(map[string]string)
+	for i := 0; i < numTests; i++ {
+		w, err := wallet.GenRandomWalletForShardID(byte(common.RandInt() % common.MaxShardNumber))
+		if err != nil {
+			panic(err)
+		}
+		privateKey, err := w.GetPrivateKey()
+		if err != nil {
+			panic(err)
+		}
+		privateKeys = append(privateKeys, privateKey)
+
+		// the legacy way: flip the global
+		common.AddressVersion = 0
+		expectedV1[privateKey] = PrivateKeyToPaymentAddress(privateKey, -1)
+		common.AddressVersion = 1
+		expectedV2[privateKey] = PrivateKeyToPaymentAddress(privateKey, -1)
+		assert.NotEqual(t, expectedV1[privateKey], expectedV2[privateKey])
+	}
+
+	// generate both versions at once, without touching the global.
+	var wg sync.WaitGroup
+	var mtx sync.Mutex
+	mismatches := make([]string, 0)
+	for i := 0; i < 8; i++ {
+		for _, version := range []int{1, 2} {
+			wg.Add(1)
+			go func(version int) {
+				defer wg.Done()
+				for _, privateKey := range privateKeys {
+					expected := expectedV2[privateKey]
+					if version == 1 {
+						expected = expectedV1[privateKey]
+					}
+					addr := PrivateKeyToPaymentAddressVersion(privateKey, version)
+					if addr != expected {
+						mtx.Lock()
+						mismatches = append(mismatches, fmt.Sprintf("version %v: expected %v, got %v", version, expected, addr))
+						mtx.Unlock()
+					}
+				}
+			}(version)
+		}
+	}
+	wg.Wait()
+	assert.Equal(t, 0, len(mismatches), mismatches)
+
+	assert.Equal(t, "", PrivateKeyToPaymentAddressVersion(privateKeys[0], 3))
+	assert.Equal(t, "", PrivateKeyToPaymentAddressVersion("", 2))
+}
+
+func TestBuildPaymentAddress(t *testing.T) {
+	for i := 0; i < numTests; i++ {
+		w, err := wallet.GenRandomWalletForShardID(byte(common.RandInt() % common.MaxShardNumber))
+		if err != nil {
+			panic(err)
+		}
+		privateKey, err := w.GetPrivateKey()
+		if err != nil {
+			panic(err)
+		}
+		info, err := GetAccountInfoFromPrivateKey(privateKey)
+		if err != nil {
+			panic(err)
+		}
+
+		addrWallet, err := wallet.Base58CheckDeserialize(info.PaymentAddress)
+		if err != nil {
+			panic(err)
+		}
+		addr := addrWallet.KeySet.PaymentAddress
+
+		addrV2, err := BuildPaymentAddress(addr.Pk, addr.Tk, addr.OTAPublic, 2)
+		assert.Equal(t, nil, err, fmt.Errorf("BuildPaymentAddress error: %v", err))
+		assert.Equal(t, info.PaymentAddress, addrV2)
+
+		addrV1, err := BuildPaymentAddress(addr.Pk, addr.Tk, nil, 1)
+		assert.Equal(t, nil, err, fmt.Errorf("BuildPaymentAddress error: %v", err))
+		assert.Equal(t, info.PaymentAddressV1, addrV1)
+
+		// invalid inputs
+		_, err = BuildPaymentAddress(addr.Pk, addr.Tk, addr.OTAPublic, 3)
+		assert.NotEqual(t, nil, err)
+		_, err = BuildPaymentAddress(addr.Pk[:31], addr.Tk, addr.OTAPublic, 2)
+		assert.NotEqual(t, nil, err)
+		_, err = BuildPaymentAddress(addr.Pk, nil, addr.OTAPublic, 1)
+		assert.NotEqual(t, nil, err)
+		_, err = BuildPaymentAddress(addr.Pk, addr.Tk, nil, 2)
+		assert.NotEqual(t, nil, err)
+	}
+}
@@ -7,8 +7,22 @@ import (
 	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler"
 	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/jsonresult"
 	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/rpc"
+	"sync"
+	"time"
 )
 
+// DefaultBeaconHeightCacheTTL is the default time-to-live of the beacon best height cached by an IncClient.
+const DefaultBeaconHeightCacheTTL = 5 * time.Second
+
+// beaconHeightCache keeps the most recent beacon best height for a short amount of time, so that consecutive
+// queries do not each need a GetBestBlock RPC.
+type beaconHeightCache struct {
+	mtx       *sync.Mutex
+	ttl       time.Duration
+	height    uint64
+	updatedAt time.Time
+}
+
 // GetActiveShard returns the number of active shards on the Incognito network.
 func (client *IncClient) GetActiveShard() (int, error) {
 	responseInBytes, err := client.rpcServer.GetActiveShards()
@@ -47,6 +61,76 @@ func (client *IncClient) GetBestBlock() (map[int]uint64, error) {
 	return res, nil
 }
 
+func newBeaconHeightCache() *beaconHeightCache {
+	return &beaconHeightCache{mtx: new(sync.Mutex), ttl: DefaultBeaconHeightCacheTTL}
+}
+
+// SetBeaconHeightCacheTTL sets the time-to-live of the cached beacon best height, and invalidates the current cached
+// value. A non-positive ttl disables the cache.
+func (client *IncClient) SetBeaconHeightCacheTTL(ttl time.Duration) {
+	cache := client.beaconHeightCache
+	cache.mtx.Lock()
+	defer cache.mtx.Unlock()
+
+	cache.ttl = ttl
+	cache.updatedAt = time.Time{}
+}
+
+// GetBeaconHeight returns the best height of the beacon chain. The result is cached for a short amount of time
+// (DefaultBeaconHeightCacheTTL, see SetBeaconHeightCacheTTL), so rapid successive calls share a single GetBestBlock RPC.
+func (client *IncClient) GetBeaconHeight() (uint64, error) {
+	cache := client.beaconHeightCache
+	cache.mtx.Lock()
+	defer cache.mtx.Unlock()
+
+	if cache.ttl > 0 && !cache.updatedAt.IsZero() && time.Since(cache.updatedAt) < cache.ttl {
+		return cache.height, nil
+	}
+
+	bestBlocks, err := client.GetBestBlock()
+	if err != nil {
+		return 0, err
+	}
+	height, ok := bestBlocks[-1]
+	if !ok {
+		return 0, fmt.Errorf("cannot find the beacon best block in %v", bestBlocks)
+	}
+	cache.height = height
+	cache.updatedAt = time.Now()
+
+	return height, nil
+}
+
+// Ping probes the health of the remote full-node. It issues a lightweight GetBestBlock RPC (bypassing the beacon height
+// cache), and returns the round-trip latency of the call together with the beacon best height reported by the node, so
+// that callers can detect slow or lagging nodes.
+//
+// The RPC is sent once to the current endpoint of the client (see rpc.RPCServer.GetURL), without retries nor failover
+// to other endpoints, so that the latency is the one of a single request to that node.
+func (client *IncClient) Ping() (latency time.Duration, beaconHeight uint64, err error) {
+	server := rpc.NewRPCServer(client.rpcServer.GetURL(), rpc.WithRetryPolicy(rpc.NoRetryPolicy)).
+		WithContext(client.rpcServer.Context())
+
+	start := time.Now()
+	responseInBytes, err := server.GetBestBlock()
+	latency = time.Since(start)
+	if err != nil {
+		return latency, 0, err
+	}
+
+	var bestBlocksResult jsonresult.BestBlockResult
+	err = rpchandler.ParseResponse(responseInBytes, &bestBlocksResult)
+	if err != nil {
+		return latency, 0, err
+	}
+	beaconBestBlock, ok := bestBlocksResult.BestBlocks[-1]
+	if !ok {
+		return latency, 0, fmt.Errorf("cannot find the beacon best block in %v", bestBlocksResult.BestBlocks)
+	}
+
+	return latency, beaconBestBlock.Height, nil
+}
+
 // GetListToken returns all tokens currently on the Incognito network.
 func (client *IncClient) GetListToken() (map[string]CustomToken, error) {
 	responseInBytes, err := client.rpcServer.ListPrivacyCustomTokenByRPC()
@@ -170,3 +254,75 @@ func (client *IncClient) GetBeaconBestState(shardID int) (*jsonresult.BeaconBest
 
 	return &res, nil
 }
+
+// ConfirmationTimeNumBlocks is the number of recent blocks sampled by EstimateConfirmationTime.
+const ConfirmationTimeNumBlocks = 10
+
+// EstimateConfirmationTime estimates how long a transaction sent now to the given shard takes to be confirmed.
+//
+// It samples the timestamps of the ConfirmationTimeNumBlocks most recent blocks of the shard, retrieved concurrently by
+// height, to compute the average block interval. This is then multiplied by the number of blocks needed to include the
+// transactions ahead in the mempool: the mempool is shared by all shards, so the shard's share is assumed to be
+// proportional, and the capacity of a block is taken as the largest number of transactions among the sampled blocks.
+// If the mempool cannot be retrieved, the estimate is the average block interval.
+func (client *IncClient) EstimateConfirmationTime(shardID byte) (time.Duration, error) {
+	bestState, err := client.GetShardBestState(int(shardID))
+	if err != nil {
+		return 0, err
+	}
+
+	bestHeight := bestState.ShardHeight
+	numSamples := uint64(ConfirmationTimeNumBlocks)
+	if bestHeight < numSamples {
+		numSamples = bestHeight
+	}
+	if numSamples < 2 {
+		return 0, fmt.Errorf("not enough blocks in shard %v to estimate the block interval", shardID)
+	}
+
+	blocks := make([][]jsonresult.ShardBlockResult, numSamples)
+	errs := make([]error, numSamples)
+	var wg sync.WaitGroup
+	for i := uint64(0); i < numSamples; i++ {
+		wg.Add(1)
+		go func(i uint64) {
+			defer wg.Done()
+			blocks[i], errs[i] = client.GetShardBlocksByHeight(shardID, bestHeight-i)
+		}(i)
+	}
+	wg.Wait()
+
+	timestamps := make([]int64, 0)
+	maxTxsPerBlock := 0
+	for i, err := range errs {
+		if err != nil {
+			return 0, fmt.Errorf("cannot retrieve the block of shard %v at height %v: %v", shardID, bestHeight-uint64(i), err)
+		}
+		if len(blocks[i]) == 0 {
+			return 0, fmt.Errorf("no block of shard %v found at height %v", shardID, bestHeight-uint64(i))
+		}
+		block := blocks[i][0]
+		timestamps = append(timestamps, block.Time)
+		if len(block.TxHashes) > maxTxsPerBlock {
+			maxTxsPerBlock = len(block.TxHashes)
+		}
+	}
+	span := timestamps[0] - timestamps[len(timestamps)-1]
+	if span <= 0 {
+		return 0, fmt.Errorf("invalid timestamps of shard %v blocks: %v", shardID, timestamps)
+	}
+	blockInterval := time.Duration(span) * time.Second / time.Duration(len(timestamps)-1)
+
+	mempool, err := client.GetRawMemPool()
+	if err != nil || maxTxsPerBlock == 0 {
+		return blockInterval, nil
+	}
+	numShards := bestState.ActiveShards
+	if numShards <= 0 {
+		numShards = common.MaxShardNumber
+	}
+	pendingTxs := len(mempool) / numShards
+	numBlocks := 1 + pendingTxs/maxTxsPerBlock
+
+	return time.Duration(numBlocks) * blockInterval, nil
+}
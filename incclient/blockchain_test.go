@@ -3,8 +3,16 @@ package incclient
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/jsonresult"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/rpc"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestIncClient_GetActiveShards(t *testing.T) {
@@ -158,3 +166,187 @@ func TestIncClient_GetBeaconBestState(t *testing.T) {
 
 	fmt.Println(string(jsb))
 }
+
+func TestIncClient_GetBeaconHeight(t *testing.T) {
+	var numCalls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		height := 1000 + atomic.AddInt32(&numCalls, 1)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"Result": map[string]interface{}{
+				"BestBlocks": map[int]interface{}{
+					-1: map[string]interface{}{"Height": height},
+					0:  map[string]interface{}{"Height": 5},
+				},
+			},
+		})
+	}))
+	defer ts.Close()
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+
+	// two rapid calls share one RPC.
+	height1, err := client.GetBeaconHeight()
+	assert.Equal(t, nil, err)
+	height2, err := client.GetBeaconHeight()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, uint64(1001), height1)
+	assert.Equal(t, height1, height2)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&numCalls))
+
+	// the cached height expires after the TTL.
+	client.SetBeaconHeightCacheTTL(50 * time.Millisecond)
+	_, err = client.GetBeaconHeight()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&numCalls))
+	time.Sleep(100 * time.Millisecond)
+	height3, err := client.GetBeaconHeight()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, uint64(1003), height3)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&numCalls))
+
+	// a non-positive TTL disables the cache.
+	client.SetBeaconHeightCacheTTL(0)
+	_, _ = client.GetBeaconHeight()
+	_, _ = client.GetBeaconHeight()
+	assert.Equal(t, int32(5), atomic.LoadInt32(&numCalls))
+}
+
+func TestIncClient_Ping(t *testing.T) {
+	delay := 200 * time.Millisecond
+	var numCalls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&numCalls, 1)
+		time.Sleep(delay)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"Result": map[string]interface{}{
+				"BestBlocks": map[int]interface{}{
+					-1: map[string]interface{}{"Height": 1234},
+					0:  map[string]interface{}{"Height": 5},
+				},
+			},
+		})
+	}))
+	defer ts.Close()
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+
+	// Ping is not served from the beacon height cache.
+	_, err := client.GetBeaconHeight()
+	assert.Equal(t, nil, err)
+	for i := 0; i < 3; i++ {
+		latency, beaconHeight, err := client.Ping()
+		assert.Equal(t, nil, err, fmt.Errorf("Ping error: %v", err))
+		assert.Equal(t, uint64(1234), beaconHeight)
+		assert.Equal(t, true, latency >= delay, fmt.Errorf("latency %v is less than the delay %v", latency, delay))
+		assert.Equal(t, true, latency < delay+5*time.Second, fmt.Errorf("latency %v is too large", latency))
+	}
+	assert.Equal(t, int32(4), atomic.LoadInt32(&numCalls))
+
+	// an unreachable node.
+	ts.Close()
+	_, _, err = client.Ping()
+	assert.NotEqual(t, nil, err)
+
+	// a failing request is neither retried nor sent to another endpoint.
+	var numFailedCalls, numBackupCalls int32
+	failingServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&numFailedCalls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failingServer.Close()
+	backupServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&numBackupCalls, 1)
+	}))
+	defer backupServer.Close()
+	client = newIncClient(rpc.NewRPCServerWithEndpoints([]string{failingServer.URL, backupServer.URL}), nil, nil, 2)
+	_, _, err = client.Ping()
+	assert.NotEqual(t, nil, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&numFailedCalls))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&numBackupCalls))
+}
+
+func TestIncClient_EstimateConfirmationTime(t *testing.T) {
+	const numBlocks = 15
+	const blockInterval = 40
+	blocks := make(map[uint64]jsonresult.ShardBlockResult)
+	for height := uint64(1); height <= numBlocks; height++ {
+		blocks[height] = jsonresult.ShardBlockResult{
+			Hash:     common.HashH([]byte(fmt.Sprintf("block-%v", height))).String(),
+			Height:   height,
+			Time:     1600000000 + int64(height)*blockInterval,
+			TxHashes: []string{"tx1", "tx2"},
+		}
+	}
+	bestHeight := uint64(numBlocks)
+	var numBlockCalls int32
+
+	var mempool []string
+	mempoolAvailable := true
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string
+			Params []json.RawMessage
+		}
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var result interface{}
+		switch req.Method {
+		case "getshardbeststate":
+			result = jsonresult.ShardBestState{ShardHeight: bestHeight, ActiveShards: 8}
+		case "retrieveblockbyheight":
+			atomic.AddInt32(&numBlockCalls, 1)
+			var height uint64
+			if len(req.Params) < 3 || json.Unmarshal(req.Params[0], &height) != nil {
+				http.Error(w, "invalid params", http.StatusBadRequest)
+				return
+			}
+			block, ok := blocks[height]
+			if !ok {
+				http.Error(w, "block not found", http.StatusBadRequest)
+				return
+			}
+			result = []jsonresult.ShardBlockResult{block}
+		case "getrawmempool":
+			if !mempoolAvailable {
+				http.Error(w, "mempool unavailable", http.StatusBadRequest)
+				return
+			}
+			result = map[string][]string{"TxHashes": mempool}
+		default:
+			http.Error(w, fmt.Sprintf("method %v not supported", req.Method), http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"Result": result})
+	}))
+	defer ts.Close()
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+
+	// an empty mempool: the next block confirms the transaction.
+	eta, err := client.EstimateConfirmationTime(0)
+	assert.Equal(t, nil, err, fmt.Errorf("EstimateConfirmationTime error: %v", err))
+	assert.Equal(t, blockInterval*time.Second, eta)
+	assert.Equal(t, int32(ConfirmationTimeNumBlocks), atomic.LoadInt32(&numBlockCalls))
+
+	// 40 pending transactions over 8 shards, i.e, 5 for this shard, take 2 more blocks of 2 transactions.
+	for i := 0; i < 40; i++ {
+		mempool = append(mempool, fmt.Sprintf("pending-%v", i))
+	}
+	eta, err = client.EstimateConfirmationTime(0)
+	assert.Equal(t, nil, err, fmt.Errorf("EstimateConfirmationTime error: %v", err))
+	assert.Equal(t, 3*blockInterval*time.Second, eta)
+
+	mempoolAvailable = false
+	eta, err = client.EstimateConfirmationTime(0)
+	assert.Equal(t, nil, err, fmt.Errorf("EstimateConfirmationTime error: %v", err))
+	assert.Equal(t, blockInterval*time.Second, eta)
+
+	// a chain with a single block has no interval.
+	bestHeight = 1
+	_, err = client.EstimateConfirmationTime(0)
+	assert.NotEqual(t, nil, err)
+
+	_, err = client.EstimateConfirmationTime(byte(common.MaxShardNumber))
+	assert.NotEqual(t, nil, err)
+}
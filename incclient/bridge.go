@@ -1,10 +1,15 @@
 package incclient
 
 import (
+	"context"
+	"encoding/hex"
 	"fmt"
 	"github.com/incognitochain/go-incognito-sdk-v2/crypto"
 	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/rpc"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	ethCommon "github.com/ethereum/go-ethereum/common"
 	"github.com/incognitochain/go-incognito-sdk-v2/common"
@@ -54,6 +59,32 @@ func NewETHDepositProof(blockNumber uint, blockHash ethCommon.Hash, txIdx uint,
 	return &proof
 }
 
+// NewEVMDepositProof creates a new EVMDepositProof for the deposit transaction at index txIdx of the EVM block with the
+// given hex-encoded hash, for users fetching proofs directly from their own EVM RPC. The block number is not needed by
+// the shielding metadata, and is left as 0.
+//
+// Each entry of nodeList is the standard base64 encoding of a node of the block's receipt trie, in its RLP-encoded form
+// (i.e, the raw bytes hashed by Keccak256 into the node's reference). The nodes are those on the path from the root of
+// the trie to the receipt of the transaction, whose key in the trie is the RLP encoding of txIdx. This is what the
+// `Prove` method of a go-ethereum trie outputs; see BuildEVMDepositProofFromReceipt.
+//
+// It returns an error if blockHash is not the hex encoding (with or without the "0x" prefix) of 32 bytes.
+func NewEVMDepositProof(blockHash string, txIdx uint, nodeList []string) (*EVMDepositProof, error) {
+	hashBytes, err := hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(blockHash, "0x"), "0X"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid block hash %v: %v", blockHash, err)
+	}
+	if len(hashBytes) != ethCommon.HashLength {
+		return nil, fmt.Errorf("invalid block hash %v: expected %v bytes, got %v", blockHash, ethCommon.HashLength, len(hashBytes))
+	}
+
+	return &EVMDepositProof{
+		blockHash: ethCommon.BytesToHash(hashBytes),
+		txIdx:     txIdx,
+		nodeList:  nodeList,
+	}, nil
+}
+
 // CreateIssuingRequestTransaction creates a centralized shielding transaction.
 // This function should only be called along with the privateKey of the centralized account.
 func (client *IncClient) CreateIssuingRequestTransaction(privateKey, receiver, tokenIDStr, tokenName string, depositAmount uint64) ([]byte, string, error) {
@@ -238,6 +269,83 @@ func (client *IncClient) CreateAndSendBurningRequestTransaction(privateKey, remo
 	return txHash, nil
 }
 
+// Stages of an Unshield call, reported to its progress callbacks.
+const (
+	UnshieldBurnSent      = "BurnSent"      // the burning transaction has been submitted
+	UnshieldBurnConfirmed = "BurnConfirmed" // the burning transaction has been included in a block
+	UnshieldProofReady    = "ProofReady"    // the burning proof is available
+)
+
+// UnshieldProgressFunc is called by Unshield each time it reaches a new stage.
+type UnshieldProgressFunc func(stage, txHash string)
+
+// UnshieldPollingInterval is the interval between two status checks of Unshield.
+var UnshieldPollingInterval = 10 * time.Second
+
+// Unshield performs the Incognito part of exiting a token to an EVM network. It creates and submits a burning
+// transaction, waits for the transaction to be confirmed, then waits for the burning proof. The returned proof is
+// ready to be submitted to the smart contract (see DecodeBurnProof).
+//
+// The waiting is bounded by ctx; if ctx is done before the proof is available, the hash of the burning
+// transaction is still returned along with the error, so that the proof can be retrieved later via GetBurnProof.
+// Optional progress callbacks are called with the hash of the burning transaction each time a new stage
+// (UnshieldBurnSent, UnshieldBurnConfirmed, UnshieldProofReady) is reached.
+//
+// networkID can be one of the following:
+//	- rpc.ETHNetworkID: the Ethereum network
+//	- rpc.BSCNetworkID: the Binance Smart Chain network
+//	- rpc.PLGNetworkID: the Polygon network
+//	- rpc.FTMNetworkID: the Fantom network
+func (client *IncClient) Unshield(ctx context.Context, privateKey, tokenID, remoteAddress string, amount uint64, networkID int,
+	progress ...UnshieldProgressFunc) (txHash string, proof *jsonresult.InstructionProof, err error) {
+	notify := func(stage string) {
+		for _, f := range progress {
+			if f != nil {
+				f(stage, txHash)
+			}
+		}
+	}
+
+	txHash, err = client.CreateAndSendBurningRequestTransaction(privateKey, remoteAddress, tokenID, amount, networkID)
+	if err != nil {
+		return "", nil, err
+	}
+	notify(UnshieldBurnSent)
+
+	for {
+		isInBlock, err := client.CheckTxInBlock(txHash)
+		if err != nil {
+			Logger.Printf("CheckTxInBlock %v error: %v\n", txHash, err)
+		} else if isInBlock {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return txHash, nil, fmt.Errorf("waiting for tx %v to be confirmed: %v", txHash, ctx.Err())
+		case <-time.After(UnshieldPollingInterval):
+		}
+	}
+	notify(UnshieldBurnConfirmed)
+
+	for {
+		proof, err = client.GetBurnProof(txHash, networkID)
+		if err == nil && proof != nil && len(proof.Instruction) > 0 {
+			break
+		}
+		Logger.Printf("burn proof for tx %v not ready: %v\n", txHash, err)
+
+		select {
+		case <-ctx.Done():
+			return txHash, nil, fmt.Errorf("waiting for the burn proof of tx %v: %v", txHash, ctx.Err())
+		case <-time.After(UnshieldPollingInterval):
+		}
+	}
+	notify(UnshieldProofReady)
+
+	return txHash, proof, nil
+}
+
 // GetBurnProof retrieves the burning proof for the Incognito network for submitting to the smart contract later.
 //
 // An additional parameter `evmNetworkID` is introduced to specify the target EVM network. evmNetworkID can be one of the following:
@@ -261,6 +369,114 @@ func (client *IncClient) GetBurnProof(txHash string, evmNetworkID ...int) (*json
 	return &tmp, nil
 }
 
+// ErrBurnProofNotReady indicates that a burn proof is not available yet (e.g, the burning transaction has not been
+// confirmed by the beacon chain). The request should be retried later.
+var ErrBurnProofNotReady = fmt.Errorf("burn proof is not ready")
+
+// BurnProofRequestInterval is the minimum interval between two consecutive requests made by GetBurnProofs, to honor
+// the rate limit of the remote node. A non-positive value disables the limit.
+var BurnProofRequestInterval = 100 * time.Millisecond
+
+// GetBurnProofs retrieves the burning proofs of many transactions concurrently, using at most `workers` simultaneous
+// requests (MaxGetCoinThreads if workers is not positive), spaced by at least BurnProofRequestInterval.
+//
+// It returns the proofs retrieved successfully, and the errors of the others, both keyed by transaction hash. A proof
+// which is not available yet gets the ErrBurnProofNotReady error. If ctx is done, the remaining transactions get the
+// error of ctx.
+//
+// networkID can be one of the following:
+//	- rpc.ETHNetworkID: the Ethereum network
+//	- rpc.BSCNetworkID: the Binance Smart Chain network
+//	- rpc.PLGNetworkID: the Polygon network
+//	- rpc.FTMNetworkID: the Fantom network
+func (client *IncClient) GetBurnProofs(ctx context.Context, txHashes []string, networkID int, workers int) (map[string]*jsonresult.InstructionProof, map[string]error) {
+	if workers <= 0 {
+		workers = MaxGetCoinThreads
+	}
+
+	var throttle <-chan time.Time
+	if BurnProofRequestInterval > 0 {
+		ticker := time.NewTicker(BurnProofRequestInterval)
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+
+	mtx := new(sync.Mutex)
+	proofs := make(map[string]*jsonresult.InstructionProof)
+	errs := make(map[string]error)
+
+	jobs := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for txHash := range jobs {
+				var proof *jsonresult.InstructionProof
+				var err error
+				select {
+				case <-ctx.Done():
+					err = ctx.Err()
+				default:
+					proof, err = client.getBurnProofOrPending(txHash, networkID)
+				}
+
+				mtx.Lock()
+				if err != nil {
+					errs[txHash] = err
+				} else {
+					proofs[txHash] = proof
+				}
+				mtx.Unlock()
+			}
+		}()
+	}
+
+	seen := make(map[string]bool)
+	for i, txHash := range txHashes {
+		if seen[txHash] {
+			continue
+		}
+		seen[txHash] = true
+
+		if i > 0 && throttle != nil {
+			select {
+			case <-ctx.Done():
+			case <-throttle:
+			}
+		}
+		jobs <- txHash
+	}
+	close(jobs)
+	wg.Wait()
+
+	return proofs, errs
+}
+
+// getBurnProofOrPending is the same as GetBurnProof, except that it returns ErrBurnProofNotReady if the proof is
+// not available yet.
+//
+// A node answers with an UnexpectedErrorCode error both when the burning transaction has not been confirmed yet, and
+// when no such transaction exists. In this case, the transaction is looked up so that an unknown transaction is
+// reported as an error instead of being polled forever.
+func (client *IncClient) getBurnProofOrPending(txHash string, networkID int) (*jsonresult.InstructionProof, error) {
+	proof, err := client.GetBurnProof(txHash, networkID)
+	if err != nil {
+		rpcErr, ok := rpchandler.AsRPCError(err)
+		if !ok || rpcErr.Code != rpchandler.UnexpectedErrorCode {
+			return nil, err
+		}
+		if _, err1 := client.GetTxDetail(txHash); err1 != nil {
+			return nil, fmt.Errorf("cannot get the burn proof of tx %v: %v (tx lookup failed: %v)", txHash, err, err1)
+		}
+		return nil, ErrBurnProofNotReady
+	}
+	if proof == nil || len(proof.Instruction) == 0 {
+		return nil, ErrBurnProofNotReady
+	}
+
+	return proof, nil
+}
 
 func (client *IncClient) GetUnifiedBurnProof(txHash string) (*jsonresult.InstructionProof, error) {
         responseInBytes, err := client.rpcServer.GetUnifiedBurnProof(txHash)
@@ -278,21 +494,68 @@ func (client *IncClient) GetUnifiedBurnProof(txHash string) (*jsonresult.Instruc
 }
 
 // GetBridgeTokens returns all bridge tokens in the network.
+//
+// A token listed several times (e.g, once per network it is bridged from) is returned once, as first listed. The
+// NetworkID and ExternalTokenIDHex of each token are derived from its ExternalTokenID.
 func (client *IncClient) GetBridgeTokens() ([]*BridgeTokenInfo, error) {
+	return client.getBridgeTokens(nil)
+}
+
+// GetBridgeTokensByNetwork returns the bridge tokens of the given networks (e.g, as in BridgeTokenInfo.Network; the
+// comparison is case-insensitive). It returns all bridge tokens if no network is given.
+//
+// A token listed under several networks is returned if one of them is given, with the first matching listing.
+func (client *IncClient) GetBridgeTokensByNetwork(networks ...string) ([]*BridgeTokenInfo, error) {
+	return client.getBridgeTokens(networks)
+}
+
+// getBridgeTokens retrieves the list of bridge tokens, keeps the ones listed under one of the given networks (all of
+// them if no network is given), and de-duplicates them by their Incognito token IDs.
+func (client *IncClient) getBridgeTokens(networks []string) ([]*BridgeTokenInfo, error) {
 	responseInBytes, err := client.rpcServer.GetAllBridgeTokens()
 	if err != nil {
 		return nil, err
 	}
 
-	res := make([]*BridgeTokenInfo, 0)
-	err = rpchandler.ParseResponse(responseInBytes, &res)
+	tokens := make([]*BridgeTokenInfo, 0)
+	err = rpchandler.ParseResponse(responseInBytes, &tokens)
 	if err != nil {
 		return nil, err
 	}
 
+	res := make([]*BridgeTokenInfo, 0)
+	seen := make(map[string]bool)
+	for _, token := range tokens {
+		if token == nil || !isListedUnderNetworks(token, networks) {
+			continue
+		}
+		if token.TokenID != nil {
+			if seen[token.TokenID.String()] {
+				continue
+			}
+			seen[token.TokenID.String()] = true
+		}
+		token.normalize()
+		res = append(res, token)
+	}
+
 	return res, nil
 }
 
+// isListedUnderNetworks checks if a bridge token is listed under one of the given networks. It returns true if no
+// network is given.
+func isListedUnderNetworks(token *BridgeTokenInfo, networks []string) bool {
+	if len(networks) == 0 {
+		return true
+	}
+	for _, network := range networks {
+		if strings.EqualFold(token.Network, network) {
+			return true
+		}
+	}
+	return false
+}
+
 // CheckShieldStatus returns the status of an eth-shielding request.
 //	* -1: error
 //	* 0: tx not found
@@ -314,6 +577,98 @@ func (client *IncClient) CheckShieldStatus(txHash string) (int, error) {
 	return status, err
 }
 
+// CheckUnifiedShieldStatus returns the status of a shielding request into a unified token (i.e, via the bridge
+// aggregator), together with the shielded amounts. If the request has not been processed yet, the returned status is
+// jsonresult.UnifiedShieldPending.
+func (client *IncClient) CheckUnifiedShieldStatus(txHash string) (*jsonresult.UnifiedShieldStatusDetail, error) {
+	responseInBytes, err := client.rpcServer.CheckUnifiedShieldStatus(txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	var res *jsonresult.UnifiedShieldStatusDetail
+	err = rpchandler.ParseResponse(responseInBytes, &res)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return &jsonresult.UnifiedShieldStatusDetail{Status: jsonresult.UnifiedShieldPending}, nil
+	}
+
+	return res, nil
+}
+
+// CheckEVMHashIssued checks if the EVM deposit at the given block hash and transaction index has already been used
+// to mint tokens on the Incognito network.
+//
+// An additional parameter `evmNetworkID` is introduced to specify the target EVM network. evmNetworkID can be one of the following:
+//	- rpc.ETHNetworkID: the Ethereum network
+//	- rpc.BSCNetworkID: the Binance Smart Chain network
+//	- rpc.PLGNetworkID: the Polygon network
+//	- rpc.FTMNetworkID: the Fantom network
+// If set empty, evmNetworkID defaults to rpc.ETHNetworkID. NOTE that only the first value of evmNetworkID is used.
+func (client *IncClient) CheckEVMHashIssued(blockHash string, txIdx uint, evmNetworkID ...int) (bool, error) {
+	responseInBytes, err := client.rpcServer.CheckEVMHashIssued(blockHash, txIdx, evmNetworkID...)
+	if err != nil {
+		return false, err
+	}
+
+	var isIssued bool
+	err = rpchandler.ParseResponse(responseInBytes, &isIssued)
+	if err != nil {
+		return false, err
+	}
+
+	return isIssued, nil
+}
+
+// RetryShield re-submits the shielding request of an EVM deposit transaction that has already been confirmed on the
+// EVM network (e.g, when the previous issuing request failed or was rejected). The deposit proof is rebuilt from
+// the given evmTxHash, and the Incognito tokenID is derived from the deposit event of the transaction.
+//
+// Before re-submitting, it checks whether the deposit has already been used to mint tokens; if so, it returns
+// ErrEVMTxAlreadyShielded without creating any transaction.
+//
+// It returns the hash of the new issuing transaction, and an error (if any).
+func (client *IncClient) RetryShield(privateKey, evmTxHash string, networkID int) (string, error) {
+	txContent, err := client.GetEVMTxByHash(evmTxHash, networkID)
+	if err != nil {
+		return "", err
+	}
+	blockHashStr, ok := txContent["blockHash"].(string)
+	if !ok {
+		return "", fmt.Errorf("EVM tx %v has not been mined", evmTxHash)
+	}
+	txIndexStr, ok := txContent["transactionIndex"].(string)
+	if !ok || len(txIndexStr) < 3 {
+		return "", fmt.Errorf("cannot parse transactionIndex in %v", txContent)
+	}
+	txIndex, err := strconv.ParseUint(txIndexStr[2:], 16, 64)
+	if err != nil {
+		return "", err
+	}
+
+	isIssued, err := client.CheckEVMHashIssued(blockHashStr, uint(txIndex), networkID)
+	if err != nil {
+		return "", err
+	}
+	if isIssued {
+		return "", ErrEVMTxAlreadyShielded
+	}
+
+	tokenIDStr, err := client.GetEVMDepositTokenID(evmTxHash, networkID)
+	if err != nil {
+		return "", err
+	}
+
+	proof, _, err := client.GetEVMDepositProof(evmTxHash, networkID)
+	if err != nil {
+		return "", err
+	}
+
+	return client.CreateAndSendIssuingEVMRequestTransaction(privateKey, tokenIDStr, *proof, networkID)
+}
+
 // GenerateTokenID generates an Incognito tokenID for a bridge token.
 func GenerateTokenID(network, tokenName string) (common.Hash, error) {
 	point := crypto.HashToPoint([]byte(network + "-" + tokenName))
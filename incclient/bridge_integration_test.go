@@ -0,0 +1,55 @@
+//go:build integration
+// +build integration
+
+package incclient
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestIncClient_Unshield burns a token on the test-net and waits for its burn proof. Run it with
+//
+//	UNSHIELD_PRIVATE_KEY=... UNSHIELD_REMOTE_ADDRESS=0x... go test -tags integration -run TestIncClient_Unshield ./incclient/
+//
+// UNSHIELD_TOKEN_ID defaults to pETH.
+func TestIncClient_Unshield(t *testing.T) {
+	privateKey := os.Getenv("UNSHIELD_PRIVATE_KEY")
+	remoteAddress := os.Getenv("UNSHIELD_REMOTE_ADDRESS")
+	if privateKey == "" || remoteAddress == "" {
+		t.Skip("UNSHIELD_PRIVATE_KEY and UNSHIELD_REMOTE_ADDRESS must be set")
+	}
+	tokenID := os.Getenv("UNSHIELD_TOKEN_ID")
+	if tokenID == "" {
+		tokenID = pEthID
+	}
+
+	err := initClients()
+	if err != nil {
+		panic(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	stages := make([]string, 0)
+	txHash, proof, err := ic.Unshield(ctx, privateKey, tokenID, remoteAddress, 1000, rpc.ETHNetworkID,
+		func(stage, txHash string) {
+			Logger.Printf("[%v] %v\n", txHash, stage)
+			stages = append(stages, stage)
+		})
+	assert.Equal(t, nil, err, err)
+	assert.NotEqual(t, "", txHash)
+	assert.Equal(t, []string{UnshieldBurnSent, UnshieldBurnConfirmed, UnshieldProofReady}, stages)
+
+	burnProof, err := DecodeBurnProof(proof)
+	assert.Equal(t, nil, err, err)
+	jsb, _ := json.Marshal(burnProof)
+	Logger.Printf("Burn proof: %v\n", string(jsb))
+}
@@ -1,11 +1,22 @@
 package incclient
 
 import (
+	"context"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"github.com/incognitochain/go-incognito-sdk-v2/internal/safemath"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/jsonresult"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/rpc"
+	"github.com/stretchr/testify/assert"
 )
 
 //UTILS
@@ -313,6 +324,169 @@ func TestIncClient_CheckShieldStatus(t *testing.T) {
 	Logger.Println(status)
 }
 
+func TestIncClient_RetryShield(t *testing.T) {
+	evmTxHash := "0x8c8bc1a0e3e4e0c4f1f84c20bbeb2ae2a1cbd24a1b2a5d47e9b0bc4fbf7c1a3d"
+	blockHash := "0x3a1e8d8c0f8ab5c2c7b7a5b1b5e6b6a9d3d0f5b8c8e7f7a6a4b3c2d1e0f9a8b7"
+
+	var mtx sync.Mutex
+	numCalls := make(map[string]int)
+	var issuedParams map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string
+			Params []json.RawMessage
+		}
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mtx.Lock()
+		numCalls[req.Method]++
+		mtx.Unlock()
+
+		var result interface{}
+		switch req.Method {
+		case "eth_getTransactionByHash":
+			result = map[string]interface{}{
+				"hash":             evmTxHash,
+				"blockHash":        blockHash,
+				"blockNumber":      "0xa",
+				"transactionIndex": "0x1f",
+				"value":            "0x0",
+			}
+		case "checkbschashissued":
+			if len(req.Params) < 1 || json.Unmarshal(req.Params[0], &issuedParams) != nil {
+				http.Error(w, "invalid params", http.StatusBadRequest)
+				return
+			}
+			result = true
+		default:
+			http.Error(w, fmt.Sprintf("method %v not supported", req.Method), http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"Result": result})
+	}))
+	defer ts.Close()
+
+	client := newIncClient(rpc.NewRPCServer(ts.URL), map[int]*rpc.RPCServer{rpc.BSCNetworkID: rpc.NewRPCServer(ts.URL)}, nil, 2)
+
+	// the deposit has already been used to mint tokens: nothing is rebuilt or re-submitted.
+	txHash, err := client.RetryShield("", evmTxHash, rpc.BSCNetworkID)
+	assert.Equal(t, ErrEVMTxAlreadyShielded, err)
+	assert.Equal(t, "", txHash)
+	assert.Equal(t, blockHash, issuedParams["BlockHash"])
+	assert.Equal(t, float64(31), issuedParams["TxIndex"])
+	assert.Equal(t, map[string]int{"eth_getTransactionByHash": 1, "checkbschashissued": 1}, numCalls)
+
+	// the network has no EVM client configured.
+	_, err = client.RetryShield("", evmTxHash, rpc.ETHNetworkID)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestIncClient_GetBurnProofs(t *testing.T) {
+	oldInterval := BurnProofRequestInterval
+	BurnProofRequestInterval = 0
+	defer func() {
+		BurnProofRequestInterval = oldInterval
+	}()
+
+	var mtx sync.Mutex
+	numRunning, maxRunning := 0, 0
+	numCalls := make(map[string]int)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string
+			Params []string
+		}
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil || len(req.Params) != 1 {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+		txHash := req.Params[0]
+		if req.Method == "gettransactionbyhash" {
+			if txHash == "pending-unconfirmed" {
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{"Result": map[string]interface{}{"Hash": txHash, "IsInBlock": true}})
+			} else {
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{
+					"Error": map[string]interface{}{"Code": -1, "Message": "tx not found"},
+				})
+			}
+			return
+		}
+		if req.Method != "getbscburnproof" {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+
+		mtx.Lock()
+		numCalls[txHash]++
+		numRunning++
+		if numRunning > maxRunning {
+			maxRunning = numRunning
+		}
+		mtx.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		mtx.Lock()
+		numRunning--
+		mtx.Unlock()
+
+		switch txHash {
+		case "pending-empty":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"Result": map[string]interface{}{}})
+		case "pending-unconfirmed", "unknown":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"Error": map[string]interface{}{"Code": -1, "Message": "proof of tx not found"},
+			})
+		case "invalid":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"Error": map[string]interface{}{"Code": -32602, "Message": "Invalid parameters"},
+			})
+		default:
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"Result": map[string]interface{}{"Instruction": "inst-" + txHash, "BeaconHeight": "0x1"},
+			})
+		}
+	}))
+	defer ts.Close()
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+
+	txHashes := []string{"pending-empty", "pending-unconfirmed", "invalid", "unknown"}
+	for i := 0; i < 10; i++ {
+		txHashes = append(txHashes, fmt.Sprintf("tx-%v", i))
+	}
+	txHashes = append(txHashes, "tx-0") // duplicates are only fetched once
+
+	workers := 3
+	proofs, errs := client.GetBurnProofs(context.Background(), txHashes, rpc.BSCNetworkID, workers)
+	assert.Equal(t, 10, len(proofs))
+	for i := 0; i < 10; i++ {
+		txHash := fmt.Sprintf("tx-%v", i)
+		assert.Equal(t, "inst-"+txHash, proofs[txHash].Instruction)
+		assert.Equal(t, 1, numCalls[txHash])
+	}
+	assert.Equal(t, 4, len(errs))
+	assert.Equal(t, ErrBurnProofNotReady, errs["pending-empty"])
+	assert.Equal(t, ErrBurnProofNotReady, errs["pending-unconfirmed"])
+
+	// invalid or unknown transactions are not reported as pending, so that they are not polled forever.
+	for _, txHash := range []string{"invalid", "unknown"} {
+		assert.NotEqual(t, nil, errs[txHash])
+		assert.NotEqual(t, ErrBurnProofNotReady, errs[txHash])
+	}
+	assert.Equal(t, true, maxRunning <= workers, fmt.Errorf("%v requests ran concurrently", maxRunning))
+
+	// a cancelled context stops all requests
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	proofs, errs = client.GetBurnProofs(ctx, []string{"tx-100", "tx-101"}, rpc.BSCNetworkID, workers)
+	assert.Equal(t, 0, len(proofs))
+	assert.Equal(t, context.Canceled, errs["tx-100"])
+	assert.Equal(t, context.Canceled, errs["tx-101"])
+	assert.Equal(t, 0, numCalls["tx-100"]+numCalls["tx-101"])
+}
+
 func TestIncClient_CreateAndSendIssuingRequestTransaction(t *testing.T) {
 	var err error
 	ic, err = NewTestNetClientWithCache()
@@ -362,3 +536,282 @@ func TestGenerateTokenID(t *testing.T) {
 }
 
 //END TEST FUNCTIONS
+
+// sampleBurnProof is a synthetic response shaped like that of the `getburnproof` RPC: the hashes and signatures are
+// made up, hence it cannot be verified against a real vault contract.
+const sampleBurnProof = `{
+	"Instruction": "f101551322d332a3cfcb963850cefaa7bdc0c97156f26200c2fdddcec5c6961e7a26282f8afbfa9a64af000000000000000000000000000000000000000000000000000000003b9aca00545e01109e79e69acd8f1f77d96af0974a6eac4408d4d00244fc9f86970cea4d3c9f3ee331d16e357ecd1285aa6cb743a7bfeb45b36a758f4f9b8a32ba0bdf56000000000000000000000000000000000000000000000000000000000012d687",
+	"BeaconHeight": "12d687",
+	"BridgeHeight": "bc614e",
+	"BeaconInstPath": [
+		"60f68dc3f988658250343ba6729261f892fda559f79841232d967028526156b3",
+		"17a34d060e2fd9bf2109818190f3daf5e9c28529b67d6ca9d9f09d733d7b5931",
+		"2bd94e0245eb5c8cdc911a00c740cd9f3b9c69d997f932403f636114a9d15b4f"
+	],
+	"BeaconInstPathIsLeft": [false, true, false],
+	"BeaconInstRoot": "c9d6fdf03e1212e98d6b06ef4e68dddb2a3b838cc916ab91da3faeda5f827aaa",
+	"BeaconBlkData": "3923db11740ac1faa8efa740c881c78a2dcf6184ccbf4f0390cbb599ac4ee47a",
+	"BeaconSigs": [
+		"264d7a016ef7316eb2183e2b5a466191f24750b25f44c9cb04a8083f98e55b02ab38da766437a4b8a012765221d7499d4387988ffb3db51fb2a08aac3e8f25ab01",
+		"73bdcfb95bf74a643e26ed89c8020611f3376765be043f06f6224c4a3b284936fa98889188ee2a88eee317861df3ba120ba296c9e81a2eb72240c3785a88edf800",
+		"0505cae01f09aa1ed8e02f495fd825efcfa44fbf6af71f2dc87e22e14e8ff5df6bc0a1cb1485015cd980e217c64013ef5da868d1656c0ab7301c194c647a4d0301",
+		"2e6d5f6843490fb1ce9172219f3b9e41d41e456bc53dada60b1f1009be390b3ebec8332437e8daf9239b8e04648c87d51770d64fc446c55f37ba6cff0d7088ec00"
+	],
+	"BeaconSigIdxs": [0, 1, 2, 3],
+	"BridgeInstPath": [
+		"030c2c21b77c2e13776716ef4d893a51deeb368af461006644187d7ee05462f7",
+		"e8f2ce2488f6f3a3032d872efc3b505b7f1a07089bb1f2fc14c025680d1fe257"
+	],
+	"BridgeInstPathIsLeft": [true, false],
+	"BridgeInstRoot": "365a9813189af00e419866b069b6cdee2481a03d977ca4f6e98afacab3d81525",
+	"BridgeBlkData": "3add26151667cc483bf62e3f0882d047cbec06d59532c8090187fb0002b7f159",
+	"BridgeSigs": [
+		"dc21f1c8fbdd33ff27de91e6498fdeb3c6d148090107c2989b420229feeb8e981eb1651f3c0112657957e4ccecfdd2fcdef88366f485b3a7ce5f4c43911b792200",
+		"d499875f8f96811f16e70c3105800643061e4e20d33371896ba1781b1768c12ce2b45db3fd80d771cd408a7a5bf6ea8fa13c131279a901d9685bf17620f92a4f00",
+		"ac145cc1d44d9d039b95e168bc32f81fad348eaf7a0a54792b4ff3cce0500a1d293196b2473f5807e305ce09dec890279253225437bebfa4fe4e604baf6d343601"
+	],
+	"BridgeSigIdxs": [0, 2, 3]
+}`
+
+func TestInstructionProof_ToContractArgs(t *testing.T) {
+	var proof jsonresult.InstructionProof
+	err := json.Unmarshal([]byte(sampleBurnProof), &proof)
+	if err != nil {
+		panic(err)
+	}
+
+	args, err := proof.ToContractArgs()
+	assert.Equal(t, nil, err, fmt.Errorf("ToContractArgs error: %v", err))
+	assert.Equal(t, 170, len(args.Instruction))
+	assert.Equal(t, uint8(241), args.Instruction[0])
+	assert.Equal(t, int64(1234567), args.Heights[0].Int64())
+	assert.Equal(t, int64(12345678), args.Heights[1].Int64())
+	assert.Equal(t, 3, len(args.InstPaths[0]))
+	assert.Equal(t, 2, len(args.InstPaths[1]))
+	assert.Equal(t, []bool{false, true, false}, args.InstPathIsLefts[0])
+	assert.Equal(t, []bool{true, false}, args.InstPathIsLefts[1])
+	assert.Equal(t, "c9d6fdf03e1212e98d6b06ef4e68dddb2a3b838cc916ab91da3faeda5f827aaa", fmt.Sprintf("%x", args.InstRoots[0]))
+	assert.Equal(t, "3add26151667cc483bf62e3f0882d047cbec06d59532c8090187fb0002b7f159", fmt.Sprintf("%x", args.BlkData[1]))
+	for i, numSigs := range []int{4, 3} {
+		assert.Equal(t, numSigs, len(args.SigVs[i]))
+		assert.Equal(t, numSigs, len(args.SigRs[i]))
+		assert.Equal(t, numSigs, len(args.SigSs[i]))
+		assert.Equal(t, numSigs, len(args.SigIndices[i]))
+	}
+	assert.Equal(t, []uint8{28, 27, 28, 27}, args.SigVs[0])
+	assert.Equal(t, "264d7a016ef7316eb2183e2b5a466191f24750b25f44c9cb04a8083f98e55b02", fmt.Sprintf("%x", args.SigRs[0][0]))
+	assert.Equal(t, "ab38da766437a4b8a012765221d7499d4387988ffb3db51fb2a08aac3e8f25ab", fmt.Sprintf("%x", args.SigSs[0][0]))
+	assert.Equal(t, int64(2), args.SigIndices[1][1].Int64())
+
+	// ToContractArgs agrees with DecodeBurnProof on a well-formed proof.
+	burnProof, err := DecodeBurnProof(&proof)
+	if err != nil {
+		panic(err)
+	}
+	assert.Equal(t, burnProof.Instruction, args.Instruction)
+	assert.Equal(t, burnProof.Heights, args.Heights)
+	assert.Equal(t, burnProof.InstPaths, args.InstPaths)
+	assert.Equal(t, burnProof.InstPathIsLefts, args.InstPathIsLefts)
+	assert.Equal(t, burnProof.InstRoots, args.InstRoots)
+	assert.Equal(t, burnProof.BlkData, args.BlkData)
+	assert.Equal(t, burnProof.SigIndices, args.SigIndices)
+	assert.Equal(t, burnProof.SigVs, args.SigVs)
+	assert.Equal(t, burnProof.SigRs, args.SigRs)
+	assert.Equal(t, burnProof.SigSs, args.SigSs)
+
+	// 0x-prefixed heights are accepted.
+	prefixedProof := proof
+	prefixedProof.BeaconHeight = "0x" + proof.BeaconHeight
+	args, err = prefixedProof.ToContractArgs()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1234567), args.Heights[0].Int64())
+
+	// malformed proofs
+	malformedProofs := []func(p *jsonresult.InstructionProof){
+		func(p *jsonresult.InstructionProof) { p.Instruction = "" },
+		func(p *jsonresult.InstructionProof) { p.Instruction = "xyz" },
+		func(p *jsonresult.InstructionProof) { p.BridgeHeight = "" },
+		func(p *jsonresult.InstructionProof) { p.BeaconInstPath = p.BeaconInstPath[1:] },
+		func(p *jsonresult.InstructionProof) { p.BridgeInstPath = []string{"abcd", "abcd"} },
+		func(p *jsonresult.InstructionProof) { p.BeaconInstRoot = "" },
+		func(p *jsonresult.InstructionProof) { p.BridgeBlkData = p.BridgeBlkData[2:] },
+		func(p *jsonresult.InstructionProof) { p.BeaconSigIndices = p.BeaconSigIndices[1:] },
+		func(p *jsonresult.InstructionProof) { p.BridgeSigs = []string{"abcd", "abcd", "abcd"} },
+	}
+	for i, malform := range malformedProofs {
+		var p jsonresult.InstructionProof
+		_ = json.Unmarshal([]byte(sampleBurnProof), &p)
+		malform(&p)
+		_, err = p.ToContractArgs()
+		assert.NotEqual(t, nil, err, fmt.Errorf("expected an error for malformed proof #%v", i))
+	}
+}
+
+func TestIncClient_GetBridgeTokens_Deduplicated(t *testing.T) {
+	networks := []string{"eth", "bsc", "plg", "ftm"}
+	allTokens := make([]*BridgeTokenInfo, 0)
+	for i := 0; i < 10; i++ {
+		tokenID := common.HashH([]byte(fmt.Sprintf("token-%v", i)))
+		allTokens = append(allTokens, &BridgeTokenInfo{TokenID: &tokenID, Network: networks[i%len(networks)]})
+	}
+	// a token listed twice, once per network.
+	duplicate := *allTokens[1]
+	duplicate.Network = "ftm"
+	listedTokens := append(append([]*BridgeTokenInfo{}, allTokens...), &duplicate)
+
+	numCalls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string
+		}
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil || req.Method != "getallbridgetokens" {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+		numCalls++
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"Result": listedTokens})
+	}))
+	defer ts.Close()
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+
+	tokens, err := client.GetBridgeTokens()
+	assert.Equal(t, nil, err, fmt.Errorf("GetBridgeTokens error: %v", err))
+	assert.Equal(t, 1, numCalls)
+	assert.Equal(t, len(allTokens), len(tokens))
+	for i, token := range tokens {
+		assert.Equal(t, allTokens[i].TokenID.String(), token.TokenID.String())
+		assert.Equal(t, allTokens[i].Network, token.Network)
+	}
+
+	ethTokens, err := client.GetBridgeTokensByNetwork("ETH")
+	assert.Equal(t, nil, err, fmt.Errorf("GetBridgeTokensByNetwork error: %v", err))
+	assert.Equal(t, 3, len(ethTokens))
+	for _, token := range ethTokens {
+		assert.Equal(t, "eth", token.Network)
+	}
+
+	// the duplicated token is found under both of its networks.
+	ftmTokens, err := client.GetBridgeTokensByNetwork("ftm")
+	assert.Equal(t, nil, err, fmt.Errorf("GetBridgeTokensByNetwork error: %v", err))
+	assert.Equal(t, 3, len(ftmTokens))
+	assert.Equal(t, allTokens[1].TokenID.String(), ftmTokens[2].TokenID.String())
+	assert.Equal(t, "ftm", ftmTokens[2].Network)
+
+	evmTokens, err := client.GetBridgeTokensByNetwork("bsc", "ftm")
+	assert.Equal(t, nil, err, fmt.Errorf("GetBridgeTokensByNetwork error: %v", err))
+	assert.Equal(t, 5, len(evmTokens))
+	assert.Equal(t, "bsc", evmTokens[0].Network)
+}
+
+func TestIncClient_CheckUnifiedShieldStatus(t *testing.T) {
+	statuses := map[string]string{
+		"pendingTx":  `null`,
+		"acceptedTx": `{"Status":1,"Data":[{"Amount":1000,"Reward":10},{"Amount":500,"Reward":0}]}`,
+		"rejectedTx": `{"Status":0,"ErrorCode":1005}`,
+		"overflowTx": `{"Status":1,"Data":[{"Amount":18446744073709551615,"Reward":1}]}`,
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string
+			Params []struct {
+				TxReqID string
+			}
+		}
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil || req.Method != "bridgeaggGetStatusShield" || len(req.Params) != 1 {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+		status, ok := statuses[req.Params[0].TxReqID]
+		if !ok {
+			_, _ = w.Write([]byte(`{"Result":null,"Error":{"Code":-1,"Message":"status not found"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"Result":%v,"Error":null}`, status)))
+	}))
+	defer ts.Close()
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+
+	status, err := client.CheckUnifiedShieldStatus("pendingTx")
+	assert.Equal(t, nil, err, fmt.Errorf("CheckUnifiedShieldStatus error: %v", err))
+	assert.Equal(t, jsonresult.UnifiedShieldPending, status.Status)
+	mintedAmount, err := status.MintedAmount()
+	assert.Equal(t, nil, err, fmt.Errorf("MintedAmount error: %v", err))
+	assert.Equal(t, uint64(0), mintedAmount)
+
+	status, err = client.CheckUnifiedShieldStatus("acceptedTx")
+	assert.Equal(t, nil, err, fmt.Errorf("CheckUnifiedShieldStatus error: %v", err))
+	assert.Equal(t, jsonresult.UnifiedShieldAccepted, status.Status)
+	mintedAmount, err = status.MintedAmount()
+	assert.Equal(t, nil, err, fmt.Errorf("MintedAmount error: %v", err))
+	assert.Equal(t, uint64(1510), mintedAmount)
+
+	status, err = client.CheckUnifiedShieldStatus("rejectedTx")
+	assert.Equal(t, nil, err, fmt.Errorf("CheckUnifiedShieldStatus error: %v", err))
+	assert.Equal(t, jsonresult.UnifiedShieldRejected, status.Status)
+	assert.Equal(t, 1005, status.ErrorCode)
+	mintedAmount, err = status.MintedAmount()
+	assert.Equal(t, nil, err, fmt.Errorf("MintedAmount error: %v", err))
+	assert.Equal(t, uint64(0), mintedAmount)
+
+	status, err = client.CheckUnifiedShieldStatus("overflowTx")
+	assert.Equal(t, nil, err, fmt.Errorf("CheckUnifiedShieldStatus error: %v", err))
+	_, err = status.MintedAmount()
+	assert.Equal(t, safemath.ErrOverflow, err)
+
+	_, err = client.CheckUnifiedShieldStatus("unknownTx")
+	assert.NotEqual(t, nil, err)
+}
+
+func TestIncClient_GetBridgeTokens_Normalized(t *testing.T) {
+	daiAddress := "0x6b175474e89094c44da98b954eedeac495271d0f"
+	usdcAddress := "0x2791bca1f2de4661ed88a30c99a7a9449aa84174"
+	daiAddressBytes, _ := hex.DecodeString(daiAddress[2:])
+	usdcAddressBytes, _ := hex.DecodeString(usdcAddress[2:])
+	daiTokenID := common.HashH([]byte("DAI"))
+	usdcTokenID := common.HashH([]byte("USDC"))
+	btcTokenID := common.HashH([]byte("BTC"))
+	unknownTokenID := common.HashH([]byte("UNKNOWN"))
+	bridgeTokens := []*BridgeTokenInfo{
+		{TokenID: &daiTokenID, Amount: 100, ExternalTokenID: daiAddressBytes, Network: "eth"},
+		{TokenID: &usdcTokenID, Amount: 200, ExternalTokenID: append([]byte("PLG"), usdcAddressBytes...), Network: "plg"},
+		{TokenID: &btcTokenID, Amount: 300, ExternalTokenID: []byte("BTC"), IsCentralized: true},
+		{TokenID: &unknownTokenID, ExternalTokenID: []byte("XYZ")},
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string
+		}
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil || req.Method != "getallbridgetokens" {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"Result": bridgeTokens})
+	}))
+	defer ts.Close()
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+
+	tokens, err := client.GetBridgeTokens()
+	assert.Equal(t, nil, err, fmt.Errorf("GetBridgeTokens error: %v", err))
+	assert.Equal(t, len(bridgeTokens), len(tokens))
+	ethNetworkID, plgNetworkID := rpc.ETHNetworkID, rpc.PLGNetworkID
+	expected := []struct {
+		networkID          *int
+		externalTokenIDHex string
+	}{
+		{&ethNetworkID, daiAddress},
+		{&plgNetworkID, usdcAddress},
+		{nil, ""},
+		{nil, ""},
+	}
+	for i, token := range tokens {
+		assert.Equal(t, bridgeTokens[i].TokenID.String(), token.TokenID.String())
+		assert.Equal(t, bridgeTokens[i].Amount, token.Amount)
+		assert.Equal(t, bridgeTokens[i].IsCentralized, token.IsCentralized)
+		assert.Equal(t, bridgeTokens[i].ExternalTokenID, token.ExternalTokenID)
+		assert.Equal(t, expected[i].networkID, token.NetworkID)
+		assert.Equal(t, expected[i].externalTokenIDHex, token.ExternalTokenIDHex)
+	}
+}
@@ -3,6 +3,7 @@ package incclient
 import (
 	"bytes"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"github.com/incognitochain/go-incognito-sdk-v2/common"
@@ -13,6 +14,7 @@ import (
 
 	rCommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/light"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/trie"
@@ -21,6 +23,25 @@ import (
 
 const EVMZeroAddress = "0x0000000000000000000000000000000000000000"
 
+// ErrEVMTxAlreadyShielded is returned when an EVM deposit transaction has already been used to mint tokens on the
+// Incognito network.
+var ErrEVMTxAlreadyShielded = fmt.Errorf("EVM tx has already been shielded")
+
+// evmDepositEventTopics holds the topics of the deposit events emitted by the Incognito vault contracts.
+// In both events, the first (non-indexed) argument is the address of the deposited token.
+var evmDepositEventTopics = []rCommon.Hash{
+	crypto.Keccak256Hash([]byte("Deposit(address,string,uint256)")),
+	crypto.Keccak256Hash([]byte("DepositV2(address,string,uint256,uint256)")),
+}
+
+// evmExternalTokenIDPrefix is the prefix of the external tokenIDs of bridge tokens on each EVM network.
+var evmExternalTokenIDPrefix = map[int]string{
+	rpc.ETHNetworkID: "",
+	rpc.BSCNetworkID: "BSC",
+	rpc.PLGNetworkID: "PLG",
+	rpc.FTMNetworkID: "FTM",
+}
+
 // BridgeTokenInfo describes the information of a bridge token.
 type BridgeTokenInfo struct {
 	TokenID         *common.Hash `json:"tokenId"`
@@ -28,6 +49,29 @@ type BridgeTokenInfo struct {
 	ExternalTokenID []byte       `json:"externalTokenId"`
 	Network         string       `json:"network"`
 	IsCentralized   bool         `json:"isCentralized"`
+
+	// NetworkID is the EVM network (e.g, rpc.ETHNetworkID) of a decentralized EVM bridge token, as derived from its
+	// ExternalTokenID. It is nil for the other tokens.
+	NetworkID *int `json:"networkId,omitempty"`
+
+	// ExternalTokenIDHex is the 0x-prefixed, lower-case hex-encoded contract address of a decentralized EVM bridge token,
+	// i.e, its ExternalTokenID without the network prefix. It is empty for the other tokens.
+	ExternalTokenIDHex string `json:"externalTokenIdHex"`
+}
+
+// normalize fills the fields of a BridgeTokenInfo derived from the ones returned by the remote node.
+func (token *BridgeTokenInfo) normalize() {
+	token.NetworkID = nil
+	token.ExternalTokenIDHex = ""
+	if token.IsCentralized {
+		return
+	}
+	networkID, tokenAddress, err := parseEVMExternalTokenID(token.ExternalTokenID)
+	if err != nil {
+		return
+	}
+	token.NetworkID = &networkID
+	token.ExternalTokenIDHex = "0x" + hex.EncodeToString(tokenAddress.Bytes())
 }
 
 // GetEVMTxByHash retrieves an EVM transaction from its hash.
@@ -321,6 +365,110 @@ func (client *IncClient) GetEVMDepositProof(txHash string, evmNetworkID ...int)
 	return NewETHDepositProof(uint(blockNumber), blockHash, uint(txIndex), encNodeList), amount, nil
 }
 
+// BuildEVMDepositProofFromReceipt builds the EVMDepositProof of a deposit transaction given its receipt, and the RLP-encoded
+// nodes of the proof of the receipt in the block's receipt trie (see NewEVMDepositProof), ordered from the root of the
+// trie to the receipt. The block hash, block number and transaction index are taken from the receipt.
+//
+// It returns an error if the nodes do not prove the receipt at its transaction index. Since the block's receipt root
+// is not known here, it does not check that the root node is the one of the block; the Incognito network does.
+func BuildEVMDepositProofFromReceipt(receipt *types.Receipt, proofNodes [][]byte) (*EVMDepositProof, error) {
+	if receipt == nil {
+		return nil, fmt.Errorf("receipt is nil")
+	}
+	if receipt.BlockHash == (rCommon.Hash{}) || receipt.BlockNumber == nil {
+		return nil, fmt.Errorf("receipt is not included in a block")
+	}
+	if len(proofNodes) == 0 {
+		return nil, fmt.Errorf("empty proof")
+	}
+
+	proof := light.NewNodeSet()
+	encNodeList := make([]string, 0)
+	for _, node := range proofNodes {
+		err := proof.Put(crypto.Keccak256(node), node)
+		if err != nil {
+			return nil, err
+		}
+		encNodeList = append(encNodeList, base64.StdEncoding.EncodeToString(node))
+	}
+
+	key, err := rlp.EncodeToBytes(receipt.TransactionIndex)
+	if err != nil {
+		return nil, fmt.Errorf("rlp encode returns an error: %v", err)
+	}
+	value, err := trie.VerifyProof(crypto.Keccak256Hash(proofNodes[0]), key, proof)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proof: %v", err)
+	}
+	valueBuf := new(bytes.Buffer)
+	expectedValue := encodeForDerive(types.Receipts{receipt}, 0, valueBuf)
+	if !bytes.Equal(value, expectedValue) {
+		return nil, fmt.Errorf("proof does not match the receipt at index %v", receipt.TransactionIndex)
+	}
+
+	return NewETHDepositProof(uint(receipt.BlockNumber.Uint64()), receipt.BlockHash, receipt.TransactionIndex, encNodeList), nil
+}
+
+// GetEVMDepositTokenID returns the Incognito tokenID of the token deposited in an EVM transaction. The deposited
+// token is read from the vault's deposit event in the transaction receipt, and then looked up in the list of bridge tokens.
+//
+// An additional parameter `evmNetworkID` is introduced to specify the target EVM network. evmNetworkID can be one of the following:
+//	- rpc.ETHNetworkID: the Ethereum network
+//	- rpc.BSCNetworkID: the Binance Smart Chain network
+//	- rpc.PLGNetworkID: the Polygon network
+//	- rpc.FTMNetworkID: the Fantom network
+// If set empty, evmNetworkID defaults to rpc.ETHNetworkID. NOTE that only the first value of evmNetworkID is used.
+func (client *IncClient) GetEVMDepositTokenID(txHash string, evmNetworkID ...int) (string, error) {
+	networkID := rpc.ETHNetworkID
+	if len(evmNetworkID) > 0 {
+		networkID = evmNetworkID[0]
+	}
+	prefix, ok := evmExternalTokenIDPrefix[networkID]
+	if !ok {
+		return "", rpc.EVMNetworkNotFoundError(networkID)
+	}
+
+	receipt, err := client.GetEVMTxReceipt(txHash, networkID)
+	if err != nil {
+		return "", err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return "", fmt.Errorf("EVM tx %v failed", txHash)
+	}
+
+	var tokenAddress []byte
+	for _, log := range receipt.Logs {
+		if len(log.Topics) == 0 || len(log.Data) < 32 {
+			continue
+		}
+		for _, topic := range evmDepositEventTopics {
+			if log.Topics[0] == topic {
+				tokenAddress = log.Data[12:32]
+				break
+			}
+		}
+		if tokenAddress != nil {
+			break
+		}
+	}
+	if tokenAddress == nil {
+		return "", fmt.Errorf("cannot find any deposit event in EVM tx %v", txHash)
+	}
+	externalTokenID := append([]byte(prefix), tokenAddress...)
+
+	bridgeTokens, err := client.GetBridgeTokens()
+	if err != nil {
+		return "", err
+	}
+	for _, token := range bridgeTokens {
+		if !token.IsCentralized && token.TokenID != nil && bytes.Equal(token.ExternalTokenID, externalTokenID) {
+			return token.TokenID.String(), nil
+		}
+	}
+
+	return "", fmt.Errorf("cannot find bridge token with externalTokenID %x", externalTokenID)
+}
+
 // GetMostRecentEVMBlockNumber retrieves the most recent EVM block number.
 //
 // An additional parameter `evmNetworkID` is introduced to specify the target EVM network. evmNetworkID can be one of the following:
@@ -6,6 +6,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	rCommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/light"
@@ -15,7 +16,10 @@ import (
 	"github.com/incognitochain/go-incognito-sdk-v2/metadata"
 	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/rpc"
 	"github.com/incognitochain/go-incognito-sdk-v2/transaction/tx_ver2"
+	"github.com/stretchr/testify/assert"
 	"log"
+	"math/big"
+	"strings"
 	"testing"
 )
 
@@ -270,3 +274,92 @@ func verifyProofAndParseReceipt(iReq *metadata.IssuingEVMRequest) (*types.Receip
 
 	return constructedReceipt, nil
 }
+
+func TestBuildEVMDepositProofFromReceipt(t *testing.T) {
+	blockHash := rCommon.HexToHash("0x6b1b0f7a4e3a3c4f0c5d2b2e1f9c8a7d6e5f4a3b2c1d0e9f8a7b6c5d4e3f2a1b")
+	blockNumber := big.NewInt(12345678)
+	receipts := make([]*types.Receipt, 0)
+	for i := 0; i < 200; i++ {
+		receipt := &types.Receipt{
+			Status:            types.ReceiptStatusSuccessful,
+			CumulativeGasUsed: uint64(21000 * (i + 1)),
+			Logs:              []*types.Log{},
+			BlockHash:         blockHash,
+			BlockNumber:       blockNumber,
+			TransactionIndex:  uint(i),
+		}
+		if i%2 == 1 {
+			receipt.Type = types.DynamicFeeTxType
+		}
+		receipts = append(receipts, receipt)
+	}
+
+	receiptList := types.Receipts(receipts)
+	receiptTrie := new(trie.Trie)
+	valueBuf := new(bytes.Buffer)
+	for i := range receipts {
+		key, err := rlp.EncodeToBytes(uint(i))
+		if err != nil {
+			panic(err)
+		}
+		receiptTrie.Update(key, encodeForDerive(receiptList, i, valueBuf))
+	}
+	assert.Equal(t, types.DeriveSha(receiptList, trie.NewStackTrie(nil)), receiptTrie.Hash())
+
+	for _, txIdx := range []uint{0, 1, 127, 128, 199} {
+		key, err := rlp.EncodeToBytes(txIdx)
+		if err != nil {
+			panic(err)
+		}
+		nodeSet := light.NewNodeSet()
+		err = receiptTrie.Prove(key, 0, nodeSet)
+		if err != nil {
+			panic(err)
+		}
+		proofNodes := make([][]byte, 0)
+		for _, node := range nodeSet.NodeList() {
+			proofNodes = append(proofNodes, node)
+		}
+
+		proof, err := BuildEVMDepositProofFromReceipt(receipts[txIdx], proofNodes)
+		assert.Equal(t, nil, err, fmt.Errorf("BuildEVMDepositProofFromReceipt error: %v", err))
+		assert.Equal(t, blockHash, proof.BlockHash())
+		assert.Equal(t, uint(blockNumber.Uint64()), proof.BlockNumber())
+		assert.Equal(t, txIdx, proof.TxIdx())
+		assert.Equal(t, len(proofNodes), len(proof.NodeList()))
+		for i, encodedNode := range proof.NodeList() {
+			node, err := base64.StdEncoding.DecodeString(encodedNode)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, proofNodes[i], node)
+		}
+
+		// the same proof built from its parts.
+		newProof, err := NewEVMDepositProof(blockHash.Hex(), txIdx, proof.NodeList())
+		assert.Equal(t, nil, err, fmt.Errorf("NewEVMDepositProof error: %v", err))
+		assert.Equal(t, &EVMDepositProof{blockHash: blockHash, txIdx: txIdx, nodeList: proof.NodeList()}, newProof)
+		newProof, err = NewEVMDepositProof(strings.TrimPrefix(blockHash.Hex(), "0x"), txIdx, proof.NodeList())
+		assert.Equal(t, nil, err, fmt.Errorf("NewEVMDepositProof error: %v", err))
+		assert.Equal(t, blockHash, newProof.BlockHash())
+
+		// a block hash must be 32 bytes of hex.
+		for _, invalidHash := range []string{"", "0x", blockHash.Hex()[:64], blockHash.Hex() + "00",
+			"0x" + strings.Repeat("zz", 32)} {
+			_, err = NewEVMDepositProof(invalidHash, txIdx, proof.NodeList())
+			assert.NotEqual(t, nil, err, fmt.Errorf("NewEVMDepositProof should fail for %v", invalidHash))
+		}
+
+		// the proof of a receipt does not prove another one.
+		otherIdx := (txIdx + 1) % uint(len(receipts))
+		_, err = BuildEVMDepositProofFromReceipt(receipts[otherIdx], proofNodes)
+		assert.NotEqual(t, nil, err)
+
+		// nor does a proof with a missing node.
+		_, err = BuildEVMDepositProofFromReceipt(receipts[txIdx], proofNodes[:len(proofNodes)-1])
+		assert.NotEqual(t, nil, err)
+	}
+
+	_, err := BuildEVMDepositProofFromReceipt(nil, [][]byte{{0}})
+	assert.NotEqual(t, nil, err)
+	_, err = BuildEVMDepositProofFromReceipt(receipts[0], nil)
+	assert.NotEqual(t, nil, err)
+}
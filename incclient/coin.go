@@ -1,6 +1,7 @@
 package incclient
 
 import (
+	"context"
 	"fmt"
 	"github.com/incognitochain/go-incognito-sdk-v2/coin"
 	"github.com/incognitochain/go-incognito-sdk-v2/common"
@@ -43,6 +44,10 @@ func (client *IncClient) GetOutputCoins(outCoinKey *rpc.OutCoinKey, tokenID stri
 		return client.GetAndCacheOutCoins(outCoinKey, tokenID, true, privateKey)
 	}
 
+	if fromCache && client.coinStore != nil && client.version == 2 && height == 0 && outCoinKey.OtaKey() != "" {
+		return client.GetOutputCoinsFromStore(outCoinKey, tokenID)
+	}
+
 	if client.version == 1 {
 		return client.GetOutputCoinsV1(outCoinKey, tokenID, height)
 	} else {
@@ -72,6 +77,81 @@ func (client *IncClient) GetOutputCoinsV2(outCoinKey *rpc.OutCoinKey, tokenID st
 	return ParseCoinFromJsonResponse(b)
 }
 
+// FindFirstActivityHeight returns the lowest block height of the OTA key's shard at which the remote full-node detects
+// an output coin (in PRV or in any token) belonging to the OTA key. It is meant to narrow down the range of blocks to be
+// scanned for a newly-imported key.
+//
+// For an OTA key, the full-node returns the coins found in blocks from the StartHeight of the query up to its current
+// height. The number of coins found from a height is therefore non-increasing in that height, and the first activity
+// height is the highest one from which all the coins of the key are still found. It is binary-searched, so only
+// O(log(height)) queries are needed (two per step: one for PRV, one for tokens, whose coins are all listed under
+// common.ConfidentialAssetID). Coins received during the search do not change the result. Coins that the full-node
+// cannot detect (e.g, pruned data) are invisible to the search, in which case the returned height is later than the
+// actual first receipt.
+//
+// It returns an error if the key has no detectable activity up to the current block height of the shard.
+func (client *IncClient) FindFirstActivityHeight(otaKey string) (uint64, error) {
+	w, err := wallet.Base58CheckDeserialize(otaKey)
+	if err != nil {
+		return 0, err
+	}
+	if w.KeySet.OTAKey.GetOTASecretKey() == nil || w.KeySet.OTAKey.GetPublicSpend() == nil {
+		return 0, fmt.Errorf("invalid OTAKey")
+	}
+	pubKey := w.KeySet.OTAKey.GetPublicSpend().ToBytesS()
+	shardID := common.GetShardIDFromLastByte(pubKey[len(pubKey)-1])
+
+	bestState, err := client.GetShardBestState(int(shardID))
+	if err != nil {
+		return 0, err
+	}
+
+	outCoinKey := rpc.NewOutCoinKey("", otaKey, "")
+	countCoinsFrom := func(startHeight uint64) (int, error) {
+		numCoins := 0
+		for _, tokenID := range []string{common.PRVIDStr, common.ConfidentialAssetID.String()} {
+			responseInBytes, err := client.rpcServer.GetListOutputCoinsByRPCV1(outCoinKey, tokenID, startHeight)
+			if err != nil {
+				return 0, err
+			}
+			var res jsonresult.ListOutputCoins
+			err = rpchandler.ParseResponse(responseInBytes, &res)
+			if err != nil {
+				return 0, err
+			}
+			for _, outCoins := range res.Outputs {
+				numCoins += len(outCoins)
+			}
+		}
+		return numCoins, nil
+	}
+
+	totalCoins, err := countCoinsFrom(1)
+	if err != nil {
+		return 0, err
+	}
+	if totalCoins == 0 {
+		return 0, fmt.Errorf("no activity found for the OTA key up to height %v of shard %v", bestState.ShardHeight, shardID)
+	}
+
+	// invariant: all the coins are found from low, and some are not found from high+1.
+	low, high := uint64(1), bestState.ShardHeight
+	for low < high {
+		mid := low + (high-low+1)/2
+		numCoins, err := countCoinsFrom(mid)
+		if err != nil {
+			return 0, err
+		}
+		if numCoins >= totalCoins {
+			low = mid
+		} else {
+			high = mid - 1
+		}
+	}
+
+	return low, nil
+}
+
 // GetListDecryptedOutCoin retrieves and decrypts all the output tokens for a private key.
 // It returns
 //	- a map from the serial number to the output coin;
@@ -109,6 +189,20 @@ func (client *IncClient) GetListDecryptedOutCoin(privateKey string, tokenID stri
 	return mapOutCoin, nil
 }
 
+// GetCoinShard returns the shardID in which a coin belongs to, which is determined by the last byte of its public key.
+//
+// If the public key of the coin is unknown, it returns 255.
+func (client *IncClient) GetCoinShard(c coin.PlainCoin) byte {
+	if c == nil {
+		return 255
+	}
+	shardID, err := c.GetShardID()
+	if err != nil {
+		return 255
+	}
+	return shardID
+}
+
 // CheckCoinsSpent checks if the provided serial numbers have been spent or not.
 //
 // Returned result in boolean list.
@@ -131,6 +225,47 @@ func (client *IncClient) CheckCoinsSpent(shardID byte, tokenID string, snList []
 	return tmp, nil
 }
 
+// checkCoinsSpentInBatches calls CheckCoinsSpent on batches of the given key images to avoid oversized requests.
+func (client *IncClient) checkCoinsSpentInBatches(shardID byte, tokenID string, keyImages []string) ([]bool, error) {
+	batchSize := 100
+	numBatches := len(keyImages) / batchSize
+	if len(keyImages)%batchSize != 0 {
+		numBatches++
+	}
+	checkSpentList := make([]bool, 0)
+	for i := 0; i < numBatches; i++ {
+		start := i * batchSize
+		end := (i + 1) * batchSize
+		if end > len(keyImages) {
+			end = len(keyImages)
+		}
+		checkSpentListBatch, err := client.CheckCoinsSpent(shardID, tokenID, keyImages[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("cannot check spent coins: %v %v %v", tokenID, len(keyImages), err)
+		}
+		checkSpentList = append(checkSpentList, checkSpentListBatch...)
+	}
+
+	return checkSpentList, nil
+}
+
+// GetUnspentCoins returns the spendable UTXOs of a private key w.r.t the given tokenID.
+//
+// It scans all output coins owned by the private key, computes their key images locally, and checks them against
+// the remote full-node (via the `hasserialnumbers` RPC) in batches. Only unspent coins with a positive value
+// are returned. The private key is never sent to the remote full-node.
+func (client *IncClient) GetUnspentCoins(privateKey, tokenID string) ([]coin.PlainCoin, error) {
+	utxoList, _, err := client.GetUnspentOutputCoins(privateKey, tokenID, 0)
+	if err != nil {
+		return nil, err
+	}
+	if utxoList == nil {
+		utxoList = make([]coin.PlainCoin, 0)
+	}
+
+	return utxoList, nil
+}
+
 // GetUnspentOutputCoins retrieves all unspent coins of a private key, without sending the private key to the remote full-node.
 func (client *IncClient) GetUnspentOutputCoins(privateKey, tokenID string, height uint64) ([]coin.PlainCoin, []*big.Int, error) {
 	keyWallet, err := wallet.Base58CheckDeserialize(privateKey)
@@ -160,27 +295,11 @@ func (client *IncClient) GetUnspentOutputCoins(privateKey, tokenID string, heigh
 	if len(listKeyImages) == 0 {
 		return nil, nil, nil
 	}
-	var checkSpentList []bool
 
 	shardID := common.GetShardIDFromLastByte(keyWallet.KeySet.PaymentAddress.Pk[len(keyWallet.KeySet.PaymentAddress.Pk)-1])
-
-	batchSize := 100
-	numBatches := len(listKeyImages) / batchSize
-	if len(listKeyImages)%batchSize != 0 {
-		numBatches++
-	}
-	checkSpentList = make([]bool, 0)
-	for i := 0; i < numBatches; i++ {
-		start := i * batchSize
-		end := (i + 1) * batchSize
-		if end > len(listKeyImages) {
-			end = len(listKeyImages)
-		}
-		checkSpentListBatch, err := client.CheckCoinsSpent(shardID, tokenID, listKeyImages[start:end])
-		if err != nil {
-			return nil, nil, fmt.Errorf("cannot check spent coins: %v %v %v", tokenID, len(listKeyImages), err)
-		}
-		checkSpentList = append(checkSpentList, checkSpentListBatch...)
+	checkSpentList, err := client.checkCoinsSpentInBatches(shardID, tokenID, listKeyImages)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	listUnspentOutputCoins := make([]coin.PlainCoin, 0)
@@ -197,6 +316,27 @@ func (client *IncClient) GetUnspentOutputCoins(privateKey, tokenID string, heigh
 
 // GetAllUTXOsV2 returns all v2 UTXOs (and associated tokenIDs) of a private key.
 func (client *IncClient) GetAllUTXOsV2(privateKey string) (map[string][]coin.PlainCoin, map[string][]*big.Int, error) {
+	return client.getAllUTXOsV2(privateKey, 0)
+}
+
+// ScanAllTokens scans and decrypts the UTXOs of a private key across every token in a single pass, and groups them
+// by tokenID. Instead of querying each token separately, all v2 token coins are retrieved at once under the
+// confidential-asset tokenID, and their tokenIDs are recovered from their asset tags. PRV UTXOs are listed under
+// common.PRVIDStr.
+//
+// `fromHeight` is the height from which output coins are scanned; it is ignored by remote nodes that index v2 coins
+// by OTA keys.
+func (client *IncClient) ScanAllTokens(privateKey string, fromHeight uint64) (map[string][]coin.PlainCoin, error) {
+	res, _, err := client.getAllUTXOsV2(privateKey, fromHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// getAllUTXOsV2 returns all v2 UTXOs (and associated tokenIDs) of a private key, scanning from the given height.
+func (client *IncClient) getAllUTXOsV2(privateKey string, fromHeight uint64) (map[string][]coin.PlainCoin, map[string][]*big.Int, error) {
 	utxoRes := make(map[string][]coin.PlainCoin)
 	idxRes := make(map[string][]*big.Int)
 	w, err := wallet.Base58CheckDeserialize(privateKey)
@@ -204,7 +344,7 @@ func (client *IncClient) GetAllUTXOsV2(privateKey string) (map[string][]coin.Pla
 		return nil, nil, err
 	}
 
-	prvUTXOs, prvIndices, err := client.GetUnspentOutputCoins(privateKey, common.PRVIDStr, 0)
+	prvUTXOs, prvIndices, err := client.GetUnspentOutputCoins(privateKey, common.PRVIDStr, fromHeight)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -213,7 +353,7 @@ func (client *IncClient) GetAllUTXOsV2(privateKey string) (map[string][]coin.Pla
 		idxRes[common.PRVIDStr] = prvIndices
 	}
 
-	tokenUTXOs, tokenIndices, err := client.GetUnspentOutputCoins(privateKey, common.ConfidentialAssetID.String(), 0)
+	tokenUTXOs, tokenIndices, err := client.GetUnspentOutputCoins(privateKey, common.ConfidentialAssetID.String(), fromHeight)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -402,6 +542,151 @@ func (client *IncClient) GetOTACoinsByIndices(shardID byte, tokenID string, idxL
 	return res, nil
 }
 
+// ScanProgressFunc is called by ScanOTACoinsByIndices each time a window of OTA coins has been retrieved, with the
+// number of indices scanned so far and the total number of indices to scan.
+type ScanProgressFunc func(scanned, total uint64)
+
+// ScanOTACoinsByIndices retrieves all OTA coins of a shard with indices in the range [fromIndex, toIndex].
+// Coins are requested in windows of at most `batchSize` indices. A remote node may return fewer coins than requested
+// when it caps the size of a response; in this case, the cap is detected and the missing indices are requested again
+// in windows of the detected size, so that no coin is missed. Windows are stitched together in the returned map.
+//
+// If `progress` is not nil, it is called after each window.
+func (client *IncClient) ScanOTACoinsByIndices(shardID byte, tokenID string, fromIndex, toIndex uint64, progress ScanProgressFunc) (map[uint64]jsonresult.ICoinInfo, error) {
+	res, _, err := client.ScanOTACoinsByIndicesWithContext(context.Background(), shardID, tokenID, fromIndex, toIndex, progress)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ScanOTACoinsByIndicesWithContext is the same as ScanOTACoinsByIndices, except that the scan can be cancelled via
+// the given context. Besides the retrieved coins, it returns a cursor, which is the first index that has not been
+// scanned (toIndex+1 if the scan completes).
+//
+// When ctx is done, the scan stops right away (a pending request to the remote node is aborted) and the coins
+// retrieved so far are returned together with the cursor and ctx.Err() (e.g, context.Canceled). The scan can be
+// resumed by calling this function again with fromIndex set to the cursor.
+func (client *IncClient) ScanOTACoinsByIndicesWithContext(ctx context.Context, shardID byte, tokenID string, fromIndex, toIndex uint64,
+	progress ScanProgressFunc) (map[uint64]jsonresult.ICoinInfo, uint64, error) {
+	if fromIndex > toIndex {
+		return nil, fromIndex, fmt.Errorf("invalid index range [%v-%v]", fromIndex, toIndex)
+	}
+
+	res := make(map[uint64]jsonresult.ICoinInfo)
+	total := toIndex - fromIndex + 1
+	windowSize := uint64(batchSize)
+	for currentIndex := fromIndex; currentIndex <= toIndex; {
+		if err := ctx.Err(); err != nil {
+			return res, currentIndex, err
+		}
+
+		nextIndex := currentIndex + windowSize - 1
+		if nextIndex > toIndex || nextIndex < currentIndex {
+			nextIndex = toIndex
+		}
+		idxList := make([]uint64, 0)
+		for i := currentIndex; i <= nextIndex; i++ {
+			idxList = append(idxList, i)
+		}
+
+		tmpRes, maxPerResponse, err := client.getOTACoinsByIndicesWithContext(ctx, shardID, tokenID, idxList)
+		if err != nil {
+			if ctx.Err() != nil {
+				return res, currentIndex, err
+			}
+			return nil, currentIndex, err
+		}
+		for idx, outCoin := range tmpRes {
+			res[idx] = outCoin
+		}
+		if uint64(maxPerResponse) < windowSize {
+			windowSize = uint64(maxPerResponse)
+		}
+		if progress != nil {
+			progress(nextIndex-fromIndex+1, total)
+		}
+
+		if nextIndex == toIndex {
+			break
+		}
+		currentIndex = nextIndex + 1
+	}
+
+	return res, toIndex + 1, nil
+}
+
+// getOTACoinsByIndicesWithContext is the same as getOTACoinsByIndicesPaginated, except that the pending request is
+// aborted as soon as ctx is done, in which case ctx.Err() is returned.
+func (client *IncClient) getOTACoinsByIndicesWithContext(ctx context.Context, shardID byte, tokenID string, idxList []uint64) (map[uint64]jsonresult.ICoinInfo, int, error) {
+	res, maxPerResponse, err := client.WithContext(ctx).getOTACoinsByIndicesPaginated(shardID, tokenID, idxList)
+	if err != nil && ctx.Err() != nil {
+		return nil, 0, ctx.Err()
+	}
+
+	return res, maxPerResponse, err
+}
+
+// getOTACoinsByIndicesPaginated retrieves the OTA coins of the given indices. If the remote node truncates a response,
+// the missing indices are requested again with at most as many indices as the node returned. A window for which the
+// node returns no coin at all is skipped if all of its indices are beyond the current coin length of the shard (see
+// GetOTACoinLengthByShard); otherwise, an error is returned. It returns the retrieved coins and the maximum number of
+// coins per response observed.
+func (client *IncClient) getOTACoinsByIndicesPaginated(shardID byte, tokenID string, idxList []uint64) (map[uint64]jsonresult.ICoinInfo, int, error) {
+	res := make(map[uint64]jsonresult.ICoinInfo)
+	maxPerResponse := len(idxList)
+	pending := idxList
+	var coinLength *uint64
+	for len(pending) > 0 {
+		window := pending
+		if len(window) > maxPerResponse {
+			window = window[:maxPerResponse]
+		}
+
+		tmpRes, err := client.GetOTACoinsByIndices(shardID, tokenID, window)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		missing := make([]uint64, 0)
+		for _, idx := range window {
+			if outCoin, ok := tmpRes[idx]; ok {
+				res[idx] = outCoin
+			} else {
+				missing = append(missing, idx)
+			}
+		}
+		if len(missing) == len(window) {
+			// the node has none of these coins: only the indices beyond its current coin length can be skipped.
+			if coinLength == nil {
+				length, err := client.GetOTACoinLengthByShard(shardID, tokenID)
+				if err != nil {
+					return nil, 0, err
+				}
+				coinLength = &length
+			}
+			for _, idx := range window {
+				if idx < *coinLength {
+					return nil, 0, fmt.Errorf("remote node returned no coin for index %v (coin length %v)", idx, *coinLength)
+				}
+			}
+			Logger.Printf("Skipping %v indices from %v, beyond the coin length %v\n", len(window), window[0], *coinLength)
+			pending = pending[len(window):]
+			continue
+		}
+		if len(missing) > 0 {
+			maxPerResponse = len(window) - len(missing)
+			Logger.Printf("Remote node returned %v/%v coins, re-requesting the missing indices in windows of %v\n",
+				maxPerResponse, len(window), maxPerResponse)
+		}
+
+		pending = append(missing, pending[len(window):]...)
+	}
+
+	return res, maxPerResponse, nil
+}
+
 // GetOTACoinLength returns the current sizes (number of output coins) of PRV and tokens for each shard.
 //
 // Sample output:
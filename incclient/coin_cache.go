@@ -363,7 +363,7 @@ func (client *IncClient) getCoinsByIndices(
 		idxList = append(idxList, i)
 	}
 
-	tmpOutCoins, err := client.GetOTACoinsByIndices(shardID, tokenIDStr, idxList)
+	tmpOutCoins, _, err := client.getOTACoinsByIndicesPaginated(shardID, tokenIDStr, idxList)
 	if err != nil {
 		status.err = err
 		statusChan <- status
@@ -0,0 +1,170 @@
+package incclient
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/coin"
+	"github.com/incognitochain/go-incognito-sdk-v2/internal/safemath"
+)
+
+// minimizeChangeMaxTries is the maximum number of subsets explored by MinimizeChange before it settles for the best
+// selection found so far.
+const minimizeChangeMaxTries = 100000
+
+// CoinSelector is a strategy to choose the input coins of a transaction.
+type CoinSelector interface {
+	// Select returns a subset of candidates whose total value is at least requiredAmount. The candidates must not be
+	// modified.
+	Select(candidates []coin.PlainCoin, requiredAmount uint64) ([]coin.PlainCoin, error)
+}
+
+// LargestFirst is a CoinSelector which spends the largest coins first, hence minimizes the number of inputs (and the
+// size of the transaction).
+type LargestFirst struct{}
+
+// Select implements CoinSelector.
+func (LargestFirst) Select(candidates []coin.PlainCoin, requiredAmount uint64) ([]coin.PlainCoin, error) {
+	sortedCoins := sortCoinsByValueDesc(candidates)
+	res := make([]coin.PlainCoin, 0)
+	totalAmount := uint64(0)
+	for _, c := range sortedCoins {
+		if totalAmount >= requiredAmount && len(res) > 0 {
+			break
+		}
+		var err error
+		totalAmount, err = safemath.AddUint64(totalAmount, c.GetValue())
+		if err != nil {
+			return nil, fmt.Errorf("total unspent amount overflows: %v", err)
+		}
+		res = append(res, c)
+	}
+	if totalAmount < requiredAmount {
+		return nil, fmt.Errorf("total unspent amount (%v) is less than the required amount (%v)", totalAmount, requiredAmount)
+	}
+	if len(res) > MaxInputSize {
+		return nil, fmt.Errorf("need %v input coins to cover %v, support at most %v", len(res), requiredAmount, MaxInputSize)
+	}
+
+	return res, nil
+}
+
+// MinimizeChange is a CoinSelector which chooses the coins leaving the smallest change (preferring fewer inputs on
+// ties). This avoids creating small change coins which would later have to be aggregated. The search is bounded: on
+// large candidate lists, the best selection found within a fixed number of tries is returned, which never leaves more
+// change than LargestFirst.
+type MinimizeChange struct{}
+
+// Select implements CoinSelector.
+func (MinimizeChange) Select(candidates []coin.PlainCoin, requiredAmount uint64) ([]coin.PlainCoin, error) {
+	best, err := LargestFirst{}.Select(candidates, requiredAmount)
+	if err != nil {
+		return nil, err
+	}
+	bestChange := totalValue(best) - requiredAmount
+	if bestChange == 0 {
+		return best, nil
+	}
+
+	sortedCoins := sortCoinsByValueDesc(candidates)
+	// suffixSums[i] is the total value of sortedCoins[i:].
+	suffixSums := make([]uint64, len(sortedCoins)+1)
+	for i := len(sortedCoins) - 1; i >= 0; i-- {
+		suffixSums[i], err = safemath.AddUint64(suffixSums[i+1], sortedCoins[i].GetValue())
+		if err != nil {
+			return best, nil
+		}
+	}
+
+	tries := 0
+	current := make([]coin.PlainCoin, 0)
+	var search func(start int, sum uint64)
+	search = func(start int, sum uint64) {
+		tries++
+		if sum >= requiredAmount {
+			change := sum - requiredAmount
+			if change < bestChange || (change == bestChange && len(current) < len(best)) {
+				best = append([]coin.PlainCoin{}, current...)
+				bestChange = change
+			}
+			return
+		}
+		if len(current) >= MaxInputSize {
+			return
+		}
+		for i := start; i < len(sortedCoins) && tries < minimizeChangeMaxTries && bestChange > 0; i++ {
+			if sum+suffixSums[i] < requiredAmount {
+				return
+			}
+			// adding a coin which overshoots by more than the best change cannot improve.
+			if sum+sortedCoins[i].GetValue() > requiredAmount+bestChange {
+				continue
+			}
+			current = append(current, sortedCoins[i])
+			search(i+1, sum+sortedCoins[i].GetValue())
+			current = current[:len(current)-1]
+		}
+	}
+	search(0, 0)
+
+	return best, nil
+}
+
+// SelectCoins chooses coins from candidates to cover target plus fee using the given strategy (LargestFirst if nil).
+// It returns an error if the candidates are insufficient.
+func SelectCoins(candidates []coin.PlainCoin, target, fee uint64, strategy CoinSelector) ([]coin.PlainCoin, error) {
+	requiredAmount, err := safemath.AddUint64(target, fee)
+	if err != nil {
+		return nil, fmt.Errorf("required amount overflows: %v", err)
+	}
+	for _, c := range candidates {
+		if c == nil {
+			return nil, fmt.Errorf("candidate coin is nil")
+		}
+	}
+	if strategy == nil {
+		strategy = LargestFirst{}
+	}
+
+	return strategy.Select(candidates, requiredAmount)
+}
+
+// chooseCoinsBySelector is the same as chooseBestCoinsByAmount, except that the coins are chosen by selector.
+func chooseCoinsBySelector(coinList []coin.PlainCoin, requiredAmount uint64, selector CoinSelector) ([]coin.PlainCoin, []uint64, error) {
+	chosenCoins, err := SelectCoins(coinList, requiredAmount, 0, selector)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	positions := make(map[string]int)
+	for i, c := range coinList {
+		positions[inputCoinID(c)] = i
+	}
+	chosenIndexList := make([]uint64, 0)
+	for _, c := range chosenCoins {
+		pos, ok := positions[inputCoinID(c)]
+		if !ok {
+			return nil, nil, fmt.Errorf("coin %v chosen by the selector is not a candidate", inputCoinID(c))
+		}
+		chosenIndexList = append(chosenIndexList, uint64(pos))
+	}
+
+	return chosenCoins, chosenIndexList, nil
+}
+
+// sortCoinsByValueDesc returns a copy of coinList sorted by values in the descending order.
+func sortCoinsByValueDesc(coinList []coin.PlainCoin) []coin.PlainCoin {
+	res := append([]coin.PlainCoin{}, coinList...)
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].GetValue() > res[j].GetValue()
+	})
+	return res
+}
+
+func totalValue(coinList []coin.PlainCoin) uint64 {
+	res := uint64(0)
+	for _, c := range coinList {
+		res += c.GetValue()
+	}
+	return res
+}
@@ -0,0 +1,157 @@
+package incclient
+
+import (
+	"fmt"
+	"math"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/coin"
+	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"github.com/incognitochain/go-incognito-sdk-v2/key"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/rpc"
+	"github.com/incognitochain/go-incognito-sdk-v2/wallet"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestCoins returns unconcealed coins with the given values. It is for testing purposes ONLY.
+func newTestCoins(t *testing.T, values ...uint64) []coin.PlainCoin {
+	w, err := wallet.GenRandomWalletForShardID(0)
+	if err != nil {
+		panic(err)
+	}
+	res := make([]coin.PlainCoin, 0)
+	for _, value := range values {
+		c, err := coin.NewCoinFromPaymentInfo(coin.NewTransferCoinParams(key.InitPaymentInfo(w.KeySet.PaymentAddress, value, []byte{})))
+		assert.Equal(t, nil, err, fmt.Errorf("NewCoinFromPaymentInfo error: %v", err))
+		res = append(res, c)
+	}
+	return res
+}
+
+func TestSelectCoins(t *testing.T) {
+	candidates := newTestCoins(t, 5, 100, 20, 50, 30)
+
+	chosen, err := SelectCoins(candidates, 60, 10, LargestFirst{})
+	assert.Equal(t, nil, err, fmt.Errorf("SelectCoins error: %v", err))
+	assert.Equal(t, []coin.PlainCoin{candidates[1]}, chosen)
+
+	chosen, err = SelectCoins(candidates, 120, 0, nil)
+	assert.Equal(t, nil, err, fmt.Errorf("SelectCoins error: %v", err))
+	assert.Equal(t, []coin.PlainCoin{candidates[1], candidates[3]}, chosen)
+
+	// 50 + 30 exactly covers 80, while LargestFirst would leave a change of 20.
+	chosen, err = SelectCoins(candidates, 75, 5, MinimizeChange{})
+	assert.Equal(t, nil, err, fmt.Errorf("SelectCoins error: %v", err))
+	assert.Equal(t, uint64(80), totalValue(chosen))
+	assert.Equal(t, 2, len(chosen))
+
+	for _, strategy := range []CoinSelector{LargestFirst{}, MinimizeChange{}} {
+		_, err = SelectCoins(candidates, 200, 6, strategy)
+		assert.NotEqual(t, nil, err)
+		_, err = SelectCoins(candidates, math.MaxUint64, 1, strategy)
+		assert.NotEqual(t, nil, err)
+		_, err = SelectCoins(nil, 1, 0, strategy)
+		assert.NotEqual(t, nil, err)
+	}
+	_, err = SelectCoins(append(candidates, nil), 1, 0, nil)
+	assert.NotEqual(t, nil, err)
+
+	// the candidates are not modified.
+	assert.Equal(t, uint64(5), candidates[0].GetValue())
+	assert.Equal(t, uint64(100), candidates[1].GetValue())
+
+	for i := 0; i < numTests; i++ {
+		values := make([]uint64, 1+common.RandInt()%10)
+		for j := range values {
+			values[j] = 1 + common.RandUint64()%1000
+		}
+		candidates = newTestCoins(t, values...)
+		total := totalValue(candidates)
+		required := 1 + common.RandUint64()%total
+
+		largestFirst, err := SelectCoins(candidates, required, 0, LargestFirst{})
+		assert.Equal(t, nil, err, fmt.Errorf("SelectCoins error: %v", err))
+		minimizeChange, err := SelectCoins(candidates, required, 0, MinimizeChange{})
+		assert.Equal(t, nil, err, fmt.Errorf("SelectCoins error: %v", err))
+		assert.Equal(t, true, totalValue(largestFirst) >= required)
+		assert.Equal(t, true, totalValue(minimizeChange) >= required)
+		assert.Equal(t, true, len(largestFirst) <= len(minimizeChange))
+
+		// compare with the smallest change among all subsets.
+		bestChange := total - required
+		for mask := 1; mask < 1<<uint(len(values)); mask++ {
+			sum := uint64(0)
+			for j, value := range values {
+				if mask&(1<<uint(j)) != 0 {
+					sum += value
+				}
+			}
+			if sum >= required && sum-required < bestChange {
+				bestChange = sum - required
+			}
+		}
+		assert.Equal(t, bestChange, totalValue(minimizeChange)-required)
+	}
+}
+
+func TestExcludeCoins(t *testing.T) {
+	candidates := newTestCoins(t, 5, 100, 20)
+	idxList := []uint64{7, 8, 9}
+
+	remaining, remainingIndices, excludedAmount, err := excludeCoins(candidates, idxList, []string{inputCoinID(candidates[1])})
+	assert.Equal(t, nil, err, fmt.Errorf("excludeCoins error: %v", err))
+	assert.Equal(t, []coin.PlainCoin{candidates[0], candidates[2]}, remaining)
+	assert.Equal(t, []uint64{7, 9}, remainingIndices)
+	assert.Equal(t, uint64(100), excludedAmount)
+
+	// the total excluded value overflows.
+	candidates = newTestCoins(t, math.MaxUint64, 1)
+	_, _, _, err = excludeCoins(candidates, nil, []string{inputCoinID(candidates[0]), inputCoinID(candidates[1])})
+	assert.NotEqual(t, nil, err)
+}
+
+func TestIncClient_CreateRawTransaction_CoinSelector(t *testing.T) {
+	shardID := byte(common.RandInt() % common.MaxShardNumber)
+	senderWallet, err := wallet.GenRandomWalletForShardID(shardID)
+	if err != nil {
+		panic(err)
+	}
+	privateKey := senderWallet.Base58CheckSerialize(wallet.PrivateKeyType)
+	receiverWallet, err := wallet.GenRandomWalletForShardID(shardID)
+	if err != nil {
+		panic(err)
+	}
+	receiver := receiverWallet.Base58CheckSerialize(wallet.PaymentAddressType)
+
+	server := newMockCoinServer()
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	// coins worth 1000, 1001, ..., 1009.
+	err = server.addCoins(senderWallet.KeySet.PaymentAddress, 10)
+	if err != nil {
+		panic(err)
+	}
+
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+	client.SetCoinStore(NewMemCoinStore())
+
+	// 1908 + 100 = 1003 + 1005 is exactly covered by two coins.
+	txParam := NewTxParam(privateKey, []string{receiver}, []uint64{1908}, 100, nil, nil, nil)
+	txParam.CoinSelector = MinimizeChange{}
+	encodedTx, _, err := client.CreateRawTransaction(txParam, 2)
+	assert.Equal(t, nil, err, fmt.Errorf("CreateRawTransaction error: %v", err))
+	tx := decodeTestTx(t, encodedTx)
+	assert.Equal(t, 2, len(tx.GetProof().GetInputCoins()))
+	assert.Equal(t, []uint64{}, receivedAmounts(t, tx, &senderWallet.KeySet))
+	assert.Equal(t, []uint64{1908}, receivedAmounts(t, tx, &receiverWallet.KeySet))
+
+	// LargestFirst spends the two largest coins and gets a change back.
+	txParam = NewTxParam(privateKey, []string{receiver}, []uint64{1908}, 100, nil, nil, nil)
+	txParam.CoinSelector = LargestFirst{}
+	encodedTx, _, err = client.CreateRawTransaction(txParam, 2)
+	assert.Equal(t, nil, err, fmt.Errorf("CreateRawTransaction error: %v", err))
+	tx = decodeTestTx(t, encodedTx)
+	assert.Equal(t, 2, len(tx.GetProof().GetInputCoins()))
+	assert.Equal(t, []uint64{1009 + 1008 - 2008}, receivedAmounts(t, tx, &senderWallet.KeySet))
+}
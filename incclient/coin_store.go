@@ -0,0 +1,260 @@
+package incclient
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/coin"
+	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/jsonresult"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/rpc"
+	"github.com/incognitochain/go-incognito-sdk-v2/wallet"
+)
+
+// CoinStore is a pluggable persistence layer for scanned output coins (v2).
+// Wallets can implement it on top of BoltDB, SQLite, etc. to avoid re-scanning the whole chain each time.
+//
+// Output coins are grouped by an OTA key and a tokenID. Since all token output coins are indexed together on the
+// chain, the tokenID used to group coins is either common.PRVIDStr or common.ConfidentialAssetID.
+// The "height" of a group is the index of the last OTA coin scanned, NOT a block height.
+//
+// Put and Get take the OTA key, the tokenID and the OTA indices of the coins, instead of a bare Put([]coin.Coin) and
+// Get(otaKey, tokenID) ([]coin.Coin, error): an output coin v2 reveals neither the OTA key it belongs to nor its index
+// on the chain. The former is needed to group the coins, and the latter is returned along with the coins by the
+// scanning methods (e.g, GetOutputCoins), and used as the ring index of the coin when it is spent. Put also records
+// the last scanned index so that a scan with no new coins still moves forward.
+type CoinStore interface {
+	// Put appends the given coins (and their OTA indices) to the store, and records lastHeight as the last
+	// scanned index for the given otaKey and tokenID.
+	Put(otaKey, tokenID string, coins []coin.Coin, indices []uint64, lastHeight uint64) error
+
+	// Get returns all stored coins with their OTA indices for the given otaKey and tokenID, sorted by index.
+	Get(otaKey, tokenID string) ([]coin.Coin, []uint64, error)
+
+	// LastHeight returns the last scanned index for the given otaKey and tokenID.
+	// The boolean value indicates whether anything has been scanned.
+	LastHeight(otaKey, tokenID string) (uint64, bool, error)
+}
+
+type memCoinEntry struct {
+	coins      map[uint64]coin.Coin
+	lastHeight uint64
+}
+
+// MemCoinStore implements a CoinStore which keeps all data in memory.
+type MemCoinStore struct {
+	mtx  *sync.RWMutex
+	data map[string]*memCoinEntry
+}
+
+// NewMemCoinStore creates a new, empty MemCoinStore.
+func NewMemCoinStore() *MemCoinStore {
+	return &MemCoinStore{
+		mtx:  new(sync.RWMutex),
+		data: make(map[string]*memCoinEntry),
+	}
+}
+
+func memCoinStoreKey(otaKey, tokenID string) string {
+	return otaKey + "-" + tokenID
+}
+
+// Put implements the Put method of a CoinStore.
+func (store *MemCoinStore) Put(otaKey, tokenID string, coins []coin.Coin, indices []uint64, lastHeight uint64) error {
+	if len(coins) != len(indices) {
+		return fmt.Errorf("expected %v indices, got %v", len(coins), len(indices))
+	}
+
+	store.mtx.Lock()
+	defer store.mtx.Unlock()
+
+	k := memCoinStoreKey(otaKey, tokenID)
+	entry, ok := store.data[k]
+	if !ok {
+		entry = &memCoinEntry{coins: make(map[uint64]coin.Coin)}
+		store.data[k] = entry
+	}
+	for i, c := range coins {
+		entry.coins[indices[i]] = c
+	}
+	entry.lastHeight = lastHeight
+
+	return nil
+}
+
+// Get implements the Get method of a CoinStore.
+func (store *MemCoinStore) Get(otaKey, tokenID string) ([]coin.Coin, []uint64, error) {
+	store.mtx.RLock()
+	defer store.mtx.RUnlock()
+
+	coins := make([]coin.Coin, 0)
+	indices := make([]uint64, 0)
+	entry, ok := store.data[memCoinStoreKey(otaKey, tokenID)]
+	if !ok {
+		return coins, indices, nil
+	}
+	for idx := range entry.coins {
+		indices = append(indices, idx)
+	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+	for _, idx := range indices {
+		coins = append(coins, entry.coins[idx])
+	}
+
+	return coins, indices, nil
+}
+
+// LastHeight implements the LastHeight method of a CoinStore.
+func (store *MemCoinStore) LastHeight(otaKey, tokenID string) (uint64, bool, error) {
+	store.mtx.RLock()
+	defer store.mtx.RUnlock()
+
+	entry, ok := store.data[memCoinStoreKey(otaKey, tokenID)]
+	if !ok {
+		return 0, false, nil
+	}
+
+	return entry.lastHeight, true, nil
+}
+
+// SetCoinStore sets the CoinStore used by the IncClient when scanning v2 output coins. Set it to nil to disable.
+func (client *IncClient) SetCoinStore(store CoinStore) {
+	client.coinStore = store
+}
+
+// GetOutputCoinsFromStore retrieves v2 output coins of an OutCoinKey using the client's CoinStore.
+// Only OTA coins with indices greater than the stored LastHeight are fetched from the remote node; the rest are
+// served from the store.
+func (client *IncClient) GetOutputCoinsFromStore(outCoinKey *rpc.OutCoinKey, tokenID string) ([]jsonresult.ICoinInfo, []*big.Int, error) {
+	return client.GetOutputCoinsFromStoreWithContext(context.Background(), outCoinKey, tokenID)
+}
+
+// GetOutputCoinsFromStoreWithContext is the same as GetOutputCoinsFromStore, except that the scan can be cancelled
+// via the given context.
+//
+// When ctx is done, the scan stops right away (a pending request to the remote node is aborted) and the coins
+// found so far are returned together with ctx.Err() (e.g, context.Canceled). The scanned range is persisted in the
+// CoinStore (its LastHeight is the cursor of the scan), so the next call resumes where the scan stopped.
+func (client *IncClient) GetOutputCoinsFromStoreWithContext(ctx context.Context, outCoinKey *rpc.OutCoinKey, tokenID string) ([]jsonresult.ICoinInfo, []*big.Int, error) {
+	if client.coinStore == nil {
+		return nil, nil, fmt.Errorf("coinStore has not been set")
+	}
+
+	tokenIDStr := tokenID
+	if tokenIDStr != common.PRVIDStr {
+		tokenIDStr = common.ConfidentialAssetID.String()
+	}
+	otaKey := outCoinKey.OtaKey()
+
+	shardID, err := GetShardIDFromPaymentAddress(outCoinKey.PaymentAddress())
+	if err != nil || shardID == 255 {
+		return nil, nil, fmt.Errorf("GetShardIDPaymentAddressKey failed: %v", err)
+	}
+
+	w, err := wallet.Base58CheckDeserialize(otaKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	keySet := w.KeySet
+	if keySet.OTAKey.GetOTASecretKey() == nil || keySet.OTAKey.GetPublicSpend() == nil {
+		return nil, nil, fmt.Errorf("invalid OTAKey")
+	}
+
+	coinLength, err := client.GetOTACoinLengthByShard(shardID, tokenIDStr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	currentIndex := uint64(0)
+	lastHeight, found, err := client.coinStore.LastHeight(otaKey, tokenIDStr)
+	if err != nil {
+		return nil, nil, err
+	}
+	if found {
+		currentIndex = lastHeight + 1
+	}
+	Logger.Printf("Current OTALength for token %v, shard %v: %v, stored LastHeight: %v (%v)\n",
+		tokenIDStr, shardID, coinLength, lastHeight, found)
+
+	var scanErr error
+	for currentIndex < coinLength {
+		nextIndex := currentIndex + uint64(batchSize)
+		if nextIndex > coinLength {
+			nextIndex = coinLength
+		}
+
+		scanErr = ctx.Err()
+		var status getCoinStatus
+		if scanErr == nil {
+			statusChan := make(chan getCoinStatus, 1)
+			client.WithContext(ctx).getCoinsByIndices(keySet, shardID, tokenIDStr, currentIndex, nextIndex-1, statusChan)
+			status = <-statusChan
+			if status.err != nil && ctx.Err() != nil {
+				scanErr = ctx.Err()
+			}
+		}
+		if scanErr != nil {
+			Logger.Printf("Scan of token %v, shard %v cancelled at index %v: %v\n", tokenIDStr, shardID, currentIndex, scanErr)
+			break
+		}
+		if status.err != nil {
+			return nil, nil, fmt.Errorf("getCoinsByIndices FAILED at indices [%v-%v]: %v", status.fromIndex, status.toIndex, status.err)
+		}
+
+		newCoins := make([]coin.Coin, 0)
+		newIndices := make([]uint64, 0)
+		for idx, outCoin := range status.data {
+			tmpCoin, ok := outCoin.(coin.Coin)
+			if !ok {
+				return nil, nil, fmt.Errorf("cannot parse coin %v as a coin.Coin", idx)
+			}
+			newCoins = append(newCoins, tmpCoin)
+			newIndices = append(newIndices, idx)
+		}
+		err = client.coinStore.Put(otaKey, tokenIDStr, newCoins, newIndices, nextIndex-1)
+		if err != nil {
+			return nil, nil, err
+		}
+		currentIndex = nextIndex
+	}
+
+	storedCoins, storedIndices, err := client.coinStore.Get(otaKey, tokenIDStr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var rawAssetTags map[string]*common.Hash
+	if tokenID != tokenIDStr && len(storedCoins) > 0 {
+		rawAssetTags, err = client.GetAllAssetTags()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	outCoins := make([]jsonresult.ICoinInfo, 0)
+	indices := make([]*big.Int, 0)
+	for i, storedCoin := range storedCoins {
+		if rawAssetTags != nil {
+			tmpCoinV2, ok := storedCoin.(*coin.CoinV2)
+			if !ok {
+				return nil, nil, fmt.Errorf("cannot parse coin %v as a CoinV2", storedIndices[i])
+			}
+			tmpTokenID, _ := tmpCoinV2.GetTokenId(&keySet, rawAssetTags)
+			if tmpTokenID == nil || tmpTokenID.String() != tokenID {
+				continue
+			}
+		}
+
+		outCoin, ok := storedCoin.(jsonresult.ICoinInfo)
+		if !ok {
+			return nil, nil, fmt.Errorf("cannot parse coin %v as an ICoinInfo", storedIndices[i])
+		}
+		outCoins = append(outCoins, outCoin)
+		indices = append(indices, new(big.Int).SetUint64(storedIndices[i]))
+	}
+
+	return outCoins, indices, scanErr
+}
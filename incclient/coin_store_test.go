@@ -0,0 +1,340 @@
+package incclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/coin"
+	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"github.com/incognitochain/go-incognito-sdk-v2/key"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/jsonresult"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/rpc"
+	"github.com/incognitochain/go-incognito-sdk-v2/wallet"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockCoinServer serves the OTA-coin RPCs from in-memory lists of PRV and token output coins.
+type mockCoinServer struct {
+	mtx        *sync.Mutex
+	coins      []jsonresult.OutCoin
+	tokenCoins []jsonresult.OutCoin
+	tokenIDs   []string
+	spent      map[string]bool
+	numCalls   map[string]int
+
+	// base58-encoded transactions in the mempool, keyed by their hashes
+	pendingTxs map[string]string
+
+	// the maximum number of coins returned by getotacoinsbyindices (0 for no limit)
+	maxPerResponse int
+
+	// whether getotacoinsbyindices returns no coin at all, e.g, as a node failing transiently
+	emptyResponses bool
+}
+
+func newMockCoinServer() *mockCoinServer {
+	return &mockCoinServer{
+		mtx:        new(sync.Mutex),
+		spent:      make(map[string]bool),
+		numCalls:   make(map[string]int),
+		pendingTxs: make(map[string]string),
+	}
+}
+
+func (m *mockCoinServer) markSpent(keyImages ...string) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	for _, keyImage := range keyImages {
+		m.spent[keyImage] = true
+	}
+}
+
+func (m *mockCoinServer) addCoins(paymentAddress key.PaymentAddress, numCoins int) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	for i := 0; i < numCoins; i++ {
+		paymentInfo := key.PaymentInfo{PaymentAddress: paymentAddress, Amount: uint64(1000 + i)}
+		c, err := coin.NewCoinFromPaymentInfo(coin.NewTransferCoinParams(&paymentInfo))
+		if err != nil {
+			return err
+		}
+		m.coins = append(m.coins, jsonresult.NewOutCoin(c))
+	}
+
+	return nil
+}
+
+func (m *mockCoinServer) addTokenCoins(paymentAddress key.PaymentAddress, tokenID common.Hash, numCoins int) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.tokenIDs = append(m.tokenIDs, tokenID.String())
+	for i := 0; i < numCoins; i++ {
+		paymentInfo := key.PaymentInfo{PaymentAddress: paymentAddress, Amount: uint64(1000 + i)}
+		c, _, err := coin.NewCoinCA(coin.NewTransferCoinParams(&paymentInfo), &tokenID)
+		if err != nil {
+			return err
+		}
+		m.tokenCoins = append(m.tokenCoins, jsonresult.NewOutCoin(c))
+	}
+
+	return nil
+}
+
+func (m *mockCoinServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	var req struct {
+		Method string
+		Params []json.RawMessage
+	}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	m.numCalls[req.Method]++
+
+	var result interface{}
+	switch req.Method {
+	case "getotacoinlength":
+		lengths := make(map[byte]uint64)
+		tokenLengths := make(map[byte]uint64)
+		for shardID := 0; shardID < common.MaxShardNumber; shardID++ {
+			lengths[byte(shardID)] = uint64(len(m.coins))
+			tokenLengths[byte(shardID)] = uint64(len(m.tokenCoins))
+		}
+		result = map[string]map[byte]uint64{
+			common.PRVIDStr:                     lengths,
+			common.ConfidentialAssetID.String(): tokenLengths,
+		}
+	case "getotacoinsbyindices":
+		var params struct {
+			TokenID string
+			Indices []uint64
+		}
+		if len(req.Params) < 1 || json.Unmarshal(req.Params[0], &params) != nil {
+			http.Error(w, "invalid params", http.StatusBadRequest)
+			return
+		}
+		coins := m.coins
+		if params.TokenID != common.PRVIDStr {
+			coins = m.tokenCoins
+		}
+		res := make(map[uint64]jsonresult.OutCoin)
+		for i, idx := range params.Indices {
+			if m.emptyResponses || (m.maxPerResponse > 0 && i >= m.maxPerResponse) {
+				break
+			}
+			if idx < uint64(len(coins)) {
+				res[idx] = coins[idx]
+			}
+		}
+		result = res
+	case "listprivacycustomtokenids":
+		result = m.tokenIDs
+	case "randomcommitmentsandpublickeys":
+		var lenDecoy int
+		if len(req.Params) < 2 || json.Unmarshal(req.Params[1], &lenDecoy) != nil || len(m.coins) == 0 {
+			http.Error(w, "invalid params", http.StatusBadRequest)
+			return
+		}
+		res := jsonresult.RandomCommitmentAndPublicKeyResult{}
+		for i := 0; i < lenDecoy; i++ {
+			idx := common.RandInt() % len(m.coins)
+			res.CommitmentIndices = append(res.CommitmentIndices, uint64(idx))
+			res.PublicKeys = append(res.PublicKeys, m.coins[idx].PublicKey)
+			res.Commitments = append(res.Commitments, m.coins[idx].Commitment)
+		}
+		result = res
+	case "hasserialnumbers":
+		var keyImages []string
+		if len(req.Params) < 2 || json.Unmarshal(req.Params[1], &keyImages) != nil {
+			http.Error(w, "invalid params", http.StatusBadRequest)
+			return
+		}
+		res := make([]bool, 0)
+		for _, keyImage := range keyImages {
+			res = append(res, m.spent[keyImage])
+		}
+		result = res
+	case "getrawmempool":
+		txHashes := make([]string, 0)
+		for txHash := range m.pendingTxs {
+			txHashes = append(txHashes, txHash)
+		}
+		result = map[string][]string{"TxHashes": txHashes}
+	case "getencodedtransactionsbyhashes":
+		var params struct {
+			TxHashList []string
+		}
+		if len(req.Params) < 1 || json.Unmarshal(req.Params[0], &params) != nil {
+			http.Error(w, "invalid params", http.StatusBadRequest)
+			return
+		}
+		res := make(map[string]string)
+		for _, txHash := range params.TxHashList {
+			if encodedTx, ok := m.pendingTxs[txHash]; ok {
+				res[txHash] = encodedTx
+			}
+		}
+		result = res
+	default:
+		http.Error(w, fmt.Sprintf("method %v not supported", req.Method), http.StatusBadRequest)
+		return
+	}
+
+	resultBytes, _ := json.Marshal(result)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"Result": json.RawMessage(resultBytes)})
+}
+
+func TestMemCoinStore_Get(t *testing.T) {
+	newCoin := func(idx uint64) coin.Coin {
+		c := new(coin.CoinV2)
+		c.SetInfo([]byte(fmt.Sprintf("%v", idx)))
+		return c
+	}
+
+	store := NewMemCoinStore()
+	err := store.Put("ota", common.PRVIDStr, []coin.Coin{newCoin(7), newCoin(3), newCoin(9)}, []uint64{7, 3, 9}, 9)
+	assert.Equal(t, nil, err)
+	err = store.Put("ota", common.PRVIDStr, []coin.Coin{newCoin(12), newCoin(1), newCoin(10)}, []uint64{12, 1, 10}, 12)
+	assert.Equal(t, nil, err)
+
+	for i := 0; i < 10; i++ {
+		coins, indices, err := store.Get("ota", common.PRVIDStr)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, []uint64{1, 3, 7, 9, 10, 12}, indices)
+		for j, c := range coins {
+			assert.Equal(t, fmt.Sprintf("%v", indices[j]), string(c.GetInfo()))
+		}
+	}
+}
+
+func TestIncClient_GetOutputCoinsFromStore(t *testing.T) {
+	myWallet, err := wallet.NewMasterKeyFromSeed(common.RandBytes(32))
+	if err != nil {
+		panic(err)
+	}
+	otherWallet, err := wallet.NewMasterKeyFromSeed(common.RandBytes(32))
+	if err != nil {
+		panic(err)
+	}
+	privateKey := myWallet.Base58CheckSerialize(wallet.PrivateKeyType)
+
+	server := newMockCoinServer()
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	for _, kw := range []*wallet.KeyWallet{myWallet, otherWallet} {
+		err = server.addCoins(kw.KeySet.PaymentAddress, 5)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+	store := NewMemCoinStore()
+	client.SetCoinStore(store)
+
+	outCoinKey, err := NewOutCoinKeyFromPrivateKey(privateKey)
+	if err != nil {
+		panic(err)
+	}
+
+	// first scan: everything is fetched from the server.
+	outCoins, indices, err := client.GetOutputCoins(outCoinKey, common.PRVIDStr, 0)
+	assert.Equal(t, nil, err, fmt.Errorf("GetOutputCoins error: %v", err))
+	assert.Equal(t, 5, len(outCoins))
+	assert.Equal(t, len(outCoins), len(indices))
+	assert.Equal(t, 1, server.numCalls["getotacoinsbyindices"])
+
+	lastHeight, found, err := store.LastHeight(outCoinKey.OtaKey(), common.PRVIDStr)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, found)
+	assert.Equal(t, uint64(9), lastHeight)
+
+	// second scan: nothing new on the server, all coins come from the store.
+	outCoins, _, err = client.GetOutputCoins(outCoinKey, common.PRVIDStr, 0)
+	assert.Equal(t, nil, err, fmt.Errorf("GetOutputCoins error: %v", err))
+	assert.Equal(t, 5, len(outCoins))
+	assert.Equal(t, 1, server.numCalls["getotacoinsbyindices"])
+	assert.Equal(t, 2, server.numCalls["getotacoinlength"])
+
+	// third scan: only the new coins are fetched.
+	err = server.addCoins(myWallet.KeySet.PaymentAddress, 3)
+	if err != nil {
+		panic(err)
+	}
+	outCoins, indices, err = client.GetOutputCoins(outCoinKey, common.PRVIDStr, 0)
+	assert.Equal(t, nil, err, fmt.Errorf("GetOutputCoins error: %v", err))
+	assert.Equal(t, 8, len(outCoins))
+	assert.Equal(t, 2, server.numCalls["getotacoinsbyindices"])
+	for _, idx := range indices {
+		assert.Equal(t, true, idx.Uint64() < 5 || idx.Uint64() >= 10, fmt.Errorf("unexpected index %v", idx))
+	}
+
+	// without the store, the client falls back to the listoutputcoins RPC.
+	client.SetCoinStore(nil)
+	_, _, err = client.GetOutputCoins(outCoinKey, common.PRVIDStr, 0)
+	assert.NotEqual(t, nil, err)
+	assert.Equal(t, 2, server.numCalls["getotacoinsbyindices"])
+}
+
+func TestIncClient_GetOutputCoinsFromStoreWithContext(t *testing.T) {
+	oldBatchSize := batchSize
+	defer func() {
+		batchSize = oldBatchSize
+	}()
+
+	myWallet, err := wallet.NewMasterKeyFromSeed(common.RandBytes(32))
+	if err != nil {
+		panic(err)
+	}
+	privateKey := myWallet.Base58CheckSerialize(wallet.PrivateKeyType)
+
+	server := newMockCoinServer()
+	err = server.addCoins(myWallet.KeySet.PaymentAddress, 10)
+	if err != nil {
+		panic(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancellingServer := &cancellingCoinServer{mockCoinServer: server, numWindows: 2, cancel: cancel, release: make(chan struct{})}
+	ts := httptest.NewServer(cancellingServer)
+	defer ts.Close()
+	defer close(cancellingServer.release)
+
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+	store := NewMemCoinStore()
+	client.SetCoinStore(store)
+	outCoinKey, err := NewOutCoinKeyFromPrivateKey(privateKey)
+	if err != nil {
+		panic(err)
+	}
+
+	// the scan is cancelled while the third window is pending: the coins of the first two windows are returned.
+	batchSize = 3
+	outCoins, indices, err := client.GetOutputCoinsFromStoreWithContext(ctx, outCoinKey, common.PRVIDStr)
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 6, len(outCoins))
+	assert.Equal(t, len(outCoins), len(indices))
+	lastHeight, found, err := store.LastHeight(outCoinKey.OtaKey(), common.PRVIDStr)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, found)
+	assert.Equal(t, uint64(5), lastHeight)
+
+	// the next scan resumes from the stored cursor.
+	cancellingServer.numWindows = 10
+	outCoins, _, err = client.GetOutputCoinsFromStoreWithContext(context.Background(), outCoinKey, common.PRVIDStr)
+	assert.Equal(t, nil, err, fmt.Errorf("GetOutputCoinsFromStoreWithContext error: %v", err))
+	assert.Equal(t, 10, len(outCoins))
+	assert.Equal(t, 4, server.numCalls["getotacoinsbyindices"]) // the cancelled request is not counted
+}
@@ -0,0 +1,145 @@
+package incclient
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/coin"
+	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"github.com/incognitochain/go-incognito-sdk-v2/common/base58"
+	"github.com/incognitochain/go-incognito-sdk-v2/wallet"
+)
+
+// DecryptedCoin is an output coin emitted by DecryptOutputCoinsStream.
+type DecryptedCoin struct {
+	// Coin is the decrypted coin, with its key image set.
+	Coin coin.PlainCoin
+
+	// Index is the OTA index of the coin in its shard.
+	Index uint64
+
+	// KeyImage is the base58-encoded key image of the coin.
+	KeyImage string
+}
+
+// DecryptOutputCoinsStream scans the output coins (v2) of a shard in windows of indices, and emits the coins of the
+// given private key and tokenID, decrypted, as soon as each window is retrieved from the remote node. Unlike
+// GetListDecryptedOutCoin, only one window of coins is held in memory at a time, which keeps the memory bounded for
+// accounts with a huge number of coins. Coins are emitted in the ascending order of their indices. Both spent and
+// unspent coins are emitted; use CheckCoinsSpent with their key images to tell them apart.
+//
+// The coin channel is closed when the scan ends. Then, the error channel yields the error which stopped the scan (if
+// any) and is closed. The caller can stop the scan early by cancelling ctx, in which case ctx.Err() is yielded; it
+// must keep receiving from the coin channel until it is closed, or cancel ctx.
+func (client *IncClient) DecryptOutputCoinsStream(ctx context.Context, privateKey, tokenID string) (<-chan DecryptedCoin, <-chan error) {
+	coinChan := make(chan DecryptedCoin)
+	errChan := make(chan error, 1)
+	go func() {
+		defer close(errChan)
+		err := client.decryptOutputCoinsStream(ctx, privateKey, tokenID, coinChan)
+		close(coinChan)
+		if err != nil {
+			errChan <- err
+		}
+	}()
+
+	return coinChan, errChan
+}
+
+func (client *IncClient) decryptOutputCoinsStream(ctx context.Context, privateKey, tokenID string, coinChan chan<- DecryptedCoin) error {
+	w, err := wallet.Base58CheckDeserialize(privateKey)
+	if err != nil {
+		return fmt.Errorf("cannot deserialize private key %v: %v", privateKey, err)
+	}
+	keySet := w.KeySet
+	if len(keySet.PrivateKey) == 0 {
+		return fmt.Errorf("%v is not a private key", privateKey)
+	}
+	if _, err = (common.Hash{}).NewHashFromStr(tokenID); err != nil {
+		return fmt.Errorf("invalid tokenID %v: %v", tokenID, err)
+	}
+
+	tokenIDStr := tokenID
+	if tokenIDStr != common.PRVIDStr {
+		tokenIDStr = common.ConfidentialAssetID.String()
+	}
+	shardID := common.GetShardIDFromLastByte(keySet.PaymentAddress.Pk[len(keySet.PaymentAddress.Pk)-1])
+
+	coinLength, err := client.WithContext(ctx).GetOTACoinLengthByShard(shardID, tokenIDStr)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+
+	var rawAssetTags map[string]*common.Hash
+	burningPubKey := wallet.GetBurningPublicKey()
+	for currentIndex := uint64(0); currentIndex < coinLength; {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+
+		nextIndex := currentIndex + uint64(batchSize)
+		if nextIndex > coinLength {
+			nextIndex = coinLength
+		}
+		idxList := make([]uint64, 0)
+		for i := currentIndex; i < nextIndex; i++ {
+			idxList = append(idxList, i)
+		}
+		outCoins, _, err := client.getOTACoinsByIndicesWithContext(ctx, shardID, tokenIDStr, idxList)
+		if err != nil {
+			return err
+		}
+
+		indices := make([]uint64, 0)
+		for idx := range outCoins {
+			indices = append(indices, idx)
+		}
+		sort.Slice(indices, func(i, j int) bool {
+			return indices[i] < indices[j]
+		})
+		for _, idx := range indices {
+			outCoin, ok := outCoins[idx].(*coin.CoinV2)
+			if !ok || bytes.Equal(outCoin.GetPublicKey().ToBytesS(), burningPubKey) {
+				continue
+			}
+			if belongs, _ := outCoin.DoesCoinBelongToKeySet(&keySet); !belongs {
+				continue
+			}
+			if tokenIDStr != tokenID {
+				if rawAssetTags == nil {
+					rawAssetTags, err = client.WithContext(ctx).GetAllAssetTags()
+					if err != nil {
+						if ctx.Err() != nil {
+							return ctx.Err()
+						}
+						return err
+					}
+				}
+				coinTokenID, _ := outCoin.GetTokenId(&keySet, rawAssetTags)
+				if coinTokenID == nil || coinTokenID.String() != tokenID {
+					continue
+				}
+			}
+
+			decryptedCoin, err := outCoin.Decrypt(&keySet)
+			if err != nil {
+				return fmt.Errorf("cannot decrypt coin %v: %v", idx, err)
+			}
+			keyImage := base58.Base58Check{}.Encode(decryptedCoin.GetKeyImage().ToBytesS(), common.ZeroByte)
+			select {
+			case coinChan <- DecryptedCoin{Coin: decryptedCoin, Index: idx, KeyImage: keyImage}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		currentIndex = nextIndex
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package incclient
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/rpc"
+	"github.com/incognitochain/go-incognito-sdk-v2/wallet"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIncClient_DecryptOutputCoinsStream(t *testing.T) {
+	oldBatchSize := batchSize
+	defer func() {
+		batchSize = oldBatchSize
+	}()
+	batchSize = 3
+
+	myWallet, err := wallet.NewMasterKeyFromSeed(common.RandBytes(32))
+	if err != nil {
+		panic(err)
+	}
+	otherWallet, err := wallet.NewMasterKeyFromSeed(common.RandBytes(32))
+	if err != nil {
+		panic(err)
+	}
+	privateKey := myWallet.Base58CheckSerialize(wallet.PrivateKeyType)
+
+	server := newMockCoinServer()
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	// indices 0-4, 8-10 and 14-16 (out of 20) belong to myWallet.
+	for _, numCoins := range []int{5, 3, 3} {
+		err = server.addCoins(myWallet.KeySet.PaymentAddress, numCoins)
+		if err != nil {
+			panic(err)
+		}
+		err = server.addCoins(otherWallet.KeySet.PaymentAddress, 3)
+		if err != nil {
+			panic(err)
+		}
+	}
+	myIndices := []uint64{0, 1, 2, 3, 4, 8, 9, 10, 14, 15, 16}
+	tokenID := common.HashH(common.RandBytes(32))
+	err = server.addTokenCoins(myWallet.KeySet.PaymentAddress, tokenID, 2)
+	if err != nil {
+		panic(err)
+	}
+	err = server.addTokenCoins(otherWallet.KeySet.PaymentAddress, common.HashH(common.RandBytes(32)), 2)
+	if err != nil {
+		panic(err)
+	}
+
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+
+	coinChan, errChan := client.DecryptOutputCoinsStream(context.Background(), privateKey, common.PRVIDStr)
+	decryptedCoins := make([]DecryptedCoin, 0)
+	for decryptedCoin := range coinChan {
+		decryptedCoins = append(decryptedCoins, decryptedCoin)
+	}
+	err = <-errChan
+	assert.Equal(t, nil, err, fmt.Errorf("DecryptOutputCoinsStream error: %v", err))
+	assert.Equal(t, len(myIndices), len(decryptedCoins))
+	for i, decryptedCoin := range decryptedCoins {
+		assert.Equal(t, myIndices[i], decryptedCoin.Index)
+		assert.Equal(t, true, decryptedCoin.Coin.GetValue() >= 1000)
+		assert.NotEqual(t, "", decryptedCoin.KeyImage)
+	}
+	// the coins are fetched in windows of batchSize indices.
+	assert.Equal(t, 7, server.numCalls["getotacoinsbyindices"])
+
+	// token coins are filtered by their tokenID.
+	coinChan, errChan = client.DecryptOutputCoinsStream(context.Background(), privateKey, tokenID.String())
+	numTokenCoins := 0
+	for decryptedCoin := range coinChan {
+		assert.Equal(t, uint64(1000+numTokenCoins), decryptedCoin.Coin.GetValue())
+		numTokenCoins++
+	}
+	err = <-errChan
+	assert.Equal(t, nil, err, fmt.Errorf("DecryptOutputCoinsStream error: %v", err))
+	assert.Equal(t, 2, numTokenCoins)
+
+	// the caller stops early.
+	numCalls := server.numCalls["getotacoinsbyindices"]
+	ctx, cancel := context.WithCancel(context.Background())
+	coinChan, errChan = client.DecryptOutputCoinsStream(ctx, privateKey, common.PRVIDStr)
+	firstCoin := <-coinChan
+	assert.Equal(t, uint64(0), firstCoin.Index)
+	cancel()
+	for range coinChan {
+	}
+	assert.Equal(t, context.Canceled, <-errChan)
+	assert.Equal(t, true, server.numCalls["getotacoinsbyindices"]-numCalls < 7)
+
+	_, errChan = client.DecryptOutputCoinsStream(context.Background(), myWallet.Base58CheckSerialize(wallet.PaymentAddressType), common.PRVIDStr)
+	assert.NotEqual(t, nil, <-errChan)
+}
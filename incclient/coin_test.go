@@ -1,10 +1,22 @@
 package incclient
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/incognitochain/go-incognito-sdk-v2/coin"
 	"github.com/incognitochain/go-incognito-sdk-v2/common"
 	"github.com/incognitochain/go-incognito-sdk-v2/common/base58"
+	"github.com/incognitochain/go-incognito-sdk-v2/key"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/jsonresult"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/rpc"
+	"github.com/incognitochain/go-incognito-sdk-v2/wallet"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 )
@@ -83,6 +95,54 @@ func TestIncClient_GetUnspentOutputCoins(t *testing.T) {
 	}
 }
 
+func TestIncClient_GetUnspentCoins(t *testing.T) {
+	w, err := wallet.NewMasterKeyFromSeed(common.RandBytes(32))
+	if err != nil {
+		panic(err)
+	}
+	privateKey := w.Base58CheckSerialize(wallet.PrivateKeyType)
+
+	server := newMockCoinServer()
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	err = server.addCoins(w.KeySet.PaymentAddress, 6)
+	if err != nil {
+		panic(err)
+	}
+
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+	client.SetCoinStore(NewMemCoinStore())
+
+	// no coins have been spent.
+	utxoList, err := client.GetUnspentCoins(privateKey, common.PRVIDStr)
+	assert.Equal(t, nil, err, fmt.Errorf("GetUnspentCoins error: %v", err))
+	assert.Equal(t, 6, len(utxoList))
+
+	// spend half of the coins.
+	spentKeyImages := make(map[string]bool)
+	for i := 0; i < len(utxoList); i += 2 {
+		keyImage := base58.Base58Check{}.Encode(utxoList[i].GetKeyImage().ToBytesS(), common.ZeroByte)
+		spentKeyImages[keyImage] = true
+		server.markSpent(keyImage)
+	}
+
+	utxoList, err = client.GetUnspentCoins(privateKey, common.PRVIDStr)
+	assert.Equal(t, nil, err, fmt.Errorf("GetUnspentCoins error: %v", err))
+	assert.Equal(t, 3, len(utxoList))
+	for _, utxo := range utxoList {
+		keyImage := base58.Base58Check{}.Encode(utxo.GetKeyImage().ToBytesS(), common.ZeroByte)
+		assert.Equal(t, false, spentKeyImages[keyImage], fmt.Errorf("coin %v has been spent", keyImage))
+	}
+
+	// spend all coins.
+	for _, utxo := range utxoList {
+		server.markSpent(base58.Base58Check{}.Encode(utxo.GetKeyImage().ToBytesS(), common.ZeroByte))
+	}
+	utxoList, err = client.GetUnspentCoins(privateKey, common.PRVIDStr)
+	assert.Equal(t, nil, err, fmt.Errorf("GetUnspentCoins error: %v", err))
+	assert.Equal(t, 0, len(utxoList))
+}
+
 func TestIncClient_GetSpentOutputCoins(t *testing.T) {
 	var err error
 	ic, err = NewTestNet1Client()
@@ -138,6 +198,75 @@ func TestIncClient_GetOTACoinsByIndices(t *testing.T) {
 	}
 }
 
+func TestIncClient_ScanOTACoinsByIndices(t *testing.T) {
+	oldBatchSize := batchSize
+	defer func() {
+		batchSize = oldBatchSize
+	}()
+
+	w, err := wallet.NewMasterKeyFromSeed(common.RandBytes(32))
+	if err != nil {
+		panic(err)
+	}
+	numCoins := 10
+	server := newMockCoinServer()
+	err = server.addCoins(w.KeySet.PaymentAddress, numCoins)
+	if err != nil {
+		panic(err)
+	}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+
+	checkCoins := func(res map[uint64]jsonresult.ICoinInfo) {
+		assert.Equal(t, numCoins, len(res))
+		for idx, outCoin := range res {
+			assert.Equal(t, server.coins[idx].PublicKey, jsonresult.NewOutCoin(outCoin).PublicKey)
+		}
+	}
+
+	// the coin set is split into three windows.
+	batchSize = 4
+	progress := make([]uint64, 0)
+	res, err := client.ScanOTACoinsByIndices(0, common.PRVIDStr, 0, uint64(numCoins-1), func(scanned, total uint64) {
+		assert.Equal(t, uint64(numCoins), total)
+		progress = append(progress, scanned)
+	})
+	assert.Equal(t, nil, err, fmt.Errorf("ScanOTACoinsByIndices error: %v", err))
+	checkCoins(res)
+	assert.Equal(t, []uint64{4, 8, 10}, progress)
+	assert.Equal(t, 3, server.numCalls["getotacoinsbyindices"])
+
+	// the node truncates its responses to 4 coins: the limit is detected and no coin is missed.
+	batchSize = 5000
+	server.maxPerResponse = 4
+	server.numCalls = make(map[string]int)
+	res, err = client.ScanOTACoinsByIndices(0, common.PRVIDStr, 0, uint64(numCoins-1), nil)
+	assert.Equal(t, nil, err, fmt.Errorf("ScanOTACoinsByIndices error: %v", err))
+	checkCoins(res)
+	assert.Equal(t, 3, server.numCalls["getotacoinsbyindices"])
+
+	// the requested range goes beyond the coins of the node: the empty window is skipped.
+	server.maxPerResponse = 0
+	server.numCalls = make(map[string]int)
+	res, err = client.ScanOTACoinsByIndices(0, common.PRVIDStr, 0, uint64(numCoins+7), nil)
+	assert.Equal(t, nil, err, fmt.Errorf("ScanOTACoinsByIndices error: %v", err))
+	checkCoins(res)
+	assert.Equal(t, 2, server.numCalls["getotacoinsbyindices"])
+	assert.Equal(t, 1, server.numCalls["getotacoinlength"])
+
+	// the node returns no coin for indices within its coin length: the scan fails instead of dropping them.
+	server.emptyResponses = true
+	_, err = client.ScanOTACoinsByIndices(0, common.PRVIDStr, 0, uint64(numCoins-1), nil)
+	assert.NotEqual(t, nil, err)
+	_, err = client.ScanOTACoinsByIndices(0, common.PRVIDStr, uint64(numCoins), uint64(numCoins+7), nil)
+	assert.Equal(t, nil, err, fmt.Errorf("ScanOTACoinsByIndices error: %v", err))
+	server.emptyResponses = false
+
+	_, err = client.ScanOTACoinsByIndices(0, common.PRVIDStr, 5, 4, nil)
+	assert.NotEqual(t, nil, err)
+}
+
 func TestIncClient_GetOTACoinLength(t *testing.T) {
 	var err error
 	ic, err = NewMainNetClient()
@@ -231,6 +360,148 @@ func TestIncClient_GetAllUTXOsV2(t *testing.T) {
 	}
 }
 
+// cancellingCoinServer wraps a mockCoinServer. Once `numWindows` getotacoinsbyindices requests have been served, it
+// calls `cancel` on the next one and leaves it pending until `release` is closed.
+type cancellingCoinServer struct {
+	*mockCoinServer
+	numWindows int
+	cancel     context.CancelFunc
+	release    chan struct{}
+
+	mtx    sync.Mutex
+	served int
+}
+
+func (s *cancellingCoinServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var req struct {
+		Method string
+	}
+	_ = json.Unmarshal(body, &req)
+	if req.Method == "getotacoinsbyindices" {
+		s.mtx.Lock()
+		s.served++
+		block := s.served > s.numWindows
+		s.mtx.Unlock()
+		if block {
+			s.cancel()
+			<-s.release
+			http.Error(w, "request aborted", http.StatusServiceUnavailable)
+			return
+		}
+	}
+
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	s.mockCoinServer.ServeHTTP(w, r)
+}
+
+func TestIncClient_ScanOTACoinsByIndicesWithContext(t *testing.T) {
+	oldBatchSize := batchSize
+	defer func() {
+		batchSize = oldBatchSize
+	}()
+
+	w, err := wallet.NewMasterKeyFromSeed(common.RandBytes(32))
+	if err != nil {
+		panic(err)
+	}
+	numCoins := 10
+	server := newMockCoinServer()
+	err = server.addCoins(w.KeySet.PaymentAddress, numCoins)
+	if err != nil {
+		panic(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancellingServer := &cancellingCoinServer{mockCoinServer: server, numWindows: 1, cancel: cancel, release: make(chan struct{})}
+	ts := httptest.NewServer(cancellingServer)
+	defer ts.Close()
+	defer close(cancellingServer.release)
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+
+	// the scan is cancelled while the second window is pending.
+	batchSize = 4
+	res, cursor, err := client.ScanOTACoinsByIndicesWithContext(ctx, 0, common.PRVIDStr, 0, uint64(numCoins-1), nil)
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, uint64(4), cursor)
+	assert.Equal(t, 4, len(res))
+	for idx := range res {
+		assert.Equal(t, true, idx < cursor, fmt.Errorf("unexpected index %v", idx))
+	}
+
+	// a cancelled context stops the scan before any request.
+	_, cursor, err = client.ScanOTACoinsByIndicesWithContext(ctx, 0, common.PRVIDStr, cursor, uint64(numCoins-1), nil)
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, uint64(4), cursor)
+
+	// resume from the cursor.
+	cancellingServer.numWindows = numCoins
+	remaining, cursor, err := client.ScanOTACoinsByIndicesWithContext(context.Background(), 0, common.PRVIDStr, cursor, uint64(numCoins-1), nil)
+	assert.Equal(t, nil, err, fmt.Errorf("ScanOTACoinsByIndicesWithContext error: %v", err))
+	assert.Equal(t, uint64(numCoins), cursor)
+	for idx, outCoin := range remaining {
+		res[idx] = outCoin
+	}
+	assert.Equal(t, numCoins, len(res))
+	for idx, outCoin := range res {
+		assert.Equal(t, server.coins[idx].PublicKey, jsonresult.NewOutCoin(outCoin).PublicKey)
+	}
+}
+
+func TestIncClient_ScanAllTokens(t *testing.T) {
+	myWallet, err := wallet.NewMasterKeyFromSeed(common.RandBytes(32))
+	if err != nil {
+		panic(err)
+	}
+	otherWallet, err := wallet.NewMasterKeyFromSeed(common.RandBytes(32))
+	if err != nil {
+		panic(err)
+	}
+	privateKey := myWallet.Base58CheckSerialize(wallet.PrivateKeyType)
+
+	server := newMockCoinServer()
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	expectedNumCoins := map[string]int{common.PRVIDStr: 2}
+	err = server.addCoins(myWallet.KeySet.PaymentAddress, 2)
+	if err != nil {
+		panic(err)
+	}
+	for i := 0; i < 3; i++ {
+		tokenID := common.HashH(common.RandBytes(32))
+		err = server.addTokenCoins(myWallet.KeySet.PaymentAddress, tokenID, i+1)
+		if err != nil {
+			panic(err)
+		}
+		err = server.addTokenCoins(otherWallet.KeySet.PaymentAddress, tokenID, 2)
+		if err != nil {
+			panic(err)
+		}
+		expectedNumCoins[tokenID.String()] = i + 1
+	}
+
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+	client.SetCoinStore(NewMemCoinStore())
+
+	allUTXOs, err := client.ScanAllTokens(privateKey, 0)
+	assert.Equal(t, nil, err, fmt.Errorf("ScanAllTokens error: %v", err))
+	assert.Equal(t, len(expectedNumCoins), len(allUTXOs))
+	for tokenID, numCoins := range expectedNumCoins {
+		assert.Equal(t, numCoins, len(allUTXOs[tokenID]), fmt.Errorf("unexpected #UTXOs of token %v", tokenID))
+	}
+	// all tokens are retrieved in one pass.
+	assert.Equal(t, 2, server.numCalls["getotacoinsbyindices"])
+
+	balances, err := client.GetAllBalancesV2(privateKey)
+	assert.Equal(t, nil, err, fmt.Errorf("GetAllBalancesV2 error: %v", err))
+	assert.Equal(t, len(expectedNumCoins), len(balances))
+	assert.Equal(t, uint64(2001), balances[common.PRVIDStr])
+}
+
 func TestIncClient_getAllTokens(t *testing.T) {
 	var err error
 	ic, err = NewMainNetClientWithCache()
@@ -248,3 +519,111 @@ func TestIncClient_getAllTokens(t *testing.T) {
 	jsb, _ := json.Marshal(tokenIDs)
 	Logger.Println(string(jsb))
 }
+
+func TestIncClient_GetCoinShard(t *testing.T) {
+	client := newIncClient(nil, nil, nil, 2)
+	for i := 0; i < numTests; i++ {
+		shardID := byte(common.RandInt() % common.MaxShardNumber)
+		w, err := wallet.GenRandomWalletForShardID(shardID)
+		if err != nil {
+			panic(err)
+		}
+
+		paymentInfo := key.InitPaymentInfo(w.KeySet.PaymentAddress, common.RandUint64()%1000000, []byte{})
+		c, err := coin.NewCoinFromPaymentInfo(coin.NewTransferCoinParams(paymentInfo))
+		if err != nil {
+			panic(err)
+		}
+		assert.Equal(t, shardID, client.GetCoinShard(c))
+	}
+	assert.Equal(t, byte(255), client.GetCoinShard(nil))
+}
+
+func TestIncClient_FindFirstActivityHeight(t *testing.T) {
+	const shardID = byte(3)
+	const bestHeight = uint64(1000)
+	w, err := wallet.GenRandomWalletForShardID(shardID)
+	if err != nil {
+		panic(err)
+	}
+	otaKey := w.Base58CheckSerialize(wallet.OTAKeyType)
+
+	// the heights of the coins of the key, by the tokenID they are listed under.
+	var activityHeights map[string][]uint64
+	numQueries := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string
+			Params []json.RawMessage
+		}
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var result interface{}
+		switch req.Method {
+		case "getshardbeststate":
+			result = jsonresult.ShardBestState{ShardID: shardID, ShardHeight: bestHeight}
+		case "listoutputcoins":
+			// as a full-node does for an OTA key, return the coins from the StartHeight up to the best height,
+			// regardless of the height range of the query.
+			numQueries++
+			var keyParams []struct {
+				StartHeight uint64
+			}
+			var tokenID string
+			if len(req.Params) < 4 || json.Unmarshal(req.Params[2], &keyParams) != nil || len(keyParams) != 1 ||
+				json.Unmarshal(req.Params[3], &tokenID) != nil {
+				http.Error(w, "invalid params", http.StatusBadRequest)
+				return
+			}
+			outCoins := make([]jsonresult.OutCoin, 0)
+			for _, height := range activityHeights[tokenID] {
+				if height >= keyParams[0].StartHeight && height <= bestHeight {
+					outCoins = append(outCoins, jsonresult.OutCoin{Version: "2"})
+				}
+			}
+			result = jsonresult.ListOutputCoins{
+				FromHeight: keyParams[0].StartHeight,
+				ToHeight:   bestHeight,
+				Outputs:    map[string][]jsonresult.OutCoin{otaKey: outCoins},
+			}
+		default:
+			http.Error(w, fmt.Sprintf("method %v not supported", req.Method), http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"Result": result})
+	}))
+	defer ts.Close()
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+
+	tokenIDStr := common.ConfidentialAssetID.String()
+	for _, firstHeight := range []uint64{1, 437, 999, bestHeight} {
+		for _, firstInToken := range []bool{false, true} {
+			activityHeights = map[string][]uint64{
+				common.PRVIDStr: {firstHeight, firstHeight + 50, firstHeight + 300},
+			}
+			if firstInToken {
+				// the first coin is received in a token, the first PRV one comes later.
+				activityHeights = map[string][]uint64{
+					tokenIDStr:      {firstHeight, firstHeight + 20},
+					common.PRVIDStr: {firstHeight + 10},
+				}
+			}
+			numQueries = 0
+			height, err := client.FindFirstActivityHeight(otaKey)
+			assert.Equal(t, nil, err, fmt.Errorf("FindFirstActivityHeight error: %v", err))
+			assert.Equal(t, firstHeight, height, fmt.Sprintf("firstInToken: %v", firstInToken))
+			assert.LessOrEqual(t, numQueries, 2*11, "expected a logarithmic number of queries")
+		}
+	}
+
+	activityHeights = nil
+	_, err = client.FindFirstActivityHeight(otaKey)
+	assert.NotEqual(t, nil, err)
+
+	_, err = client.FindFirstActivityHeight("invalid")
+	assert.NotEqual(t, nil, err)
+}
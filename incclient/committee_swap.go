@@ -0,0 +1,251 @@
+package incclient
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/key"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/jsonresult"
+)
+
+// Actions of the beacon instructions swapping committee members.
+const (
+	// SwapAction is the action of a swap instruction of the staking flow v1, which swaps the committee of a shard or
+	// of the beacon chain.
+	SwapAction = "swap"
+
+	// SwapShardAction is the action of a swap instruction of the staking flow v2 and later, which swaps the committee
+	// of a shard.
+	SwapShardAction = "swapshard"
+)
+
+const (
+	swapInstBeacon        = "beacon"
+	swapInstShard         = "shard"
+	swapInstKeySeparator  = ","
+	beaconChainID         = -1
+	maxSwapInstQueryGroup = 20
+)
+
+// SwapInstruction is a beacon instruction swapping committee members in and out of a chain.
+type SwapInstruction struct {
+	// BeaconHeight is the height of the beacon block containing the instruction.
+	BeaconHeight uint64
+
+	// Action is either SwapAction or SwapShardAction.
+	Action string
+
+	// ChainID is the shardID of the swapped committee, or -1 for the beacon committee.
+	ChainID int
+
+	// InPublicKeys are the keys entering the committee.
+	InPublicKeys []key.CommitteePublicKey
+
+	// OutPublicKeys are the keys leaving the committee.
+	OutPublicKeys []key.CommitteePublicKey
+
+	// PunishedPublicKeys are the keys swapped out as a punishment (SwapAction only).
+	PunishedPublicKeys []key.CommitteePublicKey
+}
+
+// ParseSwapInstruction parses a beacon instruction into a SwapInstruction. It returns an error if the instruction is
+// not a swap instruction, or if it is malformed. The supported formats are
+//   - [SwapAction, inKeys, outKeys, "beacon", punishedKeys, rewardReceivers]
+//   - [SwapAction, inKeys, outKeys, "shard", shardID, punishedKeys, rewardReceivers]
+//   - [SwapShardAction, inKeys, outKeys, shardID, type]
+//
+// where a list of keys is a comma-separated list of base58-encoded CommitteePublicKeys. Trailing fields after the
+// chainID are optional for SwapAction.
+func ParseSwapInstruction(inst []string) (*SwapInstruction, error) {
+	if len(inst) < 4 {
+		return nil, fmt.Errorf("invalid swap instruction %v: expected at least 4 fields, got %v", inst, len(inst))
+	}
+
+	res := &SwapInstruction{Action: inst[0]}
+	var err error
+	var remaining []string
+	switch inst[0] {
+	case SwapAction:
+		switch inst[3] {
+		case swapInstBeacon:
+			res.ChainID = beaconChainID
+			remaining = inst[4:]
+		case swapInstShard:
+			if len(inst) < 5 {
+				return nil, fmt.Errorf("invalid swap instruction %v: missing shardID", inst)
+			}
+			res.ChainID, err = strconv.Atoi(inst[4])
+			if err != nil {
+				return nil, fmt.Errorf("invalid shardID %v: %v", inst[4], err)
+			}
+			remaining = inst[5:]
+		default:
+			return nil, fmt.Errorf("invalid swap instruction %v: unknown chain %v", inst, inst[3])
+		}
+		if len(remaining) > 0 {
+			res.PunishedPublicKeys, err = parseSwapInstKeys(remaining[0])
+			if err != nil {
+				return nil, err
+			}
+		}
+	case SwapShardAction:
+		res.ChainID, err = strconv.Atoi(inst[3])
+		if err != nil {
+			return nil, fmt.Errorf("invalid shardID %v: %v", inst[3], err)
+		}
+	default:
+		return nil, fmt.Errorf("%v is not a swap instruction", inst[0])
+	}
+	if res.ChainID < beaconChainID {
+		return nil, fmt.Errorf("invalid chainID %v", res.ChainID)
+	}
+
+	res.InPublicKeys, err = parseSwapInstKeys(inst[1])
+	if err != nil {
+		return nil, err
+	}
+	res.OutPublicKeys, err = parseSwapInstKeys(inst[2])
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// parseSwapInstKeys parses a comma-separated list of base58-encoded CommitteePublicKeys.
+func parseSwapInstKeys(keyList string) ([]key.CommitteePublicKey, error) {
+	res := make([]key.CommitteePublicKey, 0)
+	if keyList == "" {
+		return res, nil
+	}
+	for _, keyStr := range strings.Split(keyList, swapInstKeySeparator) {
+		var pubKey key.CommitteePublicKey
+		if err := pubKey.FromBase58(keyStr); err != nil {
+			return nil, fmt.Errorf("invalid committee key %v: %v", keyStr, err)
+		}
+		res = append(res, pubKey)
+	}
+
+	return res, nil
+}
+
+// GetBeaconBlocksByHeight returns the beacon blocks at the given height.
+func (client *IncClient) GetBeaconBlocksByHeight(beaconHeight uint64) ([]jsonresult.BeaconBlockResult, error) {
+	responseInBytes, err := client.rpcServer.RetrieveBeaconBlockByHeight(beaconHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []jsonresult.BeaconBlockResult
+	err = rpchandler.ParseResponse(responseInBytes, &res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetCommitteeSwapInstructions returns the swap instructions (see ParseSwapInstruction) included in the beacon
+// blocks of the given epoch, sorted by beacon height. For the current epoch, only the blocks produced so far are
+// considered.
+//
+// The beacon heights of an epoch are derived from the current beacon epoch and the number of blocks per epoch, which
+// is assumed to be constant. One RPC is made for each beacon block of the epoch.
+func (client *IncClient) GetCommitteeSwapInstructions(epoch uint64) ([]SwapInstruction, error) {
+	fromHeight, toHeight, err := client.getEpochBeaconHeights(epoch)
+	if err != nil {
+		return nil, err
+	}
+
+	numBlocks := toHeight - fromHeight + 1
+	blocks := make([][]jsonresult.BeaconBlockResult, numBlocks)
+	errs := make([]error, numBlocks)
+	jobs := make(chan uint64)
+	var wg sync.WaitGroup
+	numWorkers := maxSwapInstQueryGroup
+	if numBlocks < uint64(numWorkers) {
+		numWorkers = int(numBlocks)
+	}
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for offset := range jobs {
+				blocks[offset], errs[offset] = client.GetBeaconBlocksByHeight(fromHeight + offset)
+			}
+		}()
+	}
+	for offset := uint64(0); offset < numBlocks; offset++ {
+		jobs <- offset
+	}
+	close(jobs)
+	wg.Wait()
+
+	res := make([]SwapInstruction, 0)
+	for offset, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("cannot retrieve the beacon block at height %v: %v", fromHeight+uint64(offset), err)
+		}
+		for _, block := range blocks[offset] {
+			for _, inst := range block.Instructions {
+				if len(inst) == 0 || (inst[0] != SwapAction && inst[0] != SwapShardAction) {
+					continue
+				}
+				swapInst, err := ParseSwapInstruction(inst)
+				if err != nil {
+					return nil, fmt.Errorf("beacon block %v: %v", block.Height, err)
+				}
+				swapInst.BeaconHeight = block.Height
+				res = append(res, *swapInst)
+			}
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].BeaconHeight < res[j].BeaconHeight
+	})
+
+	return res, nil
+}
+
+// getEpochBeaconHeights returns the range of beacon heights of the given epoch, capped at the current beacon height.
+func (client *IncClient) getEpochBeaconHeights(epoch uint64) (uint64, uint64, error) {
+	if epoch == 0 {
+		return 0, 0, fmt.Errorf("invalid epoch 0")
+	}
+
+	responseInBytes, err := client.rpcServer.GetBestBlock()
+	if err != nil {
+		return 0, 0, err
+	}
+	var bestBlocks jsonresult.BestBlockResult
+	err = rpchandler.ParseResponse(responseInBytes, &bestBlocks)
+	if err != nil {
+		return 0, 0, err
+	}
+	beaconBlock, ok := bestBlocks.BestBlocks[beaconChainID]
+	if !ok {
+		return 0, 0, fmt.Errorf("beacon best block not found")
+	}
+	if beaconBlock.EpochBlock == 0 {
+		return 0, 0, fmt.Errorf("invalid number of blocks per epoch")
+	}
+	if epoch > beaconBlock.Epoch {
+		return 0, 0, fmt.Errorf("epoch %v has not started, current epoch: %v", epoch, beaconBlock.Epoch)
+	}
+
+	endOfCurrentEpoch := beaconBlock.Height + beaconBlock.RemainingBlockEpoch
+	toHeight := endOfCurrentEpoch - (beaconBlock.Epoch-epoch)*beaconBlock.EpochBlock
+	if toHeight < beaconBlock.EpochBlock {
+		return 0, 0, fmt.Errorf("cannot determine the beacon heights of epoch %v", epoch)
+	}
+	fromHeight := toHeight - beaconBlock.EpochBlock + 1
+	if toHeight > beaconBlock.Height {
+		toHeight = beaconBlock.Height
+	}
+
+	return fromHeight, toHeight, nil
+}
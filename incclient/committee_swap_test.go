@@ -0,0 +1,154 @@
+package incclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"github.com/incognitochain/go-incognito-sdk-v2/key"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/jsonresult"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+// sampleCommitteeKey is the sample committee key of the key documentation.
+const sampleCommitteeKey = "121VhftSAygpEJZ6i9jGkKtR4pAHfzZ88HCwNKX6SpoNwGZSNRWcU424si7zKNQQbbEHx9T17Vrk321NeMif3XNaDimZhVgwp8mgv1aRbzhqqSVaX2sRtsymeLGqM3bNMcsNeSHWsf5ZQKU5RtvnHSCPwQV5vWkqJjpQq5dTsgEmmzvizXPUNPuysArp2kNTKJmF2nDRsta3kBFfu5YUwWrwyq23x9LnSpmeByLAfJaa4Bu1W47gNzPhQ3A29JPWKb7ikY3BnYt8pZ4Tao8HzyxfzKha6JPTzW3zZRm4ygwyRQghY31cH15o8p4JTGRL8X6uDbJCeMuTz4vtuJud3NtsGxy7yq8aSfPHRzbdt54nv3GpNVS34SBw4AoFMRVTNBW5HqUAUM9K56CKELRMn5YdkPhscA5RbPTAG9xJCAQjs43n"
+
+func newTestCommitteeKey(i int) string {
+	seed := common.HashB([]byte(fmt.Sprintf("committee-%v", i)))
+	pubKey, err := key.NewCommitteeKeyFromSeed(seed, seed)
+	if err != nil {
+		panic(err)
+	}
+	keyStr, err := pubKey.ToBase58()
+	if err != nil {
+		panic(err)
+	}
+	return keyStr
+}
+
+func mustToBase58(pubKey key.CommitteePublicKey) string {
+	keyStr, err := pubKey.ToBase58()
+	if err != nil {
+		panic(err)
+	}
+	return keyStr
+}
+
+func TestParseSwapInstruction(t *testing.T) {
+	keys := []string{sampleCommitteeKey, newTestCommitteeKey(0), newTestCommitteeKey(1)}
+
+	testCases := []struct {
+		inst               []string
+		chainID            int
+		numIn, numPunished int
+	}{
+		{[]string{SwapShardAction, keys[0], strings.Join(keys[1:], ","), "3", "0"}, 3, 1, 0},
+		{[]string{SwapAction, strings.Join(keys[:2], ","), keys[2], "shard", "1", keys[2], ""}, 1, 2, 1},
+		{[]string{SwapAction, "", keys[2], "beacon", "", ""}, -1, 0, 0},
+		{[]string{SwapAction, keys[0], keys[2], "shard", "7"}, 7, 1, 0},
+	}
+	for _, tc := range testCases {
+		swapInst, err := ParseSwapInstruction(tc.inst)
+		assert.Equal(t, nil, err, fmt.Errorf("ParseSwapInstruction(%v) error: %v", tc.inst, err))
+		assert.Equal(t, tc.inst[0], swapInst.Action)
+		assert.Equal(t, tc.chainID, swapInst.ChainID)
+		assert.Equal(t, tc.numIn, len(swapInst.InPublicKeys))
+		assert.Equal(t, tc.numPunished, len(swapInst.PunishedPublicKeys))
+		for _, pubKey := range swapInst.OutPublicKeys {
+			keyStr, err := pubKey.ToBase58()
+			assert.Equal(t, nil, err)
+			assert.Equal(t, true, strings.Contains(tc.inst[2], keyStr))
+		}
+	}
+	// keys are re-encoded to their original form
+	swapInst, err := ParseSwapInstruction(testCases[0].inst)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, sampleCommitteeKey, mustToBase58(swapInst.InPublicKeys[0]))
+
+	invalidInsts := [][]string{
+		{SwapAction, keys[0], keys[1]},
+		{"stake", keys[0], keys[1], "shard", "0"},
+		{SwapAction, keys[0], keys[1], "relay", "0"},
+		{SwapAction, keys[0], keys[1], "shard"},
+		{SwapAction, keys[0], keys[1], "shard", "x"},
+		{SwapShardAction, keys[0] + "x", keys[1], "0", "0"},
+		{SwapShardAction, keys[0], keys[1], "-2", "0"},
+	}
+	for _, inst := range invalidInsts {
+		_, err = ParseSwapInstruction(inst)
+		assert.NotEqual(t, nil, err, fmt.Errorf("expected an error for %v", inst))
+	}
+}
+
+func TestIncClient_GetCommitteeSwapInstructions(t *testing.T) {
+	keys := []string{sampleCommitteeKey, newTestCommitteeKey(0), newTestCommitteeKey(1)}
+
+	// epochs have 5 blocks; the best beacon block is the 2nd block of epoch 4.
+	swapInsts := map[uint64][][]string{
+		11: {
+			{"37", "0", "1"},
+			{SwapShardAction, keys[0], keys[1], "2", "0"},
+		},
+		15: {{SwapAction, keys[1], keys[2], "shard", "0", "", ""}},
+		16: {{SwapShardAction, keys[2], keys[0], "1", "0"}},
+	}
+	var numCalls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string
+			Params []uint64
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		var result interface{}
+		switch req.Method {
+		case "getbestblock":
+			result = jsonresult.BestBlockResult{BestBlocks: map[int]jsonresult.BestBlockItem{
+				-1: {Height: 17, Epoch: 4, EpochBlock: 5, RemainingBlockEpoch: 3},
+				0:  {Height: 100},
+			}}
+		case "retrievebeaconblockbyheight":
+			atomic.AddInt32(&numCalls, 1)
+			height := req.Params[0]
+			result = []jsonresult.BeaconBlockResult{{Height: height, Instructions: swapInsts[height]}}
+		default:
+			http.Error(w, fmt.Sprintf("method %v not supported", req.Method), http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"Result": result})
+	}))
+	defer ts.Close()
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+
+	// epoch 3 spans the beacon heights [11, 15].
+	res, err := client.GetCommitteeSwapInstructions(3)
+	assert.Equal(t, nil, err, fmt.Errorf("GetCommitteeSwapInstructions error: %v", err))
+	assert.Equal(t, int32(5), atomic.LoadInt32(&numCalls))
+	assert.Equal(t, 2, len(res))
+	assert.Equal(t, uint64(11), res[0].BeaconHeight)
+	assert.Equal(t, 2, res[0].ChainID)
+	assert.Equal(t, keys[0], mustToBase58(res[0].InPublicKeys[0]))
+	assert.Equal(t, keys[1], mustToBase58(res[0].OutPublicKeys[0]))
+	assert.Equal(t, uint64(15), res[1].BeaconHeight)
+	assert.Equal(t, SwapAction, res[1].Action)
+	assert.Equal(t, 0, res[1].ChainID)
+
+	// the current epoch is only scanned up to the best block.
+	atomic.StoreInt32(&numCalls, 0)
+	res, err = client.GetCommitteeSwapInstructions(4)
+	assert.Equal(t, nil, err, fmt.Errorf("GetCommitteeSwapInstructions error: %v", err))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&numCalls))
+	assert.Equal(t, 1, len(res))
+	assert.Equal(t, keys[2], mustToBase58(res[0].InPublicKeys[0]))
+
+	for _, epoch := range []uint64{0, 5} {
+		_, err = client.GetCommitteeSwapInstructions(epoch)
+		assert.NotEqual(t, nil, err)
+	}
+}
@@ -3,8 +3,11 @@ package incclient
 
 import (
 	"fmt"
+	"github.com/incognitochain/go-incognito-sdk-v2/coin"
 	"github.com/incognitochain/go-incognito-sdk-v2/common"
 	"github.com/incognitochain/go-incognito-sdk-v2/common/base58"
+	"github.com/incognitochain/go-incognito-sdk-v2/crypto"
+	"github.com/incognitochain/go-incognito-sdk-v2/internal/safemath"
 	"github.com/incognitochain/go-incognito-sdk-v2/key"
 	"github.com/incognitochain/go-incognito-sdk-v2/metadata"
 	"github.com/incognitochain/go-incognito-sdk-v2/wallet"
@@ -21,6 +24,46 @@ type TxParam struct {
 	txTokenParam     *TxTokenParam
 	md               metadata.Metadata
 
+	// InputCoins is an optional list of PRV coins to spend. When set, the automatic coin selection is bypassed and
+	// exactly these coins are used as the PRV inputs of the transaction. They must be unspent output coins of the sender
+	// and cover the PRV amount plus the fee. Once resolved, they are passed on as the "PRVInputCoins" of kArgs.
+	InputCoins []coin.PlainCoin
+
+	// ExcludeCoins is an optional list of base58-encoded OTA public keys of coins (or commitments, for v1 coins) that
+	// must never be chosen by the automatic coin selection, for both PRV and token inputs. It has no effect on
+	// pinned InputCoins.
+	ExcludeCoins []string
+
+	// AvoidPendingCoins indicates whether the automatic coin selection should skip coins already being spent by
+	// transactions in the mempool (see IncClient.GetPendingTransactions). This avoids double-spending rejections when
+	// sending several transactions in a row, at the cost of querying the mempool. It has no effect on pinned InputCoins.
+	AvoidPendingCoins bool
+
+	// CoinSelector is an optional strategy (e.g, LargestFirst, MinimizeChange) used by the automatic coin selection,
+	// for both PRV and token inputs. By default (nil), the built-in selection is used. It has no effect on pinned
+	// InputCoins.
+	CoinSelector CoinSelector
+
+	// OutputCoinVersion is an optional version (1 or 2) of the output coins of the transaction. When set, it pins the
+	// version of the created transaction, since a transaction v1 (resp. v2) only creates output coins v1 (resp. v2).
+	// Output coins v1 are only permitted on a network with privacy version 1. By default (0), the version of the
+	// transaction decides, and output coins are created as v2 for a transaction v2.
+	OutputCoinVersion int
+
+	// KeepDuplicateReceivers indicates whether payments to the same receiver should be kept as distinct outputs. By
+	// default (false), payments of the same token to identical payment addresses are coalesced into one output whose
+	// amount is the sum of theirs, which makes the transaction smaller and cheaper. Set it to keep one output (hence one
+	// OTA) per payment, e.g. to reconcile each payment separately on the receiver's side. As for privacy, output coins v2
+	// are unlinkable one-time addresses, so distinct outputs do not reveal that they go to the same receiver; output
+	// coins v1 do, since they all carry the receiver's public key.
+	KeepDuplicateReceivers bool
+
+	// Bumpable indicates whether the fee of the transaction can later be raised with IncClient.BumpFee. By default
+	// (false), nothing is kept once the transaction is created. Set it to have the client remember the recipients,
+	// amounts, metadata and (decrypted) input coins of a PRV transaction version 2 in memory, until MaxTxIntents newer
+	// bumpable transactions have been created.
+	Bumpable bool
+
 	// additional parameters for special functions
 	//	- "PRVInputCoins": a coinParams consisting of PRV input coins and indices used to create a transaction with given
 	//input coins.
@@ -38,6 +81,12 @@ type TxTokenParam struct {
 	hasTokenFee  bool
 	tokenFee     uint64
 	kArgs        map[string]interface{}
+
+	// InputCoins is an optional list of token coins to spend. When set, the automatic coin selection is bypassed and
+	// exactly these coins are used as the token inputs of the transaction. They must be unspent output coins of the sender
+	// and cover the token amount (plus the token fee, if any). Once resolved, they are passed on as the "TokenInputCoins"
+	// of the kArgs of the TxParam.
+	InputCoins []coin.PlainCoin
 }
 
 // CustomToken represents information of a token.
@@ -52,7 +101,21 @@ func (ct CustomToken) ToString() string {
 	return fmt.Sprintf("tokenID: %v, tokenName: %v, amount: %v", ct.tokenID, ct.tokenName, ct.tokenID)
 }
 
+// pinnedInputCoins returns the input coins pinned by the caller for the given tokenID (if any).
+func (param *TxParam) pinnedInputCoins(tokenIDStr string) []coin.PlainCoin {
+	if tokenIDStr == common.PRVIDStr {
+		return param.InputCoins
+	}
+	if param.txTokenParam != nil {
+		return param.txTokenParam.InputCoins
+	}
+	return nil
+}
+
 // NewTxParam creates a new TxParam.
+//
+// Payments of the same token to identical receivers are coalesced into one output when the transaction is created,
+// unless KeepDuplicateReceivers is set on the returned TxParam.
 func NewTxParam(privateKey string, receiverList []string, amountList []uint64, prvFee uint64,
 	tokenParam *TxTokenParam, md metadata.Metadata, kArgs map[string]interface{}) *TxParam {
 	return &TxParam{
@@ -66,6 +129,56 @@ func NewTxParam(privateKey string, receiverList []string, amountList []uint64, p
 	}
 }
 
+// prvReceivers returns the PRV receivers and amounts of the transaction, coalesced unless KeepDuplicateReceivers is set.
+func (param *TxParam) prvReceivers() ([]string, []uint64) {
+	if param.KeepDuplicateReceivers {
+		return param.receiverList, param.amountList
+	}
+	return coalesceReceivers(param.receiverList, param.amountList)
+}
+
+// tokenReceivers returns the token receivers and amounts of the transaction, coalesced unless KeepDuplicateReceivers
+// is set.
+func (param *TxParam) tokenReceivers() ([]string, []uint64) {
+	if param.txTokenParam == nil {
+		return nil, nil
+	}
+	if param.KeepDuplicateReceivers {
+		return param.txTokenParam.receiverList, param.txTokenParam.amountList
+	}
+	return coalesceReceivers(param.txTokenParam.receiverList, param.txTokenParam.amountList)
+}
+
+// coalesceReceivers merges the payments to identical payment addresses by summing their amounts. Receivers are kept in
+// the order of their first appearance. The same address in different encodings is not detected as identical.
+//
+// The input lists are returned as-is if their lengths mismatch or if a sum overflows, leaving the error to the caller.
+func coalesceReceivers(receiverList []string, amountList []uint64) ([]string, []uint64) {
+	if len(receiverList) != len(amountList) {
+		return receiverList, amountList
+	}
+
+	resReceivers := make([]string, 0)
+	resAmounts := make([]uint64, 0)
+	indices := make(map[string]int)
+	for i, receiver := range receiverList {
+		idx, ok := indices[receiver]
+		if !ok {
+			indices[receiver] = len(resReceivers)
+			resReceivers = append(resReceivers, receiver)
+			resAmounts = append(resAmounts, amountList[i])
+			continue
+		}
+		sum, err := safemath.AddUint64(resAmounts[idx], amountList[i])
+		if err != nil {
+			return receiverList, amountList
+		}
+		resAmounts[idx] = sum
+	}
+
+	return resReceivers, resAmounts
+}
+
 // NewTxTokenParam creates a new TxTokenParam.
 func NewTxTokenParam(tokenID string, tokenType int, receiverList []string, amountList []uint64, hasTokenFee bool, tokenFee uint64,
 	kArgs map[string]interface{}) *TxTokenParam {
@@ -104,6 +217,83 @@ func PrivateKeyToPaymentAddress(privateKey string, keyType int) string {
 	}
 }
 
+// PrivateKeyToPaymentAddressVersion returns the payment address of a private key for the given address version,
+// regardless of common.AddressVersion. Version should be 1 or 2 (see wallet.IsNewEncodingForAddressVersion) where
+//	- 1: payment address of version 1 (old encoding), as used by privacy-v1 networks
+//	- 2: payment address of version 2
+// Unlike PrivateKeyToPaymentAddress, it does not depend on any global state, and can be used concurrently
+// for different versions. If the private key or the version is invalid, it returns an empty string.
+func PrivateKeyToPaymentAddressVersion(privateKey string, version int) string {
+	isNewEncoding, err := wallet.IsNewEncodingForAddressVersion(version)
+	if err != nil {
+		Logger.Println(err)
+		return ""
+	}
+	keyWallet, err := wallet.Base58CheckDeserialize(privateKey)
+	if err != nil || len(keyWallet.KeySet.PrivateKey) == 0 {
+		return ""
+	}
+
+	return keyWallet.Base58CheckSerializeWithEncoding(wallet.PaymentAddressType, isNewEncoding)
+}
+
+// BuildPaymentAddress assembles a payment address from its components and returns its base58-check encoding for
+// the given address version (see PrivateKeyToPaymentAddressVersion):
+//	- 1: payment address of version 1 (old encoding), consisting of the public spend key and the public view key;
+//	otaPublic is ignored.
+//	- 2: payment address of version 2, consisting of the public spend key, the public view key and the OTA public key.
+// Each key must be a valid 32-byte point.
+func BuildPaymentAddress(spendKey, viewKey, otaPublic []byte, version int) (string, error) {
+	isNewEncoding, err := wallet.IsNewEncodingForAddressVersion(version)
+	if err != nil {
+		return "", err
+	}
+
+	keys := map[string][]byte{"public spend key": spendKey, "public view key": viewKey}
+	if version == 2 {
+		keys["OTA public key"] = otaPublic
+	}
+	for name, keyBytes := range keys {
+		if len(keyBytes) != crypto.Ed25519KeySize {
+			return "", fmt.Errorf("invalid %v length: expected %v, got %v", name, crypto.Ed25519KeySize, len(keyBytes))
+		}
+		if _, err = new(crypto.Point).FromBytesS(keyBytes); err != nil {
+			return "", fmt.Errorf("invalid %v: %v", name, err)
+		}
+	}
+
+	addr := key.PaymentAddress{
+		Pk: append([]byte{}, spendKey...),
+		Tk: append([]byte{}, viewKey...),
+	}
+	if version == 2 {
+		addr.OTAPublic = append([]byte{}, otaPublic...)
+	}
+	keyWallet := &wallet.KeyWallet{KeySet: key.KeySet{PaymentAddress: addr}}
+	res := keyWallet.Base58CheckSerializeWithEncoding(wallet.PaymentAddressType, isNewEncoding)
+	if res == "" {
+		return "", fmt.Errorf("cannot encode the payment address")
+	}
+
+	return res, nil
+}
+
+// PrivateKeyToReadonlyKeyVersion returns the readonly key of a private key for the given address version (1 or 2),
+// regardless of common.AddressVersion. If the private key or the version is invalid, it returns an empty string.
+func PrivateKeyToReadonlyKeyVersion(privateKey string, version int) string {
+	isNewEncoding, err := wallet.IsNewEncodingForAddressVersion(version)
+	if err != nil {
+		Logger.Println(err)
+		return ""
+	}
+	keyWallet, err := wallet.Base58CheckDeserialize(privateKey)
+	if err != nil || len(keyWallet.KeySet.PrivateKey) == 0 {
+		return ""
+	}
+
+	return keyWallet.Base58CheckSerializeWithEncoding(wallet.ReadonlyKeyType, isNewEncoding)
+}
+
 // PrivateKeyToPublicKey returns the public key of a private key.
 //
 // If the private key is invalid, it returns nil.
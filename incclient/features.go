@@ -0,0 +1,141 @@
+package incclient
+
+import (
+	"sync"
+	"time"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/jsonresult"
+)
+
+// DefaultFeaturesCacheTTL is the default time-to-live of the active features cached by an IncClient.
+const DefaultFeaturesCacheTTL = 10 * time.Minute
+
+// Names of the network features detected by GetActiveFeatures in addition to the feature flags triggered on the
+// beacon chain.
+const (
+	// FeaturePdexV3 indicates that pDEX v3 is enabled.
+	FeaturePdexV3 = "pdexv3"
+
+	// FeatureBridgeAgg indicates that the bridge aggregator (unified tokens) is enabled.
+	FeatureBridgeAgg = "bridgeagg"
+)
+
+// featuresCache keeps the active features of the network, which rarely change, so that routing a transaction (e.g,
+// CreatePdexTrade) does not each time need the RPCs of GetActiveFeatures.
+type featuresCache struct {
+	mtx       *sync.Mutex
+	ttl       time.Duration
+	features  map[string]bool
+	updatedAt time.Time
+}
+
+func newFeaturesCache() *featuresCache {
+	return &featuresCache{mtx: new(sync.Mutex), ttl: DefaultFeaturesCacheTTL}
+}
+
+// SetFeaturesCacheTTL sets the time-to-live of the cached active features, and invalidates the current cached value.
+// A non-positive ttl disables the cache.
+func (client *IncClient) SetFeaturesCacheTTL(ttl time.Duration) {
+	cache := client.featuresCache
+	cache.mtx.Lock()
+	defer cache.mtx.Unlock()
+
+	cache.ttl = ttl
+	cache.updatedAt = time.Time{}
+}
+
+// GetActiveFeatures returns the state of the features of the network the client is connected to. The result
+// consists of
+//   - the feature flags triggered on the beacon chain, each is active if its trigger height has been reached;
+//   - FeaturePdexV3, FeatureBridgeAgg: whether or not the remote node serves the pDEX v3 (resp. bridge aggregator) state.
+//
+// A feature missing from the result is not active. The result is cached (DefaultFeaturesCacheTTL, see
+// SetFeaturesCacheTTL), so successive calls share the same RPCs. Errors (e.g, a rate limit) are not cached.
+func (client *IncClient) GetActiveFeatures() (map[string]bool, error) {
+	cache := client.featuresCache
+	cache.mtx.Lock()
+	defer cache.mtx.Unlock()
+
+	if cache.ttl <= 0 || cache.updatedAt.IsZero() || time.Since(cache.updatedAt) >= cache.ttl {
+		features, err := client.fetchActiveFeatures()
+		if err != nil {
+			return nil, err
+		}
+		cache.features = features
+		cache.updatedAt = time.Now()
+	}
+
+	res := make(map[string]bool)
+	for feature, active := range cache.features {
+		res[feature] = active
+	}
+
+	return res, nil
+}
+
+// fetchActiveFeatures retrieves the state of the features of the network from the remote node.
+func (client *IncClient) fetchActiveFeatures() (map[string]bool, error) {
+	beaconState, err := client.GetBeaconBestState(0)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]bool)
+	for feature, triggeredHeight := range beaconState.TriggeredFeature {
+		res[feature] = triggeredHeight <= beaconState.BeaconHeight
+	}
+
+	res[FeaturePdexV3], err = client.isPdexV3Active()
+	if err != nil {
+		return nil, err
+	}
+	res[FeatureBridgeAgg], err = client.isBridgeAggActive()
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// isPdexV3Active checks if the remote node serves the pDEX v3 state. The feature is only considered inactive if the
+// node does not support the pDEX v3 RPCs (see rpchandler.IsMethodNotFound); any other error is returned.
+func (client *IncClient) isPdexV3Active() (bool, error) {
+	filter := make(map[string]interface{})
+	filter["Key"] = Params
+	filter["Verbosity"] = SimpleVerbosity
+	filter["ID"] = ""
+
+	responseInBytes, err := client.rpcServer.GetPdexState(0, filter)
+	if err == nil {
+		var res jsonresult.CurrentPdexState
+		err = rpchandler.ParseResponse(responseInBytes, &res)
+		if err == nil {
+			return res.Params != nil, nil
+		}
+	}
+	if rpchandler.IsMethodNotFound(err) {
+		return false, nil
+	}
+
+	return false, err
+}
+
+// isBridgeAggActive checks if the remote node serves the bridge aggregator state. The feature is only considered
+// inactive if the node does not support the bridge aggregator RPCs (see rpchandler.IsMethodNotFound); any other
+// error is returned.
+func (client *IncClient) isBridgeAggActive() (bool, error) {
+	responseInBytes, err := client.rpcServer.GetBridgeAggState(0)
+	if err == nil {
+		var res map[string]interface{}
+		err = rpchandler.ParseResponse(responseInBytes, &res)
+		if err == nil {
+			return res != nil, nil
+		}
+	}
+	if rpchandler.IsMethodNotFound(err) {
+		return false, nil
+	}
+
+	return false, err
+}
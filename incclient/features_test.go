@@ -0,0 +1,160 @@
+package incclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/coin"
+	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"github.com/incognitochain/go-incognito-sdk-v2/common/base58"
+	"github.com/incognitochain/go-incognito-sdk-v2/key"
+	metadataCommon "github.com/incognitochain/go-incognito-sdk-v2/metadata/common"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/jsonresult"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/rpc"
+	"github.com/incognitochain/go-incognito-sdk-v2/transaction"
+	"github.com/incognitochain/go-incognito-sdk-v2/wallet"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIncClient_GetActiveFeatures(t *testing.T) {
+	senderWallet, err := wallet.GenRandomWalletForShardID(byte(common.RandInt() % common.MaxShardNumber))
+	if err != nil {
+		panic(err)
+	}
+	privateKey := senderWallet.Base58CheckSerialize(wallet.PrivateKeyType)
+
+	// the coins must cover the default transaction fee
+	coinServer := newMockCoinServer()
+	for i := 0; i < 10; i++ {
+		paymentInfo := key.PaymentInfo{PaymentAddress: senderWallet.KeySet.PaymentAddress, Amount: 1e9}
+		c, err := coin.NewCoinFromPaymentInfo(coin.NewTransferCoinParams(&paymentInfo))
+		if err != nil {
+			panic(err)
+		}
+		coinServer.coins = append(coinServer.coins, jsonresult.NewOutCoin(c))
+	}
+
+	var pdexV3Enabled, bridgeAggEnabled, rateLimited bool
+	numBeaconStateCalls := 0
+	triggeredFeatures := map[string]uint64{"feature1": 10, "feature2": 1000}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var req struct {
+			Method string
+		}
+		if err = json.Unmarshal(body, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var result interface{}
+		switch req.Method {
+		case "getbeaconbeststate":
+			numBeaconStateCalls++
+			result = jsonresult.BeaconBestState{BeaconHeight: 100, TriggeredFeature: triggeredFeatures}
+		case "pdexv3_getState":
+			if rateLimited {
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{"Error": map[string]interface{}{"Code": rpchandler.LimitExceededErrorCode, "Message": "limit exceeded"}})
+				return
+			}
+			if !pdexV3Enabled {
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{"Error": map[string]interface{}{"Code": rpchandler.MethodNotFoundErrorCode, "Message": "method not found"}})
+				return
+			}
+			result = jsonresult.CurrentPdexState{Params: &jsonresult.Pdexv3Params{}}
+		case "bridgeagg_getState":
+			if !bridgeAggEnabled {
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{"Error": map[string]interface{}{"Code": rpchandler.MethodNotFoundErrorCode, "Message": "method not found"}})
+				return
+			}
+			result = map[string]interface{}{"UnifiedTokenVaults": map[string]interface{}{}}
+		default:
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+			coinServer.ServeHTTP(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"Result": result})
+	}))
+	defer ts.Close()
+
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+	client.SetCoinStore(NewMemCoinStore())
+
+	tokenIDToBuy := common.HashH([]byte("tokenToBuy")).String()
+	tradePath := []string{fmt.Sprintf("%v-%v-%v", common.PRVIDStr, tokenIDToBuy, common.HashH([]byte("pool")).String())}
+	for _, pdexV3Enabled = range []bool{false, true} {
+		for _, bridgeAggEnabled = range []bool{false, true} {
+			// the features have changed: invalidate the cached ones.
+			client.SetFeaturesCacheTTL(DefaultFeaturesCacheTTL)
+			numBeaconStateCalls = 0
+
+			features, err := client.GetActiveFeatures()
+			assert.Equal(t, nil, err, fmt.Errorf("GetActiveFeatures error: %v", err))
+			assert.Equal(t, map[string]bool{
+				"feature1":       true,
+				"feature2":       false,
+				FeaturePdexV3:    pdexV3Enabled,
+				FeatureBridgeAgg: bridgeAggEnabled,
+			}, features)
+
+			// the trade is routed to the active pDEX version
+			encodedTx, _, err := client.CreatePdexTrade(privateKey, tradePath, common.PRVIDStr, tokenIDToBuy, 1000, 1, 100, true)
+			assert.Equal(t, nil, err, fmt.Errorf("CreatePdexTrade error: %v", err))
+			rawTxBytes, _, err := base58.Base58Check{}.Decode(string(encodedTx))
+			assert.Equal(t, nil, err)
+			txChoice, err := transaction.DeserializeTransactionJSON(rawTxBytes)
+			assert.Equal(t, nil, err, fmt.Errorf("DeserializeTransactionJSON error: %v", err))
+
+			expectedMetadataType := metadataCommon.PDECrossPoolTradeRequestMeta
+			if pdexV3Enabled {
+				expectedMetadataType = metadataCommon.Pdexv3TradeRequestMeta
+			}
+			assert.Equal(t, expectedMetadataType, txChoice.ToTx().GetMetadataType())
+
+			// the trade re-used the cached features.
+			assert.Equal(t, 1, numBeaconStateCalls)
+		}
+	}
+
+	// a modification of the result does not alter the cache.
+	features, err := client.GetActiveFeatures()
+	assert.Equal(t, nil, err, fmt.Errorf("GetActiveFeatures error: %v", err))
+	features[FeaturePdexV3] = false
+	features, err = client.GetActiveFeatures()
+	assert.Equal(t, nil, err, fmt.Errorf("GetActiveFeatures error: %v", err))
+	assert.Equal(t, true, features[FeaturePdexV3])
+	assert.Equal(t, 1, numBeaconStateCalls)
+
+	// the cache is disabled.
+	client.SetFeaturesCacheTTL(0)
+	_, err = client.GetActiveFeatures()
+	assert.Equal(t, nil, err, fmt.Errorf("GetActiveFeatures error: %v", err))
+	assert.Equal(t, 2, numBeaconStateCalls)
+
+	// other RPC errors are returned, and not cached.
+	client.SetFeaturesCacheTTL(DefaultFeaturesCacheTTL)
+	rateLimited = true
+	_, err = client.GetActiveFeatures()
+	assert.Equal(t, true, rpchandler.IsRateLimited(err), fmt.Errorf("expected a rate limit, got %v", err))
+	_, _, err = client.CreatePdexTrade(privateKey, tradePath, common.PRVIDStr, tokenIDToBuy, 1000, 1, 100, true)
+	assert.NotEqual(t, nil, err)
+	rateLimited = false
+	features, err = client.GetActiveFeatures()
+	assert.Equal(t, nil, err, fmt.Errorf("GetActiveFeatures error: %v", err))
+	assert.Equal(t, true, features[FeaturePdexV3])
+
+	// the node cannot be reached
+	client = newIncClient(rpc.NewRPCServer("http://127.0.0.1:0"), nil, nil, 2)
+	_, err = client.GetActiveFeatures()
+	assert.NotEqual(t, nil, err)
+}
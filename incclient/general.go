@@ -2,6 +2,7 @@ package incclient
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler"
 )
 
@@ -70,3 +71,29 @@ func (client *IncClient) NewRPCCall(jsonRPC, method string, params []interface{}
 
 	return client.rpcServer.SendPostRequestWithQuery(string(query))
 }
+
+// RawRPCCall sends a request with an arbitrary method and parameters to the RPC server, and returns the raw result of
+// the response. It allows to use RPC methods of the full-node not wrapped by the SDK yet, e.g,
+//
+//	res, err := client.RawRPCCall("getblockchaininfo", nil)
+//
+// Unlike NewRPCCall, the query goes through the same path as typed calls (e.g, it honors the context bound by
+// WithContext), and an error is returned if the response contains an RPC error.
+func (client *IncClient) RawRPCCall(method string, params []interface{}) (json.RawMessage, error) {
+	if method == "" {
+		return nil, fmt.Errorf("method must not be empty")
+	}
+
+	responseInBytes, err := client.rpcServer.SendQuery(method, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var res json.RawMessage
+	err = rpchandler.ParseResponse(responseInBytes, &res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
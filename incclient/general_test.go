@@ -1,11 +1,16 @@
 package incclient
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/rpc"
 	"github.com/incognitochain/go-incognito-sdk-v2/wallet"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestIncClient_AuthorizedSubmitKey(t *testing.T) {
@@ -82,3 +87,52 @@ func TestIncClient_NewRPCCall2(t *testing.T) {
 
 	fmt.Println(string(resp))
 }
+
+func TestIncClient_RawRPCCall(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string
+			Params []interface{}
+		}
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		switch req.Method {
+		case "getfuturefeature":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"Result": map[string]interface{}{"Echo": req.Params, "Enabled": true},
+			})
+		default:
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"Error": map[string]interface{}{"Code": -32601, "Message": "Method not found"},
+			})
+		}
+	}))
+	defer ts.Close()
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+
+	raw, err := client.RawRPCCall("getfuturefeature", []interface{}{"abc", 10})
+	assert.Equal(t, nil, err, fmt.Errorf("RawRPCCall error: %v", err))
+	var res struct {
+		Echo    []interface{}
+		Enabled bool
+	}
+	err = json.Unmarshal(raw, &res)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []interface{}{"abc", float64(10)}, res.Echo)
+	assert.Equal(t, true, res.Enabled)
+
+	// nil params
+	raw, err = client.RawRPCCall("getfuturefeature", nil)
+	assert.Equal(t, nil, err, fmt.Errorf("RawRPCCall error: %v", err))
+	assert.Equal(t, `{"Echo":[],"Enabled":true}`, string(raw))
+
+	// RPC errors are returned
+	_, err = client.RawRPCCall("unknownmethod", nil)
+	assert.NotEqual(t, nil, err)
+	_, err = client.RawRPCCall("", nil)
+	assert.NotEqual(t, nil, err)
+}
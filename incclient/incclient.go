@@ -3,6 +3,7 @@
 package incclient
 
 import (
+	"context"
 	"fmt"
 	"github.com/incognitochain/go-incognito-sdk-v2/common"
 	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/rpc"
@@ -25,6 +26,54 @@ type IncClient struct {
 
 	// the utxoCache of the client
 	cache *utxoCache
+
+	// the optional CoinStore used to persist scanned output coins
+	coinStore CoinStore
+
+	// the short-lived cache of the beacon best height
+	beaconHeightCache *beaconHeightCache
+
+	// the cache of token decimals
+	tokenDecimalsCache *tokenDecimalsCache
+
+	// the stale-while-revalidate cache of the latest pDEX state
+	pdexStateCache *pdexStateCache
+
+	// the intents of recently created PRV transactions, used for fee bumping
+	txIntentStore *txIntentStore
+
+	// the protection against fees that are too high compared to the sent amount
+	feeGuard *feeGuard
+
+	// the short-lived cache of fee rates
+	feeRateCache *feeRateCache
+
+	// the pre-fetched decoys (see Prewarm)
+	decoyCache *decoyCache
+
+	// the cache of the active features of the network
+	featuresCache *featuresCache
+
+	// whether fetched transactions are verified against the transaction roots of their blocks
+	verifyInclusion bool
+}
+
+// newIncClient creates an IncClient with the given servers and version. The caches of the client are created here, so
+// that they are shared by the copies returned by WithContext.
+func newIncClient(rpcServer *rpc.RPCServer, evmServers map[int]*rpc.RPCServer, btcPortalParams *BTCPortalV4Params, version int) *IncClient {
+	return &IncClient{
+		rpcServer:          rpcServer,
+		evmServers:         evmServers,
+		btcPortalParams:    btcPortalParams,
+		version:            version,
+		beaconHeightCache:  newBeaconHeightCache(),
+		tokenDecimalsCache: newTokenDecimalsCache(),
+		pdexStateCache:     newPdexStateCache(),
+		txIntentStore:      newTxIntentStore(),
+		feeRateCache:       newFeeRateCache(),
+		decoyCache:         newDecoyCache(),
+		featuresCache:      newFeaturesCache(),
+	}
 }
 
 // NewTestNetClient creates a new IncClient with the test-net environment.
@@ -37,12 +86,7 @@ func NewTestNetClient() (*IncClient, error) {
 		rpc.FTMNetworkID: rpc.NewRPCServer(TestNetFTMHost),
 	}
 
-	incClient := IncClient{
-		rpcServer:       rpcServer,
-		evmServers:      evmServers,
-		btcPortalParams: &testNetBTCPortalV4Params,
-		version:         TestNetPrivacyVersion,
-	}
+	incClient := newIncClient(rpcServer, evmServers, &testNetBTCPortalV4Params, TestNetPrivacyVersion)
 
 	activeShards, err := incClient.GetActiveShard()
 	if err != nil {
@@ -58,7 +102,7 @@ func NewTestNetClient() (*IncClient, error) {
 		common.AddressVersion = 1
 	}
 
-	return &incClient, nil
+	return incClient, nil
 }
 
 // NewTestNetClientWithCache creates a new IncClient with the test-net environment.
@@ -88,11 +132,7 @@ func NewTestNet1Client() (*IncClient, error) {
 		rpc.FTMNetworkID: rpc.NewRPCServer(TestNet1FTMHost),
 	}
 
-	incClient := IncClient{
-		rpcServer:       rpcServer,
-		evmServers:      evmServers,
-		btcPortalParams: &testNet1BTCPortalV4Params,
-		version:         TestNet1PrivacyVersion}
+	incClient := newIncClient(rpcServer, evmServers, &testNet1BTCPortalV4Params, TestNet1PrivacyVersion)
 
 	activeShards, err := incClient.GetActiveShard()
 	if err != nil {
@@ -108,7 +148,7 @@ func NewTestNet1Client() (*IncClient, error) {
 		common.AddressVersion = 1
 	}
 
-	return &incClient, nil
+	return incClient, nil
 }
 
 // NewTestNet1ClientWithCache creates a new IncClient with the test-net-1 environment.
@@ -138,11 +178,7 @@ func NewMainNetClient() (*IncClient, error) {
 		rpc.FTMNetworkID: rpc.NewRPCServer(MainNetFTMHost),
 	}
 
-	incClient := IncClient{
-		rpcServer:       rpcServer,
-		evmServers:      evmServers,
-		btcPortalParams: &mainNetBTCPortalV4Params,
-		version:         MainNetPrivacyVersion}
+	incClient := newIncClient(rpcServer, evmServers, &mainNetBTCPortalV4Params, MainNetPrivacyVersion)
 
 	activeShards, err := incClient.GetActiveShard()
 	if err != nil {
@@ -158,7 +194,7 @@ func NewMainNetClient() (*IncClient, error) {
 		common.AddressVersion = 1
 	}
 
-	return &incClient, nil
+	return incClient, nil
 }
 
 // NewMainNetClientWithCache creates a new IncClient with the main-net environment.
@@ -185,11 +221,7 @@ func NewLocalClient(port string) (*IncClient, error) {
 		rpc.ETHNetworkID: rpc.NewRPCServer(LocalETHHost),
 	}
 
-	incClient := IncClient{
-		rpcServer:       rpcServer,
-		evmServers:      evmServers,
-		btcPortalParams: &localBTCPortalV4Params,
-		version:         LocalPrivacyVersion}
+	incClient := newIncClient(rpcServer, evmServers, &localBTCPortalV4Params, LocalPrivacyVersion)
 	if port != "" {
 		incClient.rpcServer = rpc.NewRPCServer(fmt.Sprintf("http://127.0.0.1:%v", port))
 	}
@@ -208,7 +240,7 @@ func NewLocalClient(port string) (*IncClient, error) {
 		common.AddressVersion = 1
 	}
 
-	return &incClient, nil
+	return incClient, nil
 }
 
 // NewLocalClientWithCache creates a new IncClient with the local environment.
@@ -242,12 +274,7 @@ func NewIncClient(fullNode, ethNode string, version int, networks ...string) (*I
 		rpc.PLGNetworkID: rpc.NewRPCServer(MainNetPLGHost),
 	}
 
-	incClient := IncClient{
-		rpcServer:       rpcServer,
-		evmServers:      evmServers,
-		btcPortalParams: &mainNetBTCPortalV4Params,
-		version:         version,
-	}
+	incClient := newIncClient(rpcServer, evmServers, &mainNetBTCPortalV4Params, version)
 	if len(networks) > 0 {
 		switch strings.ToLower(networks[0]) {
 		case "testnet":
@@ -282,7 +309,7 @@ func NewIncClient(fullNode, ethNode string, version int, networks ...string) (*I
 		return nil, fmt.Errorf("version %v not supported", version)
 	}
 
-	return &incClient, nil
+	return incClient, nil
 }
 
 // NewIncClientWithCache creates a new IncClient from given parameters.
@@ -306,3 +333,29 @@ func NewIncClientWithCache(fullNode, ethNode string, version int, networks ...st
 
 	return incClient, nil
 }
+
+// WithContext returns a shallow copy of an IncClient whose RPC queries (to the Incognito network and the EVM networks)
+// are bound to the given context: an in-flight HTTP request is aborted as soon as ctx is done, and the corresponding
+// method returns an error wrapping ctx.Err(). The copy shares the caches and the CoinStore of the original client.
+//
+// It gives context support to all methods of an IncClient, e.g,
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+//	defer cancel()
+//	balance, err := client.WithContext(ctx).GetBalance(privateKey, common.PRVIDStr)
+func (client *IncClient) WithContext(ctx context.Context) *IncClient {
+	if ctx == nil {
+		panic("nil context")
+	}
+
+	res := *client
+	res.rpcServer = client.rpcServer.WithContext(ctx)
+	if client.evmServers != nil {
+		res.evmServers = make(map[int]*rpc.RPCServer, len(client.evmServers))
+		for networkID, evmServer := range client.evmServers {
+			res.evmServers[networkID] = evmServer.WithContext(ctx)
+		}
+	}
+
+	return &res
+}
@@ -0,0 +1,101 @@
+package incclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/rpc"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIncClient_WithContext(t *testing.T) {
+	var numAborted int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string
+		}
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var result interface{}
+		switch req.Method {
+		case "getbestblock":
+			result = map[string]interface{}{"BestBlocks": map[string]interface{}{"-1": map[string]interface{}{"Height": 100}}}
+		default:
+			// a slow method: block until the client gives up.
+			select {
+			case <-r.Context().Done():
+				atomic.AddInt32(&numAborted, 1)
+			case <-time.After(10 * time.Second):
+			}
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"Result": result})
+	}))
+	defer ts.Close()
+
+	client := newIncClient(rpc.NewRPCServer(ts.URL), map[int]*rpc.RPCServer{rpc.ETHNetworkID: rpc.NewRPCServer(ts.URL)}, nil, 2)
+
+	// queries bound to a live context succeed.
+	ctx, cancel := context.WithCancel(context.Background())
+	bestBlocks, err := client.WithContext(ctx).GetBestBlock()
+	assert.Equal(t, nil, err, fmt.Errorf("GetBestBlock error: %v", err))
+	assert.Equal(t, uint64(100), bestBlocks[-1])
+	cancel()
+
+	// a cancelled context fails immediately.
+	_, err = client.WithContext(ctx).GetBestBlock()
+	assert.Equal(t, true, errors.Is(err, context.Canceled), fmt.Errorf("expected context.Canceled, got %v", err))
+
+	// deadlines interrupt in-flight requests.
+	testCases := []struct {
+		name string
+		call func(ctx context.Context) error
+	}{
+		{"GetPdexStateWithContext", func(ctx context.Context) error {
+			_, err := client.GetPdexStateWithContext(ctx, 0)
+			return err
+		}},
+		{"CheckPriceWithContext", func(ctx context.Context) error {
+			_, err := client.CheckPriceWithContext(ctx, "pairID", "tokenID", 1000)
+			return err
+		}},
+		{"SendRawTxWithContext", func(ctx context.Context) error {
+			return client.SendRawTxWithContext(ctx, []byte("encodedTx"))
+		}},
+		{"WithContext", func(ctx context.Context) error {
+			_, err := client.WithContext(ctx).GetEVMTokenDecimals("0x6b175474e89094c44da98b954eedeac495271d0f")
+			return err
+		}},
+	}
+	for _, tc := range testCases {
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		start := time.Now()
+		err = tc.call(ctx)
+		cancel()
+		assert.Equal(t, true, errors.Is(err, context.DeadlineExceeded), fmt.Errorf("%v: expected context.DeadlineExceeded, got %v", tc.name, err))
+		assert.Equal(t, true, time.Since(start) < 5*time.Second, fmt.Errorf("%v took %v", tc.name, time.Since(start)))
+	}
+
+	// the server sees every in-flight request aborted.
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt32(&numAborted) < int32(len(testCases)) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, int32(len(testCases)), atomic.LoadInt32(&numAborted))
+
+	// the original client is not bound to any context.
+	assert.Equal(t, context.Background(), client.rpcServer.Context())
+	_, err = client.GetBestBlock()
+	assert.Equal(t, nil, err, fmt.Errorf("GetBestBlock error: %v", err))
+}
@@ -0,0 +1,250 @@
+package incclient
+
+import (
+	"fmt"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"github.com/incognitochain/go-incognito-sdk-v2/metadata"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/jsonresult"
+)
+
+// ErrInclusionProofFailed indicates that an item returned by the remote node cannot be proven to be included in the
+// block it is claimed to belong to.
+var ErrInclusionProofFailed = fmt.Errorf("inclusion proof verification failed")
+
+// SetInclusionVerification enables (or disables) the verification of transactions returned by GetTx and GetTxs: each
+// transaction is re-hashed, and its inclusion in its block is checked against the transaction merkle root of the block
+// header. This costs extra RPCs per transaction; it is disabled by default.
+//
+// This is a consistency check ONLY: the block header (hence the root) is served by the same remote node, and the
+// signatures of its validators are not checked. It detects a node returning transactions that disagree with its own
+// blocks (e.g, corrupted or mixed-up responses), not a malicious node forging both. Transactions in the mempool (see
+// GetPendingTransactions) and output coins (e.g, GetOutputCoins, GetOTACoinsByIndices) are not covered, since the node
+// provides no inclusion proof for them.
+func (client *IncClient) SetInclusionVerification(enabled bool) {
+	client.verifyInclusion = enabled
+}
+
+// GetShardBlock retrieves the (verbosity "1") detail of a shard block given its hash.
+func (client *IncClient) GetShardBlock(blockHash string) (*jsonresult.ShardBlockResult, error) {
+	responseInBytes, err := client.rpcServer.RetrieveBlock(blockHash, "1")
+	if err != nil {
+		return nil, err
+	}
+
+	var res jsonresult.ShardBlockResult
+	err = rpchandler.ParseResponse(responseInBytes, &res)
+	if err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// GetShardBlocksByHeight retrieves the (verbosity "1") detail of the blocks of a shard at the given height.
+func (client *IncClient) GetShardBlocksByHeight(shardID byte, height uint64) ([]jsonresult.ShardBlockResult, error) {
+	responseInBytes, err := client.rpcServer.RetrieveBlockByHeight(shardID, height, "1")
+	if err != nil {
+		return nil, err
+	}
+
+	var res []jsonresult.ShardBlockResult
+	err = rpchandler.ParseResponse(responseInBytes, &res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// VerifyTxInclusion checks that a transaction belongs to the block reported by the remote node. It returns
+// ErrInclusionProofFailed if the merkle inclusion proof of the transaction does not lead to the transaction root of
+// the block header.
+func (client *IncClient) VerifyTxInclusion(txHash string) error {
+	txDetail, err := client.GetTxDetail(txHash)
+	if err != nil {
+		return err
+	}
+
+	return client.verifyTxInclusion(txHash, txDetail)
+}
+
+// verifyTxInclusion checks that the transaction txHash is included in the block of its txDetail.
+func (client *IncClient) verifyTxInclusion(txHash string, txDetail *jsonresult.TransactionDetail) error {
+	if txDetail.IsInMempool || !txDetail.IsInBlock {
+		return fmt.Errorf("tx %v is not in a block", txHash)
+	}
+
+	block, err := client.GetShardBlock(txDetail.BlockHash)
+	if err != nil {
+		return err
+	}
+
+	return verifyTxInBlock(txHash, block)
+}
+
+// verifyTxInBlock checks that the transaction txHash is included in the given block, i.e, its merkle inclusion proof
+// leads to the transaction root of the block.
+func verifyTxInBlock(txHash string, block *jsonresult.ShardBlockResult) error {
+	leaf, err := common.Hash{}.NewHashFromStr(txHash)
+	if err != nil {
+		return fmt.Errorf("invalid txHash %v: %v", txHash, err)
+	}
+	txRoot, err := common.Hash{}.NewHashFromStr(block.TxRoot)
+	if err != nil {
+		return fmt.Errorf("invalid tx root %v of block %v: %v", block.TxRoot, block.Hash, err)
+	}
+
+	txHashes := make([]common.Hash, 0, len(block.TxHashes))
+	index := -1
+	for i, h := range block.TxHashes {
+		tmpHash, err := common.Hash{}.NewHashFromStr(h)
+		if err != nil {
+			return fmt.Errorf("invalid txHash %v in block %v: %v", h, block.Hash, err)
+		}
+		txHashes = append(txHashes, *tmpHash)
+		if h == txHash {
+			index = i
+		}
+	}
+	if index == -1 {
+		Logger.Printf("tx %v not found in block %v\n", txHash, block.Hash)
+		return ErrInclusionProofFailed
+	}
+
+	path, pathIsLeft, err := buildMerkleProof(txHashes, index)
+	if err != nil {
+		return err
+	}
+	if !verifyMerkleProof(*leaf, path, pathIsLeft, *txRoot) {
+		Logger.Printf("inclusion proof of tx %v does not match the tx root %v of block %v\n", txHash, block.TxRoot, block.Hash)
+		return ErrInclusionProofFailed
+	}
+
+	return nil
+}
+
+// verifyFetchedTx checks that a transaction retrieved from the remote node has the requested hash and is included in
+// its block.
+func (client *IncClient) verifyFetchedTx(txHash string, tx metadata.Transaction, txDetail *jsonresult.TransactionDetail) error {
+	if tx.Hash().String() != txHash {
+		Logger.Printf("expected tx %v, got %v\n", txHash, tx.Hash().String())
+		return ErrInclusionProofFailed
+	}
+
+	return client.verifyTxInclusion(txHash, txDetail)
+}
+
+// verifyFetchedTxs checks that the transactions retrieved from the remote node have the requested hashes and are
+// included in their blocks. Each block is retrieved only once.
+func (client *IncClient) verifyFetchedTxs(txs map[string]metadata.Transaction) error {
+	blocks := make(map[string]*jsonresult.ShardBlockResult)
+	for txHash, tx := range txs {
+		if tx.Hash().String() != txHash {
+			Logger.Printf("expected tx %v, got %v\n", txHash, tx.Hash().String())
+			return ErrInclusionProofFailed
+		}
+
+		txDetail, err := client.GetTxDetail(txHash)
+		if err != nil {
+			return err
+		}
+		if txDetail.IsInMempool || !txDetail.IsInBlock {
+			return fmt.Errorf("tx %v is not in a block", txHash)
+		}
+		block, ok := blocks[txDetail.BlockHash]
+		if !ok {
+			block, err = client.GetShardBlock(txDetail.BlockHash)
+			if err != nil {
+				return err
+			}
+			blocks[txDetail.BlockHash] = block
+		}
+		err = verifyTxInBlock(txHash, block)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hashMerkleBranches returns the hash of two sibling nodes of a merkle tree.
+func hashMerkleBranches(left, right common.Hash) common.Hash {
+	var data [common.HashSize * 2]byte
+	copy(data[:common.HashSize], left[:])
+	copy(data[common.HashSize:], right[:])
+
+	return common.HashH(data[:])
+}
+
+// nextMerkleLevel returns the parent nodes of a level of a merkle tree. Following the construction of the transaction
+// root of a block, a node without a right sibling is paired with itself.
+func nextMerkleLevel(level []common.Hash) []common.Hash {
+	res := make([]common.Hash, 0, (len(level)+1)/2)
+	for i := 0; i < len(level); i += 2 {
+		if i+1 < len(level) {
+			res = append(res, hashMerkleBranches(level[i], level[i+1]))
+		} else {
+			res = append(res, hashMerkleBranches(level[i], level[i]))
+		}
+	}
+
+	return res
+}
+
+// buildMerkleProof returns the merkle path of the leaf at the given index. The i-th element of pathIsLeft indicates whether the i-th node of the path is the left sibling.
+func buildMerkleProof(leaves []common.Hash, index int) ([]common.Hash, []bool, error) {
+	if index < 0 || index >= len(leaves) {
+		return nil, nil, fmt.Errorf("index %v out of range [0, %v)", index, len(leaves))
+	}
+
+	path := make([]common.Hash, 0)
+	pathIsLeft := make([]bool, 0)
+	level := leaves
+	for len(level) > 1 {
+		siblingIndex := index ^ 1
+		if siblingIndex >= len(level) {
+			siblingIndex = index
+		}
+		path = append(path, level[siblingIndex])
+		pathIsLeft = append(pathIsLeft, siblingIndex < index)
+
+		level = nextMerkleLevel(level)
+		index /= 2
+	}
+
+	return path, pathIsLeft, nil
+}
+
+// computeMerkleRoot returns the merkle root of a list of leaves, or the zero hash if the list is empty.
+func computeMerkleRoot(leaves []common.Hash) common.Hash {
+	if len(leaves) == 0 {
+		return common.Hash{}
+	}
+	level := leaves
+	for len(level) > 1 {
+		level = nextMerkleLevel(level)
+	}
+
+	return level[0]
+}
+
+// verifyMerkleProof checks if the given leaf and merkle path lead to the given root.
+func verifyMerkleProof(leaf common.Hash, path []common.Hash, pathIsLeft []bool, root common.Hash) bool {
+	if len(path) != len(pathIsLeft) {
+		return false
+	}
+
+	current := leaf
+	for i, node := range path {
+		if pathIsLeft[i] {
+			current = hashMerkleBranches(node, current)
+		} else {
+			current = hashMerkleBranches(current, node)
+		}
+	}
+
+	return current == root
+}
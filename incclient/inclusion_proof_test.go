@@ -0,0 +1,275 @@
+package incclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/jsonresult"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/rpc"
+	"github.com/incognitochain/go-incognito-sdk-v2/wallet"
+	"github.com/stretchr/testify/assert"
+)
+
+// merkleRootByTreeStore computes the merkle root the way a full-node builds the transaction root of a block,
+// i.e. over an array padded to the next power of two.
+func merkleRootByTreeStore(leaves []common.Hash) common.Hash {
+	if len(leaves) == 0 {
+		return common.Hash{}
+	}
+	nextPoT := 1
+	for nextPoT < len(leaves) {
+		nextPoT <<= 1
+	}
+	arraySize := nextPoT*2 - 1
+	merkles := make([]*common.Hash, arraySize)
+	for i := range leaves {
+		merkles[i] = &leaves[i]
+	}
+	offset := nextPoT
+	for i := 0; i < arraySize-1; i += 2 {
+		switch {
+		case merkles[i] == nil:
+			merkles[offset] = nil
+		case merkles[i+1] == nil:
+			tmp := hashMerkleBranches(*merkles[i], *merkles[i])
+			merkles[offset] = &tmp
+		default:
+			tmp := hashMerkleBranches(*merkles[i], *merkles[i+1])
+			merkles[offset] = &tmp
+		}
+		offset++
+	}
+
+	return *merkles[arraySize-1]
+}
+
+func TestMerkleProof(t *testing.T) {
+	for numLeaves := 1; numLeaves <= 17; numLeaves++ {
+		leaves := make([]common.Hash, numLeaves)
+		for i := range leaves {
+			leaves[i] = common.HashH(common.RandBytes(32))
+		}
+		root := computeMerkleRoot(leaves)
+		assert.Equal(t, merkleRootByTreeStore(leaves), root, fmt.Errorf("invalid root of %v leaves", numLeaves))
+
+		for index, leaf := range leaves {
+			path, pathIsLeft, err := buildMerkleProof(leaves, index)
+			assert.Equal(t, nil, err, fmt.Errorf("buildMerkleProof error: %v", err))
+			assert.Equal(t, true, verifyMerkleProof(leaf, path, pathIsLeft, root))
+
+			// tampered proofs
+			assert.Equal(t, false, verifyMerkleProof(common.HashH(leaf[:]), path, pathIsLeft, root))
+			if len(path) > 0 {
+				tamperedPath := append([]common.Hash{}, path...)
+				tamperedPath[common.RandInt()%len(path)] = common.HashH(common.RandBytes(32))
+				assert.Equal(t, false, verifyMerkleProof(leaf, tamperedPath, pathIsLeft, root))
+				assert.Equal(t, false, verifyMerkleProof(leaf, path, pathIsLeft[1:], root))
+			}
+		}
+
+		_, _, err := buildMerkleProof(leaves, numLeaves)
+		assert.NotEqual(t, nil, err)
+	}
+}
+
+func TestIncClient_VerifyTxInclusion(t *testing.T) {
+	blockHash := common.HashH([]byte("block")).String()
+	txHashes := make([]string, 0)
+	leaves := make([]common.Hash, 0)
+	for i := 0; i < 5; i++ {
+		txHash := common.HashH(common.RandBytes(32))
+		txHashes = append(txHashes, txHash.String())
+		leaves = append(leaves, txHash)
+	}
+	block := jsonresult.ShardBlockResult{
+		Hash:     blockHash,
+		Height:   100,
+		TxRoot:   computeMerkleRoot(leaves).String(),
+		TxHashes: txHashes,
+	}
+	mempoolTxHash := common.HashH([]byte("mempool")).String()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string
+			Params []string
+		}
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil || len(req.Params) < 1 {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+
+		var result interface{}
+		switch req.Method {
+		case "gettransactionbyhash":
+			if req.Params[0] == mempoolTxHash {
+				result = jsonresult.TransactionDetail{Hash: mempoolTxHash, IsInMempool: true}
+			} else {
+				result = jsonresult.TransactionDetail{Hash: req.Params[0], BlockHash: blockHash, IsInBlock: true}
+			}
+		case "retrieveblock":
+			if req.Params[0] != blockHash {
+				http.Error(w, "block not found", http.StatusBadRequest)
+				return
+			}
+			result = block
+		default:
+			http.Error(w, fmt.Sprintf("method %v not supported", req.Method), http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"Result": result})
+	}))
+	defer ts.Close()
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+
+	// valid proofs
+	for _, txHash := range txHashes {
+		err := client.VerifyTxInclusion(txHash)
+		assert.Equal(t, nil, err, fmt.Errorf("VerifyTxInclusion(%v) error: %v", txHash, err))
+	}
+
+	// a tx not in the block
+	err := client.VerifyTxInclusion(common.HashH([]byte("other")).String())
+	assert.Equal(t, ErrInclusionProofFailed, err)
+
+	// a tampered tx list
+	block.TxHashes = append([]string{}, txHashes...)
+	block.TxHashes[4] = common.HashH([]byte("tampered")).String()
+	err = client.VerifyTxInclusion(block.TxHashes[4])
+	assert.Equal(t, ErrInclusionProofFailed, err)
+	err = client.VerifyTxInclusion(txHashes[0])
+	assert.Equal(t, ErrInclusionProofFailed, err)
+
+	// a tampered tx root
+	block.TxHashes = txHashes
+	block.TxRoot = common.HashH([]byte("root")).String()
+	err = client.VerifyTxInclusion(txHashes[0])
+	assert.Equal(t, ErrInclusionProofFailed, err)
+
+	// a tx in the mempool cannot be verified
+	err = client.VerifyTxInclusion(mempoolTxHash)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestIncClient_GetTxs_InclusionVerification(t *testing.T) {
+	senderWallet, err := wallet.GenRandomWalletForShardID(0)
+	if err != nil {
+		panic(err)
+	}
+	privateKey := senderWallet.Base58CheckSerialize(wallet.PrivateKeyType)
+	receiver := PrivateKeyToPaymentAddress(privateKey, -1)
+
+	// build a few transactions to be served by the node.
+	coinServer := newMockCoinServer()
+	err = coinServer.addCoins(senderWallet.KeySet.PaymentAddress, 5)
+	if err != nil {
+		panic(err)
+	}
+	coinTs := httptest.NewServer(coinServer)
+	defer coinTs.Close()
+	txBuilder := newIncClient(rpc.NewRPCServer(coinTs.URL), nil, nil, 2)
+	txBuilder.SetCoinStore(NewMemCoinStore())
+	encodedTxs := make(map[string]string)
+	txHashes := make([]string, 0)
+	leaves := make([]common.Hash, 0)
+	for i := 0; i < 3; i++ {
+		encodedTx, txHash, err := txBuilder.CreateRawTransactionVer2(NewTxParam(privateKey, []string{receiver}, []uint64{1000}, 100, nil, nil, nil))
+		assert.Equal(t, nil, err, fmt.Errorf("CreateRawTransactionVer2 error: %v", err))
+		encodedTxs[txHash] = string(encodedTx)
+		txHashes = append(txHashes, txHash)
+		tmpHash, _ := common.Hash{}.NewHashFromStr(txHash)
+		leaves = append(leaves, *tmpHash)
+	}
+
+	blockHash := common.HashH([]byte("block")).String()
+	block := jsonresult.ShardBlockResult{
+		Hash:     blockHash,
+		Height:   100,
+		TxRoot:   computeMerkleRoot(leaves).String(),
+		TxHashes: txHashes,
+	}
+	numBlockRequests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string
+			Params []json.RawMessage
+		}
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil || (req.Method != "getrawmempool" && len(req.Params) < 1) {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+
+		var result interface{}
+		switch req.Method {
+		case "getrawmempool":
+			result = map[string][]string{"TxHashes": txHashes}
+		case "getencodedtransactionsbyhashes":
+			var params struct {
+				TxHashList []string
+			}
+			_ = json.Unmarshal(req.Params[0], &params)
+			res := make(map[string]string)
+			for _, txHash := range params.TxHashList {
+				res[txHash] = encodedTxs[txHash]
+			}
+			result = res
+		case "gettransactionbyhash":
+			var txHash string
+			_ = json.Unmarshal(req.Params[0], &txHash)
+			result = jsonresult.TransactionDetail{Hash: txHash, BlockHash: blockHash, IsInBlock: true}
+		case "retrieveblock":
+			numBlockRequests++
+			result = block
+		default:
+			http.Error(w, fmt.Sprintf("method %v not supported", req.Method), http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"Result": result})
+	}))
+	defer ts.Close()
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+	client.SetInclusionVerification(true)
+
+	txs, err := client.GetTxs(txHashes)
+	assert.Equal(t, nil, err, fmt.Errorf("GetTxs error: %v", err))
+	assert.Equal(t, len(txHashes), len(txs))
+	for _, txHash := range txHashes {
+		assert.Equal(t, txHash, txs[txHash].Hash().String())
+	}
+	assert.Equal(t, 1, numBlockRequests)
+
+	// a tx served under another hash
+	encodedTxs[txHashes[0]], encodedTxs[txHashes[1]] = encodedTxs[txHashes[1]], encodedTxs[txHashes[0]]
+	_, err = client.GetTxs(txHashes[:2])
+	assert.Equal(t, ErrInclusionProofFailed, err)
+	encodedTxs[txHashes[0]], encodedTxs[txHashes[1]] = encodedTxs[txHashes[1]], encodedTxs[txHashes[0]]
+
+	// a tx not in the block of the node
+	block.TxHashes = txHashes[1:]
+	block.TxRoot = computeMerkleRoot(leaves[1:]).String()
+	_, err = client.GetTxs(txHashes)
+	assert.Equal(t, ErrInclusionProofFailed, err)
+
+	// a tampered tx root
+	block.TxHashes = txHashes
+	block.TxRoot = common.HashH([]byte("root")).String()
+	_, err = client.GetTxs(txHashes[2:])
+	assert.Equal(t, ErrInclusionProofFailed, err)
+
+	// pending transactions are not subject to the verification.
+	pendingTxs, err := client.GetPendingTransactions()
+	assert.Equal(t, nil, err, fmt.Errorf("GetPendingTransactions error: %v", err))
+	assert.Equal(t, len(txHashes), len(pendingTxs))
+
+	// the verification is disabled.
+	client.SetInclusionVerification(false)
+	txs, err = client.GetTxs(txHashes)
+	assert.Equal(t, nil, err, fmt.Errorf("GetTxs error: %v", err))
+	assert.Equal(t, len(txHashes), len(txs))
+}
@@ -0,0 +1,72 @@
+package incclient
+
+import (
+	"fmt"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"github.com/incognitochain/go-incognito-sdk-v2/common/base58"
+	"github.com/incognitochain/go-incognito-sdk-v2/transaction/tx_generic"
+	"github.com/incognitochain/go-incognito-sdk-v2/wallet"
+)
+
+// MessageSigningTag is the domain-separation tag prepended to every message signed with SignMessage. It makes sure a
+// signed message can never be replayed as a transaction signature (or in another protocol), and vice versa.
+const MessageSigningTag = "Incognito Signed Message:\n"
+
+// hashMessageForSigning returns the hash actually signed for a message.
+func hashMessageForSigning(message []byte) []byte {
+	data := make([]byte, 0, len(MessageSigningTag)+len(message))
+	data = append(data, []byte(MessageSigningTag)...)
+	data = append(data, message...)
+
+	return common.HashB(data)
+}
+
+// SignMessage signs an arbitrary message (e.g, for off-chain authentication) with the given private key, using the
+// same Schnorr scheme as non-private transactions. The message is prefixed with MessageSigningTag before signing.
+//
+// The signature can be verified with VerifyMessage against the public key of the private key.
+func SignMessage(privateKey string, message []byte) ([]byte, error) {
+	w, err := wallet.Base58CheckDeserialize(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(w.KeySet.PrivateKey) != common.PrivateKeySize {
+		return nil, fmt.Errorf("privateKey is invalid")
+	}
+
+	sig, _, err := tx_generic.SignNoPrivacy(&w.KeySet.PrivateKey, hashMessageForSigning(message))
+	if err != nil {
+		return nil, err
+	}
+
+	return sig, nil
+}
+
+// VerifyMessage verifies a signature created by SignMessage. The publicKey is either a base58-encoded public key
+// (as in KeyInfo.PublicKey) or a payment address.
+func VerifyMessage(publicKey string, message, sig []byte) (bool, error) {
+	pubKey, err := parsePublicKeyForVerification(publicKey)
+	if err != nil {
+		return false, err
+	}
+
+	return tx_generic.VerifySigNoPrivacy(sig, pubKey, hashMessageForSigning(message))
+}
+
+func parsePublicKeyForVerification(publicKey string) ([]byte, error) {
+	pubKey, _, err := base58.Base58Check{}.Decode(publicKey)
+	if err == nil && len(pubKey) == common.PublicKeySize {
+		return pubKey, nil
+	}
+
+	w, err := wallet.Base58CheckDeserialize(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("publicKey %v is neither a public key nor a payment address", publicKey)
+	}
+	if len(w.KeySet.PaymentAddress.Pk) != common.PublicKeySize {
+		return nil, fmt.Errorf("publicKey %v is neither a public key nor a payment address", publicKey)
+	}
+
+	return w.KeySet.PaymentAddress.Pk, nil
+}
@@ -0,0 +1,69 @@
+package incclient
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"github.com/incognitochain/go-incognito-sdk-v2/common/base58"
+	"github.com/incognitochain/go-incognito-sdk-v2/transaction/tx_generic"
+	"github.com/incognitochain/go-incognito-sdk-v2/wallet"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignMessage(t *testing.T) {
+	for i := 0; i < numTests; i++ {
+		w, err := wallet.GenRandomWalletForShardID(byte(common.RandInt() % common.MaxShardNumber))
+		if err != nil {
+			panic(err)
+		}
+		privateKey, err := w.GetPrivateKey()
+		if err != nil {
+			panic(err)
+		}
+		keyInfo, err := GetAccountInfoFromPrivateKey(privateKey)
+		if err != nil {
+			panic(err)
+		}
+
+		otherWallet, err := wallet.GenRandomWalletForShardID(byte(common.RandInt() % common.MaxShardNumber))
+		if err != nil {
+			panic(err)
+		}
+		otherPublicKey := base58.Base58Check{}.Encode(otherWallet.KeySet.PaymentAddress.Pk, common.ZeroByte)
+
+		message := []byte(fmt.Sprintf("login nonce %v", common.RandChars(16)))
+		sig, err := SignMessage(privateKey, message)
+		assert.Equal(t, nil, err, fmt.Errorf("SignMessage error: %v", err))
+
+		// round-trip with either the public key or the payment address
+		isValid, err := VerifyMessage(keyInfo.PublicKey, message, sig)
+		assert.Equal(t, nil, err, fmt.Errorf("VerifyMessage error: %v", err))
+		assert.Equal(t, true, isValid)
+		isValid, err = VerifyMessage(keyInfo.PaymentAddress, message, sig)
+		assert.Equal(t, nil, err, fmt.Errorf("VerifyMessage error: %v", err))
+		assert.Equal(t, true, isValid)
+
+		// wrong key
+		isValid, err = VerifyMessage(otherPublicKey, message, sig)
+		assert.Equal(t, nil, err, fmt.Errorf("VerifyMessage error: %v", err))
+		assert.Equal(t, false, isValid)
+
+		// wrong message
+		isValid, err = VerifyMessage(keyInfo.PublicKey, append(message, 0), sig)
+		assert.Equal(t, nil, err, fmt.Errorf("VerifyMessage error: %v", err))
+		assert.Equal(t, false, isValid)
+
+		// a raw (untagged) signature over the same message is rejected
+		rawSig, _, err := tx_generic.SignNoPrivacy(&w.KeySet.PrivateKey, common.HashB(message))
+		assert.Equal(t, nil, err)
+		isValid, err = VerifyMessage(keyInfo.PublicKey, message, rawSig)
+		assert.Equal(t, nil, err, fmt.Errorf("VerifyMessage error: %v", err))
+		assert.Equal(t, false, isValid)
+	}
+
+	_, err := SignMessage("invalid", []byte("hello"))
+	assert.NotEqual(t, nil, err)
+	_, err = VerifyMessage("invalid", []byte("hello"), nil)
+	assert.NotEqual(t, nil, err)
+}
@@ -0,0 +1,112 @@
+package incclient
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/coin"
+	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"github.com/incognitochain/go-incognito-sdk-v2/crypto"
+	"github.com/incognitochain/go-incognito-sdk-v2/key"
+	"github.com/incognitochain/go-incognito-sdk-v2/wallet"
+)
+
+// otaReceiverDomain separates the hashes used to derive OTA receivers from other hashes.
+const otaReceiverDomain = "IncognitoOTAReceiver"
+
+// MinOTAReceiverSeedSize is the minimum size (in bytes) of the secret seed used to derive OTA receivers.
+const MinOTAReceiverSeedSize = 16
+
+// GenerateOTAReceiver deterministically derives the base58-encoded OTAReceiver of the given index for a payment address,
+// i.e, the same (paymentAddress, index, seed) always result in the same receiver. Receivers of different indices are
+// different one-time addresses of the same payment address, and can be shared to receive (transfer) payments.
+//
+// The seed must be kept secret and be at least MinOTAReceiverSeedSize bytes long (e.g, 32 random bytes, or a hash of
+// the private OTA key): anyone knowing it and the payment address can re-derive the receivers and link them to the
+// payment address. The payment address alone is not enough to do so.
+func GenerateOTAReceiver(paymentAddress string, index uint64, seed []byte) (string, error) {
+	receivers, err := GenerateOTAReceiverRange(paymentAddress, index, 1, seed)
+	if err != nil {
+		return "", err
+	}
+
+	return receivers[0], nil
+}
+
+// GenerateOTAReceiverRange deterministically derives the base58-encoded OTAReceivers of indices
+// startIndex, startIndex+1, ..., startIndex+count-1 for a payment address (see GenerateOTAReceiver).
+// The receivers are derived concurrently, and the i-th returned receiver is the one of index startIndex+i.
+func GenerateOTAReceiverRange(paymentAddress string, startIndex, count uint64, seed []byte) ([]string, error) {
+	keyWallet, err := wallet.Base58CheckDeserialize(paymentAddress)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payment address %v: %v", paymentAddress, err)
+	}
+	addr := keyWallet.KeySet.PaymentAddress
+	if len(addr.Pk) == 0 || addr.GetOTAPublicKey() == nil {
+		return nil, fmt.Errorf("%v is not a payment address v2", paymentAddress)
+	}
+	if count > math.MaxUint64-startIndex {
+		return nil, fmt.Errorf("index range overflows: startIndex %v, count %v", startIndex, count)
+	}
+	if len(seed) < MinOTAReceiverSeedSize {
+		return nil, fmt.Errorf("seed too short: expect at least %v bytes, got %v", MinOTAReceiverSeedSize, len(seed))
+	}
+
+	baseSeed := []byte(otaReceiverDomain)
+	baseSeed = append(baseSeed, addr.Pk...)
+	baseSeed = append(baseSeed, addr.Tk...)
+	baseSeed = append(baseSeed, addr.OTAPublic...)
+	baseSeed = common.HashB(append(baseSeed, seed...))
+
+	res := make([]string, count)
+	errs := make([]error, count)
+	jobs := make(chan uint64)
+	var wg sync.WaitGroup
+	numWorkers := MaxGetCoinThreads
+	if count < uint64(numWorkers) {
+		numWorkers = int(count)
+	}
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for offset := range jobs {
+				res[offset], errs[offset] = deriveOTAReceiver(addr, baseSeed, startIndex+offset)
+			}
+		}()
+	}
+	for offset := uint64(0); offset < count; offset++ {
+		jobs <- offset
+	}
+	close(jobs)
+	wg.Wait()
+
+	for offset, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("cannot derive the OTAReceiver of index %v: %v", startIndex+uint64(offset), err)
+		}
+	}
+
+	return res, nil
+}
+
+// deriveOTAReceiver derives the base58-encoded OTAReceiver of an index from a base seed.
+func deriveOTAReceiver(addr key.PaymentAddress, baseSeed []byte, index uint64) (string, error) {
+	data := make([]byte, len(baseSeed)+9)
+	copy(data, baseSeed)
+	binary.BigEndian.PutUint64(data[len(baseSeed):], index)
+	otaRand := crypto.HashToScalar(data)
+	data[len(data)-1] = 1
+	concealRand := crypto.HashToScalar(data)
+
+	paymentInfo := key.InitPaymentInfo(addr, 0, []byte{})
+	receiver := new(coin.OTAReceiver)
+	err := receiver.FromCoinParamsWithRandomness(coin.NewTransferCoinParams(paymentInfo), otaRand, concealRand)
+	if err != nil {
+		return "", err
+	}
+
+	return receiver.String()
+}
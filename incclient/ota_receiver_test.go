@@ -0,0 +1,118 @@
+package incclient
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/coin"
+	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"github.com/incognitochain/go-incognito-sdk-v2/crypto"
+	"github.com/incognitochain/go-incognito-sdk-v2/wallet"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateOTAReceiverRange(t *testing.T) {
+	for i := 0; i < numTests; i++ {
+		w, err := wallet.GenRandomWalletForShardID(byte(common.RandInt() % common.MaxShardNumber))
+		if err != nil {
+			panic(err)
+		}
+		addr := w.Base58CheckSerialize(wallet.PaymentAddressType)
+		shardID := common.GetShardIDFromLastByte(w.KeySet.PaymentAddress.Pk[len(w.KeySet.PaymentAddress.Pk)-1])
+
+		seed := common.RandBytes(32)
+		startIndex := common.RandUint64() % 1000000
+		count := uint64(20)
+		receivers, err := GenerateOTAReceiverRange(addr, startIndex, count, seed)
+		assert.Equal(t, nil, err, fmt.Errorf("GenerateOTAReceiverRange error: %v", err))
+		assert.Equal(t, int(count), len(receivers))
+
+		seen := make(map[string]bool)
+		for j, receiverStr := range receivers {
+			// the range matches individually-derived receivers.
+			tmpReceiver, err := GenerateOTAReceiver(addr, startIndex+uint64(j), seed)
+			assert.Equal(t, nil, err, fmt.Errorf("GenerateOTAReceiver error: %v", err))
+			assert.Equal(t, receiverStr, tmpReceiver)
+			assert.Equal(t, false, seen[receiverStr], "duplicate receiver")
+			seen[receiverStr] = true
+
+			// the receiver is a valid one-time address of the payment address.
+			receiver := new(coin.OTAReceiver)
+			err = receiver.FromString(receiverStr)
+			assert.Equal(t, nil, err, fmt.Errorf("FromString error: %v", err))
+			assert.Equal(t, true, receiver.IsValid())
+
+			otaRandomPoint, err := receiver.TxRandom.GetTxOTARandomPoint()
+			assert.Equal(t, nil, err)
+			index, err := receiver.TxRandom.GetIndex()
+			assert.Equal(t, nil, err)
+			rK := new(crypto.Point).ScalarMult(otaRandomPoint, w.KeySet.OTAKey.GetOTASecretKey())
+			hash := crypto.HashToScalar(append(rK.ToBytesS(), common.Uint32ToBytes(index)...))
+			expectedPublicKey := new(crypto.Point).Add(new(crypto.Point).ScalarMultBase(hash), w.KeySet.OTAKey.GetPublicSpend())
+			assert.Equal(t, true, crypto.IsPointEqual(expectedPublicKey, &receiver.PublicKey), "receiver does not belong to the payment address")
+
+			senderShardID, receiverShardID, coinType, err := coin.DeriveShardInfoFromCoin(receiver.PublicKey.ToBytesS())
+			assert.Equal(t, nil, err)
+			assert.Equal(t, int(shardID), receiverShardID)
+			assert.Equal(t, int(shardID), senderShardID)
+			assert.Equal(t, coin.PrivacyTypeTransfer, coinType)
+		}
+
+		// another seed results in different receivers.
+		otherReceivers, err := GenerateOTAReceiverRange(addr, startIndex, count, common.RandBytes(32))
+		assert.Equal(t, nil, err, fmt.Errorf("GenerateOTAReceiverRange error: %v", err))
+		for _, receiverStr := range otherReceivers {
+			assert.Equal(t, false, seen[receiverStr])
+		}
+
+		// a third party knowing only the payment address cannot re-derive the receivers: without a seed (or with a
+		// short one) the derivation is refused, and the base seed computable from public data results in different
+		// receivers.
+		_, err = GenerateOTAReceiverRange(addr, startIndex, count, nil)
+		assert.NotEqual(t, nil, err)
+		_, err = GenerateOTAReceiverRange(addr, startIndex, count, seed[:MinOTAReceiverSeedSize-1])
+		assert.NotEqual(t, nil, err)
+		paymentAddress := w.KeySet.PaymentAddress
+		publicSeed := []byte(otaReceiverDomain)
+		publicSeed = append(publicSeed, paymentAddress.Pk...)
+		publicSeed = append(publicSeed, paymentAddress.Tk...)
+		publicSeed = append(publicSeed, paymentAddress.OTAPublic...)
+		publicSeed = common.HashB(publicSeed)
+		for j := uint64(0); j < count; j++ {
+			receiverStr, err := deriveOTAReceiver(paymentAddress, publicSeed, startIndex+j)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, false, seen[receiverStr], "receiver derived from public data")
+		}
+	}
+
+	// invalid inputs
+	seed := common.RandBytes(32)
+	_, err := GenerateOTAReceiverRange("abc", 0, 10, seed)
+	assert.NotEqual(t, nil, err)
+	w, err := wallet.GenRandomWalletForShardID(0)
+	if err != nil {
+		panic(err)
+	}
+	_, err = GenerateOTAReceiverRange(w.Base58CheckSerialize(wallet.PaymentAddressType), ^uint64(0), 2, seed)
+	assert.NotEqual(t, nil, err)
+	receivers, err := GenerateOTAReceiverRange(w.Base58CheckSerialize(wallet.PaymentAddressType), 0, 0, seed)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(receivers))
+}
+
+func BenchmarkGenerateOTAReceiverRange(b *testing.B) {
+	w, err := wallet.GenRandomWalletForShardID(0)
+	if err != nil {
+		panic(err)
+	}
+	addr := w.Base58CheckSerialize(wallet.PaymentAddressType)
+	seed := common.RandBytes(32)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err = GenerateOTAReceiverRange(addr, uint64(i)*10000, 10000, seed)
+		if err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package incclient
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"github.com/incognitochain/go-incognito-sdk-v2/wallet"
+)
+
+// PaymentURIScheme is the URI scheme of an Incognito payment request.
+const PaymentURIScheme = "incognito"
+
+// query parameters of a payment-request URI.
+const (
+	paymentURIAmountParam  = "amount"
+	paymentURITokenIDParam = "tokenID"
+	paymentURIMessageParam = "message"
+)
+
+// validatePaymentURIAddress checks if the given string is a valid payment address.
+func validatePaymentURIAddress(paymentAddress string) error {
+	w, err := wallet.Base58CheckDeserialize(paymentAddress)
+	if err != nil {
+		return fmt.Errorf("invalid payment address %v: %v", paymentAddress, err)
+	}
+	addr := w.KeySet.PaymentAddress
+	if len(addr.Pk) == 0 || len(addr.Tk) == 0 {
+		return fmt.Errorf("%v is not a payment address", paymentAddress)
+	}
+
+	return nil
+}
+
+// validatePaymentURITokenID checks if the given string is a valid (hex-encoded) tokenID.
+func validatePaymentURITokenID(tokenID string) error {
+	tokenIDBytes, err := hex.DecodeString(tokenID)
+	if err != nil || len(tokenIDBytes) != common.HashSize {
+		return fmt.Errorf("invalid tokenID %v", tokenID)
+	}
+
+	return nil
+}
+
+// BuildPaymentURI creates a shareable payment-request URI of the form
+//
+//	incognito:<paymentAddress>?amount=<amount>&tokenID=<tokenID>&message=<message>
+//
+// where all query parameters are optional: a zero amount, an empty tokenID or an empty message is omitted from the URI.
+// An omitted tokenID means the payment is requested in PRV.
+func BuildPaymentURI(paymentAddress string, amount uint64, tokenID, message string) (string, error) {
+	if err := validatePaymentURIAddress(paymentAddress); err != nil {
+		return "", err
+	}
+
+	params := url.Values{}
+	if amount > 0 {
+		params.Set(paymentURIAmountParam, strconv.FormatUint(amount, 10))
+	}
+	if tokenID != "" {
+		if err := validatePaymentURITokenID(tokenID); err != nil {
+			return "", err
+		}
+		params.Set(paymentURITokenIDParam, tokenID)
+	}
+	if message != "" {
+		params.Set(paymentURIMessageParam, message)
+	}
+
+	u := url.URL{Scheme: PaymentURIScheme, Opaque: paymentAddress, RawQuery: params.Encode()}
+	return u.String(), nil
+}
+
+// ParsePaymentURI decodes a payment-request URI created by BuildPaymentURI. Omitted parameters are returned as their
+// zero values (i.e, an empty tokenID stands for PRV).
+func ParsePaymentURI(uri string) (address string, amount uint64, tokenID, message string, err error) {
+	u, err := url.Parse(strings.TrimSpace(uri))
+	if err != nil {
+		return "", 0, "", "", fmt.Errorf("cannot parse payment URI: %v", err)
+	}
+	if u.Scheme != PaymentURIScheme {
+		return "", 0, "", "", fmt.Errorf("expected scheme %v, got %v", PaymentURIScheme, u.Scheme)
+	}
+
+	address = u.Opaque
+	if err = validatePaymentURIAddress(address); err != nil {
+		return "", 0, "", "", err
+	}
+
+	params, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return "", 0, "", "", fmt.Errorf("cannot parse payment URI query: %v", err)
+	}
+	for param := range params {
+		switch param {
+		case paymentURIAmountParam, paymentURITokenIDParam, paymentURIMessageParam:
+		default:
+			return "", 0, "", "", fmt.Errorf("unknown payment URI parameter %v", param)
+		}
+	}
+
+	if amountStr := params.Get(paymentURIAmountParam); amountStr != "" {
+		amount, err = strconv.ParseUint(amountStr, 10, 64)
+		if err != nil {
+			return "", 0, "", "", fmt.Errorf("invalid amount %v: %v", amountStr, err)
+		}
+		if amount == 0 {
+			return "", 0, "", "", fmt.Errorf("amount must be positive")
+		}
+	}
+
+	tokenID = params.Get(paymentURITokenIDParam)
+	if tokenID != "" {
+		if err = validatePaymentURITokenID(tokenID); err != nil {
+			return "", 0, "", "", err
+		}
+	}
+	message = params.Get(paymentURIMessageParam)
+
+	return address, amount, tokenID, message, nil
+}
@@ -0,0 +1,61 @@
+package incclient
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"github.com/incognitochain/go-incognito-sdk-v2/wallet"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildPaymentURI(t *testing.T) {
+	for i := 0; i < numTests; i++ {
+		w, err := wallet.GenRandomWalletForShardID(byte(common.RandInt() % common.MaxShardNumber))
+		if err != nil {
+			panic(err)
+		}
+		addr := w.Base58CheckSerialize(wallet.PaymentAddressType)
+
+		testCases := []struct {
+			amount           uint64
+			tokenID, message string
+		}{
+			{common.RandUint64()%1e12 + 1, common.HashH(common.RandBytes(32)).String(), "invoice #" + common.RandChars(8) + " & more?"},
+			{1000, "", ""},
+			{0, common.PRVIDStr, ""},
+			{0, "", "thanks!"},
+			{0, "", ""},
+		}
+		for _, tc := range testCases {
+			uri, err := BuildPaymentURI(addr, tc.amount, tc.tokenID, tc.message)
+			assert.Equal(t, nil, err, fmt.Errorf("BuildPaymentURI error: %v", err))
+
+			gotAddr, gotAmount, gotTokenID, gotMessage, err := ParsePaymentURI(uri)
+			assert.Equal(t, nil, err, fmt.Errorf("ParsePaymentURI(%v) error: %v", uri, err))
+			assert.Equal(t, addr, gotAddr)
+			assert.Equal(t, tc.amount, gotAmount)
+			assert.Equal(t, tc.tokenID, gotTokenID)
+			assert.Equal(t, tc.message, gotMessage)
+		}
+
+		// invalid inputs
+		_, err = BuildPaymentURI("abc", 10, "", "")
+		assert.NotEqual(t, nil, err)
+		_, err = BuildPaymentURI(addr, 10, "abc", "")
+		assert.NotEqual(t, nil, err)
+
+		invalidURIs := []string{
+			"bitcoin:" + addr + "?amount=10",
+			PaymentURIScheme + ":abc?amount=10",
+			PaymentURIScheme + ":" + addr + "?amount=-10",
+			PaymentURIScheme + ":" + addr + "?amount=0",
+			PaymentURIScheme + ":" + addr + "?tokenID=xyz",
+			PaymentURIScheme + ":" + addr + "?foo=bar",
+		}
+		for _, uri := range invalidURIs {
+			_, _, _, _, err = ParsePaymentURI(uri)
+			assert.NotEqual(t, nil, err, fmt.Errorf("expected an error for %v", uri))
+		}
+	}
+}
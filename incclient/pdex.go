@@ -7,7 +7,7 @@ import (
 	"github.com/incognitochain/go-incognito-sdk-v2/key"
 	"strings"
 
-	// "github.com/incognitochain/go-incognito-sdk-v2/metadata"
+	"github.com/incognitochain/go-incognito-sdk-v2/metadata"
 	metadataCommon "github.com/incognitochain/go-incognito-sdk-v2/metadata/common"
 	metadataPdexv3 "github.com/incognitochain/go-incognito-sdk-v2/metadata/pdexv3"
 	"github.com/incognitochain/go-incognito-sdk-v2/wallet"
@@ -116,6 +116,25 @@ func (client *IncClient) CreatePdexv3Trade(privateKey string, tradePath []string
 
 }
 
+// CreatePdexv3TradeWithSlippage is the same as CreatePdexv3Trade, except that the minimum acceptable amount of the
+// trade is derived from the expected output of each hop of tradePath (see QuoteTradePath) and a slippage tolerance in
+// BPS, following the compounding model of CalcPathMinOutput.
+//
+// It returns the base58-encoded transaction, the transaction's hash, and an error (if any).
+func (client *IncClient) CreatePdexv3TradeWithSlippage(privateKey string, tradePath []string, tokenIDToSellStr,
+	tokenIDToBuyStr string, amount uint64, expectedPerHop []uint64, slippageBps, tradingFee uint64, feeInPRV bool,
+) ([]byte, string, error) {
+	if len(expectedPerHop) != len(tradePath) {
+		return nil, "", fmt.Errorf("expected %v per-hop outputs, got %v", len(tradePath), len(expectedPerHop))
+	}
+	minAccept := CalcPathMinOutput(expectedPerHop, slippageBps)
+	if minAccept == 0 {
+		return nil, "", fmt.Errorf("minimum acceptable amount is zero (expected %v, slippage %v BPS)", expectedPerHop, slippageBps)
+	}
+
+	return client.CreatePdexv3Trade(privateKey, tradePath, tokenIDToSellStr, tokenIDToBuyStr, amount, minAccept, tradingFee, feeInPRV)
+}
+
 // CreateAndSendPdexv3TradeTransaction creates a trading transaction (version 2 only), and submits it to the Incognito network.
 //
 // It returns the transaction's hash, and an error (if any).
@@ -142,6 +161,101 @@ func (client *IncClient) CreateAndSendPdexv3TradeTransaction(privateKey string,
 	return txHash, nil
 }
 
+// CreatePDETrade creates a cross-pool trading transaction of the pDEX prior to pDEX v3. The trading fee is paid in PRV.
+//
+// It returns the base58-encoded transaction, the transaction's hash, and an error (if any).
+func (client *IncClient) CreatePDETrade(privateKey, tokenIDToSellStr, tokenIDToBuyStr string, amount, expectedBuy, tradingFee uint64) ([]byte, string, error) {
+	senderWallet, err := wallet.Base58CheckDeserialize(privateKey)
+	if err != nil {
+		return nil, "", err
+	}
+	addr := senderWallet.Base58CheckSerialize(wallet.PaymentAddressType)
+
+	_, err = common.Hash{}.NewHashFromStr(tokenIDToSellStr)
+	if err != nil {
+		return nil, "", err
+	}
+	_, err = common.Hash{}.NewHashFromStr(tokenIDToBuyStr)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// create one-time receivers for the bought tokens and the refunds
+	traderOTA, traderTxRandom, err := GenerateOTAFromPaymentAddress(addr, coin.PrivacyTypeMint)
+	if err != nil {
+		return nil, "", err
+	}
+	subTraderOTA, subTraderTxRandom, err := GenerateOTAFromPaymentAddress(addr, coin.PrivacyTypeMint)
+	if err != nil {
+		return nil, "", err
+	}
+
+	md, err := metadata.NewPDECrossPoolTradeRequest(
+		tokenIDToBuyStr, tokenIDToSellStr, amount,
+		expectedBuy, tradingFee, traderOTA, traderTxRandom,
+		subTraderOTA, subTraderTxRandom, metadata.PDECrossPoolTradeRequestMeta,
+	)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if tokenIDToSellStr == common.PRVIDStr {
+		txParam := NewTxParam(privateKey, []string{common.BurningAddress2}, []uint64{amount + tradingFee}, 0, nil, md, nil)
+		return client.CreateRawTransaction(txParam, 2)
+	}
+
+	receivers, amounts := []string{}, []uint64{}
+	if tradingFee > 0 {
+		receivers, amounts = []string{common.BurningAddress2}, []uint64{tradingFee}
+	}
+	tokenParam := NewTxTokenParam(tokenIDToSellStr, 1, []string{common.BurningAddress2}, []uint64{amount}, false, 0, nil)
+	txParam := NewTxParam(privateKey, receivers, amounts, 0, tokenParam, md, nil)
+	return client.CreateRawTokenTransaction(txParam, 2)
+}
+
+// CreatePdexTrade creates a trading transaction for the pDEX version active on the network: a pDEX v3 trade
+// (see CreatePdexv3Trade) if FeaturePdexV3 is active, a cross-pool trade (see CreatePDETrade) otherwise. The trading
+// path and feeInPRV are only used by pDEX v3 trades.
+//
+// It returns the base58-encoded transaction, the transaction's hash, and an error (if any).
+func (client *IncClient) CreatePdexTrade(privateKey string, tradePath []string, tokenIDToSellStr,
+	tokenIDToBuyStr string, amount uint64, expectedBuy, tradingFee uint64, feeInPRV bool,
+) ([]byte, string, error) {
+	features, err := client.GetActiveFeatures()
+	if err != nil {
+		return nil, "", err
+	}
+
+	if features[FeaturePdexV3] {
+		return client.CreatePdexv3Trade(privateKey, tradePath, tokenIDToSellStr, tokenIDToBuyStr, amount, expectedBuy, tradingFee, feeInPRV)
+	}
+	return client.CreatePDETrade(privateKey, tokenIDToSellStr, tokenIDToBuyStr, amount, expectedBuy, tradingFee)
+}
+
+// CreateAndSendPdexTradeTransaction creates a trading transaction for the pDEX version active on the network
+// (see CreatePdexTrade), and submits it to the Incognito network.
+//
+// It returns the transaction's hash, and an error (if any).
+func (client *IncClient) CreateAndSendPdexTradeTransaction(privateKey string, tradePath []string, tokenIDToSellStr, tokenIDToBuyStr string, amount uint64,
+	expectedBuy, tradingFee uint64, feeInPRV bool,
+) (string, error) {
+	encodedTx, txHash, err := client.CreatePdexTrade(privateKey, tradePath, tokenIDToSellStr, tokenIDToBuyStr, amount, expectedBuy, tradingFee, feeInPRV)
+	if err != nil {
+		return "", err
+	}
+
+	if tokenIDToSellStr == common.PRVIDStr {
+		err = client.SendRawTx(encodedTx)
+	} else {
+		err = client.SendRawTokenTx(encodedTx)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return txHash, nil
+}
+
 // CreatePdexv3AddOrder creates a transaction that adds a new order in pdex v3.
 //
 // It returns the base58-encoded transaction, the transaction's hash, and an error (if any).
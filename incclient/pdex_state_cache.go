@@ -0,0 +1,145 @@
+package incclient
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/jsonresult"
+)
+
+// pdexStateCache keeps the latest pDEX state retrieved by an IncClient, and serves it in a stale-while-revalidate
+// manner: a cached state younger than maxAge is returned immediately and, once older than half of maxAge, a fresher one
+// is retrieved in the background.
+type pdexStateCache struct {
+	mtx    *sync.Mutex
+	maxAge time.Duration
+	state  *jsonresult.CurrentPdexState
+
+	// the time the request of the cached state was sent, i.e, the age of the state is measured from it
+	updatedAt time.Time
+
+	// incremented every time the cache is invalidated, so that a request sent before is not stored
+	generation uint64
+
+	refreshing bool
+
+	// the number of consecutive failed background refreshes, and the time before which none is retried
+	numFailures   int
+	nextRefreshAt time.Time
+
+	// now returns the current time; it is replaced in tests
+	now func() time.Time
+
+	// wg waits for the background refreshes; it is used in tests
+	wg *sync.WaitGroup
+}
+
+func newPdexStateCache() *pdexStateCache {
+	return &pdexStateCache{mtx: new(sync.Mutex), now: time.Now, wg: new(sync.WaitGroup)}
+}
+
+// SetPdexStateMaxAge enables the stale-while-revalidate cache of the latest pDEX state, and invalidates the current
+// cached value. A non-positive maxAge disables the cache (the default).
+//
+// When enabled, GetPdexState(0) returns the cached state immediately if it was retrieved less than maxAge ago. Once the
+// state is older than half of maxAge, the call also triggers a refresh in the background; callers trade a slight
+// staleness (a few beacon blocks at most) for a lower latency. A state older than maxAge is never returned: the call
+// then waits for a fresh one.
+//
+// If a background refresh fails with a transient error (see rpchandler.IsTransient), it is retried on a later call,
+// after a delay that doubles with each consecutive failure. Otherwise, no background refresh is attempted until the
+// cached state expires, and the error is returned by the call waiting for a fresh state.
+func (client *IncClient) SetPdexStateMaxAge(maxAge time.Duration) {
+	cache := client.pdexStateCache
+	cache.mtx.Lock()
+	defer cache.mtx.Unlock()
+
+	cache.maxAge = maxAge
+	cache.state = nil
+	cache.updatedAt = time.Time{}
+	cache.generation++
+	cache.refreshing = false
+	cache.numFailures = 0
+	cache.nextRefreshAt = time.Time{}
+}
+
+// getCachedPdexState returns a copy of the cached pDEX state if it is younger than the max age of the cache, and
+// triggers a refresh in the background if the state is older than half of the max age. It also returns the current
+// generation of the cache, to be passed to updatePdexStateCache.
+func (client *IncClient) getCachedPdexState() (*jsonresult.CurrentPdexState, uint64, bool) {
+	cache := client.pdexStateCache
+	cache.mtx.Lock()
+	defer cache.mtx.Unlock()
+
+	now := cache.now()
+	if cache.maxAge <= 0 || cache.state == nil || now.Sub(cache.updatedAt) >= cache.maxAge {
+		return nil, cache.generation, false
+	}
+
+	if now.Sub(cache.updatedAt) >= cache.maxAge/2 && !cache.refreshing && !now.Before(cache.nextRefreshAt) {
+		cache.refreshing = true
+		cache.wg.Add(1)
+		go client.refreshPdexState(cache.generation, now)
+	}
+
+	return cache.state.Clone(), cache.generation, true
+}
+
+// refreshPdexState retrieves the latest pDEX state in the background, and stores it unless the cache has been
+// invalidated in the meantime.
+func (client *IncClient) refreshPdexState(generation uint64, requestedAt time.Time) {
+	cache := client.pdexStateCache
+	defer cache.wg.Done()
+
+	state, err := client.fetchPdexState(context.Background(), 0)
+
+	cache.mtx.Lock()
+	defer cache.mtx.Unlock()
+	if generation != cache.generation {
+		return
+	}
+	cache.refreshing = false
+	if err != nil {
+		Logger.Printf("cannot refresh the pDEX state: %v\n", err)
+		if !rpchandler.IsTransient(err) {
+			// do not retry before the state expires.
+			cache.nextRefreshAt = cache.updatedAt.Add(cache.maxAge)
+			return
+		}
+		backoff := cache.maxAge / 16
+		for i := 0; i < cache.numFailures && backoff < cache.maxAge/2; i++ {
+			backoff *= 2
+		}
+		cache.numFailures++
+		cache.nextRefreshAt = cache.now().Add(backoff)
+		return
+	}
+	cache.store(state, requestedAt)
+}
+
+// updatePdexStateCache stores the latest pDEX state, requested at requestedAt, if the cache is enabled and has not been
+// invalidated since generation.
+func (client *IncClient) updatePdexStateCache(state *jsonresult.CurrentPdexState, generation uint64, requestedAt time.Time) {
+	cache := client.pdexStateCache
+	cache.mtx.Lock()
+	defer cache.mtx.Unlock()
+
+	if cache.maxAge <= 0 || generation != cache.generation {
+		return
+	}
+	cache.store(state.Clone(), requestedAt)
+}
+
+// store replaces the cached state if the given one has been requested later, and resets the background refresh
+// failures. The caller must hold the lock.
+func (cache *pdexStateCache) store(state *jsonresult.CurrentPdexState, requestedAt time.Time) {
+	if cache.state != nil && !requestedAt.After(cache.updatedAt) {
+		return
+	}
+	cache.state = state
+	cache.updatedAt = requestedAt
+	cache.numFailures = 0
+	cache.nextRefreshAt = time.Time{}
+}
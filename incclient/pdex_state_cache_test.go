@@ -0,0 +1,193 @@
+package incclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/jsonresult"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIncClient_GetPdexState_StaleWhileRevalidate(t *testing.T) {
+	var mtx sync.Mutex
+	numCalls := 0
+	failStatus := 0
+	var blocked, released chan struct{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string
+		}
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil || req.Method != "pdexv3_getState" {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+
+		// every state has a different version so that callers can tell them apart.
+		mtx.Lock()
+		numCalls++
+		version := uint64(numCalls)
+		status := failStatus
+		tmpBlocked, tmpReleased := blocked, released
+		blocked, released = nil, nil
+		mtx.Unlock()
+		if tmpBlocked != nil {
+			close(tmpBlocked)
+			<-tmpReleased
+		}
+		if status != 0 {
+			http.Error(w, "failed", status)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"Result": jsonresult.CurrentPdexState{NftIDs: map[string]uint64{"version": version}},
+		})
+	}))
+	defer ts.Close()
+	client := newIncClient(rpc.NewRPCServer(ts.URL, rpc.WithRetryPolicy(rpc.NoRetryPolicy)), nil, nil, 2)
+
+	now := time.Unix(1600000000, 0)
+	client.pdexStateCache.now = func() time.Time {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return now
+	}
+	advance := func(d time.Duration) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		now = now.Add(d)
+	}
+	getNumCalls := func() int {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return numCalls
+	}
+	setFailStatus := func(status int) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		failStatus = status
+	}
+	// blockNextCall makes the next request wait until release is called; the returned channel is closed once the
+	// request has reached the node.
+	blockNextCall := func() (<-chan struct{}, func()) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		blocked, released = make(chan struct{}), make(chan struct{})
+		tmpReleased := released
+		return blocked, func() { close(tmpReleased) }
+	}
+	getVersion := func() uint64 {
+		state, err := client.GetPdexState(0)
+		assert.Equal(t, nil, err, fmt.Errorf("GetPdexState error: %v", err))
+		if state == nil {
+			return 0
+		}
+		return state.NftIDs["version"]
+	}
+
+	// the cache is disabled by default
+	assert.Equal(t, uint64(1), getVersion())
+	assert.Equal(t, uint64(2), getVersion())
+
+	maxAge := time.Minute
+	client.SetPdexStateMaxAge(maxAge)
+
+	// the first call has to wait for the node.
+	assert.Equal(t, uint64(3), getVersion())
+
+	// a state younger than half of the max age is returned without a refresh.
+	advance(10 * time.Second)
+	assert.Equal(t, uint64(3), getVersion())
+	client.pdexStateCache.wg.Wait()
+	assert.Equal(t, 3, getNumCalls())
+
+	// an older state is returned while the node has not answered the refresh yet.
+	advance(20 * time.Second)
+	reached, release := blockNextCall()
+	assert.Equal(t, uint64(3), getVersion())
+	<-reached
+	// only one refresh runs at a time.
+	assert.Equal(t, uint64(3), getVersion())
+	release()
+	client.pdexStateCache.wg.Wait()
+	assert.Equal(t, 4, getNumCalls())
+	state, err := client.GetPdexState(0)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, uint64(4), state.NftIDs["version"])
+
+	// callers cannot modify the cached state.
+	state.NftIDs["version"] = 1000
+	assert.Equal(t, uint64(4), getVersion())
+	client.pdexStateCache.wg.Wait()
+	assert.Equal(t, 4, getNumCalls())
+
+	// a refresh finishing after the cache has been invalidated is not stored.
+	advance(maxAge / 2)
+	reached, release = blockNextCall()
+	assert.Equal(t, uint64(4), getVersion())
+	<-reached
+	client.SetPdexStateMaxAge(maxAge)
+	release()
+	client.pdexStateCache.wg.Wait()
+	assert.Equal(t, uint64(6), getVersion())
+
+	// a state older than the max age is not returned.
+	advance(maxAge)
+	assert.Equal(t, uint64(7), getVersion())
+
+	// a refresh failing with a transient error is retried after a growing delay.
+	setFailStatus(http.StatusServiceUnavailable)
+	advance(maxAge / 2)
+	assert.Equal(t, uint64(7), getVersion())
+	client.pdexStateCache.wg.Wait()
+	assert.Equal(t, 8, getNumCalls())
+	assert.Equal(t, uint64(7), getVersion())
+	client.pdexStateCache.wg.Wait()
+	assert.Equal(t, 8, getNumCalls())
+	advance(maxAge / 16)
+	assert.Equal(t, uint64(7), getVersion())
+	client.pdexStateCache.wg.Wait()
+	assert.Equal(t, 9, getNumCalls())
+	advance(maxAge / 16)
+	assert.Equal(t, uint64(7), getVersion())
+	client.pdexStateCache.wg.Wait()
+	assert.Equal(t, 9, getNumCalls())
+	setFailStatus(0)
+	advance(maxAge / 16)
+	assert.Equal(t, uint64(7), getVersion())
+	client.pdexStateCache.wg.Wait()
+	assert.Equal(t, 10, getNumCalls())
+	assert.Equal(t, uint64(10), getVersion())
+
+	// a refresh failing with another error is not retried until the state expires, when the error is returned.
+	setFailStatus(http.StatusBadRequest)
+	advance(maxAge / 2)
+	assert.Equal(t, uint64(10), getVersion())
+	client.pdexStateCache.wg.Wait()
+	assert.Equal(t, 11, getNumCalls())
+	advance(maxAge / 4)
+	assert.Equal(t, uint64(10), getVersion())
+	client.pdexStateCache.wg.Wait()
+	assert.Equal(t, 11, getNumCalls())
+	advance(maxAge / 4)
+	_, err = client.GetPdexState(0)
+	assert.NotEqual(t, nil, err)
+	assert.Equal(t, 12, getNumCalls())
+	setFailStatus(0)
+
+	// states at a specific height are never cached.
+	mtx.Lock()
+	numCalls = 0
+	mtx.Unlock()
+	for i := 1; i <= 2; i++ {
+		state, err = client.GetPdexState(100)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, uint64(i), state.NftIDs["version"])
+	}
+}
@@ -1,9 +1,19 @@
 package incclient
 
 import (
+	"fmt"
+	"github.com/incognitochain/go-incognito-sdk-v2/coin"
 	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"github.com/incognitochain/go-incognito-sdk-v2/common/base58"
+	"github.com/incognitochain/go-incognito-sdk-v2/key"
+	"github.com/incognitochain/go-incognito-sdk-v2/metadata"
 	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/jsonresult"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/rpc"
+	"github.com/incognitochain/go-incognito-sdk-v2/transaction"
+	"github.com/incognitochain/go-incognito-sdk-v2/wallet"
+	"github.com/stretchr/testify/assert"
 	"math/big"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -39,3 +49,67 @@ func TestIncClient_CreateAndSendPdexv3WithdrawStakeRewardTransaction(t *testing.
 	}
 	Logger.Printf("TxHash: %v\n", txHash)
 }
+
+func TestIncClient_CreatePdexv3TradeWithSlippage(t *testing.T) {
+	senderWallet, err := wallet.GenRandomWalletForShardID(byte(common.RandInt() % common.MaxShardNumber))
+	if err != nil {
+		panic(err)
+	}
+	privateKey := senderWallet.Base58CheckSerialize(wallet.PrivateKeyType)
+
+	// the coins must cover the default transaction fee
+	coinServer := newMockCoinServer()
+	for i := 0; i < 10; i++ {
+		paymentInfo := key.PaymentInfo{PaymentAddress: senderWallet.KeySet.PaymentAddress, Amount: 1e9}
+		c, err := coin.NewCoinFromPaymentInfo(coin.NewTransferCoinParams(&paymentInfo))
+		if err != nil {
+			panic(err)
+		}
+		coinServer.coins = append(coinServer.coins, jsonresult.NewOutCoin(c))
+	}
+	ts := httptest.NewServer(coinServer)
+	defer ts.Close()
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+	client.SetCoinStore(NewMemCoinStore())
+
+	tokenB := common.HashH([]byte("tokenB")).String()
+	tokenC := common.HashH([]byte("tokenC")).String()
+	tokenD := common.HashH([]byte("tokenD")).String()
+	poolID := func(token0, token1 string) string {
+		return fmt.Sprintf("%v-%v-%v", token0, token1, common.HashH([]byte(token0+token1)).String())
+	}
+	singleHopPath := []string{poolID(common.PRVIDStr, tokenC)}
+	threeHopPath := []string{poolID(common.PRVIDStr, tokenB), poolID(tokenB, tokenC), poolID(tokenC, tokenD)}
+	slippageBps := uint64(50)
+
+	testCases := []struct {
+		tradePath      []string
+		tokenToBuy     string
+		expectedPerHop []uint64
+	}{
+		{singleHopPath, tokenC, []uint64{200000}},
+		{threeHopPath, tokenD, []uint64{7000, 3000, 200000}},
+	}
+	for _, tc := range testCases {
+		encodedTx, _, err := client.CreatePdexv3TradeWithSlippage(privateKey, tc.tradePath, common.PRVIDStr, tc.tokenToBuy,
+			1000, tc.expectedPerHop, slippageBps, 100, true)
+		assert.Equal(t, nil, err, fmt.Errorf("CreatePdexv3TradeWithSlippage error: %v", err))
+
+		rawTxBytes, _, err := base58.Base58Check{}.Decode(string(encodedTx))
+		assert.Equal(t, nil, err)
+		txChoice, err := transaction.DeserializeTransactionJSON(rawTxBytes)
+		assert.Equal(t, nil, err, fmt.Errorf("DeserializeTransactionJSON error: %v", err))
+		info, err := metadata.GetTradeRequestInfo(txChoice.ToTx().GetMetadata())
+		assert.Equal(t, nil, err, fmt.Errorf("GetTradeRequestInfo error: %v", err))
+		assert.Equal(t, CalcPathMinOutput(tc.expectedPerHop, slippageBps), info.MinAcceptableAmount)
+		assert.Equal(t, tc.tradePath, info.TradingPath)
+	}
+
+	// the per-hop outputs must match the trade path.
+	_, _, err = client.CreatePdexv3TradeWithSlippage(privateKey, threeHopPath, common.PRVIDStr, tokenC,
+		1000, []uint64{200000}, slippageBps, 100, true)
+	assert.NotEqual(t, nil, err)
+	_, _, err = client.CreatePdexv3TradeWithSlippage(privateKey, singleHopPath, common.PRVIDStr, tokenC,
+		1000, []uint64{200000}, jsonresult.BPS, 100, true)
+	assert.NotEqual(t, nil, err)
+}
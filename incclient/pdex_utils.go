@@ -1,16 +1,22 @@
 package incclient
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"io"
+	"math"
 	"math/big"
 	"sort"
 	"strings"
 
 	// "github.com/incognitochain/go-incognito-sdk-v2/common"
+	metadataPdexv3 "github.com/incognitochain/go-incognito-sdk-v2/metadata/pdexv3"
 	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler"
 	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/jsonresult"
-	// "github.com/incognitochain/go-incognito-sdk-v2/rpchandler/rpc"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/rpc"
 	"github.com/incognitochain/go-incognito-sdk-v2/wallet"
 )
 
@@ -35,12 +41,39 @@ type Share struct {
 	TokenID1Str string
 	TokenID2Str string
 	ShareAmount uint64
+	PoolID      string
 }
 
 // GetPdexState retrieves the state of pDEX at the provided beacon height.
-// If the beacon height is set to 0, it returns the latest pDEX state.
+// If the beacon height is set to 0, it returns the latest pDEX state, which may be served from a cache if enabled
+// (see SetPdexStateMaxAge).
 func (client *IncClient) GetPdexState(beaconHeight uint64) (*jsonresult.CurrentPdexState, error) {
-	responseInBytes, err := client.rpcServer.GetPdexState(beaconHeight)
+	return client.GetPdexStateWithContext(context.Background(), beaconHeight)
+}
+
+// GetPdexStateWithContext is the same as GetPdexState, except that the request is aborted when ctx is done.
+func (client *IncClient) GetPdexStateWithContext(ctx context.Context, beaconHeight uint64) (*jsonresult.CurrentPdexState, error) {
+	if beaconHeight != 0 {
+		return client.fetchPdexState(ctx, beaconHeight)
+	}
+
+	state, generation, ok := client.getCachedPdexState()
+	if ok {
+		return state, nil
+	}
+	requestedAt := client.pdexStateCache.now()
+	state, err := client.fetchPdexState(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	client.updatePdexStateCache(state, generation, requestedAt)
+
+	return state, nil
+}
+
+// fetchPdexState retrieves the state of pDEX at the provided beacon height from the remote node.
+func (client *IncClient) fetchPdexState(ctx context.Context, beaconHeight uint64) (*jsonresult.CurrentPdexState, error) {
+	responseInBytes, err := client.rpcServer.WithContext(ctx).GetPdexState(beaconHeight)
 	if err != nil {
 		return nil, err
 	}
@@ -137,6 +170,407 @@ func (client *IncClient) GetPoolShareAmount(poolID, nftID string) (uint64, error
 	return share.Amount, nil
 }
 
+// GetAllShares returns all pDEX shares of an nftID at the provided beacon height (0 for the latest pDEX state).
+// The result is sorted by (TokenID1Str, TokenID2Str), and then by PoolID for pools of the same pair, so that
+// consecutive calls on the same state return the same output.
+func (client *IncClient) GetAllShares(beaconHeight uint64, nftID string) ([]*Share, error) {
+	pools, err := client.GetAllPdexPoolPairs(beaconHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*Share, 0)
+	for poolID, pool := range pools {
+		if pool == nil {
+			continue
+		}
+		share, ok := pool.Shares[nftID]
+		if !ok || share == nil || share.Amount == 0 {
+			continue
+		}
+		res = append(res, &Share{
+			TokenID1Str: pool.State.Token0ID.String(),
+			TokenID2Str: pool.State.Token1ID.String(),
+			ShareAmount: share.Amount,
+			PoolID:      poolID,
+		})
+	}
+	sortShares(res)
+
+	return res, nil
+}
+
+// SharesSnapshot holds the (sorted) pDEX shares of an nftID taken from a single pDEX state, so that they can be paged
+// through consistently without retrieving the state again. It is returned by IncClient.GetSharesSnapshot and is safe
+// for concurrent use.
+type SharesSnapshot struct {
+	nftID  string
+	shares []*Share
+}
+
+// NftID returns the nftID of the snapshot.
+func (snapshot *SharesSnapshot) NftID() string {
+	return snapshot.nftID
+}
+
+// Total returns the number of shares in the snapshot.
+func (snapshot *SharesSnapshot) Total() int {
+	return len(snapshot.shares)
+}
+
+// Page returns the shares of the snapshot in [offset, offset+limit), in the same order as GetAllShares, together with
+// the total number of shares. The returned shares are copies, and an empty page is returned past the last share.
+func (snapshot *SharesSnapshot) Page(offset, limit int) ([]*Share, int, error) {
+	if offset < 0 {
+		return nil, 0, fmt.Errorf("invalid offset %v", offset)
+	}
+	if limit <= 0 {
+		return nil, 0, fmt.Errorf("invalid limit %v", limit)
+	}
+
+	total := len(snapshot.shares)
+	if offset >= total {
+		return []*Share{}, total, nil
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	res := make([]*Share, 0, end-offset)
+	for _, share := range snapshot.shares[offset:end] {
+		tmpShare := *share
+		res = append(res, &tmpShare)
+	}
+
+	return res, total, nil
+}
+
+// GetSharesSnapshot retrieves the latest pDEX state and returns the shares of an nftID as a SharesSnapshot, whose
+// pages all come from that state. The response is decoded one pool pair at a time, so that only the shares of nftID
+// are kept besides the raw response, not the decoded state of every pool.
+func (client *IncClient) GetSharesSnapshot(nftID string) (*SharesSnapshot, error) {
+	filter := make(map[string]interface{})
+	filter["Key"] = PoolPairs
+	filter["Verbosity"] = FullVerbosity
+	filter["ID"] = ""
+
+	responseInBytes, err := client.rpcServer.GetPdexState(0, filter)
+	if err != nil {
+		return nil, err
+	}
+	shares, err := decodeSharesStream(bytes.NewReader(responseInBytes), nftID)
+	if err != nil {
+		return nil, err
+	}
+	sortShares(shares)
+
+	return &SharesSnapshot{nftID: nftID, shares: shares}, nil
+}
+
+// GetSharesPaged returns a page of the latest pDEX shares of an nftID, in the same order as GetAllShares, together
+// with the total number of shares of the nftID.
+//
+// Each call retrieves the latest pDEX state, so the pages of consecutive calls may come from different states. To page
+// through the shares of a single state with one RPC, use GetSharesSnapshot and SharesSnapshot.Page instead.
+func (client *IncClient) GetSharesPaged(nftID string, offset, limit int) ([]*Share, int, error) {
+	if offset < 0 {
+		return nil, 0, fmt.Errorf("invalid offset %v", offset)
+	}
+	if limit <= 0 {
+		return nil, 0, fmt.Errorf("invalid limit %v", limit)
+	}
+
+	snapshot, err := client.GetSharesSnapshot(nftID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return snapshot.Page(offset, limit)
+}
+
+// poolShareEntry is the part of a pool pair needed to extract the shares of an nftID.
+type poolShareEntry struct {
+	State struct {
+		Token0ID common.Hash
+		Token1ID common.Hash
+	}
+	Shares map[string]*struct {
+		Amount uint64
+	}
+}
+
+// decodeSharesStream reads the response of a pDEX-state RPC with the PoolPairs filter from r, decoding one pool pair
+// at a time, and returns the non-zero shares of nftID.
+func decodeSharesStream(r io.Reader, nftID string) ([]*Share, error) {
+	dec := json.NewDecoder(r)
+	res := make([]*Share, 0)
+	var rpcErr *rpchandler.RPCError
+
+	err := decodeObjectStream(dec, func(key string) error {
+		switch key {
+		case "Error":
+			return dec.Decode(&rpcErr)
+		case "Result":
+			return decodeObjectStream(dec, func(key string) error {
+				if key != PoolPairs {
+					return skipJSONValue(dec)
+				}
+				return decodeObjectStream(dec, func(poolID string) error {
+					var pool poolShareEntry
+					if err := dec.Decode(&pool); err != nil {
+						return err
+					}
+					share, ok := pool.Shares[nftID]
+					if !ok || share == nil || share.Amount == 0 {
+						return nil
+					}
+					res = append(res, &Share{
+						TokenID1Str: pool.State.Token0ID.String(),
+						TokenID2Str: pool.State.Token1ID.String(),
+						ShareAmount: share.Amount,
+						PoolID:      poolID,
+					})
+					return nil
+				})
+			})
+		default:
+			return skipJSONValue(dec)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("decode pDEX state error: %v", err)
+	}
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	return res, nil
+}
+
+// decodeObjectStream reads a JSON object (or null) from dec, calling fn for each key. fn must consume the value of
+// the key.
+func decodeObjectStream(dec *json.Decoder, fn func(key string) error) error {
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if t == nil {
+		return nil
+	}
+	if delim, ok := t.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("expected an object, got %v", t)
+	}
+	for dec.More() {
+		t, err = dec.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := t.(string)
+		if !ok {
+			return fmt.Errorf("expected an object key, got %v", t)
+		}
+		if err = fn(key); err != nil {
+			return err
+		}
+	}
+	_, err = dec.Token()
+	return err
+}
+
+// skipJSONValue discards the next JSON value of dec.
+func skipJSONValue(dec *json.Decoder) error {
+	var tmp json.RawMessage
+	return dec.Decode(&tmp)
+}
+
+// sortShares sorts shares by (TokenID1Str, TokenID2Str), and then by PoolID.
+func sortShares(shares []*Share) {
+	sort.Slice(shares, func(i, j int) bool {
+		if shares[i].TokenID1Str != shares[j].TokenID1Str {
+			return shares[i].TokenID1Str < shares[j].TokenID1Str
+		}
+		if shares[i].TokenID2Str != shares[j].TokenID2Str {
+			return shares[i].TokenID2Str < shares[j].TokenID2Str
+		}
+		return shares[i].PoolID < shares[j].PoolID
+	})
+}
+
+// LPPosition describes a liquidity-provider position in a pDEX pool.
+type LPPosition struct {
+	// PoolID is the ID of the pool.
+	PoolID string
+
+	// NftID is the NFT holding the position.
+	NftID string
+
+	// Token1ID and Token2ID are the tokens of the pool, in the order given to EstimateLPPosition.
+	Token1ID string
+	Token2ID string
+
+	// ShareAmount is the share amount of the position, TotalShareAmount is the total share amount of the pool.
+	ShareAmount      uint64
+	TotalShareAmount uint64
+
+	// Token1Amount and Token2Amount are the amounts of tokens the position is currently entitled to.
+	Token1Amount uint64
+	Token2Amount uint64
+
+	// CurrentPrice is the current price of Token1 in terms of Token2 (i.e, the amount of Token2 per Token1).
+	CurrentPrice float64
+}
+
+// ImpermanentLoss returns the impermanent loss of the position compared to holding the tokens since it was
+// provided at the given entry price (of Token1 in terms of Token2). See ComputeImpermanentLoss.
+func (p LPPosition) ImpermanentLoss(entryPrice float64) float64 {
+	return ComputeImpermanentLoss(entryPrice, p.CurrentPrice)
+}
+
+// ComputeImpermanentLoss returns the impermanent loss of a constant-product liquidity position whose price moved from
+// entryPrice to currentPrice, as a fraction of the value of just holding the tokens. The result is always non-positive,
+// e.g, -0.0572 (i.e, -5.72%) when the price doubles.
+//
+// If any of the prices is not positive, it returns 0.
+func ComputeImpermanentLoss(entryPrice, currentPrice float64) float64 {
+	if entryPrice <= 0 || currentPrice <= 0 {
+		return 0
+	}
+	priceRatio := currentPrice / entryPrice
+
+	return 2*math.Sqrt(priceRatio)/(1+priceRatio) - 1
+}
+
+// EstimateLPPosition returns the current position of a pDEX nftID in the pool of pair token1-token2. If the nftID has
+// shares in several pools of the pair, the pool with the largest share amount is used.
+//
+// Note that the pDEX keeps track of LP shares by NFT instead of payment address; see GetMyNFTs for the NFTs of an
+// account. The entry price of the position is not recorded on-chain, use LPPosition.ImpermanentLoss to compare the
+// position against holding the tokens from a known entry price.
+func (client *IncClient) EstimateLPPosition(nftID, token1, token2 string) (*LPPosition, error) {
+	pools, err := client.GetPdexPoolPair(0, token1, token2)
+	if err != nil {
+		return nil, err
+	}
+
+	poolID, shareAmount := findLargestSharePool(pools, nftID)
+	if shareAmount == 0 {
+		return nil, fmt.Errorf("nftID %v has no share in pools of pair %v-%v", nftID, token1, token2)
+	}
+
+	return newLPPosition(poolID, nftID, token1, token2, pools[poolID], shareAmount)
+}
+
+// CalculateWithdrawalAmount returns the amounts of tokenID1 and tokenID2 a pDEX nftID would get back for withdrawing
+// sharesToWithdraw shares from the pool poolID of pair tokenID1-tokenID2 at the provided beacon height (0 for the latest
+// pDEX state), i.e, the pool reserves pro-rata to sharesToWithdraw over the total share amount of the pool.
+//
+// It returns an error if the pool does not exist or is not of pair tokenID1-tokenID2, if the nftID has no share in the
+// pool, or if sharesToWithdraw is 0, or exceeds the share amount of the nftID or the total share amount of the pool.
+func (client *IncClient) CalculateWithdrawalAmount(beaconHeight uint64, poolID, nftID, tokenID1, tokenID2 string, sharesToWithdraw uint64) (amount1, amount2 uint64, err error) {
+	if sharesToWithdraw == 0 {
+		return 0, 0, fmt.Errorf("sharesToWithdraw must be positive")
+	}
+	pool, err := client.GetPoolPairStateByID(beaconHeight, poolID)
+	if err != nil {
+		return 0, 0, err
+	}
+	if pool == nil {
+		return 0, 0, fmt.Errorf("pool %v not found", poolID)
+	}
+
+	share, ok := pool.Shares[nftID]
+	if !ok || share == nil || share.Amount == 0 {
+		return 0, 0, fmt.Errorf("nftID %v has no share in pool %v", nftID, poolID)
+	}
+	totalShareAmount := pool.State.ShareAmount
+	if sharesToWithdraw > totalShareAmount {
+		return 0, 0, fmt.Errorf("sharesToWithdraw %v exceeds the total share amount %v of pool %v",
+			sharesToWithdraw, totalShareAmount, poolID)
+	}
+	if sharesToWithdraw > share.Amount {
+		return 0, 0, fmt.Errorf("sharesToWithdraw %v exceeds the share amount %v of nftID %v in pool %v",
+			sharesToWithdraw, share.Amount, nftID, poolID)
+	}
+
+	position, err := newLPPosition(poolID, nftID, tokenID1, tokenID2, pool, sharesToWithdraw)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return position.Token1Amount, position.Token2Amount, nil
+}
+
+// findLargestSharePool returns the ID of the pool in which nftID has the largest share amount, together with that
+// amount. Ties are broken by the smallest pool ID. It returns a zero share amount if nftID has no share in the pools.
+func findLargestSharePool(pools map[string]*jsonresult.Pdexv3PoolPairState, nftID string) (string, uint64) {
+	poolIDs := make([]string, 0)
+	for poolID := range pools {
+		poolIDs = append(poolIDs, poolID)
+	}
+	sort.Strings(poolIDs)
+
+	var resPoolID string
+	var resShareAmount uint64
+	for _, poolID := range poolIDs {
+		pool := pools[poolID]
+		if pool == nil {
+			continue
+		}
+		share, ok := pool.Shares[nftID]
+		if !ok || share == nil || share.Amount <= resShareAmount {
+			continue
+		}
+		resPoolID, resShareAmount = poolID, share.Amount
+	}
+
+	return resPoolID, resShareAmount
+}
+
+// newLPPosition computes the LPPosition of a share amount in the given pool.
+func newLPPosition(poolID, nftID, token1, token2 string, pool *jsonresult.Pdexv3PoolPairState, shareAmount uint64) (*LPPosition, error) {
+	state := pool.State
+	if state.ShareAmount == 0 {
+		return nil, fmt.Errorf("pool %v has no share", poolID)
+	}
+
+	var reserve1, reserve2 uint64
+	var virtual1, virtual2 *big.Int
+	switch {
+	case state.Token0ID.String() == token1 && state.Token1ID.String() == token2:
+		reserve1, reserve2 = state.Token0RealAmount, state.Token1RealAmount
+		virtual1, virtual2 = state.Token0VirtualAmount, state.Token1VirtualAmount
+	case state.Token0ID.String() == token2 && state.Token1ID.String() == token1:
+		reserve1, reserve2 = state.Token1RealAmount, state.Token0RealAmount
+		virtual1, virtual2 = state.Token1VirtualAmount, state.Token0VirtualAmount
+	default:
+		return nil, fmt.Errorf("pool %v is not of pair %v-%v", poolID, token1, token2)
+	}
+
+	// amount = reserve * shareAmount / totalShareAmount
+	totalShare := new(big.Int).SetUint64(state.ShareAmount)
+	amount1 := new(big.Int).Mul(new(big.Int).SetUint64(reserve1), new(big.Int).SetUint64(shareAmount))
+	amount1.Div(amount1, totalShare)
+	amount2 := new(big.Int).Mul(new(big.Int).SetUint64(reserve2), new(big.Int).SetUint64(shareAmount))
+	amount2.Div(amount2, totalShare)
+
+	var currentPrice float64
+	if virtual1 != nil && virtual2 != nil && virtual1.Sign() > 0 {
+		currentPrice, _ = new(big.Float).Quo(new(big.Float).SetInt(virtual2), new(big.Float).SetInt(virtual1)).Float64()
+	}
+
+	return &LPPosition{
+		PoolID:           poolID,
+		NftID:            nftID,
+		Token1ID:         token1,
+		Token2ID:         token2,
+		ShareAmount:      shareAmount,
+		TotalShareAmount: state.ShareAmount,
+		Token1Amount:     amount1.Uint64(),
+		Token2Amount:     amount2.Uint64(),
+		CurrentPrice:     currentPrice,
+	}, nil
+}
+
 func calculateBuyAmount(amountIn uint64, virtualReserveIn *big.Int, virtualReserveOut *big.Int) (uint64, error) {
 	if amountIn <= 0 {
 		return 0, fmt.Errorf("invalid input amount %d", amountIn)
@@ -152,8 +586,14 @@ func calculateBuyAmount(amountIn uint64, virtualReserveIn *big.Int, virtualReser
 }
 
 // CheckPrice gets the remote server to check price for trading things.
+// It only returns the amount of tokens received; see CheckPriceDetailed for the full conversion details.
 func (client *IncClient) CheckPrice(pairID, tokenToSell string, sellAmount uint64) (uint64, error) {
-	pairs, err := client.GetAllPdexPoolPairs(0)
+	return client.CheckPriceWithContext(context.Background(), pairID, tokenToSell, sellAmount)
+}
+
+// CheckPriceWithContext is the same as CheckPrice, except that the request is aborted when ctx is done.
+func (client *IncClient) CheckPriceWithContext(ctx context.Context, pairID, tokenToSell string, sellAmount uint64) (uint64, error) {
+	pairs, err := client.WithContext(ctx).GetAllPdexPoolPairs(0)
 	if err != nil {
 		return 0, err
 	}
@@ -162,6 +602,353 @@ func (client *IncClient) CheckPrice(pairID, tokenToSell string, sellAmount uint6
 		return 0, fmt.Errorf("No pool found for ID %s", pairID)
 	}
 
+	return getPoolBuyAmount(pairID, pair, tokenToSell, sellAmount)
+}
+
+// CheckPriceDetailed returns the price for selling sellAmount of tokenToSell for tokenToBuy, together with the
+// pool used to price the trade. Among all pools of the pair tokenToSell-tokenToBuy, the one giving the highest
+// buy amount is chosen.
+func (client *IncClient) CheckPriceDetailed(tokenToSell, tokenToBuy string, sellAmount uint64) (*rpc.ConvertedPrice, error) {
+	pairs, err := client.GetAllPdexPoolPairs(0)
+	if err != nil {
+		return nil, err
+	}
+
+	return getBestPoolPrice(pairs, tokenToSell, tokenToBuy, sellAmount)
+}
+
+// getBestPoolPrice returns the price for selling sellAmount of tokenToSell for tokenToBuy using the pool of the given
+// pool pairs that gives the highest buy amount.
+func getBestPoolPrice(pairs map[string]*jsonresult.Pdexv3PoolPairState, tokenToSell, tokenToBuy string, sellAmount uint64) (*rpc.ConvertedPrice, error) {
+	pairIDs := make([]string, 0)
+	for pairID := range pairs {
+		pairIDs = append(pairIDs, pairID)
+	}
+	sort.Strings(pairIDs)
+
+	var res *rpc.ConvertedPrice
+	for _, pairID := range pairIDs {
+		pair := pairs[pairID]
+		if pair == nil {
+			continue
+		}
+		token0, token1 := pair.State.Token0ID.String(), pair.State.Token1ID.String()
+		if !(token0 == tokenToSell && token1 == tokenToBuy) && !(token0 == tokenToBuy && token1 == tokenToSell) {
+			continue
+		}
+
+		buyAmount, err := getPoolBuyAmount(pairID, pair, tokenToSell, sellAmount)
+		if err != nil {
+			return nil, err
+		}
+		if res == nil || buyAmount > res.Price {
+			res = &rpc.ConvertedPrice{
+				FromTokenIDStr: tokenToSell,
+				ToTokenIDStr:   tokenToBuy,
+				Amount:         sellAmount,
+				Price:          buyAmount,
+				PoolID:         pairID,
+			}
+		}
+	}
+	if res == nil {
+		return nil, fmt.Errorf("No pool found for pair %s-%s", tokenToSell, tokenToBuy)
+	}
+
+	return res, nil
+}
+
+// GetTradeValueWithSlippage returns the expected amount of tokenToBuy received when selling sellAmount of tokenToSell
+// to the best pool of the pair in poolPairs (see CheckPriceDetailed), together with the minimum acceptable amount
+// after applying a slippage tolerance of maxSlippageBps BPS (see jsonresult.BPS), i.e,
+//
+//	minAcceptable = expected * (BPS - maxSlippageBps) / BPS
+//
+// rounded down, so that the protection is never looser than requested. The minimum acceptable amount can be used as
+// the minimum accepted amount of a trade request.
+func GetTradeValueWithSlippage(tokenToSell, tokenToBuy string, sellAmount uint64, maxSlippageBps uint64,
+	poolPairs map[string]*jsonresult.Pdexv3PoolPairState) (expected, minAcceptable uint64, err error) {
+	if maxSlippageBps > jsonresult.BPS {
+		return 0, 0, fmt.Errorf("slippage %v BPS exceeds %v BPS", maxSlippageBps, jsonresult.BPS)
+	}
+	price, err := getBestPoolPrice(poolPairs, tokenToSell, tokenToBuy, sellAmount)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return price.Price, CalcPathMinOutput([]uint64{price.Price}, maxSlippageBps), nil
+}
+
+// FindBestTradeRoute searches the pool graph formed by poolPairs for the trade path (a list of pool IDs) of at most
+// maxHops hops that gives the highest output when selling sellAmount of tokenToSell for tokenToBuy, and returns it
+// together with the expected output. Unlike routing through PRV only, any token can be an intermediate hop (e.g, a
+// stablecoin with deeper liquidity). A path never visits a token twice, and maxHops must be in
+// [1, pdexv3.MaxTradePathLength].
+//
+// The search is exhaustive over all such paths: a path holding fewer tokens at some intermediate hop may still end up
+// with a better output, since it leaves other tokens unvisited. Since the output of the remaining hops only grows with
+// their input, only the best pool between two tokens is tried for each hop. The number of paths grows quickly with
+// maxHops on dense pool graphs.
+//
+// The output of each hop is calculated as in CheckPrice; trading fees are not taken into account. An error is
+// returned if no path connects the two tokens within maxHops.
+func FindBestTradeRoute(tokenToSell, tokenToBuy string, sellAmount uint64,
+	poolPairs map[string]*jsonresult.Pdexv3PoolPairState, maxHops int) ([]string, uint64, error) {
+	if maxHops < 1 || maxHops > metadataPdexv3.MaxTradePathLength {
+		return nil, 0, fmt.Errorf("maxHops must be in [1, %v], got %v", metadataPdexv3.MaxTradePathLength, maxHops)
+	}
+	if tokenToSell == tokenToBuy {
+		return nil, 0, fmt.Errorf("tokenToSell and tokenToBuy must be different, got %v", tokenToSell)
+	}
+	if sellAmount == 0 {
+		return nil, 0, fmt.Errorf("sellAmount must be positive")
+	}
+
+	// neighbors maps a token to the tokens it has a pool with, and the (sorted) IDs of these pools.
+	pairIDs := make([]string, 0)
+	for pairID, pair := range poolPairs {
+		if pair == nil || pair.State.Token0VirtualAmount == nil || pair.State.Token1VirtualAmount == nil {
+			continue
+		}
+		pairIDs = append(pairIDs, pairID)
+	}
+	sort.Strings(pairIDs)
+	neighbors := make(map[string]map[string][]string)
+	for _, pairID := range pairIDs {
+		pair := poolPairs[pairID]
+		token0, token1 := pair.State.Token0ID.String(), pair.State.Token1ID.String()
+		if token0 == token1 {
+			continue
+		}
+		for _, tokens := range [][2]string{{token0, token1}, {token1, token0}} {
+			if neighbors[tokens[0]] == nil {
+				neighbors[tokens[0]] = make(map[string][]string)
+			}
+			neighbors[tokens[0]][tokens[1]] = append(neighbors[tokens[0]][tokens[1]], pairID)
+		}
+	}
+	nextTokens := make(map[string][]string)
+	for token, tmpNeighbors := range neighbors {
+		for nextToken := range tmpNeighbors {
+			nextTokens[token] = append(nextTokens[token], nextToken)
+		}
+		sort.Strings(nextTokens[token])
+	}
+
+	var bestPath []string
+	bestAmount := uint64(0)
+	visited := map[string]bool{tokenToSell: true}
+	path := make([]string, 0, maxHops)
+	var search func(currentToken string, currentAmount uint64)
+	search = func(currentToken string, currentAmount uint64) {
+		for _, nextToken := range nextTokens[currentToken] {
+			if visited[nextToken] {
+				continue
+			}
+			bestPairID, buyAmount := "", uint64(0)
+			for _, pairID := range neighbors[currentToken][nextToken] {
+				tmpAmount, err := getPoolBuyAmount(pairID, poolPairs[pairID], currentToken, currentAmount)
+				if err == nil && tmpAmount > buyAmount {
+					bestPairID, buyAmount = pairID, tmpAmount
+				}
+			}
+			if buyAmount == 0 {
+				continue
+			}
+
+			path = append(path, bestPairID)
+			if nextToken == tokenToBuy {
+				if bestPath == nil || buyAmount > bestAmount {
+					bestPath = append([]string{}, path...)
+					bestAmount = buyAmount
+				}
+			} else if len(path) < maxHops {
+				visited[nextToken] = true
+				search(nextToken, buyAmount)
+				visited[nextToken] = false
+			}
+			path = path[:len(path)-1]
+		}
+	}
+	search(tokenToSell, sellAmount)
+
+	if bestPath == nil {
+		return nil, 0, fmt.Errorf("no trade path from %v to %v within %v hop(s)", tokenToSell, tokenToBuy, maxHops)
+	}
+
+	return bestPath, bestAmount, nil
+}
+
+// CalcPathMinOutput returns the minimum acceptable output of a (multi-hop) trade, given the expected output of each
+// hop of the trade path and a slippage tolerance in BPS (see jsonresult.BPS).
+//
+// The slippage is applied once, to the end-to-end quote (i.e, the expected output of the last hop) rather than to
+// each hop, but the tolerance compounds with the length of the path: since the price of every hop may move between
+// quoting and execution, a path of n hops with a slippage of s BPS has a minimum output of
+//
+//	expectedPerHop[n-1] * (1 - s/BPS)^n
+//
+// rounded down. A single-hop trade thus gets the usual expected * (1 - s/BPS). It returns 0 if expectedPerHop is
+// empty or the slippage is at least BPS.
+func CalcPathMinOutput(expectedPerHop []uint64, slippageBps uint64) uint64 {
+	n := len(expectedPerHop)
+	if n == 0 || slippageBps >= jsonresult.BPS {
+		return 0
+	}
+
+	exp := big.NewInt(int64(n))
+	num := new(big.Int).Exp(big.NewInt(int64(jsonresult.BPS-slippageBps)), exp, nil)
+	den := new(big.Int).Exp(big.NewInt(jsonresult.BPS), exp, nil)
+	res := new(big.Int).SetUint64(expectedPerHop[n-1])
+	res.Mul(res, num)
+	res.Div(res, den)
+
+	return res.Uint64()
+}
+
+// QuoteTradePath returns the expected output of each hop when selling sellAmount of tokenToSell along the given trade
+// path (a list of pool IDs) at the latest beacon height. The output of a hop is the input of the next one; the last
+// value is the end-to-end quote of the trade. The result can be passed to CalcPathMinOutput.
+func (client *IncClient) QuoteTradePath(tradePath []string, tokenToSell string, sellAmount uint64) ([]uint64, error) {
+	if len(tradePath) == 0 {
+		return nil, fmt.Errorf("trade path is empty")
+	}
+	pairs, err := client.GetAllPdexPoolPairs(0)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]uint64, 0, len(tradePath))
+	currentToken, currentAmount := tokenToSell, sellAmount
+	for _, pairID := range tradePath {
+		pair, exists := pairs[pairID]
+		if !exists || pair == nil {
+			return nil, fmt.Errorf("No pool found for ID %s", pairID)
+		}
+		buyAmount, err := getPoolBuyAmount(pairID, pair, currentToken, currentAmount)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, buyAmount)
+
+		if currentToken == pair.State.Token0ID.String() {
+			currentToken = pair.State.Token1ID.String()
+		} else {
+			currentToken = pair.State.Token0ID.String()
+		}
+		currentAmount = buyAmount
+	}
+
+	return res, nil
+}
+
+// MaxPoolHistoryPoints is the maximum number of points GetPoolHistory samples in a single call.
+var MaxPoolHistoryPoints = 1000
+
+// PoolSnapshot describes the reserves of a pDEX pool at a specific beacon height.
+type PoolSnapshot struct {
+	// BeaconHeight is the beacon height at which the pool is sampled.
+	BeaconHeight uint64
+
+	// PoolID is the ID of the sampled pool. It is empty if no pool of the pair exists at BeaconHeight.
+	PoolID string
+
+	// Token1Amount and Token2Amount are the real reserves of the first and second tokens (in the order given to
+	// GetPoolHistory).
+	Token1Amount uint64
+	Token2Amount uint64
+
+	// ShareAmount is the total share amount of the pool.
+	ShareAmount uint64
+}
+
+// GetPoolHistory samples the reserves of the pool of pair tokenID1-tokenID2 every step beacon blocks, from fromHeight
+// up to toHeight (toHeight is always sampled). If toHeight = 0, it is the current beacon height.
+// When several pools of the pair exist at a height, the one with the largest tokenID1 reserve is sampled.
+//
+// Note that each sampled point costs one pdexv3_getState RPC, which is fairly heavy on the full-node (all pool pairs
+// are returned), so choose the step accordingly; at most MaxPoolHistoryPoints points can be sampled per call.
+// Moreover, the full-node must keep the pDEX states of the sampled heights (i.e, it must be an archival node).
+func (client *IncClient) GetPoolHistory(tokenID1, tokenID2 string, fromHeight, toHeight, step uint64) ([]PoolSnapshot, error) {
+	if step == 0 {
+		return nil, fmt.Errorf("step must be positive")
+	}
+	if toHeight == 0 {
+		var err error
+		toHeight, err = client.GetBeaconHeight()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if fromHeight == 0 || fromHeight > toHeight {
+		return nil, fmt.Errorf("invalid height range [%v, %v]", fromHeight, toHeight)
+	}
+	numPoints := (toHeight-fromHeight)/step + 1
+	if (toHeight-fromHeight)%step != 0 {
+		numPoints++
+	}
+	if numPoints > uint64(MaxPoolHistoryPoints) {
+		return nil, fmt.Errorf("too many points to sample: %v, max: %v", numPoints, MaxPoolHistoryPoints)
+	}
+
+	res := make([]PoolSnapshot, 0, numPoints)
+	for height := fromHeight; ; height += step {
+		if height > toHeight {
+			height = toHeight
+		}
+
+		pairs, err := client.GetAllPdexPoolPairs(height)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get pool pairs at beacon height %v: %v", height, err)
+		}
+		res = append(res, newPoolSnapshot(height, pairs, tokenID1, tokenID2))
+
+		if height == toHeight {
+			break
+		}
+	}
+
+	return res, nil
+}
+
+// newPoolSnapshot returns the snapshot of the most liquid pool of pair tokenID1-tokenID2 in the given pool pairs.
+func newPoolSnapshot(beaconHeight uint64, pairs map[string]*jsonresult.Pdexv3PoolPairState, tokenID1, tokenID2 string) PoolSnapshot {
+	pairIDs := make([]string, 0)
+	for pairID := range pairs {
+		pairIDs = append(pairIDs, pairID)
+	}
+	sort.Strings(pairIDs)
+
+	res := PoolSnapshot{BeaconHeight: beaconHeight}
+	for _, pairID := range pairIDs {
+		pair := pairs[pairID]
+		if pair == nil {
+			continue
+		}
+
+		var amount1, amount2 uint64
+		token0, token1 := pair.State.Token0ID.String(), pair.State.Token1ID.String()
+		if token0 == tokenID1 && token1 == tokenID2 {
+			amount1, amount2 = pair.State.Token0RealAmount, pair.State.Token1RealAmount
+		} else if token0 == tokenID2 && token1 == tokenID1 {
+			amount1, amount2 = pair.State.Token1RealAmount, pair.State.Token0RealAmount
+		} else {
+			continue
+		}
+
+		if res.PoolID == "" || amount1 > res.Token1Amount {
+			res.PoolID = pairID
+			res.Token1Amount = amount1
+			res.Token2Amount = amount2
+			res.ShareAmount = pair.State.ShareAmount
+		}
+	}
+
+	return res
+}
+
+// getPoolBuyAmount calculates the amount received when selling sellAmount of tokenToSell to the given pool.
+func getPoolBuyAmount(pairID string, pair *jsonresult.Pdexv3PoolPairState, tokenToSell string, sellAmount uint64) (uint64, error) {
 	var virtualAmtSell, virtualAmtBuy *big.Int
 	switch tokenToSell {
 	case pair.State.Token0ID.String():
@@ -181,6 +968,84 @@ func (client *IncClient) CheckPrice(pairID, tokenToSell string, sellAmount uint6
 	return buyAmount, nil
 }
 
+// calculateTradingFee returns the minimum trading fee (in the selling token) required to sell sellAmount to a pool
+// with the given fee rate, i.e, sellAmount * feeRateBPS / BPS rounded up.
+func calculateTradingFee(sellAmount uint64, feeRateBPS uint) (uint64, error) {
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(sellAmount), new(big.Int).SetUint64(uint64(feeRateBPS)))
+	fee.Add(fee, big.NewInt(jsonresult.BPS-1))
+	fee.Div(fee, big.NewInt(jsonresult.BPS))
+	if !fee.IsUint64() {
+		return 0, fmt.Errorf("trading fee %s out of uint64 range", fee.String())
+	}
+	return fee.Uint64(), nil
+}
+
+// getPoolTradeValue calculates the amount received when selling sellAmount of tokenToSell to the given pool, together
+// with the minimum trading fee charged by the pool. The fee rate of the pool is read from params (see
+// jsonresult.Pdexv3Params.GetFeeRateBPS), and defaults to the network fee rate if the pool does not specify one.
+func getPoolTradeValue(pairID string, pair *jsonresult.Pdexv3PoolPairState, params *jsonresult.Pdexv3Params,
+	tokenToSell string, sellAmount uint64) (uint64, uint64, error) {
+	if params == nil {
+		return 0, 0, fmt.Errorf("pDEX params not found")
+	}
+	buyAmount, err := getPoolBuyAmount(pairID, pair, tokenToSell, sellAmount)
+	if err != nil {
+		return 0, 0, err
+	}
+	tradingFee, err := calculateTradingFee(sellAmount, params.GetFeeRateBPS(pairID))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return buyAmount, tradingFee, nil
+}
+
+// GetTradeValue returns the amount received when selling sellAmount of tokenToSell to the pool pairID at the latest
+// beacon height, together with the minimum trading fee (in tokenToSell) to pay for the trade.
+//
+// Unlike a global fee estimate, the trading fee is calculated from the fee rate of the pool itself, so the quote
+// matches the on-chain result. Pools without a specific fee rate use the default fee rate of the network.
+func (client *IncClient) GetTradeValue(pairID, tokenToSell string, sellAmount uint64) (buyAmount, tradingFee uint64, err error) {
+	pair, err := client.GetPoolPairStateByID(0, pairID)
+	if err != nil {
+		return 0, 0, err
+	}
+	if pair == nil {
+		return 0, 0, fmt.Errorf("No pool found for ID %s", pairID)
+	}
+	params, err := client.GetDexParams(0)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return getPoolTradeValue(pairID, pair, params, tokenToSell, sellAmount)
+}
+
+// ErrPoolNotFound indicates that pDEX has no pool for a pair of tokens.
+var ErrPoolNotFound = fmt.Errorf("pool not found")
+
+// GetPoolReserves returns the current (real) reserves of the pDEX pool of pair tokenID1-tokenID2, oriented as
+// requested: reserve1 is the amount of tokenID1 and reserve2 is the amount of tokenID2, regardless of the order of the
+// tokens in the pool. If the pair has several pools, the reserves of the most liquid one are returned.
+//
+// It returns ErrPoolNotFound if no pool exists for the pair; a pool that exists with zero reserves is not an error.
+func (client *IncClient) GetPoolReserves(tokenID1, tokenID2 string) (reserve1, reserve2 uint64, err error) {
+	if tokenID1 == tokenID2 {
+		return 0, 0, fmt.Errorf("tokenID1 and tokenID2 must be different, got %v", tokenID1)
+	}
+	allPoolPairs, err := client.GetAllPdexPoolPairs(0)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	snapshot := newPoolSnapshot(0, allPoolPairs, tokenID1, tokenID2)
+	if snapshot.PoolID == "" {
+		return 0, 0, ErrPoolNotFound
+	}
+
+	return snapshot.Token1Amount, snapshot.Token2Amount, nil
+}
+
 // CheckNFTMintingStatus retrieves the status of a (pDEX) NFT minting transaction.
 func (client *IncClient) CheckNFTMintingStatus(txHash string) (*jsonresult.MintNFTStatus, error) {
 	responseInBytes, err := client.rpcServer.CheckNFTMintingStatus(txHash)
@@ -201,13 +1066,29 @@ func (client *IncClient) CheckNFTMintingStatus(txHash string) (*jsonresult.MintN
 }
 
 // CheckTradeStatus checks the status of a trading transaction.
+//
+// It is kept for compatibility; use GetTradeStatusDetail for a typed status.
 func (client *IncClient) CheckTradeStatus(txHash string) (*jsonresult.DEXTradeStatus, error) {
+	detail, err := client.GetTradeStatusDetail(txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &jsonresult.DEXTradeStatus{
+		Status:     int(detail.Status),
+		BuyAmount:  detail.ReceivedAmount,
+		TokenToBuy: detail.TokenToBuy,
+	}, nil
+}
+
+// GetTradeStatusDetail returns the full status of a trading transaction as a typed struct.
+func (client *IncClient) GetTradeStatusDetail(txHash string) (*jsonresult.TradeStatusDetail, error) {
 	responseInBytes, err := client.rpcServer.CheckTradeStatus(txHash)
 	if err != nil {
 		return nil, err
 	}
 
-	var res jsonresult.DEXTradeStatus
+	var res jsonresult.TradeStatusDetail
 	err = rpchandler.ParseResponse(responseInBytes, &res)
 	if err != nil {
 		return nil, err
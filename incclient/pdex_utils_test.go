@@ -2,8 +2,18 @@ package incclient
 
 import (
 	"encoding/json"
+	"fmt"
+	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	metadataPdexv3 "github.com/incognitochain/go-incognito-sdk-v2/metadata/pdexv3"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/jsonresult"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/rpc"
 	"github.com/stretchr/testify/assert"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -71,6 +81,37 @@ func TestIncClient_CheckTradeStatus(t *testing.T) {
 	Logger.Printf("status: %v\n", status)
 }
 
+func TestIncClient_GetTradeStatusDetail(t *testing.T) {
+	tokenToBuy := common.Hash{1}.String()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"Result": map[string]interface{}{
+				"Status":       1,
+				"BuyAmount":    12345,
+				"TokenToBuy":   tokenToBuy,
+				"BeaconHeight": 100,
+			},
+		})
+	}))
+	defer ts.Close()
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+
+	detail, err := client.GetTradeStatusDetail(common.Hash{2}.String())
+	assert.Equal(t, nil, err, fmt.Errorf("GetTradeStatusDetail error: %v", err))
+	assert.Equal(t, &jsonresult.TradeStatusDetail{
+		Status:         jsonresult.TradeAccepted,
+		ReceivedAmount: 12345,
+		TokenToBuy:     tokenToBuy,
+		BeaconHeight:   100,
+	}, detail)
+	assert.Equal(t, true, detail.IsAccepted())
+	assert.Equal(t, "accepted", detail.Status.String())
+
+	status, err := client.CheckTradeStatus(common.Hash{2}.String())
+	assert.Equal(t, nil, err, fmt.Errorf("CheckTradeStatus error: %v", err))
+	assert.Equal(t, &jsonresult.DEXTradeStatus{Status: 1, BuyAmount: 12345, TokenToBuy: tokenToBuy}, status)
+}
+
 func TestIncClient_CheckAddLiquidityStatus(t *testing.T) {
 	var err error
 	ic, err = NewTestNetClient()
@@ -369,3 +410,711 @@ func TestIncClient_CloneDEXState(t *testing.T) {
 
 	assert.Equal(t, true, reflect.DeepEqual(clonedState, currentState), "cloned and original states mismatch")
 }
+
+// newTestPoolPair returns a pool pair of token0 and token1 whose real and virtual reserves are amount0 and amount1.
+func newTestPoolPair(token0, token1 string, amount0, amount1 uint64) *jsonresult.Pdexv3PoolPairState {
+	token0ID, _ := common.Hash{}.NewHashFromStr(token0)
+	token1ID, _ := common.Hash{}.NewHashFromStr(token1)
+	return &jsonresult.Pdexv3PoolPairState{State: jsonresult.Pdexv3PoolPair{
+		Token0ID:            *token0ID,
+		Token1ID:            *token1ID,
+		Token0RealAmount:    amount0,
+		Token1RealAmount:    amount1,
+		Token0VirtualAmount: new(big.Int).SetUint64(amount0),
+		Token1VirtualAmount: new(big.Int).SetUint64(amount1),
+	}}
+}
+
+// newPdexStateServer returns a test server answering every pdexv3_getState request with the given state.
+func newPdexStateServer(state jsonresult.CurrentPdexState) *httptest.Server {
+	return httptest.NewServer(pdexStateHandler(state))
+}
+
+// pdexStateHandler returns a handler answering every pdexv3_getState request with the given state.
+func pdexStateHandler(state jsonresult.CurrentPdexState) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string
+		}
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil || req.Method != "pdexv3_getState" {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"Result": state})
+	}
+}
+
+func TestIncClient_CheckPriceDetailed(t *testing.T) {
+	tokenA := common.PRVIDStr
+	tokenB := common.Hash{6}.String()
+	tokenC := common.Hash{7}.String()
+	poolPairs := map[string]*jsonresult.Pdexv3PoolPairState{
+		"pool-AB-1": newTestPoolPair(tokenA, tokenB, 1000000, 2000000),
+		"pool-AB-2": newTestPoolPair(tokenA, tokenB, 1000000, 3000000),
+		"pool-BA-3": newTestPoolPair(tokenB, tokenA, 4000000, 1000000),
+		"pool-AC-1": newTestPoolPair(tokenA, tokenC, 1000000, 9000000),
+	}
+	ts := newPdexStateServer(jsonresult.CurrentPdexState{PoolPairs: poolPairs})
+	defer ts.Close()
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+
+	sellAmount := uint64(1000)
+	res, err := client.CheckPriceDetailed(tokenA, tokenB, sellAmount)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, &rpc.ConvertedPrice{
+		FromTokenIDStr: tokenA,
+		ToTokenIDStr:   tokenB,
+		Amount:         sellAmount,
+		Price:          3996,
+		PoolID:         "pool-BA-3",
+	}, res)
+
+	// CheckPrice returns the same amount for the chosen pool.
+	buyAmount, err := client.CheckPrice(res.PoolID, tokenA, sellAmount)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, res.Price, buyAmount)
+
+	// selling the other way round.
+	res, err = client.CheckPriceDetailed(tokenB, tokenA, sellAmount)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, tokenB, res.FromTokenIDStr)
+	assert.Equal(t, tokenA, res.ToTokenIDStr)
+	assert.Equal(t, "pool-AB-1", res.PoolID)
+	assert.Equal(t, uint64(499), res.Price)
+
+	// no pool for the pair.
+	_, err = client.CheckPriceDetailed(tokenB, tokenC, sellAmount)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestIncClient_GetPoolHistory(t *testing.T) {
+	tokenA := common.PRVIDStr
+	tokenB := common.Hash{6}.String()
+	tokenC := common.Hash{7}.String()
+	newPool := func(token0, token1 string, amount0, amount1, share uint64) *jsonresult.Pdexv3PoolPairState {
+		pool := newTestPoolPair(token0, token1, amount0, amount1)
+		pool.State.ShareAmount = share
+		return pool
+	}
+	states := map[uint64]map[string]*jsonresult.Pdexv3PoolPairState{
+		100: {
+			"pool-AC": newPool(tokenA, tokenC, 5000, 5000, 5000),
+		},
+		110: {
+			"pool-BA-1": newPool(tokenB, tokenA, 2000, 1000, 1400),
+			"pool-AC":   newPool(tokenA, tokenC, 5000, 5000, 5000),
+		},
+		120: {
+			"pool-BA-1": newPool(tokenB, tokenA, 1800, 1100, 1400),
+			"pool-AB-2": newPool(tokenA, tokenB, 3000, 9000, 5100),
+		},
+	}
+
+	var mtx sync.Mutex
+	requestedHeights := make([]uint64, 0)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string
+			Params []struct {
+				BeaconHeight uint64
+			}
+		}
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil || req.Method != "pdexv3_getState" || len(req.Params) != 1 {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+		height := req.Params[0].BeaconHeight
+
+		mtx.Lock()
+		requestedHeights = append(requestedHeights, height)
+		mtx.Unlock()
+
+		// return the latest state at the requested height
+		var state map[string]*jsonresult.Pdexv3PoolPairState
+		stateHeight := uint64(0)
+		for h, tmpState := range states {
+			if h <= height && h >= stateHeight {
+				state, stateHeight = tmpState, h
+			}
+		}
+		if state == nil {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"Error": map[string]interface{}{"Code": -1, "Message": "state not found"},
+			})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"Result": jsonresult.CurrentPdexState{PoolPairs: state},
+		})
+	}))
+	defer ts.Close()
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+
+	history, err := client.GetPoolHistory(tokenA, tokenB, 100, 120, 10)
+	assert.Equal(t, nil, err, fmt.Errorf("GetPoolHistory error: %v", err))
+	assert.Equal(t, []PoolSnapshot{
+		{BeaconHeight: 100},
+		{BeaconHeight: 110, PoolID: "pool-BA-1", Token1Amount: 1000, Token2Amount: 2000, ShareAmount: 1400},
+		{BeaconHeight: 120, PoolID: "pool-AB-2", Token1Amount: 3000, Token2Amount: 9000, ShareAmount: 5100},
+	}, history)
+	assert.Equal(t, []uint64{100, 110, 120}, requestedHeights)
+
+	// the end height is always sampled
+	requestedHeights = requestedHeights[:0]
+	history, err = client.GetPoolHistory(tokenB, tokenA, 100, 120, 15)
+	assert.Equal(t, nil, err, fmt.Errorf("GetPoolHistory error: %v", err))
+	assert.Equal(t, []PoolSnapshot{
+		{BeaconHeight: 100},
+		{BeaconHeight: 115, PoolID: "pool-BA-1", Token1Amount: 2000, Token2Amount: 1000, ShareAmount: 1400},
+		{BeaconHeight: 120, PoolID: "pool-AB-2", Token1Amount: 9000, Token2Amount: 3000, ShareAmount: 5100},
+	}, history)
+	assert.Equal(t, []uint64{100, 115, 120}, requestedHeights)
+
+	// RPC errors are returned
+	_, err = client.GetPoolHistory(tokenA, tokenB, 90, 120, 10)
+	assert.NotEqual(t, nil, err)
+
+	// invalid params
+	_, err = client.GetPoolHistory(tokenA, tokenB, 100, 120, 0)
+	assert.NotEqual(t, nil, err)
+	_, err = client.GetPoolHistory(tokenA, tokenB, 130, 120, 10)
+	assert.NotEqual(t, nil, err)
+	_, err = client.GetPoolHistory(tokenA, tokenB, 1, 1+uint64(MaxPoolHistoryPoints)*10, 10)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestComputeImpermanentLoss(t *testing.T) {
+	testCases := []struct {
+		entryPrice, currentPrice, expected float64
+	}{
+		{1, 1, 0},
+		{10, 20, -0.0572},
+		{10, 5, -0.0572},
+		{2, 8, -0.2},
+		{8, 2, -0.2},
+		{1, 9, -0.4},
+		{0.5, 50, -0.802},
+		{0, 1, 0},
+		{1, -1, 0},
+	}
+
+	for _, tc := range testCases {
+		il := ComputeImpermanentLoss(tc.entryPrice, tc.currentPrice)
+		assert.InDelta(t, tc.expected, il, 1e-4, fmt.Errorf("entry %v, current %v", tc.entryPrice, tc.currentPrice))
+		assert.LessOrEqual(t, il, float64(0))
+	}
+}
+
+func TestIncClient_EstimateLPPosition(t *testing.T) {
+	tokenA := common.PRVIDStr
+	tokenB := common.Hash{6}.String()
+	nftID := common.Hash{9}.String()
+	newPool := func(amount0, amount1, totalShare uint64, shares map[string]uint64) *jsonresult.Pdexv3PoolPairState {
+		pool := newTestPoolPair(tokenA, tokenB, amount0, amount1)
+		pool.State.ShareAmount = totalShare
+		pool.Shares = make(map[string]*jsonresult.Pdexv3Share)
+		for id, amount := range shares {
+			pool.Shares[id] = &jsonresult.Pdexv3Share{Amount: amount}
+		}
+		return pool
+	}
+	poolPairs := map[string]*jsonresult.Pdexv3PoolPairState{
+		tokenA + "-" + tokenB + "-1": newPool(1000000, 4000000, 2000000, map[string]uint64{nftID: 1000}),
+		tokenA + "-" + tokenB + "-2": newPool(1000000, 4000000, 2000000, map[string]uint64{nftID: 500000}),
+	}
+	ts := newPdexStateServer(jsonresult.CurrentPdexState{PoolPairs: poolPairs})
+	defer ts.Close()
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+
+	position, err := client.EstimateLPPosition(nftID, tokenB, tokenA)
+	assert.Equal(t, nil, err, fmt.Errorf("EstimateLPPosition error: %v", err))
+	assert.Equal(t, &LPPosition{
+		PoolID:           tokenA + "-" + tokenB + "-2",
+		NftID:            nftID,
+		Token1ID:         tokenB,
+		Token2ID:         tokenA,
+		ShareAmount:      500000,
+		TotalShareAmount: 2000000,
+		Token1Amount:     1000000,
+		Token2Amount:     250000,
+		CurrentPrice:     0.25,
+	}, position)
+	assert.InDelta(t, -0.2, position.ImpermanentLoss(1), 1e-9)
+	assert.Equal(t, float64(0), position.ImpermanentLoss(0.25))
+
+	_, err = client.EstimateLPPosition(common.Hash{10}.String(), tokenA, tokenB)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestIncClient_CalculateWithdrawalAmount(t *testing.T) {
+	tokenA := common.PRVIDStr
+	tokenB := common.Hash{6}.String()
+	nftID := common.Hash{9}.String()
+	pool := newTestPoolPair(tokenA, tokenB, 1000000, 4000000)
+	pool.State.ShareAmount = 2000000
+	pool.Shares = map[string]*jsonresult.Pdexv3Share{
+		nftID:                    {Amount: 500000},
+		common.Hash{10}.String(): {Amount: 1500000},
+	}
+	poolID := tokenA + "-" + tokenB + "-1"
+	// the nftID has a larger share in another pool of the same pair, which must not be used.
+	otherPool := newTestPoolPair(tokenA, tokenB, 1000000, 1000000)
+	otherPool.State.ShareAmount = 1000000
+	otherPool.Shares = map[string]*jsonresult.Pdexv3Share{nftID: {Amount: 1000000}}
+	otherPoolID := tokenA + "-" + tokenB + "-2"
+	poolPairs := map[string]*jsonresult.Pdexv3PoolPairState{poolID: pool, otherPoolID: otherPool}
+	ts := newPdexStateServer(jsonresult.CurrentPdexState{PoolPairs: poolPairs})
+	defer ts.Close()
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+
+	amount1, amount2, err := client.CalculateWithdrawalAmount(0, poolID, nftID, tokenB, tokenA, 200000)
+	assert.Equal(t, nil, err, fmt.Errorf("CalculateWithdrawalAmount error: %v", err))
+	assert.Equal(t, uint64(400000), amount1)
+	assert.Equal(t, uint64(100000), amount2)
+
+	// withdrawing all the shares of the nftID
+	amount1, amount2, err = client.CalculateWithdrawalAmount(0, poolID, nftID, tokenA, tokenB, 500000)
+	assert.Equal(t, nil, err, fmt.Errorf("CalculateWithdrawalAmount error: %v", err))
+	assert.Equal(t, uint64(250000), amount1)
+	assert.Equal(t, uint64(1000000), amount2)
+
+	// more than the holdings, more than the total, nothing, no share in the pool, an unknown pool, or another pair
+	for _, tc := range []struct {
+		poolID           string
+		nftID            string
+		tokenID2         string
+		sharesToWithdraw uint64
+	}{
+		{poolID, nftID, tokenB, 500001},
+		{poolID, nftID, tokenB, 2000001},
+		{poolID, nftID, tokenB, 0},
+		{poolID, common.Hash{11}.String(), tokenB, 1},
+		{"unknown", nftID, tokenB, 1},
+		{poolID, nftID, common.Hash{12}.String(), 1},
+	} {
+		_, _, err = client.CalculateWithdrawalAmount(0, tc.poolID, tc.nftID, tokenA, tc.tokenID2, tc.sharesToWithdraw)
+		assert.NotEqual(t, nil, err, fmt.Errorf("expected an error for %v", tc))
+	}
+}
+
+// newTestSharePools returns numPools pools between random tokens, in which nftID holds shareAmount(i) shares of the
+// i-th pool and another NFT holds one share.
+func newTestSharePools(nftID string, numPools int, shareAmount func(i int) uint64) map[string]*jsonresult.Pdexv3PoolPairState {
+	tokens := []common.Hash{common.PRVCoinID, {1}, {2}, {3}}
+	poolPairs := make(map[string]*jsonresult.Pdexv3PoolPairState)
+	for i := 0; i < numPools; i++ {
+		token0 := tokens[common.RandInt()%len(tokens)]
+		token1 := tokens[common.RandInt()%len(tokens)]
+		poolID := fmt.Sprintf("%v-%v-%v", token0.String(), token1.String(), common.RandChars(8))
+		pool := newTestPoolPair(token0.String(), token1.String(), 1, 1)
+		pool.Shares = map[string]*jsonresult.Pdexv3Share{
+			nftID:                    {Amount: shareAmount(i)},
+			common.Hash{10}.String(): {Amount: 1},
+		}
+		poolPairs[poolID] = pool
+	}
+	return poolPairs
+}
+
+func TestIncClient_GetAllShares(t *testing.T) {
+	nftID := common.Hash{9}.String()
+	poolPairs := newTestSharePools(nftID, numTests, func(i int) uint64 {
+		return uint64(i) // the first pool has no share
+	})
+	ts := newPdexStateServer(jsonresult.CurrentPdexState{PoolPairs: poolPairs})
+	defer ts.Close()
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+
+	shares, err := client.GetAllShares(0, nftID)
+	assert.Equal(t, nil, err, fmt.Errorf("GetAllShares error: %v", err))
+	assert.Equal(t, numTests-1, len(shares))
+	for i := 1; i < len(shares); i++ {
+		prev, cur := shares[i-1], shares[i]
+		assert.True(t, prev.TokenID1Str < cur.TokenID1Str ||
+			(prev.TokenID1Str == cur.TokenID1Str && prev.TokenID2Str < cur.TokenID2Str) ||
+			(prev.TokenID1Str == cur.TokenID1Str && prev.TokenID2Str == cur.TokenID2Str && prev.PoolID < cur.PoolID),
+			fmt.Sprintf("shares %v and %v are not sorted", *prev, *cur))
+	}
+	for i := 0; i < numTests; i++ {
+		tmpShares, err := client.GetAllShares(0, nftID)
+		assert.Equal(t, nil, err, fmt.Errorf("GetAllShares error: %v", err))
+		assert.Equal(t, shares, tmpShares)
+	}
+
+	shares, err = client.GetAllShares(0, common.Hash{11}.String())
+	assert.Equal(t, nil, err, fmt.Errorf("GetAllShares error: %v", err))
+	assert.Equal(t, 0, len(shares))
+}
+
+func TestIncClient_GetSharesPaged(t *testing.T) {
+	nftID := common.Hash{9}.String()
+	poolPairs := newTestSharePools(nftID, 53, func(i int) uint64 {
+		return uint64(i % 5) // every fifth pool has no share
+	})
+	numCalls := int32(0)
+	handler := pdexStateHandler(jsonresult.CurrentPdexState{PoolPairs: poolPairs, NftIDs: map[string]uint64{nftID: 1}})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&numCalls, 1)
+		handler(w, r)
+	}))
+	defer ts.Close()
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+
+	allShares, err := client.GetAllShares(0, nftID)
+	assert.Equal(t, nil, err, fmt.Errorf("GetAllShares error: %v", err))
+
+	// paging through a snapshot retrieves the pDEX state once.
+	for _, limit := range []int{1, 7, 100} {
+		atomic.StoreInt32(&numCalls, 0)
+		snapshot, err := client.GetSharesSnapshot(nftID)
+		assert.Equal(t, nil, err, fmt.Errorf("GetSharesSnapshot error: %v", err))
+		assert.Equal(t, nftID, snapshot.NftID())
+		assert.Equal(t, len(allShares), snapshot.Total())
+		shares := make([]*Share, 0)
+		for offset := 0; ; offset += limit {
+			page, total, err := snapshot.Page(offset, limit)
+			assert.Equal(t, nil, err, fmt.Errorf("Page error: %v", err))
+			assert.Equal(t, len(allShares), total)
+			if len(page) == 0 {
+				break
+			}
+			assert.True(t, len(page) <= limit)
+			shares = append(shares, page...)
+		}
+		assert.Equal(t, allShares, shares)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&numCalls))
+	}
+
+	// the pages are copies of the shares in the snapshot.
+	snapshot, err := client.GetSharesSnapshot(nftID)
+	assert.Equal(t, nil, err, fmt.Errorf("GetSharesSnapshot error: %v", err))
+	page, _, err := snapshot.Page(1, 1)
+	assert.Equal(t, nil, err, fmt.Errorf("Page error: %v", err))
+	page[0].ShareAmount = 0
+	page, _, err = snapshot.Page(1, 1)
+	assert.Equal(t, nil, err, fmt.Errorf("Page error: %v", err))
+	assert.Equal(t, allShares[1], page[0])
+	_, _, err = snapshot.Page(-1, 10)
+	assert.NotEqual(t, nil, err)
+	_, _, err = snapshot.Page(0, 0)
+	assert.NotEqual(t, nil, err)
+
+	// GetSharesPaged retrieves the latest pDEX state on every call.
+	atomic.StoreInt32(&numCalls, 0)
+	for offset := 0; offset < len(allShares); offset += 10 {
+		page, total, err := client.GetSharesPaged(nftID, offset, 10)
+		assert.Equal(t, nil, err, fmt.Errorf("GetSharesPaged error: %v", err))
+		assert.Equal(t, len(allShares), total)
+		assert.Equal(t, allShares[offset:offset+len(page)], page)
+	}
+	assert.Equal(t, int32((len(allShares)+9)/10), atomic.LoadInt32(&numCalls))
+
+	page, total, err := client.GetSharesPaged(common.Hash{11}.String(), 0, 10)
+	assert.Equal(t, nil, err, fmt.Errorf("GetSharesPaged error: %v", err))
+	assert.Equal(t, 0, total)
+	assert.Equal(t, 0, len(page))
+
+	_, _, err = client.GetSharesPaged(nftID, -1, 10)
+	assert.NotEqual(t, nil, err)
+	_, _, err = client.GetSharesPaged(nftID, 0, 0)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestIncClient_GetTradeValue(t *testing.T) {
+	tokenA := common.PRVIDStr
+	tokenB := common.Hash{6}.String()
+	// both pools have the same reserves, but pool-AB-1 has a specific fee rate.
+	poolPairs := map[string]*jsonresult.Pdexv3PoolPairState{
+		"pool-AB-1": newTestPoolPair(tokenA, tokenB, 1000000, 2000000),
+		"pool-AB-2": newTestPoolPair(tokenA, tokenB, 1000000, 2000000),
+	}
+	params := &jsonresult.Pdexv3Params{
+		DefaultFeeRateBPS: 30,
+		FeeRateBPS:        map[string]uint{"pool-AB-1": 10},
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Params []struct {
+				Filter map[string]interface{}
+			}
+		}
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil || len(req.Params) == 0 {
+			http.Error(w, "invalid params", http.StatusBadRequest)
+			return
+		}
+
+		var result jsonresult.CurrentPdexState
+		switch req.Params[0].Filter["Key"] {
+		case Params:
+			result.Params = params
+		case PoolPair:
+			poolID, _ := req.Params[0].Filter["ID"].(string)
+			result.PoolPairs = map[string]*jsonresult.Pdexv3PoolPairState{poolID: poolPairs[poolID]}
+		default:
+			result.PoolPairs = poolPairs
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"Result": result})
+	}))
+	defer ts.Close()
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+
+	sellAmount := uint64(12345)
+	expectedBuyAmount, err := client.CheckPrice("pool-AB-1", tokenA, sellAmount)
+	assert.Equal(t, nil, err)
+
+	// the pool-specific fee rate: ceil(12345 * 10 / 10000) = 13.
+	buyAmount, tradingFee, err := client.GetTradeValue("pool-AB-1", tokenA, sellAmount)
+	assert.Equal(t, nil, err, fmt.Errorf("GetTradeValue error: %v", err))
+	assert.Equal(t, expectedBuyAmount, buyAmount)
+	assert.Equal(t, uint64(13), tradingFee)
+
+	// the default fee rate: ceil(12345 * 30 / 10000) = 38.
+	buyAmount, tradingFee, err = client.GetTradeValue("pool-AB-2", tokenA, sellAmount)
+	assert.Equal(t, nil, err, fmt.Errorf("GetTradeValue error: %v", err))
+	assert.Equal(t, expectedBuyAmount, buyAmount)
+	assert.Equal(t, uint64(38), tradingFee)
+
+	assert.Equal(t, uint(10), params.GetFeeRateBPS("pool-AB-1"))
+	assert.Equal(t, uint(30), params.GetFeeRateBPS("pool-AB-2"))
+
+	// invalid inputs.
+	_, _, err = client.GetTradeValue("pool-AB-3", tokenA, sellAmount)
+	assert.NotEqual(t, nil, err)
+	_, _, err = client.GetTradeValue("pool-AB-1", common.Hash{7}.String(), sellAmount)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestCalcPathMinOutput(t *testing.T) {
+	slippageBps := uint64(100) // 1%
+
+	// a single hop: 1000000 * 0.99 = 990000.
+	singleHopMin := CalcPathMinOutput([]uint64{1000000}, slippageBps)
+	assert.Equal(t, uint64(990000), singleHopMin)
+
+	// three hops with the same end-to-end quote: 1000000 * 0.99^3 = 970299.
+	threeHopMin := CalcPathMinOutput([]uint64{5000, 300000, 1000000}, slippageBps)
+	assert.Equal(t, uint64(970299), threeHopMin)
+	assert.Equal(t, true, threeHopMin < singleHopMin)
+
+	// only the end-to-end quote matters.
+	assert.Equal(t, threeHopMin, CalcPathMinOutput([]uint64{1, 2, 1000000}, slippageBps))
+
+	// no slippage.
+	assert.Equal(t, uint64(1000000), CalcPathMinOutput([]uint64{5000, 300000, 1000000}, 0))
+
+	// large amounts do not overflow.
+	assert.Equal(t, uint64(18264723299588073368), CalcPathMinOutput([]uint64{1, 2, 18446744073709551615}, 33))
+
+	// invalid inputs.
+	assert.Equal(t, uint64(0), CalcPathMinOutput(nil, slippageBps))
+	assert.Equal(t, uint64(0), CalcPathMinOutput([]uint64{1000000}, jsonresult.BPS))
+}
+
+func TestIncClient_QuoteTradePath(t *testing.T) {
+	tokenA := common.PRVIDStr
+	tokenB := common.Hash{6}.String()
+	tokenC := common.Hash{7}.String()
+	poolPairs := map[string]*jsonresult.Pdexv3PoolPairState{
+		"pool-AB": newTestPoolPair(tokenA, tokenB, 1000000, 2000000),
+		"pool-CB": newTestPoolPair(tokenC, tokenB, 4000000, 1000000),
+	}
+	ts := newPdexStateServer(jsonresult.CurrentPdexState{PoolPairs: poolPairs})
+	defer ts.Close()
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+
+	// A -> B -> C: each hop is priced on the output of the previous one.
+	sellAmount := uint64(1000)
+	firstHop, err := client.CheckPrice("pool-AB", tokenA, sellAmount)
+	assert.Equal(t, nil, err)
+	secondHop, err := client.CheckPrice("pool-CB", tokenB, firstHop)
+	assert.Equal(t, nil, err)
+	expectedPerHop, err := client.QuoteTradePath([]string{"pool-AB", "pool-CB"}, tokenA, sellAmount)
+	assert.Equal(t, nil, err, fmt.Errorf("QuoteTradePath error: %v", err))
+	assert.Equal(t, []uint64{firstHop, secondHop}, expectedPerHop)
+
+	// invalid paths.
+	_, err = client.QuoteTradePath(nil, tokenA, sellAmount)
+	assert.NotEqual(t, nil, err)
+	_, err = client.QuoteTradePath([]string{"pool-AB", "pool-AD"}, tokenA, sellAmount)
+	assert.NotEqual(t, nil, err)
+	_, err = client.QuoteTradePath([]string{"pool-CB"}, tokenA, sellAmount)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestIncClient_GetPoolReserves(t *testing.T) {
+	tokenA := common.PRVIDStr
+	tokenB := common.Hash{6}.String()
+	tokenC := common.Hash{7}.String()
+	tokenD := common.Hash{8}.String()
+	poolPairs := map[string]*jsonresult.Pdexv3PoolPairState{
+		"pool-BA-1": newTestPoolPair(tokenB, tokenA, 2000, 1000),
+		"pool-AB-2": newTestPoolPair(tokenA, tokenB, 3000, 9000),
+		"pool-CB":   newTestPoolPair(tokenC, tokenB, 0, 0),
+	}
+
+	ts := newPdexStateServer(jsonresult.CurrentPdexState{PoolPairs: poolPairs})
+	defer ts.Close()
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+
+	// the most liquid pool is used, whatever the order of the tokens.
+	reserve1, reserve2, err := client.GetPoolReserves(tokenA, tokenB)
+	assert.Equal(t, nil, err, fmt.Errorf("GetPoolReserves error: %v", err))
+	assert.Equal(t, uint64(3000), reserve1)
+	assert.Equal(t, uint64(9000), reserve2)
+
+	reserve1, reserve2, err = client.GetPoolReserves(tokenB, tokenA)
+	assert.Equal(t, nil, err, fmt.Errorf("GetPoolReserves error: %v", err))
+	assert.Equal(t, uint64(9000), reserve1)
+	assert.Equal(t, uint64(3000), reserve2)
+
+	// a pool with zero reserves
+	reserve1, reserve2, err = client.GetPoolReserves(tokenB, tokenC)
+	assert.Equal(t, nil, err, fmt.Errorf("GetPoolReserves error: %v", err))
+	assert.Equal(t, uint64(0), reserve1)
+	assert.Equal(t, uint64(0), reserve2)
+
+	// no pool for the pair
+	_, _, err = client.GetPoolReserves(tokenA, tokenD)
+	assert.Equal(t, ErrPoolNotFound, err)
+	_, _, err = client.GetPoolReserves(tokenA, tokenA)
+	assert.NotEqual(t, nil, err)
+	assert.NotEqual(t, ErrPoolNotFound, err)
+}
+
+func TestGetTradeValueWithSlippage(t *testing.T) {
+	tokenA := common.PRVIDStr
+	tokenB := common.Hash{6}.String()
+	tokenC := common.Hash{7}.String()
+	poolPairs := map[string]*jsonresult.Pdexv3PoolPairState{
+		"pool-AB-1": newTestPoolPair(tokenA, tokenB, 1000000, 2000000),
+		"pool-BA-3": newTestPoolPair(tokenB, tokenA, 4000000, 1000000),
+		"pool-AC-1": newTestPoolPair(tokenA, tokenC, 1000000, 9000000),
+	}
+
+	// selling 1000 A gives 3996 B with the best pool (pool-BA-3).
+	testCases := []struct {
+		slippageBps, expectedMin uint64
+	}{
+		{0, 3996},
+		{50, 3976},  // 3976.02
+		{33, 3982},  // 3982.8132
+		{100, 3956}, // 3956.04
+		{9999, 0},   // 0.3996
+		{10000, 0},
+	}
+	for _, tc := range testCases {
+		expected, minAcceptable, err := GetTradeValueWithSlippage(tokenA, tokenB, 1000, tc.slippageBps, poolPairs)
+		assert.Equal(t, nil, err, fmt.Errorf("GetTradeValueWithSlippage error: %v", err))
+		assert.Equal(t, uint64(3996), expected)
+		assert.Equal(t, tc.expectedMin, minAcceptable, fmt.Errorf("slippage %v BPS", tc.slippageBps))
+	}
+
+	// invalid slippage
+	_, _, err := GetTradeValueWithSlippage(tokenA, tokenB, 1000, jsonresult.BPS+1, poolPairs)
+	assert.NotEqual(t, nil, err)
+
+	// no pool for the pair
+	_, _, err = GetTradeValueWithSlippage(tokenB, tokenC, 1000, 50, poolPairs)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestFindBestTradeRoute(t *testing.T) {
+	tokenA := common.PRVIDStr
+	tokenX := common.Hash{6}.String()
+	tokenS := common.Hash{7}.String() // a stablecoin
+	tokenY := common.Hash{8}.String()
+	tokenZ := common.Hash{9}.String()
+	// X has a shallow pool against PRV, but deep liquidity against the stablecoin.
+	poolPairs := map[string]*jsonresult.Pdexv3PoolPairState{
+		"pool-AX": newTestPoolPair(tokenA, tokenX, 1000, 1000),
+		"pool-AS": newTestPoolPair(tokenA, tokenS, 1000000, 1000000),
+		"pool-SX": newTestPoolPair(tokenS, tokenX, 1000000, 1000000),
+		"pool-YX": newTestPoolPair(tokenY, tokenX, 1000000, 1000000),
+		"pool-Z":  newTestPoolPair(tokenZ, common.Hash{10}.String(), 1000000, 1000000),
+	}
+
+	testCases := []struct {
+		tokenToBuy     string
+		maxHops        int
+		expectedPath   []string
+		expectedAmount uint64
+	}{
+		{tokenX, 1, []string{"pool-AX"}, 500},
+		{tokenX, 2, []string{"pool-AS", "pool-SX"}, 998},
+		{tokenX, 5, []string{"pool-AS", "pool-SX"}, 998},
+		{tokenY, 2, []string{"pool-AX", "pool-YX"}, 499},
+		{tokenY, 3, []string{"pool-AS", "pool-SX", "pool-YX"}, 997},
+		{tokenS, 3, []string{"pool-AS"}, 999},
+	}
+	for _, tc := range testCases {
+		path, amount, err := FindBestTradeRoute(tokenA, tc.tokenToBuy, 1000, poolPairs, tc.maxHops)
+		assert.Equal(t, nil, err, fmt.Errorf("FindBestTradeRoute error: %v", err))
+		assert.Equal(t, tc.expectedPath, path)
+		assert.Equal(t, tc.expectedAmount, amount)
+	}
+
+	// no path within maxHops
+	_, _, err := FindBestTradeRoute(tokenA, tokenY, 1000, poolPairs, 1)
+	assert.NotEqual(t, nil, err)
+	_, _, err = FindBestTradeRoute(tokenA, tokenZ, 1000, poolPairs, 5)
+	assert.NotEqual(t, nil, err)
+
+	// invalid inputs
+	_, _, err = FindBestTradeRoute(tokenA, tokenX, 1000, poolPairs, 0)
+	assert.NotEqual(t, nil, err)
+	_, _, err = FindBestTradeRoute(tokenA, tokenX, 1000, poolPairs, 6)
+	assert.NotEqual(t, nil, err)
+	_, _, err = FindBestTradeRoute(tokenA, tokenA, 1000, poolPairs, 2)
+	assert.NotEqual(t, nil, err)
+	_, _, err = FindBestTradeRoute(tokenA, tokenX, 0, poolPairs, 2)
+	assert.NotEqual(t, nil, err)
+
+	// holding more of an intermediate token does not always lead to a better output: A -> B -> M gives more M than
+	// A -> C -> M, but the best route goes through M and then B, which the former has already visited.
+	tokenB := common.Hash{11}.String()
+	tokenC := common.Hash{12}.String()
+	tokenM := common.Hash{13}.String()
+	tokenT := common.Hash{14}.String()
+	poolPairs = map[string]*jsonresult.Pdexv3PoolPairState{
+		"pool-AB":   newTestPoolPair(tokenA, tokenB, 1000000, 1000000),
+		"pool-AC":   newTestPoolPair(tokenA, tokenC, 1000000, 1000000),
+		"pool-BM-1": newTestPoolPair(tokenB, tokenM, 1000000, 10000000),
+		"pool-BM-2": newTestPoolPair(tokenB, tokenM, 10000000, 10000000),
+		"pool-CM":   newTestPoolPair(tokenC, tokenM, 1000000, 5000000),
+		"pool-BT":   newTestPoolPair(tokenB, tokenT, 1000000, 1000000),
+	}
+	path, amount, err := FindBestTradeRoute(tokenA, tokenT, 1000, poolPairs, 4)
+	assert.Equal(t, nil, err, fmt.Errorf("FindBestTradeRoute error: %v", err))
+	assert.Equal(t, []string{"pool-AC", "pool-CM", "pool-BM-2", "pool-BT"}, path)
+	assert.Equal(t, uint64(4962), amount)
+	_, viaB, err := FindBestTradeRoute(tokenA, tokenT, 1000, poolPairs, 2)
+	assert.Equal(t, nil, err, fmt.Errorf("FindBestTradeRoute error: %v", err))
+	assert.True(t, viaB < amount)
+
+	// a dense graph: every pair of 20 tokens has two pools, the one with the lower ID being shallower, and the pool
+	// between the first and the last token is the shallowest.
+	tokens := make([]string, 20)
+	for i := range tokens {
+		tokens[i] = common.Hash{byte(i + 1), 1}.String()
+	}
+	densePools := make(map[string]*jsonresult.Pdexv3PoolPairState)
+	for i := range tokens {
+		for j := i + 1; j < len(tokens); j++ {
+			reserve := uint64(1000000)
+			if i == 0 && j == len(tokens)-1 {
+				reserve = 10000
+			}
+			densePools[fmt.Sprintf("pool-%v-%v-1", i, j)] = newTestPoolPair(tokens[i], tokens[j], reserve/2, reserve/2)
+			densePools[fmt.Sprintf("pool-%v-%v-2", i, j)] = newTestPoolPair(tokens[i], tokens[j], reserve, reserve)
+		}
+	}
+	start := time.Now()
+	path, amount, err = FindBestTradeRoute(tokens[0], tokens[len(tokens)-1], 1000, densePools, metadataPdexv3.MaxTradePathLength)
+	assert.Equal(t, nil, err, fmt.Errorf("FindBestTradeRoute error: %v", err))
+	assert.Equal(t, []string{"pool-0-1-2", fmt.Sprintf("pool-1-%v-2", len(tokens)-1)}, path)
+	assert.Equal(t, uint64(998), amount)
+	assert.True(t, time.Since(start) < 10*time.Second, "the search took too long")
+}
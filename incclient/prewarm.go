@@ -0,0 +1,230 @@
+package incclient
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"github.com/incognitochain/go-incognito-sdk-v2/crypto"
+	"github.com/incognitochain/go-incognito-sdk-v2/privacy"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/rpc"
+	"github.com/incognitochain/go-incognito-sdk-v2/transaction/utils"
+)
+
+// DefaultFeeRateCacheTTL is the default time-to-live of the fee rates cached by an IncClient.
+const DefaultFeeRateCacheTTL = 1 * time.Minute
+
+// prewarmNumDecoys is the number of decoys pre-fetched by Prewarm for each shard and token, i.e, enough for a few
+// transactions with several inputs.
+const prewarmNumDecoys = 10 * (privacy.RingSize - 1)
+
+// feeRateCache keeps the fee rates (per kb) returned by the remote node for a short amount of time.
+type feeRateCache struct {
+	mtx   *sync.Mutex
+	ttl   time.Duration
+	rates map[string]cachedFeeRate
+}
+
+type cachedFeeRate struct {
+	feePerKB  uint64
+	updatedAt time.Time
+}
+
+func newFeeRateCache() *feeRateCache {
+	return &feeRateCache{mtx: new(sync.Mutex), ttl: DefaultFeeRateCacheTTL, rates: make(map[string]cachedFeeRate)}
+}
+
+// SetFeeRateCacheTTL sets the time-to-live of the cached fee rates, and invalidates the current cached values.
+// A non-positive ttl disables the cache.
+func (client *IncClient) SetFeeRateCacheTTL(ttl time.Duration) {
+	cache := client.feeRateCache
+	cache.mtx.Lock()
+	defer cache.mtx.Unlock()
+
+	cache.ttl = ttl
+	cache.rates = make(map[string]cachedFeeRate)
+}
+
+func shardTokenKey(shardID byte, tokenID string) string {
+	return fmt.Sprintf("%v-%v", shardID, tokenID)
+}
+
+// getFeePerKB returns the fee rate (per kb) of the given shard and token, from the cache if possible.
+func (client *IncClient) getFeePerKB(shardID byte, tokenID string) (uint64, error) {
+	cache := client.feeRateCache
+	key := shardTokenKey(shardID, tokenID)
+	cache.mtx.Lock()
+	rate, ok := cache.rates[key]
+	ttl := cache.ttl
+	cache.mtx.Unlock()
+	if ok && ttl > 0 && time.Since(rate.updatedAt) < ttl {
+		return rate.feePerKB, nil
+	}
+
+	return client.fetchFeePerKB(client.rpcServer, shardID, tokenID)
+}
+
+// fetchFeePerKB retrieves the fee rate (per kb) of the given shard and token from server, and caches it.
+func (client *IncClient) fetchFeePerKB(server *rpc.RPCServer, shardID byte, tokenID string) (uint64, error) {
+	responseInBytes, err := server.EstimateFeeWithEstimator(-1, shardID, 10, tokenID)
+	if err != nil {
+		return 0, err
+	}
+
+	var feeEstimateResult rpc.EstimateFeeResult
+	err = rpchandler.ParseResponse(responseInBytes, &feeEstimateResult)
+	if err != nil {
+		return 0, err
+	}
+
+	cache := client.feeRateCache
+	cache.mtx.Lock()
+	if cache.ttl > 0 {
+		cache.rates[shardTokenKey(shardID, tokenID)] = cachedFeeRate{feePerKB: feeEstimateResult.EstimateFeeCoinPerKb, updatedAt: time.Now()}
+	}
+	cache.mtx.Unlock()
+
+	return feeEstimateResult.EstimateFeeCoinPerKb, nil
+}
+
+// decoyCache keeps pre-fetched random coins (decoys) of each shard and token. Each decoy is handed out once.
+type decoyCache struct {
+	mtx    *sync.Mutex
+	decoys map[string]*decoyList
+}
+
+// decoyList consists of parallel lists of decoys, in the format of getRandomCommitmentV2. assetTags is empty for
+// PRV decoys.
+type decoyList struct {
+	indices     []uint64
+	commitments []*crypto.Point
+	publicKeys  []*crypto.Point
+	assetTags   []*crypto.Point
+}
+
+func newDecoyCache() *decoyCache {
+	return &decoyCache{mtx: new(sync.Mutex), decoys: make(map[string]*decoyList)}
+}
+
+// add appends the decoys returned by fetchRandomCommitmentV2 to the cache.
+func (cache *decoyCache) add(shardID byte, tokenID string, kvArgs map[string]interface{}) error {
+	indices, ok1 := kvArgs[utils.CommitmentIndices].([]uint64)
+	commitments, ok2 := kvArgs[utils.Commitments].([]*crypto.Point)
+	publicKeys, ok3 := kvArgs[utils.PublicKeys].([]*crypto.Point)
+	assetTags, ok4 := kvArgs[utils.AssetTags].([]*crypto.Point)
+	if !ok1 || !ok2 || !ok3 || !ok4 || len(commitments) != len(indices) || len(publicKeys) != len(indices) {
+		return fmt.Errorf("invalid random commitments")
+	}
+	if len(assetTags) != len(indices) {
+		assetTags = nil
+	}
+
+	cache.mtx.Lock()
+	defer cache.mtx.Unlock()
+
+	key := shardTokenKey(shardID, tokenID)
+	list, ok := cache.decoys[key]
+	if !ok || (len(list.assetTags) == 0) != (len(assetTags) == 0) {
+		list = &decoyList{}
+		cache.decoys[key] = list
+	}
+	list.indices = append(list.indices, indices...)
+	list.commitments = append(list.commitments, commitments...)
+	list.publicKeys = append(list.publicKeys, publicKeys...)
+	list.assetTags = append(list.assetTags, assetTags...)
+
+	return nil
+}
+
+// take removes lenDecoy decoys of the given shard and token from the cache, and returns them in the format of
+// getRandomCommitmentV2. It returns false if the cache does not hold enough decoys.
+func (cache *decoyCache) take(shardID byte, tokenID string, lenDecoy int) (map[string]interface{}, bool) {
+	cache.mtx.Lock()
+	defer cache.mtx.Unlock()
+
+	list, ok := cache.decoys[shardTokenKey(shardID, tokenID)]
+	if !ok || len(list.indices) < lenDecoy {
+		return nil, false
+	}
+
+	result := make(map[string]interface{})
+	result[utils.CommitmentIndices] = append([]uint64{}, list.indices[:lenDecoy]...)
+	result[utils.Commitments] = append([]*crypto.Point{}, list.commitments[:lenDecoy]...)
+	result[utils.PublicKeys] = append([]*crypto.Point{}, list.publicKeys[:lenDecoy]...)
+	assetTags := make([]*crypto.Point, 0)
+	if len(list.assetTags) > 0 {
+		assetTags = append(assetTags, list.assetTags[:lenDecoy]...)
+		list.assetTags = list.assetTags[lenDecoy:]
+	}
+	result[utils.AssetTags] = assetTags
+	list.indices = list.indices[lenDecoy:]
+	list.commitments = list.commitments[lenDecoy:]
+	list.publicKeys = list.publicKeys[lenDecoy:]
+
+	return result, true
+}
+
+// Prewarm populates the caches used when building transactions of the given tokens (PRV is always included), so
+// that subsequent builds do not wait for these queries. For each shard and token, it concurrently
+//   - pre-fetches random coins used as decoys; each of them is used by one transaction only;
+//   - caches the fee rate (see SetFeeRateCacheTTL);
+//   - caches the decimals of the token (see GetTokenDecimals); tokens whose decimals cannot be determined (e.g,
+//     non-bridge tokens) are skipped.
+//
+// The ring size is a constant of the protocol (privacy.RingSize), hence needs no pre-fetching.
+//
+// It returns the first error encountered, or ctx.Err() if ctx is done before all caches are populated.
+func (client *IncClient) Prewarm(ctx context.Context, tokenIDs []string) error {
+	tokenSet := map[string]bool{common.PRVIDStr: true}
+	for _, tokenID := range tokenIDs {
+		if _, err := (common.Hash{}).NewHashFromStr(tokenID); err != nil {
+			return fmt.Errorf("invalid tokenID %v: %v", tokenID, err)
+		}
+		tokenSet[tokenID] = true
+	}
+	server := client.rpcServer.WithContext(ctx)
+
+	var wg sync.WaitGroup
+	errCh := make(chan error, 2*common.MaxShardNumber*len(tokenSet)+len(tokenSet))
+	for tokenID := range tokenSet {
+		for shardID := 0; shardID < common.MaxShardNumber; shardID++ {
+			wg.Add(2)
+			go func(shardID byte, tokenID string) {
+				defer wg.Done()
+				kvArgs, err := fetchRandomCommitmentV2(server, shardID, tokenID, prewarmNumDecoys)
+				if err == nil {
+					err = client.decoyCache.add(shardID, tokenID, kvArgs)
+				}
+				if err != nil {
+					errCh <- fmt.Errorf("cannot pre-fetch decoys of token %v, shard %v: %v", tokenID, shardID, err)
+				}
+			}(byte(shardID), tokenID)
+			go func(shardID byte, tokenID string) {
+				defer wg.Done()
+				if _, err := client.fetchFeePerKB(server, shardID, tokenID); err != nil {
+					errCh <- fmt.Errorf("cannot pre-fetch the fee rate of token %v, shard %v: %v", tokenID, shardID, err)
+				}
+			}(byte(shardID), tokenID)
+		}
+		if tokenID != common.PRVIDStr {
+			wg.Add(1)
+			go func(tokenID string) {
+				defer wg.Done()
+				_, _ = client.GetTokenDecimals(tokenID)
+			}(tokenID)
+		}
+	}
+	wg.Wait()
+	close(errCh)
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	for err := range errCh {
+		return err
+	}
+	return nil
+}
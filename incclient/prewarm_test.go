@@ -0,0 +1,155 @@
+package incclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/rpc"
+	"github.com/incognitochain/go-incognito-sdk-v2/wallet"
+	"github.com/stretchr/testify/assert"
+)
+
+// feeCoinServer wraps a mockCoinServer, and additionally serves fee estimations and bridge tokens.
+type feeCoinServer struct {
+	*mockCoinServer
+	feePerKB uint64
+}
+
+func (s *feeCoinServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var req struct {
+		Method string
+	}
+	_ = json.Unmarshal(body, &req)
+
+	var result interface{}
+	switch req.Method {
+	case "estimatefeewithestimator":
+		result = rpc.EstimateFeeResult{EstimateFeeCoinPerKb: s.feePerKB}
+	case "getallbridgetokens":
+		result = []interface{}{}
+	default:
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		s.mockCoinServer.ServeHTTP(w, r)
+		return
+	}
+
+	s.mtx.Lock()
+	s.numCalls[req.Method]++
+	s.mtx.Unlock()
+	resultBytes, _ := json.Marshal(result)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"Result": json.RawMessage(resultBytes)})
+}
+
+func TestIncClient_Prewarm(t *testing.T) {
+	shardID := byte(common.RandInt() % common.MaxShardNumber)
+	senderWallet, err := wallet.GenRandomWalletForShardID(shardID)
+	if err != nil {
+		panic(err)
+	}
+	privateKey := senderWallet.Base58CheckSerialize(wallet.PrivateKeyType)
+
+	server := &feeCoinServer{mockCoinServer: newMockCoinServer(), feePerKB: 10}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	err = server.addCoins(senderWallet.KeySet.PaymentAddress, 10)
+	if err != nil {
+		panic(err)
+	}
+
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+	client.SetCoinStore(NewMemCoinStore())
+
+	// a cancelled context aborts the pre-warming.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = client.Prewarm(ctx, nil)
+	assert.Equal(t, context.Canceled, err)
+
+	err = client.Prewarm(context.Background(), []string{"invalid token"})
+	assert.NotEqual(t, nil, err)
+
+	err = client.Prewarm(context.Background(), nil)
+	assert.Equal(t, nil, err, fmt.Errorf("Prewarm error: %v", err))
+	assert.Equal(t, common.MaxShardNumber, server.numCalls["estimatefeewithestimator"])
+	numDecoyCalls := server.numCalls["randomcommitmentsandpublickeys"]
+	assert.Equal(t, common.MaxShardNumber, numDecoyCalls)
+
+	// subsequent builds do not query the decoys nor the fee rate.
+	for i := 0; i < 3; i++ {
+		receiverWallet, err := wallet.GenRandomWalletForShardID(byte(common.RandInt() % common.MaxShardNumber))
+		if err != nil {
+			panic(err)
+		}
+		receiver := receiverWallet.Base58CheckSerialize(wallet.PaymentAddressType)
+		param := NewTxParam(privateKey, []string{receiver}, []uint64{1 + common.RandUint64()%1000}, 100, nil, nil, nil)
+
+		_, _, err = client.CreateRawTransaction(param, 2)
+		assert.Equal(t, nil, err, fmt.Errorf("CreateRawTransaction error: %v", err))
+
+		fee, err := client.EstimateTxFee(param)
+		assert.Equal(t, nil, err, fmt.Errorf("EstimateTxFee error: %v", err))
+		assert.NotEqual(t, uint64(0), fee)
+	}
+	assert.Equal(t, numDecoyCalls, server.numCalls["randomcommitmentsandpublickeys"])
+	assert.Equal(t, common.MaxShardNumber, server.numCalls["estimatefeewithestimator"])
+
+	// disabling the fee-rate cache makes the client query the fee rate again.
+	client.SetFeeRateCacheTTL(0)
+	_, err = client.EstimateTxFee(NewTxParam(privateKey, nil, nil, 100, nil, nil, nil))
+	assert.Equal(t, nil, err, fmt.Errorf("EstimateTxFee error: %v", err))
+	assert.Equal(t, common.MaxShardNumber+1, server.numCalls["estimatefeewithestimator"])
+}
+
+func TestIncClient_Prewarm_WithContext(t *testing.T) {
+	shardID := byte(common.RandInt() % common.MaxShardNumber)
+	senderWallet, err := wallet.GenRandomWalletForShardID(shardID)
+	if err != nil {
+		panic(err)
+	}
+	privateKey := senderWallet.Base58CheckSerialize(wallet.PrivateKeyType)
+	receiver := PrivateKeyToPaymentAddress(privateKey, -1)
+
+	server := &feeCoinServer{mockCoinServer: newMockCoinServer(), feePerKB: 10}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	err = server.addCoins(senderWallet.KeySet.PaymentAddress, 10)
+	if err != nil {
+		panic(err)
+	}
+
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+	client.SetCoinStore(NewMemCoinStore())
+
+	// caches warmed through a copy returned by WithContext are used by the original client.
+	ctx, cancel := context.WithCancel(context.Background())
+	err = client.WithContext(ctx).Prewarm(ctx, nil)
+	cancel()
+	assert.Equal(t, nil, err, fmt.Errorf("Prewarm error: %v", err))
+	numDecoyCalls := server.numCalls["randomcommitmentsandpublickeys"]
+	numFeeCalls := server.numCalls["estimatefeewithestimator"]
+
+	_, _, err = client.CreateRawTransaction(NewTxParam(privateKey, []string{receiver}, []uint64{1000}, 100, nil, nil, nil), 2)
+	assert.Equal(t, nil, err, fmt.Errorf("CreateRawTransaction error: %v", err))
+	_, err = client.EstimateTxFee(NewTxParam(privateKey, []string{receiver}, []uint64{1000}, 100, nil, nil, nil))
+	assert.Equal(t, nil, err, fmt.Errorf("EstimateTxFee error: %v", err))
+	assert.Equal(t, numDecoyCalls, server.numCalls["randomcommitmentsandpublickeys"])
+	assert.Equal(t, numFeeCalls, server.numCalls["estimatefeewithestimator"])
+
+	// and the settings of a copy apply to the original client.
+	client.WithContext(context.Background()).SetFeeRateCacheTTL(0)
+	_, err = client.EstimateTxFee(NewTxParam(privateKey, []string{receiver}, []uint64{1000}, 100, nil, nil, nil))
+	assert.Equal(t, nil, err, fmt.Errorf("EstimateTxFee error: %v", err))
+	assert.Equal(t, numFeeCalls+1, server.numCalls["estimatefeewithestimator"])
+}
@@ -0,0 +1,85 @@
+package incclient
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PrivacyWarningCode identifies the kind of a PrivacyWarning.
+type PrivacyWarningCode string
+
+// codes of the warnings returned by PrivacyHeuristics.
+const (
+	// RoundAmountWarning indicates that the amount has very few significant digits (e.g, 1.5 PRV).
+	RoundAmountWarning PrivacyWarningCode = "RoundAmount"
+
+	// RepeatedDigitsWarning indicates that the amount is made of a single repeated digit (e.g, 777777777).
+	RepeatedDigitsWarning PrivacyWarningCode = "RepeatedDigits"
+
+	// SingleRecipientWarning indicates that the transaction pays a single recipient.
+	SingleRecipientWarning PrivacyWarningCode = "SingleRecipient"
+)
+
+const (
+	// roundAmountMaxSignificantDigits is the maximum number of significant digits of a round amount.
+	roundAmountMaxSignificantDigits = 2
+
+	// roundAmountMinTrailingZeros is the minimum number of trailing zeros of a round amount.
+	roundAmountMinTrailingZeros = 3
+
+	// repeatedDigitsMinLength is the minimum number of digits of an amount made of a single repeated digit to be
+	// considered a pattern.
+	repeatedDigitsMinLength = 4
+)
+
+// PrivacyWarning is an advisory warning about a send that may help chain analysis link the transaction to its sender
+// or its recipients.
+type PrivacyWarning struct {
+	Code    PrivacyWarningCode
+	Message string
+}
+
+// String returns the string representation of a PrivacyWarning.
+func (w PrivacyWarning) String() string {
+	return fmt.Sprintf("[%v] %v", w.Code, w.Message)
+}
+
+// PrivacyHeuristics inspects the amount (in the smallest unit of the token) and the number of recipients of a send,
+// and returns warnings for patterns that make the transaction easier to link, such as round amounts (which stand out
+// among change outputs), amounts made of a repeated digit, and single-recipient sends (whose change output is easier
+// to tell apart from the payment).
+//
+// The result is advisory only: it is meant to be surfaced to users before they send, and does not prevent anything.
+// It returns nil if no pattern is detected.
+func PrivacyHeuristics(amount uint64, numRecipients int) []PrivacyWarning {
+	var res []PrivacyWarning
+
+	amountStr := strconv.FormatUint(amount, 10)
+	if amount > 0 {
+		significantDigits := strings.TrimRight(amountStr, "0")
+		numTrailingZeros := len(amountStr) - len(significantDigits)
+		if len(significantDigits) <= roundAmountMaxSignificantDigits && numTrailingZeros >= roundAmountMinTrailingZeros {
+			res = append(res, PrivacyWarning{
+				Code:    RoundAmountWarning,
+				Message: fmt.Sprintf("amount %v is a round number; consider adding a few random digits", amount),
+			})
+		}
+	}
+
+	if len(amountStr) >= repeatedDigitsMinLength && strings.Count(amountStr, amountStr[:1]) == len(amountStr) {
+		res = append(res, PrivacyWarning{
+			Code:    RepeatedDigitsWarning,
+			Message: fmt.Sprintf("amount %v is made of a single repeated digit", amount),
+		})
+	}
+
+	if numRecipients == 1 {
+		res = append(res, PrivacyWarning{
+			Code:    SingleRecipientWarning,
+			Message: "sending to a single recipient makes the change output easier to identify",
+		})
+	}
+
+	return res
+}
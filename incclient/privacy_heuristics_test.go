@@ -0,0 +1,47 @@
+package incclient
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrivacyHeuristics(t *testing.T) {
+	getCodes := func(warnings []PrivacyWarning) []PrivacyWarningCode {
+		var res []PrivacyWarningCode
+		for _, w := range warnings {
+			res = append(res, w.Code)
+		}
+		return res
+	}
+
+	testCases := []struct {
+		amount        uint64
+		numRecipients int
+		expected      []PrivacyWarningCode
+	}{
+		// round amounts
+		{1000000000, 2, []PrivacyWarningCode{RoundAmountWarning}},
+		{1500000000, 3, []PrivacyWarningCode{RoundAmountWarning}},
+		{5000, 1, []PrivacyWarningCode{RoundAmountWarning, SingleRecipientWarning}},
+		// non-round amounts
+		{1234567891, 2, nil},
+		{1230000000, 2, nil},
+		{500, 2, nil},
+		{0, 2, nil},
+		// repeated digits
+		{777777777, 2, []PrivacyWarningCode{RepeatedDigitsWarning}},
+		{999, 2, nil},
+		// single recipient
+		{1234567891, 1, []PrivacyWarningCode{SingleRecipientWarning}},
+	}
+
+	for _, tc := range testCases {
+		warnings := PrivacyHeuristics(tc.amount, tc.numRecipients)
+		assert.Equal(t, tc.expected, getCodes(warnings), fmt.Errorf("amount %v, %v recipients", tc.amount, tc.numRecipients))
+		for _, w := range warnings {
+			assert.NotEqual(t, "", w.Message)
+		}
+	}
+}
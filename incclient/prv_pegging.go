@@ -24,14 +24,13 @@ func (client *IncClient) CreateIssuingPRVPeggingRequestTransaction(
 		return nil, "", err
 	}
 
-	mdType := metadata.IssuingPRVERC20RequestMeta
+	networkID := rpc.ETHNetworkID
 	if len(evmNetworkID) > 0 {
-		switch evmNetworkID[0] {
-		case rpc.BSCNetworkID:
-			mdType = metadata.IssuingPRVBEP20RequestMeta
-		case rpc.PLGNetworkID:
-			return nil, "", rpc.EVMNetworkNotFoundError(evmNetworkID[0])
-		}
+		networkID = evmNetworkID[0]
+	}
+	mdType, ok := rpc.PRVPeggingIssuingMetadata[networkID]
+	if !ok {
+		return nil, "", rpc.EVMNetworkNotFoundError(networkID)
 	}
 
 	var issuingPRVPeggingRequestMeta *metadata.IssuingEVMRequest
@@ -64,6 +63,8 @@ func (client *IncClient) CreateAndSendIssuingPRVPeggingRequestTransaction(
 }
 
 // CreateBurningPRVPeggingRequestTransaction creates a PRV pegging burning transaction for exiting the Incognito network.
+// PRV pegging is available on the networks of rpc.PRVPeggingBurningMetadata (i.e, Ethereum and the Binance Smart Chain);
+// other evmNetworkIDs are rejected with rpc.EVMNetworkNotFoundError.
 //
 // It returns the base58-encoded transaction, the transaction's hash, and an error (if any).
 func (client *IncClient) CreateBurningPRVPeggingRequestTransaction(
@@ -88,14 +89,13 @@ func (client *IncClient) CreateBurningPRVPeggingRequestTransaction(
 		remoteAddress = remoteAddress[2:]
 	}
 
-	mdType := metadata.BurningPRVERC20RequestMeta
+	networkID := rpc.ETHNetworkID
 	if len(evmNetworkIDs) > 0 {
-		switch evmNetworkIDs[0] {
-		case rpc.BSCNetworkID:
-			mdType = metadata.BurningPRVBEP20RequestMeta
-		case rpc.PLGNetworkID:
-			return nil, "", rpc.EVMNetworkNotFoundError(evmNetworkIDs[0])
-		}
+		networkID = evmNetworkIDs[0]
+	}
+	mdType, ok := rpc.PRVPeggingBurningMetadata[networkID]
+	if !ok {
+		return nil, "", rpc.EVMNetworkNotFoundError(networkID)
 	}
 
 	var md *metadata.BurningRequest
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/incognitochain/go-incognito-sdk-v2/common"
 	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/rpc"
+	"github.com/incognitochain/go-incognito-sdk-v2/wallet"
 	"github.com/stretchr/testify/assert"
 	"testing"
 	"time"
@@ -178,3 +179,20 @@ func TestIncClient_UnShieldPRVPegging(t *testing.T) {
 		fmt.Printf("Finish getting the burning proof\n")
 	}
 }
+
+func TestIncClient_CreateBurningPRVPeggingRequestTransaction_UnsupportedNetwork(t *testing.T) {
+	w, err := wallet.GenRandomWalletForShardID(0)
+	if err != nil {
+		panic(err)
+	}
+	privateKey := w.Base58CheckSerialize(wallet.PrivateKeyType)
+	client := newIncClient(nil, nil, nil, 2)
+
+	for _, networkID := range []int{rpc.PLGNetworkID, rpc.FTMNetworkID} {
+		_, _, err = client.CreateBurningPRVPeggingRequestTransaction(privateKey, "0x0000000000000000000000000000000000000001", 100, networkID)
+		assert.Equal(t, rpc.EVMNetworkNotFoundError(networkID), err)
+
+		_, _, err = client.CreateIssuingPRVPeggingRequestTransaction(privateKey, EVMDepositProof{}, networkID)
+		assert.Equal(t, rpc.EVMNetworkNotFoundError(networkID), err)
+	}
+}
@@ -1,11 +1,101 @@
 package incclient
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"github.com/incognitochain/go-incognito-sdk-v2/common/base58"
+	"github.com/incognitochain/go-incognito-sdk-v2/internal/safemath"
+	"github.com/incognitochain/go-incognito-sdk-v2/key"
 	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler"
 	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/jsonresult"
+	"github.com/incognitochain/go-incognito-sdk-v2/wallet"
 )
 
+// RewardStats describes the reward pools of an epoch and the parameters used to distribute them among validators.
+type RewardStats struct {
+	// Epoch is the epoch of the reward pools.
+	Epoch uint64
+
+	// IsCurrentEpoch indicates whether Epoch is the on-going epoch, whose reward pools are still accumulating.
+	IsCurrentEpoch bool
+
+	// ShardRewards is the PRV reward pool of each shard.
+	ShardRewards map[byte]uint64
+
+	// TotalReward is the sum of all shard reward pools.
+	TotalReward uint64
+
+	// BeaconCommitteeSize is the number of beacon validators sharing the beacon reward.
+	BeaconCommitteeSize int
+
+	// ShardCommitteeSizes is the number of validators sharing the reward of each shard.
+	ShardCommitteeSizes map[byte]int
+}
+
+// GetRewardFeatureStats returns the reward pools of the given epoch as well as their distribution parameters.
+// If epoch = 0, it returns the statistics of the current epoch.
+//
+// Note that the committee sizes are retrieved from the latest beacon state, i.e. they reflect the committees at the
+// time of querying, not necessarily those of the given epoch.
+func (client *IncClient) GetRewardFeatureStats(epoch uint64) (*RewardStats, error) {
+	beaconState, err := client.GetBeaconBestState(0)
+	if err != nil {
+		return nil, err
+	}
+
+	isCurrentEpoch := false
+	if epoch == 0 || epoch == beaconState.Epoch {
+		epoch = beaconState.Epoch
+		isCurrentEpoch = true
+	} else if epoch > beaconState.Epoch {
+		return nil, fmt.Errorf("epoch %v has not started, current epoch: %v", epoch, beaconState.Epoch)
+	}
+
+	rewardResponses := make(map[byte][]byte)
+	for shardID := 0; shardID < common.MaxShardNumber; shardID++ {
+		responseInBytes, err := client.rpcServer.GetRewardAmountByEpoch(byte(shardID), epoch)
+		if err != nil {
+			return nil, err
+		}
+		rewardResponses[byte(shardID)] = responseInBytes
+	}
+
+	return newRewardStats(epoch, isCurrentEpoch, rewardResponses, beaconState)
+}
+
+// newRewardStats parses the raw per-shard reward responses and the beacon state into a RewardStats.
+func newRewardStats(epoch uint64, isCurrentEpoch bool, rewardResponses map[byte][]byte, beaconState *jsonresult.BeaconBestState) (*RewardStats, error) {
+	res := &RewardStats{
+		Epoch:               epoch,
+		IsCurrentEpoch:      isCurrentEpoch,
+		ShardRewards:        make(map[byte]uint64),
+		BeaconCommitteeSize: len(beaconState.BeaconCommittee),
+		ShardCommitteeSizes: make(map[byte]int),
+	}
+
+	for shardID, responseInBytes := range rewardResponses {
+		var amount uint64
+		err := rpchandler.ParseResponse(responseInBytes, &amount)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse reward of shard %v: %v", shardID, err)
+		}
+		res.ShardRewards[shardID] = amount
+		res.TotalReward, err = safemath.AddUint64(res.TotalReward, amount)
+		if err != nil {
+			return nil, fmt.Errorf("total reward overflows: %v", err)
+		}
+	}
+
+	for shardID, committee := range beaconState.ShardCommittee {
+		res.ShardCommitteeSizes[shardID] = len(committee)
+	}
+
+	return res, nil
+}
+
 // GetRewardAmount returns the current reward for a base58-encoded payment address.
 // The returned results is a mapping from a tokenID to the corresponding reward amount.
 //
@@ -46,6 +136,74 @@ func (client *IncClient) GetRewardAmount(paymentAddress string) (map[string]uint
 	return res, err
 }
 
+// RewardAmountsError holds the per-address errors of a GetRewardAmounts call.
+type RewardAmountsError map[string]error
+
+// Error implements the error interface.
+func (e RewardAmountsError) Error() string {
+	addresses := make([]string, 0, len(e))
+	for addr := range e {
+		addresses = append(addresses, addr)
+	}
+	sort.Strings(addresses)
+
+	errStrs := make([]string, 0, len(addresses))
+	for _, addr := range addresses {
+		errStrs = append(errStrs, fmt.Sprintf("%v: %v", addr, e[addr]))
+	}
+	return fmt.Sprintf("cannot get reward amounts of %v address(es): %v", len(e), strings.Join(errStrs, "; "))
+}
+
+// GetRewardAmounts returns the current rewards of a list of base58-encoded payment addresses, as a mapping from
+// a payment address to its tokenID-reward mapping (see GetRewardAmount).
+//
+// The rewards of all addresses are retrieved with a single listrewardamount query (see ListReward), and filtered by
+// the public keys of the given addresses. An address without any reward gets a zero PRV reward. If some of the
+// addresses are invalid, the rewards of the remaining addresses are still returned, together with a
+// RewardAmountsError describing the invalid ones.
+func (client *IncClient) GetRewardAmounts(paymentAddresses []string) (map[string]map[string]uint64, error) {
+	res := make(map[string]map[string]uint64)
+	if len(paymentAddresses) == 0 {
+		return res, nil
+	}
+
+	allRewards, err := client.ListReward()
+	if err != nil {
+		return nil, err
+	}
+
+	errs := make(RewardAmountsError)
+	for _, addr := range paymentAddresses {
+		if _, ok := res[addr]; ok {
+			continue
+		}
+		if _, ok := errs[addr]; ok {
+			continue
+		}
+		w, err := wallet.Base58CheckDeserialize(addr)
+		if err != nil {
+			errs[addr] = err
+			continue
+		}
+		if len(w.KeySet.PaymentAddress.Pk) == 0 {
+			errs[addr] = fmt.Errorf("invalid payment address")
+			continue
+		}
+
+		pubKeyStr := base58.Base58Check{}.Encode(w.KeySet.PaymentAddress.Pk, common.ZeroByte)
+		rewards := map[string]uint64{common.PRVIDStr: 0}
+		for tokenID, amount := range allRewards[pubKeyStr] {
+			rewards[tokenID.String()] = amount
+		}
+		res[addr] = rewards
+	}
+
+	if len(errs) > 0 {
+		return res, errs
+	}
+	return res, nil
+}
+
 // ListReward returns the staking rewards on the blockchain.
 // The returned results is a mapping from a public key to a tokenID-reward mapping.
 //
@@ -114,3 +272,130 @@ func (client *IncClient) GetSyncStats() (*jsonresult.SyncStats, error) {
 
 	return &res, nil
 }
+
+// StakingRole is the role of a validator in the staking flow of the Incognito network.
+type StakingRole string
+
+const (
+	// StakingRoleNotStaked indicates the key does not appear in any committee or candidate list.
+	StakingRoleNotStaked StakingRole = "notstaked"
+
+	// StakingRoleCandidate indicates the key is waiting for the random number to be assigned to a shard.
+	StakingRoleCandidate StakingRole = "candidate"
+
+	// StakingRoleSubstitute indicates the key has been assigned to a shard and is waiting to be swapped into its committee.
+	StakingRoleSubstitute StakingRole = "substitute"
+
+	// StakingRoleCommittee indicates the key is currently a committee member.
+	StakingRoleCommittee StakingRole = "committee"
+
+	// StakingRoleSlashed indicates the key has been penalized with a forced un-stake (e.g, for missing too many signatures).
+	StakingRoleSlashed StakingRole = "slashed"
+)
+
+// StakingStatus describes the state of a validator key in the latest beacon state.
+type StakingStatus struct {
+	// Role is the current role of the key.
+	Role StakingRole
+
+	// IsBeacon indicates whether the key is staked for the beacon chain instead of a shard.
+	IsBeacon bool
+
+	// ShardID is the shard the key has been assigned to. It is -1 if the key has not been assigned to any shard
+	// (i.e, it is a beacon validator, a candidate, or not staked at all).
+	ShardID int
+
+	// AutoStaking indicates whether the key is re-staked automatically after being swapped out of the committee.
+	AutoStaking bool
+
+	// StakingTx is the hash of the staking transaction of the key, if any.
+	StakingTx string
+
+	// Epoch is the epoch of the beacon state from which the status is derived.
+	Epoch uint64
+
+	// BeaconHeight is the height of the beacon state from which the status is derived.
+	BeaconHeight uint64
+}
+
+// GetStakingStatus returns the staking status of a base58-encoded committee public key, i.e, whether it is a
+// candidate, a substitute (pending) validator, a committee member or has been slashed, and which shard it belongs to.
+//
+// The status is derived from the latest beacon state. A key is reported as StakingRoleSlashed if it has received a
+// forced un-stake penalty in the current epoch, even though it may still appear in a committee until the end of the epoch.
+func (client *IncClient) GetStakingStatus(committeeKey string) (*StakingStatus, error) {
+	pubKey := new(key.CommitteePublicKey)
+	if err := pubKey.FromBase58(committeeKey); err != nil {
+		return nil, fmt.Errorf("invalid committee key %v: %v", committeeKey, err)
+	}
+
+	beaconState, err := client.GetBeaconBestState(0)
+	if err != nil {
+		return nil, err
+	}
+
+	return newStakingStatus(pubKey, beaconState)
+}
+
+// newStakingStatus looks up the given committee key in the committee and candidate lists of a beacon state.
+func newStakingStatus(pubKey *key.CommitteePublicKey, beaconState *jsonresult.BeaconBestState) (*StakingStatus, error) {
+	// committee keys are encoded with ToBase58 by the full-node, so the encoded form is canonical.
+	keyStr, err := pubKey.ToBase58()
+	if err != nil {
+		return nil, err
+	}
+	contains := func(keyList []string) bool {
+		for _, k := range keyList {
+			if k == keyStr {
+				return true
+			}
+		}
+		return false
+	}
+
+	res := &StakingStatus{
+		Role:         StakingRoleNotStaked,
+		ShardID:      -1,
+		AutoStaking:  beaconState.AutoStaking[keyStr],
+		Epoch:        beaconState.Epoch,
+		BeaconHeight: beaconState.BeaconHeight,
+	}
+	if txHash, ok := beaconState.StakingTx[keyStr]; ok {
+		res.StakingTx = txHash.String()
+	}
+
+	switch {
+	case contains(beaconState.BeaconCommittee):
+		res.Role, res.IsBeacon = StakingRoleCommittee, true
+	case contains(beaconState.BeaconPendingValidator):
+		res.Role, res.IsBeacon = StakingRoleSubstitute, true
+	case contains(beaconState.CandidateBeaconWaitingForCurrentRandom),
+		contains(beaconState.CandidateBeaconWaitingForNextRandom):
+		res.Role, res.IsBeacon = StakingRoleCandidate, true
+	case contains(beaconState.CandidateShardWaitingForCurrentRandom),
+		contains(beaconState.CandidateShardWaitingForNextRandom):
+		res.Role = StakingRoleCandidate
+	default:
+		for shardID, committee := range beaconState.ShardCommittee {
+			if contains(committee) {
+				res.Role, res.ShardID = StakingRoleCommittee, int(shardID)
+				break
+			}
+		}
+		if res.Role != StakingRoleNotStaked {
+			break
+		}
+		for shardID, pendingValidators := range beaconState.ShardPendingValidator {
+			if contains(pendingValidators) {
+				res.Role, res.ShardID = StakingRoleSubstitute, int(shardID)
+				break
+			}
+		}
+	}
+
+	if penalty, ok := beaconState.MissingSignaturePenalty[keyStr]; ok && penalty.ForceUnStake {
+		res.Role = StakingRoleSlashed
+	}
+
+	return res, nil
+}
@@ -3,7 +3,18 @@ package incclient
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"github.com/incognitochain/go-incognito-sdk-v2/common/base58"
+	"github.com/incognitochain/go-incognito-sdk-v2/key"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/jsonresult"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/rpc"
+	"github.com/incognitochain/go-incognito-sdk-v2/wallet"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestIncClient_GetRewardAmount(t *testing.T) {
@@ -90,3 +101,212 @@ func TestIncClient_GetSyncStats(t *testing.T) {
 
 	fmt.Println(string(jsb))
 }
+
+func TestIncClient_GetRewardFeatureStats(t *testing.T) {
+	var err error
+	ic, err = NewTestNet1Client()
+	if err != nil {
+		panic(err)
+	}
+
+	stats, err := ic.GetRewardFeatureStats(0)
+	if err != nil {
+		panic(err)
+	}
+
+	jsb, err := json.MarshalIndent(stats, "", "\t")
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(string(jsb))
+}
+
+func TestNewRewardStats(t *testing.T) {
+	// synthetic responses shaped like those of the `getrewardamountbyepoch` RPC
+	rewardResponses := map[byte][]byte{
+		0: []byte(`{"Id":1,"Result":1284610300920,"Error":null,"Params":[0,2460],"Method":"getrewardamountbyepoch","Jsonrpc":"1.0"}`),
+		1: []byte(`{"Id":1,"Result":1023851749364,"Error":null,"Params":[1,2460],"Method":"getrewardamountbyepoch","Jsonrpc":"1.0"}`),
+	}
+	beaconState := &jsonresult.BeaconBestState{
+		Epoch:           2461,
+		BeaconCommittee: []string{"b0", "b1", "b2", "b3"},
+		ShardCommittee: map[byte][]string{
+			0: {"s00", "s01", "s02"},
+			1: {"s10", "s11"},
+		},
+	}
+
+	stats, err := newRewardStats(2460, false, rewardResponses, beaconState)
+	if err != nil {
+		panic(err)
+	}
+
+	if stats.Epoch != 2460 || stats.IsCurrentEpoch {
+		panic(fmt.Sprintf("invalid epoch: %v, %v", stats.Epoch, stats.IsCurrentEpoch))
+	}
+	if stats.ShardRewards[0] != 1284610300920 || stats.ShardRewards[1] != 1023851749364 {
+		panic(fmt.Sprintf("invalid shard rewards: %v", stats.ShardRewards))
+	}
+	if stats.TotalReward != 1284610300920+1023851749364 {
+		panic(fmt.Sprintf("invalid total reward: %v", stats.TotalReward))
+	}
+	if stats.BeaconCommitteeSize != 4 || stats.ShardCommitteeSizes[0] != 3 || stats.ShardCommitteeSizes[1] != 2 {
+		panic(fmt.Sprintf("invalid committee sizes: %v, %v", stats.BeaconCommitteeSize, stats.ShardCommitteeSizes))
+	}
+
+	// an RPC error must be surfaced
+	rewardResponses[1] = []byte(`{"Id":1,"Result":null,"Error":{"Code":-1,"Message":"epoch not found"},"Params":[1,2460],"Method":"getrewardamountbyepoch","Jsonrpc":"1.0"}`)
+	_, err = newRewardStats(2460, false, rewardResponses, beaconState)
+	if err == nil {
+		panic("expect an error")
+	}
+}
+
+func TestIncClient_GetRewardAmounts(t *testing.T) {
+	tokenID := "ffd8d42dc40a8d166ea4848baf8b5f6e9fe0e9c30d60062eb7d44a8df9e00854"
+	rewards := make(map[string]map[string]uint64)
+	listRewards := make(map[string]map[string]uint64)
+	addresses := make([]string, 0)
+	for i := 0; i < numTests; i++ {
+		w, err := wallet.GenRandomWalletForShardID(byte(i % common.MaxShardNumber))
+		if err != nil {
+			panic(err)
+		}
+		addr := w.Base58CheckSerialize(wallet.PaymentAddressType)
+		addresses = append(addresses, addr)
+		if i%3 == 0 {
+			// an address without rewards.
+			continue
+		}
+		rewards[addr] = map[string]uint64{common.PRVIDStr: uint64(i+1) * 1000, tokenID: uint64(i)}
+		pubKeyStr := base58.Base58Check{}.Encode(w.KeySet.PaymentAddress.Pk, common.ZeroByte)
+		listRewards[pubKeyStr] = rewards[addr]
+	}
+
+	var mtx sync.Mutex
+	numCalls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string
+		}
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil || req.Method != "listrewardamount" {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+		mtx.Lock()
+		numCalls++
+		mtx.Unlock()
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"Result": listRewards})
+	}))
+	defer ts.Close()
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+
+	// all succeed, with a single query.
+	res, err := client.GetRewardAmounts(append(addresses, addresses[0]))
+	assert.Equal(t, nil, err, fmt.Errorf("GetRewardAmounts error: %v", err))
+	assert.Equal(t, 1, numCalls)
+	assert.Equal(t, len(addresses), len(res))
+	for _, addr := range addresses {
+		if reward, ok := rewards[addr]; ok {
+			assert.Equal(t, reward, res[addr])
+		} else {
+			assert.Equal(t, map[string]uint64{common.PRVIDStr: 0}, res[addr])
+		}
+	}
+
+	// partial results
+	res, err = client.GetRewardAmounts(append(addresses, "invalid-1", "invalid-2"))
+	assert.NotEqual(t, nil, err)
+	errs, ok := err.(RewardAmountsError)
+	assert.Equal(t, true, ok, fmt.Errorf("expected a RewardAmountsError, got %T", err))
+	assert.Equal(t, 2, len(errs))
+	assert.NotEqual(t, nil, errs["invalid-1"])
+	assert.NotEqual(t, nil, errs["invalid-2"])
+	assert.Equal(t, len(addresses), len(res))
+
+	// empty input
+	numCalls = 0
+	res, err = client.GetRewardAmounts(nil)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(res))
+	assert.Equal(t, 0, numCalls)
+}
+
+func TestIncClient_GetStakingStatus(t *testing.T) {
+	committeeKeys := make([]string, 10)
+	for i := range committeeKeys {
+		pubKey, err := key.NewCommitteeKeyFromSeed(common.RandBytes(32), common.RandBytes(32))
+		if err != nil {
+			panic(err)
+		}
+		committeeKeys[i], err = pubKey.ToBase58()
+		if err != nil {
+			panic(err)
+		}
+	}
+	stakingTx := common.HashH([]byte("staking-tx"))
+
+	// a synthetic response shaped like that of the `getbeaconbeststate` RPC (only the fields used are set)
+	beaconStateResponse := fmt.Sprintf(`{"Id":1,"Result":{
+		"Epoch":2461,
+		"BeaconHeight":1230450,
+		"BeaconCommittee":["%v"],
+		"BeaconPendingValidator":["%v"],
+		"CandidateShardWaitingForCurrentRandom":["%v"],
+		"CandidateBeaconWaitingForCurrentRandom":[],
+		"CandidateShardWaitingForNextRandom":["%v"],
+		"CandidateBeaconWaitingForNextRandom":[],
+		"ShardCommittee":{"0":["%v"],"3":["%v","%v"]},
+		"ShardPendingValidator":{"0":[],"5":["%v"]},
+		"AutoStaking":{"%v":true,"%v":false},
+		"StakingTx":{"%v":"%v"},
+		"MissingSignaturePenalty":{"%v":{"MinPercent":50,"Time":0,"ForceUnStake":true}}
+	},"Error":null,"Params":null,"Method":"getbeaconbeststate","Jsonrpc":"1.0"}`,
+		committeeKeys[0], committeeKeys[1], committeeKeys[2], committeeKeys[3],
+		committeeKeys[4], committeeKeys[5], committeeKeys[6], committeeKeys[7],
+		committeeKeys[5], committeeKeys[7],
+		committeeKeys[5], stakingTx.String(),
+		committeeKeys[6],
+	)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string
+		}
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil || req.Method != "getbeaconbeststate" {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(beaconStateResponse))
+	}))
+	defer ts.Close()
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+
+	expected := []StakingStatus{
+		{Role: StakingRoleCommittee, IsBeacon: true, ShardID: -1},
+		{Role: StakingRoleSubstitute, IsBeacon: true, ShardID: -1},
+		{Role: StakingRoleCandidate, ShardID: -1},
+		{Role: StakingRoleCandidate, ShardID: -1},
+		{Role: StakingRoleCommittee, ShardID: 0},
+		{Role: StakingRoleCommittee, ShardID: 3, AutoStaking: true, StakingTx: stakingTx.String()},
+		{Role: StakingRoleSlashed, ShardID: 3},
+		{Role: StakingRoleSubstitute, ShardID: 5},
+		{Role: StakingRoleNotStaked, ShardID: -1},
+		{Role: StakingRoleNotStaked, ShardID: -1},
+	}
+	for i, committeeKey := range committeeKeys {
+		status, err := client.GetStakingStatus(committeeKey)
+		assert.Equal(t, nil, err, fmt.Errorf("GetStakingStatus error: %v", err))
+
+		expected[i].Epoch = 2461
+		expected[i].BeaconHeight = 1230450
+		assert.Equal(t, expected[i], *status, fmt.Errorf("invalid status of key #%v", i))
+	}
+
+	// invalid committee keys
+	_, err := client.GetStakingStatus("abc")
+	assert.NotEqual(t, nil, err)
+}
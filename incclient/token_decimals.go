@@ -0,0 +1,238 @@
+package incclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	rCommon "github.com/ethereum/go-ethereum/common"
+	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/rpc"
+)
+
+const (
+	// PRVDecimals is the number of decimals of PRV.
+	PRVDecimals = 9
+
+	// MaxBridgedTokenDecimals is the maximum number of decimals of a bridged token on the Incognito network.
+	// Deposits of EVM tokens with more decimals are scaled down to this precision when minted.
+	MaxBridgedTokenDecimals = 9
+
+	// evmNativeTokenDecimals is the number of decimals of the native coin of an EVM network (ETH, BNB, MATIC, FTM).
+	evmNativeTokenDecimals = 18
+
+	// evmDecimalsSelector is the ABI selector of the ERC20 `decimals()` function.
+	evmDecimalsSelector = "0x313ce567"
+)
+
+// tokenDecimalsCache keeps the decimals of tokens retrieved by an IncClient. The decimals of a token never change,
+// so cached values never expire.
+type tokenDecimalsCache struct {
+	mtx      *sync.RWMutex
+	decimals map[string]int
+}
+
+func newTokenDecimalsCache() *tokenDecimalsCache {
+	return &tokenDecimalsCache{mtx: new(sync.RWMutex), decimals: make(map[string]int)}
+}
+
+// GetTokenDecimals returns the number of decimals of the given token on the Incognito network, i.e, an amount of
+// 10^decimals in the smallest unit of the token stands for one token. PRV has PRVDecimals decimals.
+//
+// For a token bridged from an EVM network, the decimals are read from the token contract on the corresponding EVM network
+// (the native coin of an EVM network has 18 decimals). Since the Incognito network stores bridged amounts with at most
+// MaxBridgedTokenDecimals decimals, the returned value is capped at MaxBridgedTokenDecimals: an 18-decimal ERC20 token
+// has 9 decimals on the Incognito network.
+//
+// An error is returned if the decimals of the token cannot be determined (e.g, the token is not a decentralized
+// bridge token). Retrieved values are cached by the client.
+func (client *IncClient) GetTokenDecimals(tokenID string) (int, error) {
+	if tokenID == common.PRVIDStr {
+		return PRVDecimals, nil
+	}
+
+	cache := client.tokenDecimalsCache
+	cache.mtx.RLock()
+	decimals, ok := cache.decimals[tokenID]
+	cache.mtx.RUnlock()
+	if ok {
+		return decimals, nil
+	}
+
+	bridgeTokens, err := client.GetBridgeTokens()
+	if err != nil {
+		return 0, err
+	}
+	var tokenInfo *BridgeTokenInfo
+	for _, token := range bridgeTokens {
+		if token.TokenID != nil && token.TokenID.String() == tokenID {
+			tokenInfo = token
+			break
+		}
+	}
+	if tokenInfo == nil {
+		return 0, fmt.Errorf("cannot determine the decimals of token %v: not a bridge token", tokenID)
+	}
+	if tokenInfo.IsCentralized {
+		return 0, fmt.Errorf("cannot determine the decimals of token %v: centralized bridge token", tokenID)
+	}
+
+	networkID, tokenAddress, err := parseEVMExternalTokenID(tokenInfo.ExternalTokenID)
+	if err != nil {
+		return 0, fmt.Errorf("cannot determine the decimals of token %v: %v", tokenID, err)
+	}
+	if tokenAddress == rCommon.HexToAddress(EVMZeroAddress) {
+		decimals = evmNativeTokenDecimals
+	} else {
+		decimals, err = client.GetEVMTokenDecimals(tokenAddress.String(), networkID)
+		if err != nil {
+			return 0, err
+		}
+	}
+	if decimals > MaxBridgedTokenDecimals {
+		decimals = MaxBridgedTokenDecimals
+	}
+
+	cache.mtx.Lock()
+	cache.decimals[tokenID] = decimals
+	cache.mtx.Unlock()
+
+	return decimals, nil
+}
+
+// ConvertToNanoAmount converts a human-readable amount of a token (e.g, "1.5") into its smallest unit on the
+// Incognito network, using the decimals returned by GetTokenDecimals. For example, "1.5" PRV is 1500000000 nano PRV.
+//
+// An error is returned if the amount is malformed, has more fractional digits than the decimals of the token, or
+// overflows a uint64.
+func (client *IncClient) ConvertToNanoAmount(tokenID string, amount string) (uint64, error) {
+	decimals, err := client.GetTokenDecimals(tokenID)
+	if err != nil {
+		return 0, err
+	}
+
+	return parseDecimalAmount(amount, decimals)
+}
+
+// FormatAmount is the reverse of ConvertToNanoAmount: it formats an amount of a token in its smallest unit into a
+// human-readable amount (e.g, 1500000000 nano PRV is "1.5"), using the decimals returned by GetTokenDecimals.
+func (client *IncClient) FormatAmount(tokenID string, amount uint64) (string, error) {
+	decimals, err := client.GetTokenDecimals(tokenID)
+	if err != nil {
+		return "", err
+	}
+
+	return formatDecimalAmount(amount, decimals), nil
+}
+
+// parseDecimalAmount converts a non-negative decimal string into an integer amount with the given number of decimals.
+func parseDecimalAmount(amount string, decimals int) (uint64, error) {
+	parts := strings.Split(amount, ".")
+	if len(parts) > 2 || (parts[0] == "" && (len(parts) == 1 || parts[1] == "")) {
+		return 0, fmt.Errorf("invalid amount %v", amount)
+	}
+	intPart := parts[0]
+	fracPart := ""
+	if len(parts) == 2 {
+		fracPart = strings.TrimRight(parts[1], "0")
+	}
+	for _, digits := range []string{intPart, parts[len(parts)-1]} {
+		for _, c := range digits {
+			if c < '0' || c > '9' {
+				return 0, fmt.Errorf("invalid amount %v", amount)
+			}
+		}
+	}
+	if len(fracPart) > decimals {
+		return 0, fmt.Errorf("amount %v has more than %v decimals", amount, decimals)
+	}
+
+	res, ok := new(big.Int).SetString(intPart+fracPart+strings.Repeat("0", decimals-len(fracPart)), 10)
+	if !ok || !res.IsUint64() {
+		return 0, fmt.Errorf("amount %v overflows", amount)
+	}
+
+	return res.Uint64(), nil
+}
+
+// formatDecimalAmount formats an integer amount with the given number of decimals, without trailing zeros.
+func formatDecimalAmount(amount uint64, decimals int) string {
+	digits := fmt.Sprintf("%0*d", decimals+1, amount)
+	intPart := digits[:len(digits)-decimals]
+	fracPart := strings.TrimRight(digits[len(digits)-decimals:], "0")
+	if fracPart == "" {
+		return intPart
+	}
+
+	return intPart + "." + fracPart
+}
+
+// GetEVMTokenDecimals returns the number of decimals of an ERC20 token by calling the `decimals()` function of its contract.
+//
+// An additional parameter `evmNetworkID` is introduced to specify the target EVM network. evmNetworkID can be one of the following:
+//	- rpc.ETHNetworkID: the Ethereum network
+//	- rpc.BSCNetworkID: the Binance Smart Chain network
+//	- rpc.PLGNetworkID: the Polygon network
+//	- rpc.FTMNetworkID: the Fantom network
+// If set empty, evmNetworkID defaults to rpc.ETHNetworkID. NOTE that only the first value of evmNetworkID is used.
+func (client *IncClient) GetEVMTokenDecimals(tokenAddress string, evmNetworkID ...int) (int, error) {
+	networkID := rpc.ETHNetworkID
+	if len(evmNetworkID) > 0 {
+		networkID = evmNetworkID[0]
+	}
+
+	var evmClient *rpc.RPCServer
+	var ok bool
+	if evmClient, ok = client.evmServers[networkID]; !ok || evmClient == nil {
+		return 0, rpc.EVMNetworkNotFoundError(networkID)
+	}
+
+	method := "eth_call"
+	params := []interface{}{
+		map[string]interface{}{"to": tokenAddress, "data": evmDecimalsSelector},
+		"latest",
+	}
+
+	request := rpchandler.CreateJsonRequest("2.0", method, params, 1)
+	query, err := json.Marshal(request)
+	if err != nil {
+		return 0, err
+	}
+
+	responseInBytes, err := evmClient.SendPostRequestWithQuery(string(query))
+	if err != nil {
+		return 0, err
+	}
+
+	var res string
+	err = rpchandler.ParseResponse(responseInBytes, &res)
+	if err != nil {
+		return 0, err
+	}
+
+	resBytes := common.FromHex(res)
+	if len(resBytes) == 0 || len(resBytes) > 32 {
+		return 0, fmt.Errorf("invalid decimals() result %v of token %v", res, tokenAddress)
+	}
+	decimals := new(big.Int).SetBytes(resBytes)
+	if !decimals.IsUint64() || decimals.Uint64() > 255 {
+		return 0, fmt.Errorf("invalid decimals() result %v of token %v", res, tokenAddress)
+	}
+
+	return int(decimals.Uint64()), nil
+}
+
+// parseEVMExternalTokenID returns the EVM network and the contract address of a bridge token from its external tokenID.
+func parseEVMExternalTokenID(externalTokenID []byte) (int, rCommon.Address, error) {
+	for networkID, prefix := range evmExternalTokenIDPrefix {
+		if len(externalTokenID) == len(prefix)+rCommon.AddressLength && bytes.HasPrefix(externalTokenID, []byte(prefix)) {
+			return networkID, rCommon.BytesToAddress(externalTokenID[len(prefix):]), nil
+		}
+	}
+
+	return 0, rCommon.Address{}, fmt.Errorf("unsupported external tokenID %x", externalTokenID)
+}
@@ -0,0 +1,201 @@
+package incclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	rCommon "github.com/ethereum/go-ethereum/common"
+	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIncClient_GetTokenDecimals(t *testing.T) {
+	daiAddress := rCommon.HexToAddress("0x6b175474e89094c44da98b954eedeac495271d0f")
+	usdcAddress := rCommon.HexToAddress("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48")
+	contractDecimals := map[string]int{
+		strings.ToLower(daiAddress.String()):  18,
+		strings.ToLower(usdcAddress.String()): 6,
+	}
+
+	daiTokenID := common.HashH([]byte("DAI"))
+	usdcTokenID := common.HashH([]byte("USDC"))
+	bnbTokenID := common.HashH([]byte("BNB"))
+	centralizedTokenID := common.HashH([]byte("BTC"))
+	bridgeTokens := []*BridgeTokenInfo{
+		{TokenID: &daiTokenID, ExternalTokenID: daiAddress.Bytes()},
+		{TokenID: &usdcTokenID, ExternalTokenID: append([]byte("PLG"), usdcAddress.Bytes()...)},
+		{TokenID: &bnbTokenID, ExternalTokenID: append([]byte("BSC"), rCommon.HexToAddress(EVMZeroAddress).Bytes()...)},
+		{TokenID: &centralizedTokenID, IsCentralized: true},
+	}
+
+	var mtx sync.Mutex
+	numCalls := make(map[string]int)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string
+			Params []json.RawMessage
+		}
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mtx.Lock()
+		numCalls[req.Method]++
+		mtx.Unlock()
+
+		var result interface{}
+		switch req.Method {
+		case "getallbridgetokens":
+			result = bridgeTokens
+		case "eth_call":
+			var callParams struct {
+				To   string
+				Data string
+			}
+			if len(req.Params) < 1 || json.Unmarshal(req.Params[0], &callParams) != nil || callParams.Data != evmDecimalsSelector {
+				http.Error(w, "invalid params", http.StatusBadRequest)
+				return
+			}
+			decimals, ok := contractDecimals[strings.ToLower(callParams.To)]
+			if !ok {
+				http.Error(w, "contract not found", http.StatusBadRequest)
+				return
+			}
+			result = fmt.Sprintf("0x%064x", decimals)
+		default:
+			http.Error(w, fmt.Sprintf("method %v not supported", req.Method), http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"Result": result})
+	}))
+	defer ts.Close()
+
+	client := newIncClient(rpc.NewRPCServer(ts.URL), map[int]*rpc.RPCServer{
+		rpc.ETHNetworkID: rpc.NewRPCServer(ts.URL),
+		rpc.PLGNetworkID: rpc.NewRPCServer(ts.URL),
+	}, nil, 2)
+
+	decimals, err := client.GetTokenDecimals(common.PRVIDStr)
+	assert.Equal(t, nil, err, fmt.Errorf("GetTokenDecimals(PRV) error: %v", err))
+	assert.Equal(t, PRVDecimals, decimals)
+	assert.Equal(t, 0, len(numCalls))
+
+	// DAI has 18 decimals on Ethereum, and is stored with 9 decimals on the Incognito network.
+	evmDecimals, err := client.GetEVMTokenDecimals(daiAddress.String(), rpc.ETHNetworkID)
+	assert.Equal(t, nil, err, fmt.Errorf("GetEVMTokenDecimals error: %v", err))
+	assert.Equal(t, 18, evmDecimals)
+	for i := 0; i < numTests; i++ {
+		decimals, err = client.GetTokenDecimals(daiTokenID.String())
+		assert.Equal(t, nil, err, fmt.Errorf("GetTokenDecimals(DAI) error: %v", err))
+		assert.Equal(t, MaxBridgedTokenDecimals, decimals)
+	}
+
+	decimals, err = client.GetTokenDecimals(usdcTokenID.String())
+	assert.Equal(t, nil, err, fmt.Errorf("GetTokenDecimals(USDC) error: %v", err))
+	assert.Equal(t, 6, decimals)
+
+	// the native coin of an EVM network does not need a contract call.
+	decimals, err = client.GetTokenDecimals(bnbTokenID.String())
+	assert.Equal(t, nil, err, fmt.Errorf("GetTokenDecimals(BNB) error: %v", err))
+	assert.Equal(t, MaxBridgedTokenDecimals, decimals)
+
+	// subsequent queries are served from the cache.
+	assert.Equal(t, map[string]int{"getallbridgetokens": 3, "eth_call": 3}, numCalls)
+
+	// unknown tokens.
+	_, err = client.GetTokenDecimals(centralizedTokenID.String())
+	assert.NotEqual(t, nil, err)
+	_, err = client.GetTokenDecimals(common.HashH([]byte("unknown")).String())
+	assert.NotEqual(t, nil, err)
+}
+
+func TestIncClient_ConvertToNanoAmount(t *testing.T) {
+	usdcAddress := rCommon.HexToAddress("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48")
+	usdcTokenID := common.HashH([]byte("USDC"))
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string
+		}
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var result interface{}
+		switch req.Method {
+		case "getallbridgetokens":
+			result = []*BridgeTokenInfo{{TokenID: &usdcTokenID, ExternalTokenID: usdcAddress.Bytes()}}
+		case "eth_call":
+			result = fmt.Sprintf("0x%064x", 6)
+		default:
+			http.Error(w, fmt.Sprintf("method %v not supported", req.Method), http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"Result": result})
+	}))
+	defer ts.Close()
+
+	client := newIncClient(rpc.NewRPCServer(ts.URL), map[int]*rpc.RPCServer{rpc.ETHNetworkID: rpc.NewRPCServer(ts.URL)}, nil, 2)
+
+	testCases := []struct {
+		tokenID  string
+		amount   string
+		expected uint64
+		isValid  bool
+	}{
+		{common.PRVIDStr, "1.5", 1500000000, true},
+		{common.PRVIDStr, "0.000000001", 1, true},
+		{common.PRVIDStr, "12", 12000000000, true},
+		{common.PRVIDStr, ".25", 250000000, true},
+		{common.PRVIDStr, "3.", 3000000000, true},
+		{common.PRVIDStr, "0.1000000000", 100000000, true},
+		{common.PRVIDStr, "0.0000000001", 0, false},
+		{common.PRVIDStr, "18446744073.709551616", 0, false},
+		{common.PRVIDStr, "-1", 0, false},
+		{common.PRVIDStr, "1.2.3", 0, false},
+		{common.PRVIDStr, "1e9", 0, false},
+		{common.PRVIDStr, ".", 0, false},
+		{common.PRVIDStr, "", 0, false},
+		{usdcTokenID.String(), "2.5", 2500000, true},
+		{usdcTokenID.String(), "0.0000001", 0, false},
+		{common.HashH([]byte("unknown")).String(), "1", 0, false},
+	}
+	for _, tc := range testCases {
+		amount, err := client.ConvertToNanoAmount(tc.tokenID, tc.amount)
+		assert.Equal(t, tc.isValid, err == nil, fmt.Errorf("ConvertToNanoAmount(%v) error: %v", tc.amount, err))
+		assert.Equal(t, tc.expected, amount)
+	}
+
+	formatTestCases := []struct {
+		tokenID  string
+		amount   uint64
+		expected string
+	}{
+		{common.PRVIDStr, 1500000000, "1.5"},
+		{common.PRVIDStr, 1, "0.000000001"},
+		{common.PRVIDStr, 0, "0"},
+		{common.PRVIDStr, 12000000000, "12"},
+		{common.PRVIDStr, math.MaxUint64, "18446744073.709551615"},
+		{usdcTokenID.String(), 2500000, "2.5"},
+	}
+	for _, tc := range formatTestCases {
+		formatted, err := client.FormatAmount(tc.tokenID, tc.amount)
+		assert.Equal(t, nil, err, fmt.Errorf("FormatAmount(%v) error: %v", tc.amount, err))
+		assert.Equal(t, tc.expected, formatted)
+
+		amount, err := client.ConvertToNanoAmount(tc.tokenID, formatted)
+		assert.Equal(t, nil, err, fmt.Errorf("ConvertToNanoAmount(%v) error: %v", formatted, err))
+		assert.Equal(t, tc.amount, amount)
+	}
+	_, err := client.FormatAmount(common.HashH([]byte("unknown")).String(), 1)
+	assert.NotEqual(t, nil, err)
+}
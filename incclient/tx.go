@@ -1,27 +1,41 @@
 package incclient
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/incognitochain/go-incognito-sdk-v2/coin"
 	"github.com/incognitochain/go-incognito-sdk-v2/common"
 	"github.com/incognitochain/go-incognito-sdk-v2/common/base58"
+	"github.com/incognitochain/go-incognito-sdk-v2/internal/safemath"
 	"github.com/incognitochain/go-incognito-sdk-v2/metadata"
+	"github.com/incognitochain/go-incognito-sdk-v2/privacy"
 	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler"
+	"github.com/incognitochain/go-incognito-sdk-v2/transaction"
 	"github.com/incognitochain/go-incognito-sdk-v2/transaction/tx_generic"
 	"github.com/incognitochain/go-incognito-sdk-v2/transaction/tx_ver1"
 	"github.com/incognitochain/go-incognito-sdk-v2/transaction/tx_ver2"
+	"github.com/incognitochain/go-incognito-sdk-v2/transaction/utils"
 	"github.com/incognitochain/go-incognito-sdk-v2/wallet"
 )
 
 // CreateRawTransaction creates a PRV transaction with the provided version.
-// Version = -1 indicates that whichever version is accepted.
+// Version = -1 indicates that whichever version is accepted. If param.OutputCoinVersion is set, the version of the
+// transaction must be -1 or match it.
 //
-// It returns the base58-encoded transaction, the transaction's hash, and an error (if any).
+// It returns the base58-encoded transaction, the transaction's hash, and an error (if any). If the fee is too high
+// compared to the sent amount, a warning is logged or a *FeeExceedsAmountError is returned, depending on SetFeeGuard.
 func (client *IncClient) CreateRawTransaction(param *TxParam, version int8) ([]byte, string, error) {
 	if param.txTokenParam != nil {
 		return nil, "", fmt.Errorf("method supports PRV transaction only")
 	}
+	if err := client.checkFeeGuard(param); err != nil {
+		return nil, "", err
+	}
+	version, err := client.resolveTxVersion(param, version)
+	if err != nil {
+		return nil, "", err
+	}
 	if version == -1 { //Try either one of the version, if possible
 		encodedTx, txHash, err := client.CreateRawTransactionVer1(param)
 		if err != nil {
@@ -55,7 +69,7 @@ func (client *IncClient) CreateRawTransactionVer1(param *TxParam) ([]byte, strin
 	}
 
 	//Create list of payment infos
-	paymentInfos, err := createPaymentInfos(param.receiverList, param.amountList)
+	paymentInfos, err := createPaymentInfos(param.prvReceivers())
 	if err != nil {
 		return nil, "", err
 	}
@@ -66,7 +80,10 @@ func (client *IncClient) CreateRawTransactionVer1(param *TxParam) ([]byte, strin
 	}
 	totalAmount := param.fee
 	for _, amount := range param.amountList {
-		totalAmount += amount
+		totalAmount, err = safemath.AddUint64(totalAmount, amount)
+		if err != nil {
+			return nil, "", fmt.Errorf("total amount overflows: %v", err)
+		}
 	}
 
 	hasPrivacy := true
@@ -101,17 +118,232 @@ func (client *IncClient) CreateRawTransactionVer1(param *TxParam) ([]byte, strin
 //
 // It returns the base58-encoded transaction, the transaction's hash, and an error (if any).
 func (client *IncClient) CreateRawTransactionVer2(param *TxParam) ([]byte, string, error) {
+	tx, err := client.createTxVer2(param, false)
+	if err != nil {
+		return nil, "", err
+	}
+
+	txBytes, err := json.Marshal(tx)
+	if err != nil {
+		return nil, "", fmt.Errorf("cannot marshal txver2: %v", err)
+	}
+
+	base58CheckData := base58.Base58Check{}.Encode(txBytes, common.ZeroByte)
+
+	return []byte(base58CheckData), tx.Hash().String(), nil
+}
+
+// CreateRawTransactionWithPaymentProofs creates a PRV transaction version 2 like CreateRawTransaction, together with
+// a tx_ver2.PaymentProof for each receiver, keyed by the receiver's payment address. A proof shows that an output
+// coin of the transaction pays its receiver, and can be checked by anyone with tx_ver2.VerifyPaymentProof.
+//
+// The proofs can only be created here: they rely on the shared randoms of the output coins, which are not part of the
+// transaction and are lost once it is serialized. If a receiver appears several times in param, its proof is for the
+// first output coin paying it. All receivers must be payment addresses v2.
+//
+// It returns the base58-encoded transaction, the transaction's hash, the proofs, and an error (if any).
+func (client *IncClient) CreateRawTransactionWithPaymentProofs(param *TxParam) ([]byte, string, map[string]*tx_ver2.PaymentProof, error) {
+	if param.txTokenParam != nil {
+		return nil, "", nil, fmt.Errorf("method supports PRV transaction only")
+	}
+	if err := client.checkFeeGuard(param); err != nil {
+		return nil, "", nil, err
+	}
+	if _, err := client.resolveTxVersion(param, 2); err != nil {
+		return nil, "", nil, err
+	}
+
+	tx, err := client.createTxVer2(param, false)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	proofs := make(map[string]*tx_ver2.PaymentProof)
+	for _, receiver := range param.receiverList {
+		if _, ok := proofs[receiver]; ok {
+			continue
+		}
+		proofs[receiver], err = tx.ProveSentTo(param.senderPrivateKey, receiver)
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("cannot prove the payment to %v: %v", receiver, err)
+		}
+	}
+
+	txBytes, err := json.Marshal(tx)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("cannot marshal txver2: %v", err)
+	}
+
+	base58CheckData := base58.Base58Check{}.Encode(txBytes, common.ZeroByte)
+
+	return []byte(base58CheckData), tx.Hash().String(), proofs, nil
+}
+
+// PreviewTransaction creates a PRV transaction version 2 without generating its range proofs.
+//
+// The returned transaction has the same structure and (almost) the same size as the real one, and is much cheaper to
+// create. It is meant for previewing or estimating purposes only; it will be rejected by SendRawTx and by the network.
+//
+// Only PRV transactions are supported: an error is returned if param has a TxTokenParam.
+func (client *IncClient) PreviewTransaction(param *TxParam) (*tx_ver2.Tx, error) {
+	if param.txTokenParam != nil {
+		return nil, fmt.Errorf("cannot preview a token transaction: only PRV transactions are supported")
+	}
+
+	return client.createTxVer2(param, true)
+}
+
+// EstimateTxFee estimates the fee (in nano PRV) needed for a PRV transaction version 2 based on its size
+// and the current fee per KB returned by the remote node. The range proofs are not generated during the estimation.
+//
+// Like PreviewTransaction, it does not support token transactions.
+func (client *IncClient) EstimateTxFee(param *TxParam) (uint64, error) {
+	tx, err := client.PreviewTransaction(param)
+	if err != nil {
+		return 0, err
+	}
+
+	feePerKB, err := client.getFeePerKB(common.GetShardIDFromLastByte(tx.GetSenderAddrLastByte()), common.PRVIDStr)
+	if err != nil {
+		return 0, err
+	}
+
+	return feePerKB * tx.GetTxActualSize(), nil
+}
+
+// approximate sizes (in bytes) of the components of a transaction version 2, used to estimate its size from its shape.
+// Each input coin is hidden among privacy.RingSize coins in the ring of the MLSAG signature, in which every column takes
+// estimatedRingColumnSize bytes and every ring member of an input takes estimatedRingMemberSize bytes. A token
+// transaction has an extra ring column for its confidential asset, a blinded asset tag per coin, and is bundled with a
+// PRV transaction (one input, one output) paying its fee.
+const (
+	estimatedPRVTxBaseSize     = 1148
+	estimatedPRVTxInputSize    = 168
+	estimatedPRVTxOutputSize   = 362
+	estimatedTokenTxBaseSize   = 3582
+	estimatedTokenTxInputSize  = 208
+	estimatedTokenTxOutputSize = 406
+	estimatedRingColumnSize    = 43
+	estimatedRingMemberSize    = 49
+)
+
+// estimateTxV2SizeByShape returns the approximate size (in KB) of a transaction version 2 with numInputs input coins
+// and numOutputs output coins. Like GetTxActualSize, the size is rounded up to the next KB.
+func estimateTxV2SizeByShape(numInputs, numOutputs int, hasTokenData bool) uint64 {
+	baseSize, inputSize, outputSize := estimatedPRVTxBaseSize, estimatedPRVTxInputSize, estimatedPRVTxOutputSize
+	numRingColumns := 1
+	if hasTokenData {
+		baseSize, inputSize, outputSize = estimatedTokenTxBaseSize, estimatedTokenTxInputSize, estimatedTokenTxOutputSize
+		numRingColumns = 2
+	}
+
+	ringSize := privacy.RingSize * (numRingColumns*estimatedRingColumnSize + numInputs*estimatedRingMemberSize)
+	size := uint64(baseSize + ringSize + numInputs*inputSize + numOutputs*outputSize)
+	return (size + 1023) / 1024
+}
+
+// estimateTxV2Size returns the approximate size (in KB) of a PRV transaction version 2 with numInputs input coins and
+// two output coins (the receiver and the change).
+func estimateTxV2Size(numInputs int) uint64 {
+	return estimateTxV2SizeByShape(numInputs, 2, false)
+}
+
+// EstimateTxFeeByShape estimates the fee (in nano PRV) needed for a transaction version 2 spending numInputs input coins
+// and creating numOutputs output coins (including the change), without creating the transaction. The estimated size
+// accounts for the ring of privacy.RingSize coins of each input; if hasTokenData is true, it is the size of a token
+// transaction together with the PRV transaction paying its fee. The fee is then derived from the current fee per KB
+// of the shard of privateKey (the sender) returned by the remote node.
+//
+// An error is returned if the estimated size exceeds common.MaxTxSize, in which case the transaction should be split
+// (e.g, by consolidating the input coins first).
+func (client *IncClient) EstimateTxFeeByShape(privateKey string, numInputs, numOutputs int, hasTokenData bool) (uint64, error) {
+	if numInputs < 1 {
+		return 0, fmt.Errorf("number of inputs must be positive, got %v", numInputs)
+	}
+	if numOutputs < 1 {
+		return 0, fmt.Errorf("number of outputs must be positive, got %v", numOutputs)
+	}
+	size := estimateTxV2SizeByShape(numInputs, numOutputs, hasTokenData)
+	if size > common.MaxTxSize {
+		return 0, fmt.Errorf("estimated tx size %vKB exceeds the maximum tx size %vKB", size, common.MaxTxSize)
+	}
+
+	shardID, err := getSenderShardID(privateKey)
+	if err != nil {
+		return 0, err
+	}
+	feePerKB, err := client.getFeePerKB(shardID, common.PRVIDStr)
+	if err != nil {
+		return 0, err
+	}
+
+	fee, err := safemath.MulUint64(feePerKB, size)
+	if err != nil {
+		return 0, fmt.Errorf("fee overflows: %v", err)
+	}
+
+	return fee, nil
+}
+
+// getSenderShardID returns the shard of the sender with the given private key.
+func getSenderShardID(privateKey string) (byte, error) {
+	pubKey := PrivateKeyToPublicKey(privateKey)
+	if len(pubKey) == 0 {
+		return 0, fmt.Errorf("invalid private key")
+	}
+
+	return common.GetShardIDFromLastByte(pubKey[len(pubKey)-1]), nil
+}
+
+// EstimatePRVReserveForFees estimates the total PRV fee (in nano PRV) needed for numTxs future PRV transactions sent by
+// privateKey, each of which spends avgInputs input coins, based on the current fee per KB of the sender's shard returned
+// by the remote node. It is meant for advising users on how much PRV to keep for paying fees.
+//
+// Note that transactions created with a zero fee are charged DefaultPRVFee instead of the fee rate of the network.
+func (client *IncClient) EstimatePRVReserveForFees(privateKey string, numTxs int, avgInputs int) (uint64, error) {
+	if numTxs < 0 {
+		return 0, fmt.Errorf("invalid number of transactions %v", numTxs)
+	}
+	if avgInputs < 1 || avgInputs > MaxInputSize {
+		return 0, fmt.Errorf("average number of inputs must be in [1, %v], got %v", MaxInputSize, avgInputs)
+	}
+	shardID, err := getSenderShardID(privateKey)
+	if err != nil {
+		return 0, err
+	}
+	if numTxs == 0 {
+		return 0, nil
+	}
+
+	feePerKB, err := client.getFeePerKB(shardID, common.PRVIDStr)
+	if err != nil {
+		return 0, err
+	}
+
+	feePerTx, err := safemath.MulUint64(feePerKB, estimateTxV2Size(avgInputs))
+	if err != nil {
+		return 0, fmt.Errorf("fee overflows: %v", err)
+	}
+	totalFee, err := safemath.MulUint64(feePerTx, uint64(numTxs))
+	if err != nil {
+		return 0, fmt.Errorf("total fee overflows: %v", err)
+	}
+
+	return totalFee, nil
+}
+
+// createTxVer2 creates a PRV transaction version 2. If estimationOnly is true, the range proofs are skipped.
+func (client *IncClient) createTxVer2(param *TxParam, estimationOnly bool) (*tx_ver2.Tx, error) {
 	privateKey := param.senderPrivateKey
 	//Create sender private key from string
 	senderWallet, err := wallet.Base58CheckDeserialize(privateKey)
 	if err != nil {
-		return nil, "", fmt.Errorf("cannot init private key %v: %v", privateKey, err)
+		return nil, fmt.Errorf("cannot init private key %v: %v", privateKey, err)
 	}
 
 	//Create list of payment infos
-	paymentInfos, err := createPaymentInfos(param.receiverList, param.amountList)
+	paymentInfos, err := createPaymentInfos(param.prvReceivers())
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
 
 	txFee := param.fee
@@ -122,7 +354,10 @@ func (client *IncClient) CreateRawTransactionVer2(param *TxParam) ([]byte, strin
 	//Calculate the total transacted amount
 	totalAmount := txFee
 	for _, amount := range param.amountList {
-		totalAmount += amount
+		totalAmount, err = safemath.AddUint64(totalAmount, amount)
+		if err != nil {
+			return nil, fmt.Errorf("total amount overflows: %v", err)
+		}
 	}
 
 	hasPrivacy := true
@@ -132,7 +367,10 @@ func (client *IncClient) CreateRawTransactionVer2(param *TxParam) ([]byte, strin
 
 	coinsToSpend, kArgs, err := client.initParamsV2(param, common.PRVIDStr, totalAmount)
 	if err != nil {
-		return nil, "", err
+		return nil, err
+	}
+	if estimationOnly {
+		kArgs[utils.EstimationOnly] = true
 	}
 
 	txParam := tx_generic.NewTxPrivacyInitParams(&(senderWallet.KeySet.PrivateKey), paymentInfos, coinsToSpend, txFee, hasPrivacy, &common.PRVCoinID, param.md, nil, kArgs)
@@ -140,17 +378,13 @@ func (client *IncClient) CreateRawTransactionVer2(param *TxParam) ([]byte, strin
 	tx := new(tx_ver2.Tx)
 	err = tx.Init(txParam)
 	if err != nil {
-		return nil, "", fmt.Errorf("init txver2 error: %v", err)
+		return nil, fmt.Errorf("init txver2 error: %v", err)
 	}
-
-	txBytes, err := json.Marshal(tx)
-	if err != nil {
-		return nil, "", fmt.Errorf("cannot marshal txver2: %v", err)
+	if !estimationOnly && param.Bumpable {
+		client.recordTxIntent(tx.Hash().String(), param, txFee, coinsToSpend)
 	}
 
-	base58CheckData := base58.Base58Check{}.Encode(txBytes, common.ZeroByte)
-
-	return []byte(base58CheckData), tx.Hash().String(), nil
+	return tx, nil
 }
 
 // CreateAndSendRawTransaction creates a PRV transaction with the provided version, and submits it to the Incognito network.
@@ -213,10 +447,21 @@ func (client *IncClient) CreateRawTransactionWithInputCoins(param *TxParam, inpu
 }
 
 // SendRawTx sends submits a raw PRV transaction to the Incognito blockchain.
+//
+// Estimation-only transactions (see PreviewTransaction) are rejected before being sent.
 func (client *IncClient) SendRawTx(encodedTx []byte) error {
-	responseInBytes, err := client.rpcServer.SendRawTx(string(encodedTx))
+	return client.SendRawTxWithContext(context.Background(), encodedTx)
+}
+
+// SendRawTxWithContext is the same as SendRawTx, except that the request is aborted when ctx is done.
+func (client *IncClient) SendRawTxWithContext(ctx context.Context, encodedTx []byte) error {
+	if err := checkEstimationOnlyTx(encodedTx); err != nil {
+		return err
+	}
+
+	responseInBytes, err := client.rpcServer.WithContext(ctx).SendRawTx(string(encodedTx))
 	if err != nil {
-		return nil
+		return err
 	}
 
 	err = rpchandler.ParseResponse(responseInBytes, nil)
@@ -226,3 +471,29 @@ func (client *IncClient) SendRawTx(encodedTx []byte) error {
 
 	return nil
 }
+
+// PredictTxHash returns the hash that the Incognito network will assign to a base58-encoded transaction (as returned
+// by CreateRawTransaction or CreateRawTokenTransaction), i.e, the transaction ID reported by the remote node after
+// the transaction is submitted. It allows callers to track a transaction right after building it.
+//
+// It works for all transaction types (PRV or token, version 1 or 2).
+func (client *IncClient) PredictTxHash(encodedTx []byte) (string, error) {
+	rawTxBytes, _, err := base58.Base58Check{}.Decode(string(encodedTx))
+	if err != nil {
+		return "", fmt.Errorf("cannot decode transaction: %v", err)
+	}
+	txChoice, err := transaction.DeserializeTransactionJSON(rawTxBytes)
+	if err != nil {
+		return "", fmt.Errorf("cannot parse transaction: %v", err)
+	}
+	tx := txChoice.ToTx()
+	if tx == nil {
+		return "", fmt.Errorf("cannot parse transaction: unknown transaction type")
+	}
+	txHash := tx.Hash()
+	if txHash == nil {
+		return "", fmt.Errorf("cannot compute the hash of the transaction")
+	}
+
+	return txHash.String(), nil
+}
@@ -0,0 +1,121 @@
+package incclient
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/coin"
+	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"github.com/incognitochain/go-incognito-sdk-v2/metadata"
+)
+
+// MaxTxIntents is the maximum number of transaction intents remembered by an IncClient for fee bumping.
+// When the limit is reached, the oldest intent is forgotten.
+const MaxTxIntents = 1000
+
+// txIntent records what a PRV transaction created by an IncClient was meant to do: its sender, recipients, amounts,
+// fee, metadata and the input coins it spends.
+//
+// On the Incognito network, the recipients of a transaction v2 are one-time addresses which cannot be mapped back to
+// payment addresses, so the intent has to be kept locally when the transaction is created. Intents are only kept for
+// transactions created with TxParam.Bumpable. The sender is identified by a salted hash of its private key, so that
+// the private key itself is not kept in memory.
+type txIntent struct {
+	senderKeyHash common.Hash
+	receiverList  []string
+	amountList    []uint64
+	fee           uint64
+	md            metadata.Metadata
+	inputCoins    []coin.PlainCoin
+}
+
+// txIntentStore keeps the intents of the most recent PRV transactions created by an IncClient, indexed by
+// transaction hash.
+type txIntentStore struct {
+	mtx     *sync.Mutex
+	salt    []byte
+	intents map[string]*txIntent
+	order   []string
+}
+
+func newTxIntentStore() *txIntentStore {
+	return &txIntentStore{mtx: new(sync.Mutex), salt: common.RandBytes(32), intents: make(map[string]*txIntent)}
+}
+
+// keyHash returns the salted hash identifying the given private key in the store.
+func (s *txIntentStore) keyHash(privateKey string) common.Hash {
+	return common.HashH(append(append([]byte{}, s.salt...), privateKey...))
+}
+
+// add remembers the intent of the transaction with the given hash.
+func (s *txIntentStore) add(txHash string, intent *txIntent) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if _, ok := s.intents[txHash]; !ok {
+		s.order = append(s.order, txHash)
+	}
+	s.intents[txHash] = intent
+	for len(s.order) > MaxTxIntents {
+		delete(s.intents, s.order[0])
+		s.order = s.order[1:]
+	}
+}
+
+// get returns the intent of the transaction with the given hash, if any.
+func (s *txIntentStore) get(txHash string) (*txIntent, bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	intent, ok := s.intents[txHash]
+	return intent, ok
+}
+
+// recordTxIntent remembers the intent of a PRV transaction built from param and spending inputCoins.
+func (client *IncClient) recordTxIntent(txHash string, param *TxParam, fee uint64, inputCoins []coin.PlainCoin) {
+	intent := &txIntent{
+		senderKeyHash: client.txIntentStore.keyHash(param.senderPrivateKey),
+		receiverList:  append([]string{}, param.receiverList...),
+		amountList:    append([]uint64{}, param.amountList...),
+		fee:           fee,
+		md:            param.md,
+		inputCoins:    append([]coin.PlainCoin{}, inputCoins...),
+	}
+	client.txIntentStore.add(txHash, intent)
+}
+
+// BumpFee re-creates a PRV transaction previously created by this client with a higher fee. The new transaction sends
+// the same amounts to the same recipients (with the same metadata) and spends the same input coins, but it is signed
+// with fresh decoys. It returns the base58-encoded transaction, the transaction's hash, and an error (if any).
+// The new transaction is not submitted; use SendRawTx to broadcast it.
+//
+// Only PRV transactions version 2 created by this IncClient instance with TxParam.Bumpable set (e.g, via
+// CreateRawTransaction or CreateAndSendRawTransaction) can be bumped, because their recipients cannot be recovered
+// from the network. The new transaction is bumpable as well.
+// An error is returned if the original transaction is unknown, was created with another private key, if newFee is
+// not higher than the original fee, or if the input coins of the original transaction cannot cover the higher fee.
+// The new transaction is built by CreateRawTransaction, so the new fee is checked against the fee guard of the client
+// (see SetFeeGuard).
+//
+// NOTE: the Incognito network has no replace-by-fee mechanism. The returned transaction is a distinct transaction
+// which double-spends the inputs of the original one, so at most one of them can be confirmed. Nodes may reject the
+// new transaction while the original one is still in their mempool, and the original one may still be mined; callers
+// must check which one ends up being confirmed before acting on either.
+func (client *IncClient) BumpFee(privateKey, originalTxHash string, newFee uint64) ([]byte, string, error) {
+	intent, ok := client.txIntentStore.get(originalTxHash)
+	if !ok {
+		return nil, "", fmt.Errorf("transaction %v was not created by this client", originalTxHash)
+	}
+	if intent.senderKeyHash != client.txIntentStore.keyHash(privateKey) {
+		return nil, "", fmt.Errorf("transaction %v was not created with the given private key", originalTxHash)
+	}
+	if newFee <= intent.fee {
+		return nil, "", fmt.Errorf("new fee %v must be greater than the original fee %v", newFee, intent.fee)
+	}
+
+	param := NewTxParam(privateKey, intent.receiverList, intent.amountList, newFee, nil, intent.md, nil)
+	param.InputCoins = intent.inputCoins
+	param.Bumpable = true
+
+	return client.CreateRawTransaction(param, 2)
+}
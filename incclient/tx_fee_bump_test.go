@@ -0,0 +1,187 @@
+package incclient
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/coin"
+	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"github.com/incognitochain/go-incognito-sdk-v2/common/base58"
+	"github.com/incognitochain/go-incognito-sdk-v2/key"
+	"github.com/incognitochain/go-incognito-sdk-v2/metadata"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/rpc"
+	"github.com/incognitochain/go-incognito-sdk-v2/transaction"
+	"github.com/incognitochain/go-incognito-sdk-v2/wallet"
+	"github.com/stretchr/testify/assert"
+)
+
+// decodeTestTx is for testing purposes ONLY.
+func decodeTestTx(t *testing.T, encodedTx []byte) metadata.Transaction {
+	rawTxBytes, _, err := base58.Base58Check{}.Decode(string(encodedTx))
+	assert.Equal(t, nil, err, fmt.Errorf("decode error: %v", err))
+	txChoice, err := transaction.DeserializeTransactionJSON(rawTxBytes)
+	assert.Equal(t, nil, err, fmt.Errorf("DeserializeTransactionJSON error: %v", err))
+	return txChoice.ToTx()
+}
+
+// receivedAmounts returns the amounts of the output coins of tx belonging to keySet. It is for testing purposes ONLY.
+func receivedAmounts(t *testing.T, tx metadata.Transaction, keySet *key.KeySet) []uint64 {
+	res := make([]uint64, 0)
+	for _, outCoin := range tx.GetProof().GetOutputCoins() {
+		c, ok := outCoin.(*coin.CoinV2)
+		assert.Equal(t, true, ok)
+		if belongs, _ := c.DoesCoinBelongToKeySet(keySet); !belongs {
+			continue
+		}
+		plainCoin, err := c.Decrypt(keySet)
+		assert.Equal(t, nil, err, fmt.Errorf("Decrypt error: %v", err))
+		res = append(res, plainCoin.GetValue())
+	}
+	return res
+}
+
+// bumpableTxParam returns a TxParam with Bumpable set. It is for testing purposes ONLY.
+func bumpableTxParam(privateKey string, receiverList []string, amountList []uint64, fee uint64,
+	tokenParam *TxTokenParam, md metadata.Metadata, kArgs map[string]interface{}) *TxParam {
+	param := NewTxParam(privateKey, receiverList, amountList, fee, tokenParam, md, kArgs)
+	param.Bumpable = true
+	return param
+}
+
+func TestIncClient_BumpFee(t *testing.T) {
+	shardID := byte(common.RandInt() % common.MaxShardNumber)
+	senderWallet, err := wallet.GenRandomWalletForShardID(shardID)
+	if err != nil {
+		panic(err)
+	}
+	privateKey := senderWallet.Base58CheckSerialize(wallet.PrivateKeyType)
+
+	server := newMockCoinServer()
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	err = server.addCoins(senderWallet.KeySet.PaymentAddress, 10)
+	if err != nil {
+		panic(err)
+	}
+
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+	client.SetCoinStore(NewMemCoinStore())
+
+	for i := 0; i < numTests; i++ {
+		receiverWallet, err := wallet.GenRandomWalletForShardID(byte(common.RandInt() % common.MaxShardNumber))
+		if err != nil {
+			panic(err)
+		}
+		receiver := receiverWallet.Base58CheckSerialize(wallet.PaymentAddressType)
+		amount := 1 + common.RandUint64()%1000
+
+		encodedTx, txHash, err := client.CreateRawTransaction(bumpableTxParam(privateKey, []string{receiver}, []uint64{amount}, 100, nil, nil, nil), 2)
+		assert.Equal(t, nil, err, fmt.Errorf("CreateRawTransaction error: %v", err))
+		originalTx := decodeTestTx(t, encodedTx)
+
+		_, _, err = client.BumpFee(privateKey, txHash, 100)
+		assert.NotEqual(t, nil, err)
+
+		// the bumped fee is paid out of the change of the original transaction.
+		change := uint64(0)
+		for _, value := range receivedAmounts(t, originalTx, &senderWallet.KeySet) {
+			change += value
+		}
+		if change == 0 {
+			_, _, err = client.BumpFee(privateKey, txHash, 101)
+			assert.NotEqual(t, nil, err)
+			continue
+		}
+		newFee := 100 + 1 + common.RandUint64()%change
+
+		encodedBumpedTx, bumpedTxHash, err := client.BumpFee(privateKey, txHash, newFee)
+		assert.Equal(t, nil, err, fmt.Errorf("BumpFee error: %v", err))
+		assert.NotEqual(t, txHash, bumpedTxHash)
+		bumpedTx := decodeTestTx(t, encodedBumpedTx)
+
+		assert.Equal(t, newFee, bumpedTx.GetTxFee())
+		assert.Equal(t, []uint64{amount}, receivedAmounts(t, originalTx, &receiverWallet.KeySet))
+		assert.Equal(t, []uint64{amount}, receivedAmounts(t, bumpedTx, &receiverWallet.KeySet))
+
+		// both transactions spend the same input coins.
+		originalKeyImages := make(map[string]bool)
+		for _, inputCoin := range originalTx.GetProof().GetInputCoins() {
+			originalKeyImages[inputCoin.GetKeyImage().String()] = true
+		}
+		assert.Equal(t, len(originalKeyImages), len(bumpedTx.GetProof().GetInputCoins()))
+		for _, inputCoin := range bumpedTx.GetProof().GetInputCoins() {
+			assert.Equal(t, true, originalKeyImages[inputCoin.GetKeyImage().String()])
+		}
+	}
+
+	_, _, err = client.BumpFee(privateKey, common.HashH([]byte("unknown")).String(), 200)
+	assert.NotEqual(t, nil, err)
+
+	receiverWallet, err := wallet.GenRandomWalletForShardID(shardID)
+	if err != nil {
+		panic(err)
+	}
+	receiver := receiverWallet.Base58CheckSerialize(wallet.PaymentAddressType)
+
+	// nothing is kept for a transaction which is not bumpable.
+	_, txHash, err := client.CreateRawTransaction(NewTxParam(privateKey, []string{receiver}, []uint64{500}, 100, nil, nil, nil), 2)
+	assert.Equal(t, nil, err, fmt.Errorf("CreateRawTransaction error: %v", err))
+	_, ok := client.txIntentStore.get(txHash)
+	assert.Equal(t, false, ok)
+	_, _, err = client.BumpFee(privateKey, txHash, 200)
+	assert.NotEqual(t, nil, err)
+
+	// the new fee is checked against the fee guard.
+	_, txHash, err = client.CreateRawTransaction(bumpableTxParam(privateKey, []string{receiver}, []uint64{500}, 100, nil, nil, nil), 2)
+	assert.Equal(t, nil, err, fmt.Errorf("CreateRawTransaction error: %v", err))
+	client.SetFeeGuard(DefaultMaxFeeRatio, true)
+	_, _, err = client.BumpFee(privateKey, txHash, 501)
+	_, ok = err.(*FeeExceedsAmountError)
+	assert.Equal(t, true, ok, fmt.Errorf("expected a *FeeExceedsAmountError, got %v", err))
+}
+
+func TestIncClient_BumpFee_WithContext(t *testing.T) {
+	shardID := byte(common.RandInt() % common.MaxShardNumber)
+	senderWallet, err := wallet.GenRandomWalletForShardID(shardID)
+	if err != nil {
+		panic(err)
+	}
+	privateKey := senderWallet.Base58CheckSerialize(wallet.PrivateKeyType)
+	otherWallet, err := wallet.GenRandomWalletForShardID(shardID)
+	if err != nil {
+		panic(err)
+	}
+
+	server := newMockCoinServer()
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	err = server.addCoins(senderWallet.KeySet.PaymentAddress, 10)
+	if err != nil {
+		panic(err)
+	}
+
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+	client.SetCoinStore(NewMemCoinStore())
+
+	// a transaction created through a copy returned by WithContext can be bumped by the original client, and vice versa.
+	receiver := PrivateKeyToPaymentAddress(privateKey, -1)
+	_, txHash, err := client.WithContext(context.Background()).CreateRawTransaction(bumpableTxParam(privateKey, []string{receiver}, []uint64{1000}, 100, nil, nil, nil), 2)
+	assert.Equal(t, nil, err, fmt.Errorf("CreateRawTransaction error: %v", err))
+	_, _, err = client.BumpFee(privateKey, txHash, 200)
+	assert.Equal(t, nil, err, fmt.Errorf("BumpFee error: %v", err))
+
+	_, txHash, err = client.CreateRawTransaction(bumpableTxParam(privateKey, []string{receiver}, []uint64{1000}, 100, nil, nil, nil), 2)
+	assert.Equal(t, nil, err, fmt.Errorf("CreateRawTransaction error: %v", err))
+	_, _, err = client.WithContext(context.Background()).BumpFee(privateKey, txHash, 200)
+	assert.Equal(t, nil, err, fmt.Errorf("BumpFee error: %v", err))
+
+	// only the sender can bump the fee, and its private key is not kept by the client.
+	_, _, err = client.BumpFee(otherWallet.Base58CheckSerialize(wallet.PrivateKeyType), txHash, 200)
+	assert.NotEqual(t, nil, err)
+	intent, ok := client.txIntentStore.get(txHash)
+	assert.Equal(t, true, ok)
+	assert.NotEqual(t, common.HashH([]byte(privateKey)), intent.senderKeyHash)
+	assert.Equal(t, client.txIntentStore.keyHash(privateKey), intent.senderKeyHash)
+}
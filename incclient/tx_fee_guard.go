@@ -0,0 +1,87 @@
+package incclient
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// DefaultMaxFeeRatio is the default maximum ratio between the fee and the total sent amount of a transaction above
+// which the fee is considered a mistake.
+const DefaultMaxFeeRatio = 1.0
+
+// FeeExceedsAmountError indicates that the fee of a transaction exceeds the allowed fraction of its total sent amount
+// (see SetFeeGuard).
+type FeeExceedsAmountError struct {
+	// Fee is the fee of the transaction.
+	Fee uint64
+
+	// TotalAmount is the total amount sent by the transaction, in the token of the fee.
+	TotalAmount *big.Int
+
+	// MaxRatio is the maximum ratio between the fee and TotalAmount allowed by the fee guard.
+	MaxRatio float64
+}
+
+// Error implements the error interface.
+func (e *FeeExceedsAmountError) Error() string {
+	return fmt.Sprintf("fee exceeds the allowed fraction of the sent amount: fee %v, total amount %v, max ratio %v",
+		e.Fee, e.TotalAmount, e.MaxRatio)
+}
+
+// feeGuard describes how the transaction builders react to a fee that is too high compared to the sent amount.
+type feeGuard struct {
+	maxRatio float64
+	enforce  bool
+}
+
+// SetFeeGuard configures the protection of CreateRawTransaction, CreateRawTokenTransaction and BumpFee against
+// fat-finger fees. A transaction is flagged when its fee is greater than maxRatio times the total amount it sends in
+// the token of the fee (e.g, a maxRatio of 0.5 flags a fee of more than half the sent amount). A flagged transaction is
+// rejected with a *FeeExceedsAmountError if enforce is true; otherwise, a warning is logged and the transaction is
+// created anyway. A non-positive maxRatio disables the check.
+//
+// By default, the ratio is DefaultMaxFeeRatio and flagged transactions only trigger a warning.
+func (client *IncClient) SetFeeGuard(maxRatio float64, enforce bool) {
+	client.feeGuard = &feeGuard{maxRatio: maxRatio, enforce: enforce}
+}
+
+// checkFeeGuard checks the fee of a transaction against the fee guard of the client. The fee is compared to the amount
+// sent in its token: the PRV amount, or the token amount for a token transaction paying its fee in the token.
+// Transactions sending nothing in the token of their fee (e.g, most transactions with metadata) are never flagged.
+func (client *IncClient) checkFeeGuard(param *TxParam) error {
+	guard := client.feeGuard
+	if guard == nil {
+		guard = &feeGuard{maxRatio: DefaultMaxFeeRatio}
+	}
+	if guard.maxRatio <= 0 {
+		return nil
+	}
+
+	fee := param.fee
+	if fee == 0 {
+		fee = DefaultPRVFee
+	}
+	amountList := param.amountList
+	if param.txTokenParam != nil && param.txTokenParam.hasTokenFee {
+		fee = param.txTokenParam.tokenFee
+		amountList = param.txTokenParam.amountList
+	}
+	totalAmount := new(big.Int)
+	for _, amount := range amountList {
+		totalAmount.Add(totalAmount, new(big.Int).SetUint64(amount))
+	}
+	if totalAmount.Sign() == 0 {
+		return nil
+	}
+
+	maxFee := new(big.Float).Mul(new(big.Float).SetInt(totalAmount), big.NewFloat(guard.maxRatio))
+	if new(big.Float).SetUint64(fee).Cmp(maxFee) <= 0 {
+		return nil
+	}
+	if guard.enforce {
+		return &FeeExceedsAmountError{Fee: fee, TotalAmount: totalAmount, MaxRatio: guard.maxRatio}
+	}
+	Logger.Printf("WARNING: fee %v exceeds %v times the total sent amount %v\n", fee, guard.maxRatio, totalAmount)
+
+	return nil
+}
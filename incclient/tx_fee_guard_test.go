@@ -0,0 +1,70 @@
+package incclient
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIncClient_checkFeeGuard(t *testing.T) {
+	client := newIncClient(nil, nil, nil, 2)
+
+	// the default guard only warns.
+	err := client.checkFeeGuard(NewTxParam("", []string{""}, []uint64{1}, 1000, nil, nil, nil))
+	assert.Equal(t, nil, err, fmt.Errorf("checkFeeGuard error: %v", err))
+
+	client.SetFeeGuard(0.5, true)
+	for i := 0; i < numTests; i++ {
+		amount := 2 + 2*uint64(i)
+		atThreshold := NewTxParam("", []string{"", ""}, []uint64{amount / 2, amount / 2}, amount/2, nil, nil, nil)
+		err = client.checkFeeGuard(atThreshold)
+		assert.Equal(t, nil, err, fmt.Errorf("checkFeeGuard error: %v", err))
+
+		beyondThreshold := NewTxParam("", []string{"", ""}, []uint64{amount / 2, amount / 2}, amount/2+1, nil, nil, nil)
+		err = client.checkFeeGuard(beyondThreshold)
+		feeErr, ok := err.(*FeeExceedsAmountError)
+		assert.Equal(t, true, ok, fmt.Errorf("expected a *FeeExceedsAmountError, got %v", err))
+		assert.Equal(t, amount/2+1, feeErr.Fee)
+		assert.Equal(t, amount, feeErr.TotalAmount.Uint64())
+	}
+
+	// transactions sending no PRV are never flagged.
+	err = client.checkFeeGuard(NewTxParam("", []string{}, []uint64{}, 1000, nil, nil, nil))
+	assert.Equal(t, nil, err, fmt.Errorf("checkFeeGuard error: %v", err))
+
+	// a zero fee stands for DefaultPRVFee.
+	err = client.checkFeeGuard(NewTxParam("", []string{""}, []uint64{2 * DefaultPRVFee}, 0, nil, nil, nil))
+	assert.Equal(t, nil, err, fmt.Errorf("checkFeeGuard error: %v", err))
+	err = client.checkFeeGuard(NewTxParam("", []string{""}, []uint64{2*DefaultPRVFee - 1}, 0, nil, nil, nil))
+	assert.NotEqual(t, nil, err)
+
+	// warn-only and disabled guards never reject.
+	client.SetFeeGuard(0.5, false)
+	err = client.checkFeeGuard(NewTxParam("", []string{""}, []uint64{1}, 1000, nil, nil, nil))
+	assert.Equal(t, nil, err, fmt.Errorf("checkFeeGuard error: %v", err))
+	client.SetFeeGuard(0, true)
+	err = client.checkFeeGuard(NewTxParam("", []string{""}, []uint64{1}, 1000, nil, nil, nil))
+	assert.Equal(t, nil, err, fmt.Errorf("checkFeeGuard error: %v", err))
+
+	// a token fee is compared to the token amount; a PRV fee of a token transaction to the PRV amount.
+	client.SetFeeGuard(DefaultMaxFeeRatio, true)
+	tokenID := common.HashH([]byte("token")).String()
+	tokenParam := NewTxTokenParam(tokenID, 1, []string{""}, []uint64{10}, true, 11, nil)
+	err = client.checkFeeGuard(NewTxParam("", nil, nil, 1000, tokenParam, nil, nil))
+	_, ok := err.(*FeeExceedsAmountError)
+	assert.Equal(t, true, ok, fmt.Errorf("expected a *FeeExceedsAmountError, got %v", err))
+	tokenParam = NewTxTokenParam(tokenID, 1, []string{""}, []uint64{10}, false, 0, nil)
+	err = client.checkFeeGuard(NewTxParam("", nil, nil, 1000, tokenParam, nil, nil))
+	assert.Equal(t, nil, err, fmt.Errorf("checkFeeGuard error: %v", err))
+
+	// CreateRawTransaction and CreateRawTokenTransaction reject a flagged transaction before doing anything else.
+	_, _, err = client.CreateRawTransaction(NewTxParam("", []string{""}, []uint64{1}, 2, nil, nil, nil), 2)
+	_, ok = err.(*FeeExceedsAmountError)
+	assert.Equal(t, true, ok, fmt.Errorf("expected a *FeeExceedsAmountError, got %v", err))
+	tokenParam = NewTxTokenParam(tokenID, 1, []string{""}, []uint64{10}, true, 11, nil)
+	_, _, err = client.CreateRawTokenTransaction(NewTxParam("", nil, nil, 0, tokenParam, nil, nil), 2)
+	_, ok = err.(*FeeExceedsAmountError)
+	assert.Equal(t, true, ok, fmt.Errorf("expected a *FeeExceedsAmountError, got %v", err))
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/incognitochain/go-incognito-sdk-v2/coin"
 	"github.com/incognitochain/go-incognito-sdk-v2/common"
 	"github.com/incognitochain/go-incognito-sdk-v2/common/base58"
+	"github.com/incognitochain/go-incognito-sdk-v2/internal/safemath"
 	"github.com/incognitochain/go-incognito-sdk-v2/metadata"
 	"github.com/incognitochain/go-incognito-sdk-v2/wallet"
 	"sort"
@@ -90,6 +91,9 @@ type TxOut struct {
 	TokenFee   uint64
 	Metadata   metadata.Metadata
 	Note       string
+
+	// BlockHeight is the height of the shard block including the transaction, 0 if it is not in a block yet.
+	BlockHeight uint64
 }
 
 // GetLockTime returns the lock-time.
@@ -356,7 +360,7 @@ func (client *IncClient) GetListTxsOutV1(privateKey string, tokenIDStr string) (
 			continue
 		}
 
-		tx, err := client.GetTx(txHash)
+		tx, txDetail, err := client.getTxWithDetail(txHash)
 		if err != nil {
 			return nil, err
 		}
@@ -393,16 +397,17 @@ func (client *IncClient) GetListTxsOutV1(privateKey string, tokenIDStr string) (
 				note += " (Tx Fee)"
 			}
 			newTxOut := TxOut{
-				Version:    tx.GetVersion(),
-				LockTime:   tx.GetLockTime(),
-				TxHash:     txHash,
-				TokenID:    tx.GetTokenID().String(),
-				SpentCoins: spentCoins,
-				Receivers:  receivers,
-				Amount:     amount,
-				Metadata:   tx.GetMetadata(),
-				PRVFee:     fee,
-				Note:       note,
+				Version:     tx.GetVersion(),
+				LockTime:    tx.GetLockTime(),
+				TxHash:      txHash,
+				TokenID:     tx.GetTokenID().String(),
+				SpentCoins:  spentCoins,
+				Receivers:   receivers,
+				Amount:      amount,
+				Metadata:    tx.GetMetadata(),
+				PRVFee:      fee,
+				Note:        note,
+				BlockHeight: txDetail.BlockHeight,
 			}
 			if !isPRVFee {
 				newTxOut.PRVFee = 0
@@ -470,7 +475,7 @@ func (client *IncClient) GetListTxsOutV2(privateKey string, tokenIDStr string) (
 			continue
 		}
 
-		tx, err := client.GetTx(txHash)
+		tx, txDetail, err := client.getTxWithDetail(txHash)
 		if err != nil {
 			return nil, err
 		}
@@ -508,16 +513,17 @@ func (client *IncClient) GetListTxsOutV2(privateKey string, tokenIDStr string) (
 			}
 			note = strings.TrimSpace(note)
 			newTxOut := TxOut{
-				Version:    tx.GetVersion(),
-				LockTime:   tx.GetLockTime(),
-				TxHash:     txHash,
-				TokenID:    tx.GetTokenID().String(),
-				SpentCoins: spentCoins,
-				Receivers:  receivers,
-				Amount:     amount,
-				Metadata:   tx.GetMetadata(),
-				PRVFee:     fee,
-				Note:       note,
+				Version:     tx.GetVersion(),
+				LockTime:    tx.GetLockTime(),
+				TxHash:      txHash,
+				TokenID:     tx.GetTokenID().String(),
+				SpentCoins:  spentCoins,
+				Receivers:   receivers,
+				Amount:      amount,
+				Metadata:    tx.GetMetadata(),
+				PRVFee:      fee,
+				Note:        note,
+				BlockHeight: txDetail.BlockHeight,
 			}
 			if !isPRVFee {
 				newTxOut.PRVFee = 0
@@ -596,3 +602,51 @@ func (client *IncClient) GetTxHistoryV2(privateKey string, tokenIDStr string) (*
 
 	return &res, nil
 }
+
+// GetTotalFeesPaid returns the total PRV fee (in nano PRV) paid by a private key for the transactions it originated in
+// the shard blocks [fromHeight, toHeight]. A toHeight of 0 means no upper bound.
+//
+// Transactions are detected from the history of out-going PRV transactions (see GetListTxsOut), i.e, via the key images
+// of the spent PRV coins of the account. Therefore, it can only account for transactions whose (PRV) inputs the account
+// owns: fees paid in a pToken and transactions created by others on behalf of the account are not counted. The block
+// heights come with the history, so no RPC is made per transaction on top of it.
+func (client *IncClient) GetTotalFeesPaid(privateKey string, fromHeight, toHeight uint64) (uint64, error) {
+	if toHeight != 0 && fromHeight > toHeight {
+		return 0, fmt.Errorf("fromHeight %v is greater than toHeight %v", fromHeight, toHeight)
+	}
+
+	txOuts, err := client.GetListTxsOut(privateKey, common.PRVIDStr)
+	if err != nil {
+		return 0, err
+	}
+
+	return sumTxOutFees(txOuts, fromHeight, toHeight)
+}
+
+// sumTxOutFees returns the total PRV fee of the given out-going transactions included in the blocks
+// [fromHeight, toHeight] (no upper bound if toHeight is 0). Transactions not in a block yet are only counted if
+// fromHeight is 0 and toHeight is 0.
+func sumTxOutFees(txOuts []TxOut, fromHeight, toHeight uint64) (uint64, error) {
+	totalFee := uint64(0)
+	seen := make(map[string]bool)
+	for _, txOut := range txOuts {
+		if txOut.PRVFee == 0 || seen[txOut.TxHash] {
+			continue
+		}
+		seen[txOut.TxHash] = true
+
+		if fromHeight != 0 || toHeight != 0 {
+			if txOut.BlockHeight == 0 || txOut.BlockHeight < fromHeight || (toHeight != 0 && txOut.BlockHeight > toHeight) {
+				continue
+			}
+		}
+
+		var err error
+		totalFee, err = safemath.AddUint64(totalFee, txOut.PRVFee)
+		if err != nil {
+			return 0, fmt.Errorf("total fee overflows: %v", err)
+		}
+	}
+
+	return totalFee, nil
+}
@@ -1,9 +1,23 @@
 package incclient
 
 import (
-	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"github.com/incognitochain/go-incognito-sdk-v2/common/base58"
+	"github.com/incognitochain/go-incognito-sdk-v2/metadata"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/jsonresult"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/rpc"
+	"github.com/incognitochain/go-incognito-sdk-v2/wallet"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestIncClient_GetListTxsInV1(t *testing.T) {
@@ -158,3 +172,174 @@ func TestIncClient_GetTxHistoryV2(t *testing.T) {
 		log.Printf("%v\n", txOut.Summarize())
 	}
 }
+
+func TestSumTxOutFees(t *testing.T) {
+	// a synthetic history: txHash -> (block height, PRV fee)
+	history := []struct {
+		txHash      string
+		blockHeight uint64
+		fee         uint64
+	}{
+		{"tx-1", 100, 100},
+		{"tx-2", 150, 250},
+		{"tx-3", 200, 1000},
+		{"tx-4", 300, 40},
+		{"tx-token-fee", 180, 0}, // the fee was paid in a pToken
+	}
+	txOuts := make([]TxOut, 0)
+	for _, tx := range history {
+		txOuts = append(txOuts, TxOut{TxHash: tx.txHash, PRVFee: tx.fee, TokenID: common.PRVIDStr, BlockHeight: tx.blockHeight})
+	}
+	txOuts = append(txOuts, txOuts[0]) // duplicates are counted once
+
+	testCases := []struct {
+		fromHeight, toHeight, expected uint64
+	}{
+		{0, 0, 1390},
+		{100, 300, 1390},
+		{101, 0, 1290},
+		{150, 200, 1250},
+		{201, 299, 0},
+		{301, 0, 0},
+	}
+	for _, tc := range testCases {
+		totalFee, err := sumTxOutFees(txOuts, tc.fromHeight, tc.toHeight)
+		assert.Equal(t, nil, err, fmt.Errorf("sumTxOutFees error: %v", err))
+		assert.Equal(t, tc.expected, totalFee, fmt.Errorf("heights [%v, %v]", tc.fromHeight, tc.toHeight))
+	}
+
+	// a transaction not in a block yet is only counted without a height range
+	pendingTxOuts := append(txOuts, TxOut{TxHash: "tx-pending", PRVFee: 10})
+	totalFee, err := sumTxOutFees(pendingTxOuts, 0, 0)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, uint64(1400), totalFee)
+	totalFee, err = sumTxOutFees(pendingTxOuts, 0, 1000)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, uint64(1390), totalFee)
+
+	// invalid height range
+	_, err = new(IncClient).GetTotalFeesPaid("", 10, 5)
+	assert.NotEqual(t, nil, err)
+}
+
+// newTestTxDetail returns the detail of a PRV transaction v2 as returned by the `gettransactionbyhash` RPC. It is for
+// testing purposes ONLY.
+func newTestTxDetail(tx metadata.Transaction, blockHeight uint64) jsonresult.TransactionDetail {
+	return jsonresult.TransactionDetail{
+		BlockHeight:  blockHeight,
+		ShardID:      common.GetShardIDFromLastByte(tx.GetSenderAddrLastByte()),
+		Hash:         tx.Hash().String(),
+		Version:      tx.GetVersion(),
+		Type:         tx.GetType(),
+		RawLockTime:  tx.GetLockTime(),
+		Fee:          tx.GetTxFee(),
+		Proof:        base64.StdEncoding.EncodeToString(tx.GetProof().Bytes()),
+		RawSigPubKey: tx.GetSigPubKey(),
+		Sig:          base58.Base58Check{}.Encode(tx.GetSig(), common.ZeroByte),
+		Info:         "null",
+		IsInBlock:    true,
+	}
+}
+
+func TestIncClient_GetTotalFeesPaid(t *testing.T) {
+	senderWallet, err := wallet.GenRandomWalletForShardID(byte(common.RandInt() % common.MaxShardNumber))
+	if err != nil {
+		panic(err)
+	}
+	privateKey := senderWallet.Base58CheckSerialize(wallet.PrivateKeyType)
+	receiver := PrivateKeyToPaymentAddress(privateKey, -1)
+
+	coinServer := newMockCoinServer()
+	err = coinServer.addCoins(senderWallet.KeySet.PaymentAddress, 5)
+	if err != nil {
+		panic(err)
+	}
+	txHashesBySN := make(map[string]string)
+	txDetails := make(map[string]jsonresult.TransactionDetail)
+	numTxDetailCalls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var req struct {
+			Method string
+			Params []json.RawMessage
+		}
+		if err = json.Unmarshal(body, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var result interface{}
+		switch req.Method {
+		case "gettransactionbyserialnumber":
+			var params struct {
+				SerialNumbers []string
+			}
+			_ = json.Unmarshal(req.Params[0], &params)
+			res := make(map[string]string)
+			for _, sn := range params.SerialNumbers {
+				if txHash, ok := txHashesBySN[sn]; ok {
+					res[sn] = txHash
+				}
+			}
+			result = res
+		case "gettransactionbyhash":
+			numTxDetailCalls++
+			var txHash string
+			_ = json.Unmarshal(req.Params[0], &txHash)
+			txDetail, ok := txDetails[txHash]
+			if !ok {
+				http.Error(w, "tx not found", http.StatusBadRequest)
+				return
+			}
+			result = txDetail
+		default:
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+			coinServer.ServeHTTP(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"Result": result})
+	}))
+	defer ts.Close()
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+	client.SetCoinStore(NewMemCoinStore())
+
+	utxoList, _, err := client.GetUnspentOutputCoins(privateKey, common.PRVIDStr, 0)
+	if err != nil {
+		panic(err)
+	}
+	// three transactions with fees 100, 200, 300 at heights 100, 200, 300, each spending its own coin.
+	for i := 1; i <= 3; i++ {
+		txParam := NewTxParam(privateKey, []string{receiver}, []uint64{1}, uint64(100*i), nil, nil, nil)
+		txParam.InputCoins = utxoList[i-1 : i]
+		tx, err := client.createTxVer2(txParam, false)
+		assert.Equal(t, nil, err, fmt.Errorf("createTxVer2 error: %v", err))
+		txDetails[tx.Hash().String()] = newTestTxDetail(tx, uint64(100*i))
+		for _, inputCoin := range tx.GetProof().GetInputCoins() {
+			sn := base58.Base58Check{}.Encode(inputCoin.GetKeyImage().ToBytesS(), common.ZeroByte)
+			txHashesBySN[sn] = tx.Hash().String()
+			coinServer.markSpent(sn)
+		}
+	}
+
+	testCases := []struct {
+		fromHeight, toHeight, expected uint64
+	}{
+		{0, 0, 600},
+		{150, 0, 500},
+		{150, 250, 200},
+		{301, 0, 0},
+	}
+	for _, tc := range testCases {
+		numTxDetailCalls = 0
+		totalFee, err := client.GetTotalFeesPaid(privateKey, tc.fromHeight, tc.toHeight)
+		assert.Equal(t, nil, err, fmt.Errorf("GetTotalFeesPaid error: %v", err))
+		assert.Equal(t, tc.expected, totalFee, fmt.Errorf("heights [%v, %v]", tc.fromHeight, tc.toHeight))
+
+		// each transaction is retrieved once, together with its block height.
+		assert.Equal(t, len(txDetails), numTxDetailCalls)
+	}
+}
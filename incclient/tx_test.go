@@ -1,15 +1,26 @@
 package incclient
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"math"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/incognitochain/go-incognito-sdk-v2/coin"
 	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"github.com/incognitochain/go-incognito-sdk-v2/common/base58"
+	"github.com/incognitochain/go-incognito-sdk-v2/key"
 	"github.com/incognitochain/go-incognito-sdk-v2/metadata"
+	"github.com/incognitochain/go-incognito-sdk-v2/privacy"
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler/rpc"
+	"github.com/incognitochain/go-incognito-sdk-v2/transaction/tx_ver2"
+	"github.com/incognitochain/go-incognito-sdk-v2/wallet"
+	"github.com/stretchr/testify/assert"
 )
 
 // getBalanceByVersion is for testing purposes ONLY.
@@ -185,6 +196,63 @@ func TestIncClient_CreateAndSendTransaction(t *testing.T) {
 
 }
 
+// newEncodedTestTxV2 returns a base58-encoded transaction v2 paying to a random address.
+// If estimationOnly is true, the range proof of the transaction is skipped.
+func newEncodedTestTxV2(estimationOnly bool) ([]byte, error) {
+	w, err := wallet.NewMasterKeyFromSeed(common.RandBytes(32))
+	if err != nil {
+		return nil, err
+	}
+	paymentInfo := &key.PaymentInfo{PaymentAddress: w.KeySet.PaymentAddress, Amount: 1000}
+	outCoin, err := coin.NewCoinFromPaymentInfo(coin.NewTransferCoinParams(paymentInfo))
+	if err != nil {
+		return nil, err
+	}
+
+	tx := new(tx_ver2.Tx)
+	tx.Version = 2
+	tx.Type = common.TxNormalType
+	tx.Proof, err = privacy.ProveV2([]coin.PlainCoin{}, []*coin.CoinV2{outCoin}, nil, false, []*key.PaymentInfo{paymentInfo}, estimationOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	txBytes, err := json.Marshal(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(base58.Base58Check{}.Encode(txBytes, common.ZeroByte)), nil
+}
+
+func TestIncClient_SendRawTx_EstimationOnly(t *testing.T) {
+	numCalls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		numCalls++
+		_, _ = w.Write([]byte(`{"Result": {"TxID": ""}}`))
+	}))
+	defer ts.Close()
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+
+	encodedTx, err := newEncodedTestTxV2(true)
+	if err != nil {
+		panic(err)
+	}
+	err = client.SendRawTx(encodedTx)
+	assert.NotEqual(t, nil, err)
+	err = client.SendRawTokenTx(encodedTx)
+	assert.NotEqual(t, nil, err)
+	assert.Equal(t, 0, numCalls)
+
+	encodedTx, err = newEncodedTestTxV2(false)
+	if err != nil {
+		panic(err)
+	}
+	err = client.SendRawTx(encodedTx)
+	assert.Equal(t, nil, err, fmt.Errorf("SendRawTx error: %v", err))
+	assert.Equal(t, 1, numCalls)
+}
+
 func TestIncClient_GetTx(t *testing.T) {
 	ic, err := NewTestNetClient()
 	if err != nil {
@@ -496,3 +564,715 @@ func compareInputCoins(inCoinList1 []coin.PlainCoin, inCoinList2 []coin.PlainCoi
 
 	return true, nil
 }
+
+func TestIncClient_CreateRawTransaction_PinnedInputCoins(t *testing.T) {
+	shardID := byte(common.RandInt() % common.MaxShardNumber)
+	senderWallet, err := wallet.GenRandomWalletForShardID(shardID)
+	if err != nil {
+		panic(err)
+	}
+	otherWallet, err := wallet.GenRandomWalletForShardID(shardID)
+	if err != nil {
+		panic(err)
+	}
+	privateKey := senderWallet.Base58CheckSerialize(wallet.PrivateKeyType)
+	receiver := otherWallet.Base58CheckSerialize(wallet.PaymentAddressType)
+
+	server := newMockCoinServer()
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	for _, w := range []*wallet.KeyWallet{senderWallet, otherWallet} {
+		err = server.addCoins(w.KeySet.PaymentAddress, 10)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+	client.SetCoinStore(NewMemCoinStore())
+
+	utxoList, _, err := client.GetUnspentOutputCoins(privateKey, common.PRVIDStr, 0)
+	if err != nil {
+		panic(err)
+	}
+	assert.Equal(t, 10, len(utxoList))
+
+	for i := 0; i < numTests; i++ {
+		numPinned := 1 + common.RandInt()%3
+		pinnedCoins := make([]coin.PlainCoin, 0)
+		pinnedKeyImages := make(map[string]bool)
+		for len(pinnedCoins) < numPinned {
+			pinnedCoin := utxoList[common.RandInt()%len(utxoList)]
+			if pinnedKeyImages[pinnedCoin.GetKeyImage().String()] {
+				continue
+			}
+			pinnedCoins = append(pinnedCoins, pinnedCoin)
+			pinnedKeyImages[pinnedCoin.GetKeyImage().String()] = true
+		}
+
+		txParam := NewTxParam(privateKey, []string{receiver}, []uint64{1}, 100, nil, nil, nil)
+		txParam.InputCoins = pinnedCoins
+		tx, err := client.createTxVer2(txParam, false)
+		assert.Equal(t, nil, err, fmt.Errorf("createTxVer2 error: %v", err))
+
+		inputCoins := tx.GetProof().GetInputCoins()
+		assert.Equal(t, len(pinnedCoins), len(inputCoins))
+		for _, inputCoin := range inputCoins {
+			assert.Equal(t, true, pinnedKeyImages[inputCoin.GetKeyImage().String()], "input coin was not pinned")
+		}
+	}
+
+	// the pinned coins do not cover the amount.
+	txParam := NewTxParam(privateKey, []string{receiver}, []uint64{1e9}, 100, nil, nil, nil)
+	txParam.InputCoins = utxoList[:1]
+	_, err = client.createTxVer2(txParam, false)
+	assert.NotEqual(t, nil, err)
+
+	// the pinned coin does not belong to the sender.
+	otherUTXOs, _, err := client.GetUnspentOutputCoins(otherWallet.Base58CheckSerialize(wallet.PrivateKeyType), common.PRVIDStr, 0)
+	if err != nil {
+		panic(err)
+	}
+	txParam = NewTxParam(privateKey, []string{receiver}, []uint64{1}, 100, nil, nil, nil)
+	txParam.InputCoins = otherUTXOs[:1]
+	_, err = client.createTxVer2(txParam, false)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestIncClient_CreateRawTransaction_ExcludeCoins(t *testing.T) {
+	shardID := byte(common.RandInt() % common.MaxShardNumber)
+	senderWallet, err := wallet.GenRandomWalletForShardID(shardID)
+	if err != nil {
+		panic(err)
+	}
+	privateKey := senderWallet.Base58CheckSerialize(wallet.PrivateKeyType)
+	receiver := PrivateKeyToPaymentAddress(privateKey, -1)
+
+	server := newMockCoinServer()
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	err = server.addCoins(senderWallet.KeySet.PaymentAddress, 10)
+	if err != nil {
+		panic(err)
+	}
+
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+	client.SetCoinStore(NewMemCoinStore())
+
+	utxoList, _, err := client.GetUnspentOutputCoins(privateKey, common.PRVIDStr, 0)
+	if err != nil {
+		panic(err)
+	}
+
+	for i := 0; i < numTests; i++ {
+		excludedCoins := make([]string, 0)
+		excludedKeyImages := make(map[string]bool)
+		for _, utxo := range utxoList {
+			if common.RandInt()%2 == 0 {
+				excludedCoins = append(excludedCoins, base58.Base58Check{}.Encode(utxo.GetPublicKey().ToBytesS(), common.ZeroByte))
+				excludedKeyImages[utxo.GetKeyImage().String()] = true
+			}
+		}
+		if len(excludedCoins) == len(utxoList) {
+			continue
+		}
+
+		txParam := NewTxParam(privateKey, []string{receiver}, []uint64{1}, 100, nil, nil, nil)
+		txParam.ExcludeCoins = excludedCoins
+		tx, err := client.createTxVer2(txParam, false)
+		assert.Equal(t, nil, err, fmt.Errorf("createTxVer2 error: %v", err))
+		for _, inputCoin := range tx.GetProof().GetInputCoins() {
+			assert.Equal(t, false, excludedKeyImages[inputCoin.GetKeyImage().String()], "an excluded coin was spent")
+		}
+	}
+
+	// excluding all coins but one makes the balance insufficient.
+	excludedCoins := make([]string, 0)
+	for _, utxo := range utxoList[1:] {
+		excludedCoins = append(excludedCoins, base58.Base58Check{}.Encode(utxo.GetPublicKey().ToBytesS(), common.ZeroByte))
+	}
+	txParam := NewTxParam(privateKey, []string{receiver}, []uint64{2000}, 100, nil, nil, nil)
+	txParam.ExcludeCoins = excludedCoins
+	_, err = client.createTxVer2(txParam, false)
+	assert.NotEqual(t, nil, err)
+	assert.Contains(t, err.Error(), "excluded")
+}
+
+func TestIncClient_CreateRawTransaction_AvoidPendingCoins(t *testing.T) {
+	shardID := byte(common.RandInt() % common.MaxShardNumber)
+	senderWallet, err := wallet.GenRandomWalletForShardID(shardID)
+	if err != nil {
+		panic(err)
+	}
+	privateKey := senderWallet.Base58CheckSerialize(wallet.PrivateKeyType)
+	receiver := PrivateKeyToPaymentAddress(privateKey, -1)
+
+	server := newMockCoinServer()
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	err = server.addCoins(senderWallet.KeySet.PaymentAddress, 10)
+	if err != nil {
+		panic(err)
+	}
+
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+	client.SetCoinStore(NewMemCoinStore())
+
+	for i := 0; i < numTests; i++ {
+		server.mtx.Lock()
+		server.pendingTxs = make(map[string]string)
+		server.mtx.Unlock()
+
+		// the first transaction stays in the mempool.
+		encodedTx, txHash, err := client.CreateRawTransactionVer2(NewTxParam(privateKey, []string{receiver}, []uint64{1000}, 100, nil, nil, nil))
+		assert.Equal(t, nil, err, fmt.Errorf("CreateRawTransactionVer2 error: %v", err))
+		server.mtx.Lock()
+		server.pendingTxs[txHash] = string(encodedTx)
+		server.mtx.Unlock()
+
+		pendingTxs, err := client.GetPendingTransactions()
+		assert.Equal(t, nil, err, fmt.Errorf("GetPendingTransactions error: %v", err))
+		assert.Equal(t, 1, len(pendingTxs))
+		pendingKeyImages := make(map[string]bool)
+		for _, inputCoin := range pendingTxs[txHash].GetProof().GetInputCoins() {
+			pendingKeyImages[inputCoin.GetKeyImage().String()] = true
+		}
+		assert.NotEqual(t, 0, len(pendingKeyImages))
+
+		// the second transaction must not spend the coins of the pending one.
+		txParam := NewTxParam(privateKey, []string{receiver}, []uint64{1000}, 100, nil, nil, nil)
+		txParam.AvoidPendingCoins = true
+		tx, err := client.createTxVer2(txParam, false)
+		assert.Equal(t, nil, err, fmt.Errorf("createTxVer2 error: %v", err))
+		for _, inputCoin := range tx.GetProof().GetInputCoins() {
+			assert.Equal(t, false, pendingKeyImages[inputCoin.GetKeyImage().String()], "a pending coin was spent")
+		}
+
+		// re-using txParam after the second transaction is pending as well: its coins must also be avoided.
+		encodedTx, err = json.Marshal(tx)
+		assert.Equal(t, nil, err, fmt.Errorf("json.Marshal error: %v", err))
+		server.mtx.Lock()
+		server.pendingTxs[tx.Hash().String()] = base58.Base58Check{}.Encode(encodedTx, common.ZeroByte)
+		server.mtx.Unlock()
+		for _, inputCoin := range tx.GetProof().GetInputCoins() {
+			pendingKeyImages[inputCoin.GetKeyImage().String()] = true
+		}
+		tx, err = client.createTxVer2(txParam, false)
+		assert.Equal(t, nil, err, fmt.Errorf("createTxVer2 error: %v", err))
+		for _, inputCoin := range tx.GetProof().GetInputCoins() {
+			assert.Equal(t, false, pendingKeyImages[inputCoin.GetKeyImage().String()], "a pending coin was spent")
+		}
+	}
+}
+
+func TestIncClient_PredictTxHash(t *testing.T) {
+	shardID := byte(common.RandInt() % common.MaxShardNumber)
+	senderWallet, err := wallet.GenRandomWalletForShardID(shardID)
+	if err != nil {
+		panic(err)
+	}
+	privateKey := senderWallet.Base58CheckSerialize(wallet.PrivateKeyType)
+	receiver := PrivateKeyToPaymentAddress(privateKey, -1)
+
+	server := newMockCoinServer()
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	err = server.addCoins(senderWallet.KeySet.PaymentAddress, 10)
+	if err != nil {
+		panic(err)
+	}
+
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+	client.SetCoinStore(NewMemCoinStore())
+
+	for i := 0; i < numTests; i++ {
+		txParam := NewTxParam(privateKey, []string{receiver}, []uint64{1 + common.RandUint64()%1000}, 100, nil, nil, nil)
+		encodedTx, txHash, err := client.CreateRawTransaction(txParam, 2)
+		assert.Equal(t, nil, err, fmt.Errorf("CreateRawTransaction error: %v", err))
+
+		predictedHash, err := client.PredictTxHash(encodedTx)
+		assert.Equal(t, nil, err, fmt.Errorf("PredictTxHash error: %v", err))
+		assert.Equal(t, txHash, predictedHash)
+	}
+
+	_, err = client.PredictTxHash([]byte("abc"))
+	assert.NotEqual(t, nil, err)
+	_, err = client.PredictTxHash([]byte(base58.Base58Check{}.Encode([]byte("{}"), common.ZeroByte)))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestIncClient_EstimatePRVReserveForFees(t *testing.T) {
+	shardID := byte(common.RandInt() % common.MaxShardNumber)
+	senderWallet, err := wallet.GenRandomWalletForShardID(shardID)
+	if err != nil {
+		panic(err)
+	}
+	privateKey := senderWallet.Base58CheckSerialize(wallet.PrivateKeyType)
+	requestedShardID := byte(255)
+	feePerKB := uint64(1000)
+	numCalls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string
+			Params []interface{}
+		}
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil || req.Method != "estimatefeewithestimator" || len(req.Params) < 2 {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+		addr, _ := req.Params[1].(string)
+		requestedShardID, err = GetShardIDFromPaymentAddress(addr)
+		if err != nil {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+		numCalls++
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"Result": rpc.EstimateFeeResult{EstimateFeeCoinPerKb: feePerKB},
+		})
+	}))
+	defer ts.Close()
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+
+	testCases := []struct {
+		numTxs, avgInputs int
+		expectedSize      uint64
+	}{
+		{1, 1, 3},
+		{5, 1, 3},
+		{3, 2, 4},
+		{10, 10, 8},
+		{2, MaxInputSize, 19},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expectedSize, estimateTxV2Size(tc.avgInputs))
+
+		fee, err := client.EstimatePRVReserveForFees(privateKey, tc.numTxs, tc.avgInputs)
+		assert.Equal(t, nil, err, fmt.Errorf("EstimatePRVReserveForFees error: %v", err))
+		assert.Equal(t, uint64(tc.numTxs)*tc.expectedSize*feePerKB, fee)
+		assert.Equal(t, shardID, requestedShardID)
+	}
+
+	// no transactions, no fee
+	numCalls = 0
+	fee, err := client.EstimatePRVReserveForFees(privateKey, 0, 1)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, uint64(0), fee)
+	assert.Equal(t, 0, numCalls)
+
+	// invalid inputs
+	_, err = client.EstimatePRVReserveForFees(privateKey, -1, 1)
+	assert.NotEqual(t, nil, err)
+	_, err = client.EstimatePRVReserveForFees(privateKey, 1, 0)
+	assert.NotEqual(t, nil, err)
+	_, err = client.EstimatePRVReserveForFees(privateKey, 1, MaxInputSize+1)
+	assert.NotEqual(t, nil, err)
+	_, err = client.EstimatePRVReserveForFees("invalid", 1, 1)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestIncClient_EstimateTxFeeByShape(t *testing.T) {
+	shardID := byte(common.RandInt() % common.MaxShardNumber)
+	senderWallet, err := wallet.GenRandomWalletForShardID(shardID)
+	if err != nil {
+		panic(err)
+	}
+	privateKey := senderWallet.Base58CheckSerialize(wallet.PrivateKeyType)
+	requestedShardID := byte(255)
+	feePerKB := uint64(1000)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string
+			Params []interface{}
+		}
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil || req.Method != "estimatefeewithestimator" || len(req.Params) < 2 {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+		addr, _ := req.Params[1].(string)
+		requestedShardID, err = GetShardIDFromPaymentAddress(addr)
+		if err != nil {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"Result": rpc.EstimateFeeResult{EstimateFeeCoinPerKb: feePerKB},
+		})
+	}))
+	defer ts.Close()
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+
+	// sizes measured from real transactions: a PRV transaction with 1 input and 2 outputs has 2776 bytes; a token
+	// transaction (with its fee transaction) with 1 input and 2 outputs has 5682 bytes.
+	testCases := []struct {
+		numInputs, numOutputs int
+		hasTokenData          bool
+		expectedSize          uint64
+	}{
+		{1, 2, false, 3},
+		{1, 2, true, 6},
+		{2, 2, false, 4},
+		{8, 16, true, 16},
+		{MaxInputSize, MaxOutputSize, false, 29},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expectedSize, estimateTxV2SizeByShape(tc.numInputs, tc.numOutputs, tc.hasTokenData))
+
+		fee, err := client.EstimateTxFeeByShape(privateKey, tc.numInputs, tc.numOutputs, tc.hasTokenData)
+		assert.Equal(t, nil, err, fmt.Errorf("EstimateTxFeeByShape error: %v", err))
+		assert.Equal(t, tc.expectedSize*feePerKB, fee)
+		assert.Equal(t, shardID, requestedShardID)
+	}
+
+	// the fee grows with the number of inputs and outputs, and is higher for token transactions.
+	for i := 0; i < numTests; i++ {
+		numInputs := 1 + common.RandInt()%MaxInputSize
+		numOutputs := 1 + common.RandInt()%MaxOutputSize
+		fee, err := client.EstimateTxFeeByShape(privateKey, numInputs, numOutputs, false)
+		assert.Equal(t, nil, err)
+		moreInputsFee, err := client.EstimateTxFeeByShape(privateKey, numInputs+10, numOutputs, false)
+		assert.Equal(t, nil, err)
+		moreOutputsFee, err := client.EstimateTxFeeByShape(privateKey, numInputs, numOutputs+10, false)
+		assert.Equal(t, nil, err)
+		tokenFee, err := client.EstimateTxFeeByShape(privateKey, numInputs, numOutputs, true)
+		assert.Equal(t, nil, err)
+
+		assert.Greater(t, moreInputsFee, fee)
+		assert.Greater(t, moreOutputsFee, fee)
+		assert.Greater(t, tokenFee, fee)
+	}
+
+	// invalid inputs
+	_, err = client.EstimateTxFeeByShape(privateKey, 0, 1, false)
+	assert.NotEqual(t, nil, err)
+	_, err = client.EstimateTxFeeByShape(privateKey, 1, 0, false)
+	assert.NotEqual(t, nil, err)
+	_, err = client.EstimateTxFeeByShape(privateKey, 200, 2, false)
+	assert.NotEqual(t, nil, err)
+	_, err = client.EstimateTxFeeByShape("invalid", 1, 2, false)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestEstimateTxV2Size(t *testing.T) {
+	shardID := byte(common.RandInt() % common.MaxShardNumber)
+	senderWallet, err := wallet.GenRandomWalletForShardID(shardID)
+	if err != nil {
+		panic(err)
+	}
+	privateKey := senderWallet.Base58CheckSerialize(wallet.PrivateKeyType)
+	receiver := PrivateKeyToPaymentAddress(privateKey, -1)
+
+	server := newMockCoinServer()
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	err = server.addCoins(senderWallet.KeySet.PaymentAddress, MaxInputSize)
+	if err != nil {
+		panic(err)
+	}
+
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+	client.SetCoinStore(NewMemCoinStore())
+	utxoList, _, err := client.GetUnspentOutputCoins(privateKey, common.PRVIDStr, 0)
+	if err != nil {
+		panic(err)
+	}
+
+	// the estimated size must not be smaller than the actual one.
+	for _, numInputs := range []int{1, 2, 5, 10, MaxInputSize} {
+		txParam := NewTxParam(privateKey, []string{receiver}, []uint64{1}, 100, nil, nil, nil)
+		txParam.InputCoins = utxoList[:numInputs]
+		tx, err := client.createTxVer2(txParam, false)
+		assert.Equal(t, nil, err, fmt.Errorf("createTxVer2 error: %v", err))
+		assert.LessOrEqual(t, tx.GetTxActualSize(), estimateTxV2Size(numInputs), fmt.Errorf("size of a tx with %v inputs", numInputs))
+	}
+}
+
+func TestIncClient_CreateRawTransaction_OutputCoinVersion(t *testing.T) {
+	shardID := byte(common.RandInt() % common.MaxShardNumber)
+	senderWallet, err := wallet.GenRandomWalletForShardID(shardID)
+	if err != nil {
+		panic(err)
+	}
+	privateKey := senderWallet.Base58CheckSerialize(wallet.PrivateKeyType)
+	receiver := PrivateKeyToPaymentAddress(privateKey, -1)
+
+	server := newMockCoinServer()
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	err = server.addCoins(senderWallet.KeySet.PaymentAddress, 10)
+	if err != nil {
+		panic(err)
+	}
+
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+	client.SetCoinStore(NewMemCoinStore())
+
+	// output coins v2 are explicitly requested.
+	txParam := NewTxParam(privateKey, []string{receiver}, []uint64{1000}, 100, nil, nil, nil)
+	txParam.OutputCoinVersion = 2
+	encodedTx, _, err := client.CreateRawTransaction(txParam, -1)
+	assert.Equal(t, nil, err, fmt.Errorf("CreateRawTransaction error: %v", err))
+	rawTxBytes, _, err := base58.Base58Check{}.Decode(string(encodedTx))
+	assert.Equal(t, nil, err, fmt.Errorf("Decode error: %v", err))
+	tx := new(tx_ver2.Tx)
+	err = json.Unmarshal(rawTxBytes, tx)
+	assert.Equal(t, nil, err, fmt.Errorf("Unmarshal error: %v", err))
+	assert.NotEqual(t, 0, len(tx.GetProof().GetOutputCoins()))
+	for _, outCoin := range tx.GetProof().GetOutputCoins() {
+		assert.Equal(t, uint8(2), outCoin.GetVersion())
+	}
+
+	// the version of the transaction must match the version of the output coins.
+	_, _, err = client.CreateRawTransaction(txParam, 1)
+	assert.NotEqual(t, nil, err)
+
+	// output coins v1 are not permitted on a privacy-v2 network.
+	txParam.OutputCoinVersion = 1
+	_, _, err = client.CreateRawTransaction(txParam, -1)
+	assert.NotEqual(t, nil, err)
+	assert.Contains(t, err.Error(), "not permitted")
+	txTokenParam := NewTxParam(privateKey, []string{}, []uint64{}, 100,
+		NewTxTokenParam(common.HashH(common.RandBytes(32)).String(), 1, []string{receiver}, []uint64{1000}, false, 0, nil), nil, nil)
+	txTokenParam.OutputCoinVersion = 1
+	_, _, err = client.CreateRawTokenTransaction(txTokenParam, -1)
+	assert.NotEqual(t, nil, err)
+	assert.Contains(t, err.Error(), "not permitted")
+
+	// a privacy-v1 network permits them, but not from a transaction v2.
+	v1Client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 1)
+	_, _, err = v1Client.CreateRawTransaction(txParam, 2)
+	assert.NotEqual(t, nil, err)
+	assert.Contains(t, err.Error(), "cannot be created by a transaction v2")
+
+	txParam.OutputCoinVersion = 3
+	_, _, err = client.CreateRawTransaction(txParam, -1)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestIncClient_EstimateTxFee(t *testing.T) {
+	// the sender's public key ends with a byte that is not a shard ID.
+	var senderWallet *wallet.KeyWallet
+	for senderWallet == nil {
+		w, err := wallet.NewMasterKeyFromSeed(common.RandBytes(32))
+		if err != nil {
+			panic(err)
+		}
+		if pk := w.KeySet.PaymentAddress.Pk; int(pk[len(pk)-1]) >= common.MaxShardNumber {
+			senderWallet = w
+		}
+	}
+	privateKey := senderWallet.Base58CheckSerialize(wallet.PrivateKeyType)
+	pk := senderWallet.KeySet.PaymentAddress.Pk
+	shardID := common.GetShardIDFromLastByte(pk[len(pk)-1])
+
+	const feePerKB = 10
+	var queriedAddresses []string
+	coinServer := newMockCoinServer()
+	err := coinServer.addCoins(senderWallet.KeySet.PaymentAddress, 5)
+	if err != nil {
+		panic(err)
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var req struct {
+			Method string
+			Params []json.RawMessage
+		}
+		_ = json.Unmarshal(body, &req)
+		if req.Method != "estimatefeewithestimator" {
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+			coinServer.ServeHTTP(w, r)
+			return
+		}
+
+		var addr string
+		if len(req.Params) < 2 || json.Unmarshal(req.Params[1], &addr) != nil {
+			http.Error(w, "invalid params", http.StatusBadRequest)
+			return
+		}
+		queriedAddresses = append(queriedAddresses, addr)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"Result": rpc.EstimateFeeResult{EstimateFeeCoinPerKb: feePerKB}})
+	}))
+	defer ts.Close()
+
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+	client.SetCoinStore(NewMemCoinStore())
+
+	receiver := PrivateKeyToPaymentAddress(privateKey, -1)
+	param := NewTxParam(privateKey, []string{receiver}, []uint64{1000}, 100, nil, nil, nil)
+	fee, err := client.EstimateTxFee(param)
+	assert.Equal(t, nil, err, fmt.Errorf("EstimateTxFee error: %v", err))
+	assert.NotEqual(t, uint64(0), fee)
+	assert.Equal(t, uint64(0), fee%feePerKB)
+
+	// the node only learns the sender's shard, not the last byte of its public key.
+	if !assert.Equal(t, 1, len(queriedAddresses)) {
+		return
+	}
+	queriedWallet, err := wallet.Base58CheckDeserialize(queriedAddresses[0])
+	assert.Equal(t, nil, err)
+	queriedPk := queriedWallet.KeySet.PaymentAddress.Pk
+	assert.Equal(t, shardID, queriedPk[len(queriedPk)-1])
+}
+
+func TestIncClient_PreviewTransaction(t *testing.T) {
+	shardID := byte(common.RandInt() % common.MaxShardNumber)
+	senderWallet, err := wallet.GenRandomWalletForShardID(shardID)
+	if err != nil {
+		panic(err)
+	}
+	privateKey := senderWallet.Base58CheckSerialize(wallet.PrivateKeyType)
+	receiver := PrivateKeyToPaymentAddress(privateKey, -1)
+
+	server := newMockCoinServer()
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	err = server.addCoins(senderWallet.KeySet.PaymentAddress, 5)
+	if err != nil {
+		panic(err)
+	}
+
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+	client.SetCoinStore(NewMemCoinStore())
+
+	param := NewTxParam(privateKey, []string{receiver}, []uint64{1000}, 100, nil, nil, nil)
+	previewTx, err := client.PreviewTransaction(param)
+	assert.Equal(t, nil, err, fmt.Errorf("PreviewTransaction error: %v", err))
+	assert.Equal(t, true, previewTx.IsEstimationOnly())
+
+	realTx, err := client.createTxVer2(param, false)
+	assert.Equal(t, nil, err, fmt.Errorf("createTxVer2 error: %v", err))
+	assert.Equal(t, false, realTx.IsEstimationOnly())
+	assert.Equal(t, len(realTx.GetProof().GetInputCoins()), len(previewTx.GetProof().GetInputCoins()))
+	assert.Equal(t, len(realTx.GetProof().GetOutputCoins()), len(previewTx.GetProof().GetOutputCoins()))
+	assert.LessOrEqual(t, previewTx.GetTxActualSize(), realTx.GetTxActualSize())
+	assert.LessOrEqual(t, realTx.GetTxActualSize()-previewTx.GetTxActualSize(), uint64(1))
+
+	// the preview cannot be submitted.
+	txBytes, err := json.Marshal(previewTx)
+	if err != nil {
+		panic(err)
+	}
+	err = client.SendRawTx([]byte(base58.Base58Check{}.Encode(txBytes, common.ZeroByte)))
+	assert.NotEqual(t, nil, err)
+
+	// token transactions are not supported.
+	tokenParam := NewTxTokenParam(common.PRVIDStr, 1, []string{receiver}, []uint64{1000}, false, 0, nil)
+	param = NewTxParam(privateKey, nil, nil, 100, tokenParam, nil, nil)
+	_, err = client.PreviewTransaction(param)
+	assert.NotEqual(t, nil, err)
+	_, err = client.EstimateTxFee(param)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestIncClient_CreateRawTransaction_KeepDuplicateReceivers(t *testing.T) {
+	shardID := byte(common.RandInt() % common.MaxShardNumber)
+	senderWallet, err := wallet.GenRandomWalletForShardID(shardID)
+	if err != nil {
+		panic(err)
+	}
+	privateKey := senderWallet.Base58CheckSerialize(wallet.PrivateKeyType)
+	receiverWallets := make([]*wallet.KeyWallet, 2)
+	receivers := make([]string, 2)
+	for i := range receiverWallets {
+		receiverWallets[i], err = wallet.GenRandomWalletForShardID(byte(common.RandInt() % common.MaxShardNumber))
+		if err != nil {
+			panic(err)
+		}
+		receivers[i] = receiverWallets[i].Base58CheckSerialize(wallet.PaymentAddressType)
+	}
+
+	server := newMockCoinServer()
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	err = server.addCoins(senderWallet.KeySet.PaymentAddress, 10)
+	if err != nil {
+		panic(err)
+	}
+
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+	client.SetCoinStore(NewMemCoinStore())
+
+	receiverList := []string{receivers[0], receivers[1], receivers[0]}
+	amountList := []uint64{10, 20, 30}
+
+	// by default, the two payments to the first receiver are coalesced.
+	txParam := NewTxParam(privateKey, receiverList, amountList, 100, nil, nil, nil)
+	tx, err := client.createTxVer2(txParam, false)
+	assert.Equal(t, nil, err, fmt.Errorf("createTxVer2 error: %v", err))
+	assert.Equal(t, []uint64{40}, receivedAmounts(t, tx, &receiverWallets[0].KeySet))
+	assert.Equal(t, []uint64{20}, receivedAmounts(t, tx, &receiverWallets[1].KeySet))
+	assert.Equal(t, 3, len(tx.GetProof().GetOutputCoins())) // 2 receivers + change
+
+	// opting out keeps one output per payment.
+	txParam = NewTxParam(privateKey, receiverList, amountList, 100, nil, nil, nil)
+	txParam.KeepDuplicateReceivers = true
+	tx, err = client.createTxVer2(txParam, false)
+	assert.Equal(t, nil, err, fmt.Errorf("createTxVer2 error: %v", err))
+	assert.Equal(t, []uint64{10, 30}, receivedAmounts(t, tx, &receiverWallets[0].KeySet))
+	assert.Equal(t, []uint64{20}, receivedAmounts(t, tx, &receiverWallets[1].KeySet))
+	assert.Equal(t, 4, len(tx.GetProof().GetOutputCoins()))
+
+	// the caller's lists are left untouched.
+	assert.Equal(t, []string{receivers[0], receivers[1], receivers[0]}, receiverList)
+	assert.Equal(t, []uint64{10, 20, 30}, amountList)
+}
+
+func TestIncClient_CreateRawTransactionWithPaymentProofs(t *testing.T) {
+	shardID := byte(common.RandInt() % common.MaxShardNumber)
+	senderWallet, err := wallet.GenRandomWalletForShardID(shardID)
+	if err != nil {
+		panic(err)
+	}
+	privateKey := senderWallet.Base58CheckSerialize(wallet.PrivateKeyType)
+	receivers := make([]string, 2)
+	for i := range receivers {
+		receiverWallet, err := wallet.GenRandomWalletForShardID(byte(common.RandInt() % common.MaxShardNumber))
+		if err != nil {
+			panic(err)
+		}
+		receivers[i] = receiverWallet.Base58CheckSerialize(wallet.PaymentAddressType)
+	}
+
+	server := newMockCoinServer()
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	err = server.addCoins(senderWallet.KeySet.PaymentAddress, 10)
+	if err != nil {
+		panic(err)
+	}
+
+	client := newIncClient(rpc.NewRPCServer(ts.URL), nil, nil, 2)
+	client.SetCoinStore(NewMemCoinStore())
+
+	// the two payments to the first receiver are coalesced, so its proof is for their sum.
+	txParam := NewTxParam(privateKey, []string{receivers[0], receivers[1], receivers[0]}, []uint64{10, 20, 30}, 100, nil, nil, nil)
+	encodedTx, txHash, proofs, err := client.CreateRawTransactionWithPaymentProofs(txParam)
+	assert.Equal(t, nil, err, fmt.Errorf("CreateRawTransactionWithPaymentProofs error: %v", err))
+	assert.Equal(t, txHash, decodeTestTx(t, encodedTx).Hash().String())
+	assert.Equal(t, 2, len(proofs))
+	for i, expectedAmount := range []uint64{40, 20} {
+		isValid, err := tx_ver2.VerifyPaymentProof(proofs[receivers[i]], txHash, receivers[i], expectedAmount)
+		assert.Equal(t, nil, err, fmt.Errorf("VerifyPaymentProof error: %v", err))
+		assert.Equal(t, true, isValid)
+
+		isValid, err = tx_ver2.VerifyPaymentProof(proofs[receivers[i]], txHash, receivers[1-i], expectedAmount)
+		assert.Equal(t, nil, err, fmt.Errorf("VerifyPaymentProof error: %v", err))
+		assert.Equal(t, false, isValid)
+	}
+
+	// token transactions and output coins v1 are not supported.
+	tokenParam := NewTxTokenParam(common.PRVIDStr, 1, []string{receivers[0]}, []uint64{10}, false, 0, nil)
+	_, _, _, err = client.CreateRawTransactionWithPaymentProofs(NewTxParam(privateKey, nil, nil, 100, tokenParam, nil, nil))
+	assert.NotEqual(t, nil, err)
+	txParam = NewTxParam(privateKey, []string{receivers[0]}, []uint64{10}, 100, nil, nil, nil)
+	txParam.OutputCoinVersion = 1
+	_, _, _, err = client.CreateRawTransactionWithPaymentProofs(txParam)
+	assert.NotEqual(t, nil, err)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/incognitochain/go-incognito-sdk-v2/transaction"
 	"github.com/incognitochain/go-incognito-sdk-v2/transaction/tx_generic"
+	"github.com/incognitochain/go-incognito-sdk-v2/transaction/tx_ver2"
 	"math/big"
 	"sort"
 	"time"
@@ -12,6 +13,7 @@ import (
 	"github.com/incognitochain/go-incognito-sdk-v2/common"
 	"github.com/incognitochain/go-incognito-sdk-v2/common/base58"
 	"github.com/incognitochain/go-incognito-sdk-v2/crypto"
+	"github.com/incognitochain/go-incognito-sdk-v2/internal/safemath"
 	"github.com/incognitochain/go-incognito-sdk-v2/key"
 	"github.com/incognitochain/go-incognito-sdk-v2/metadata"
 	"github.com/incognitochain/go-incognito-sdk-v2/privacy"
@@ -153,7 +155,11 @@ func createPaymentInfos(addrList []string, amountList []uint64) ([]*key.PaymentI
 func chooseBestCoinsByAmount(coinList []coin.PlainCoin, requiredAmount uint64) ([]coin.PlainCoin, []uint64, error) {
 	totalInputAmount := uint64(0)
 	for _, inputCoin := range coinList {
-		totalInputAmount += inputCoin.GetValue()
+		var err error
+		totalInputAmount, err = safemath.AddUint64(totalInputAmount, inputCoin.GetValue())
+		if err != nil {
+			return nil, nil, fmt.Errorf("total unspent amount overflows: %v", err)
+		}
 	}
 
 	if totalInputAmount < requiredAmount {
@@ -203,6 +209,203 @@ func chooseBestCoinsByAmount(coinList []coin.PlainCoin, requiredAmount uint64) (
 	return coinsToSpend, chosenIndexList, nil
 }
 
+// withPinnedInputCoins resolves the input coins pinned in txParam for the given tokenIDStr (see TxParam.InputCoins)
+// into a coinParams, and returns a copy of txParam carrying it in kArgs, the same way CreateRawTransactionWithInputCoins
+// provides its input coins. The pinned coins must be unspent output coins of the sender of the given version, and
+// cover totalAmount. txParam is returned as is if it has no pinned coins, or if its kArgs already provide the input coins.
+func (client *IncClient) withPinnedInputCoins(txParam *TxParam, tokenIDStr string, totalAmount uint64, version int8) (*TxParam, error) {
+	pinnedCoins := txParam.pinnedInputCoins(tokenIDStr)
+	if len(pinnedCoins) == 0 {
+		return txParam, nil
+	}
+	inCoinKey := tokenInCoinKey
+	if tokenIDStr == common.PRVIDStr {
+		inCoinKey = prvInCoinKey
+	}
+	if _, ok := txParam.kArgs[inCoinKey]; ok {
+		return txParam, nil
+	}
+
+	utxoList, idxList, err := client.GetUnspentOutputCoins(txParam.senderPrivateKey, tokenIDStr, 0)
+	if err != nil {
+		return nil, err
+	}
+	coinV1List, coinV2List, idxV2List, err := divideCoins(utxoList, idxList, true)
+	if err != nil {
+		return nil, fmt.Errorf("cannot divide coin: %v", err)
+	}
+
+	var cp coinParams
+	if version == 1 {
+		cp.coinList, _, err = choosePinnedCoins(coinV1List, pinnedCoins, totalAmount)
+	} else {
+		var chosenIdxList []uint64
+		cp.coinList, chosenIdxList, err = choosePinnedCoins(coinV2List, pinnedCoins, totalAmount)
+		for _, idx := range chosenIdxList {
+			cp.idxList = append(cp.idxList, idxV2List[idx])
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	kArgs := make(map[string]interface{})
+	for k, v := range txParam.kArgs {
+		kArgs[k] = v
+	}
+	kArgs[inCoinKey] = cp
+	tmpParam := *txParam
+	tmpParam.kArgs = kArgs
+
+	return &tmpParam, nil
+}
+
+// choosePinnedCoins returns the coins in coinList corresponding to the given pinned coins, together with their positions
+// in coinList. Coins are matched by their public keys (v2) or commitments (v1). It returns an error if a pinned coin is
+// not found in coinList (i.e, it is not an unspent coin of the sender), or if the pinned coins do not cover the required amount.
+func choosePinnedCoins(coinList []coin.PlainCoin, pinnedCoins []coin.PlainCoin, requiredAmount uint64) ([]coin.PlainCoin, []uint64, error) {
+	if len(pinnedCoins) > MaxInputSize {
+		return nil, nil, fmt.Errorf("support at most %v input coins, got %v", MaxInputSize, len(pinnedCoins))
+	}
+
+	positions := make(map[string]int)
+	for i, c := range coinList {
+		positions[inputCoinID(c)] = i
+	}
+
+	coinsToSpend := make([]coin.PlainCoin, 0)
+	chosenIndexList := make([]uint64, 0)
+	chosen := make(map[string]bool)
+	totalChosenAmount := uint64(0)
+	for _, pinnedCoin := range pinnedCoins {
+		if pinnedCoin == nil {
+			return nil, nil, fmt.Errorf("pinned input coin is nil")
+		}
+		id := inputCoinID(pinnedCoin)
+		if chosen[id] {
+			return nil, nil, fmt.Errorf("input coin %v is pinned more than once", id)
+		}
+		pos, ok := positions[id]
+		if !ok {
+			return nil, nil, fmt.Errorf("input coin %v is not an unspent coin of the sender", id)
+		}
+
+		var err error
+		totalChosenAmount, err = safemath.AddUint64(totalChosenAmount, coinList[pos].GetValue())
+		if err != nil {
+			return nil, nil, fmt.Errorf("total pinned amount overflows: %v", err)
+		}
+		coinsToSpend = append(coinsToSpend, coinList[pos])
+		chosenIndexList = append(chosenIndexList, uint64(pos))
+		chosen[id] = true
+	}
+
+	if totalChosenAmount < requiredAmount {
+		return nil, nil, fmt.Errorf("total pinned amount (%v) is less than the required amount (%v)", totalChosenAmount, requiredAmount)
+	}
+
+	return coinsToSpend, chosenIndexList, nil
+}
+
+// excludeCoins removes the coins whose IDs (see inputCoinID) are in the excluded list from coinList, together with their
+// corresponding entries in idxList (if not nil). It also returns the total value of the removed coins.
+func excludeCoins(coinList []coin.PlainCoin, idxList []uint64, excluded []string) ([]coin.PlainCoin, []uint64, uint64, error) {
+	if len(excluded) == 0 {
+		return coinList, idxList, 0, nil
+	}
+	excludedMap := make(map[string]bool)
+	for _, id := range excluded {
+		excludedMap[id] = true
+	}
+
+	resCoins := make([]coin.PlainCoin, 0)
+	var resIndices []uint64
+	if idxList != nil {
+		resIndices = make([]uint64, 0)
+	}
+	excludedAmount := uint64(0)
+	var err error
+	for i, c := range coinList {
+		if excludedMap[inputCoinID(c)] {
+			excludedAmount, err = safemath.AddUint64(excludedAmount, c.GetValue())
+			if err != nil {
+				return nil, nil, 0, fmt.Errorf("excluded amount overflows: %v", err)
+			}
+			continue
+		}
+		resCoins = append(resCoins, c)
+		if idxList != nil {
+			resIndices = append(resIndices, idxList[i])
+		}
+	}
+
+	return resCoins, resIndices, excludedAmount, nil
+}
+
+// chooseCoinsWithExclusion chooses the best coins to spend from coinList after removing the excluded coins, using selector
+// if not nil. It returns the chosen coins and their positions in the filtered idxList. If the remaining coins are
+// insufficient, the returned error notes the excluded value.
+func chooseCoinsWithExclusion(coinList []coin.PlainCoin, idxList []uint64, excluded []string, requiredAmount uint64, selector CoinSelector) ([]coin.PlainCoin, []uint64, []uint64, error) {
+	coinList, idxList, excludedAmount, err := excludeCoins(coinList, idxList, excluded)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var coinsToSpend []coin.PlainCoin
+	var chosenIdxList []uint64
+	if selector != nil {
+		coinsToSpend, chosenIdxList, err = chooseCoinsBySelector(coinList, requiredAmount, selector)
+	} else {
+		coinsToSpend, chosenIdxList, err = chooseBestCoinsByAmount(coinList, requiredAmount)
+	}
+	if err != nil {
+		if excludedAmount > 0 {
+			return nil, nil, nil, fmt.Errorf("%v (excluded coins hold %v)", err, excludedAmount)
+		}
+		return nil, nil, nil, err
+	}
+
+	return coinsToSpend, chosenIdxList, idxList, nil
+}
+
+// getExcludedCoins returns the IDs (see inputCoinID) of the coins in coinList that must not be chosen by the automatic
+// coin selection: the ExcludeCoins of txParam, plus the coins being spent by pending transactions if AvoidPendingCoins is set.
+// The mempool is queried on every call, so that a TxParam re-used for several transactions sees the latest pending ones.
+func (client *IncClient) getExcludedCoins(txParam *TxParam, coinList []coin.PlainCoin) ([]string, error) {
+	if !txParam.AvoidPendingCoins {
+		return txParam.ExcludeCoins, nil
+	}
+
+	pendingKeyImages, err := client.getPendingKeyImages()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get pending key images: %v", err)
+	}
+
+	res := append([]string{}, txParam.ExcludeCoins...)
+	for _, c := range coinList {
+		if c.GetKeyImage() == nil {
+			continue
+		}
+		keyImageStr := base58.Base58Check{}.Encode(c.GetKeyImage().ToBytesS(), common.ZeroByte)
+		if pendingKeyImages[keyImageStr] {
+			res = append(res, inputCoinID(c))
+		}
+	}
+
+	return res, nil
+}
+
+// inputCoinID returns a string identifying an output coin: its public key for a v2 coin, or its commitment for a v1
+// coin (all v1 coins of an account share the same public key).
+func inputCoinID(c coin.PlainCoin) string {
+	if c.GetVersion() == 2 && c.GetPublicKey() != nil {
+		return base58.Base58Check{}.Encode(c.GetPublicKey().ToBytesS(), common.ZeroByte)
+	}
+	if c.GetCommitment() != nil {
+		return base58.Base58Check{}.Encode(c.GetCommitment().ToBytesS(), common.ZeroByte)
+	}
+	return ""
+}
+
 // divideCoins divides the list of coins w.r.t their version and sort them by values if needed.
 func divideCoins(coinList []coin.PlainCoin, idxList []*big.Int, needSorted bool) ([]coin.PlainCoin, []coin.PlainCoin, []uint64, error) {
 	if idxList != nil {
@@ -297,6 +500,31 @@ func getVersionFromInputCoins(inputCoins []coin.PlainCoin) (uint8, error) {
 	return version, nil
 }
 
+// checkEstimationOnlyTx returns an error if the given base58-encoded transaction was created for estimation only
+// (i.e, without range proofs). Transactions that cannot be decoded are left to be rejected by the remote node.
+func checkEstimationOnlyTx(encodedTx []byte) error {
+	rawTxBytes, _, err := base58.Base58Check{}.Decode(string(encodedTx))
+	if err != nil {
+		return nil
+	}
+	txChoice, err := transaction.DeserializeTransactionJSON(rawTxBytes)
+	if err != nil {
+		return nil
+	}
+
+	isEstimationOnly := false
+	if txChoice.Version2 != nil {
+		isEstimationOnly = txChoice.Version2.IsEstimationOnly()
+	} else if txChoice.TokenVersion2 != nil {
+		isEstimationOnly = txChoice.TokenVersion2.IsEstimationOnly()
+	}
+	if isEstimationOnly {
+		return fmt.Errorf("transaction was created for estimation only and cannot be submitted")
+	}
+
+	return nil
+}
+
 func (client *IncClient) getRandomCommitmentV1(inputCoins []coin.PlainCoin, tokenID string) (map[string]interface{}, error) {
 	if len(inputCoins) == 0 {
 		return nil, fmt.Errorf("no input coin to retrieve random commitments, tokenID: %v", tokenID)
@@ -349,8 +577,17 @@ func (client *IncClient) getRandomCommitmentV2(shardID byte, tokenID string, len
 	if lenDecoy == 0 {
 		return nil, fmt.Errorf("no input coin to retrieve random commitments")
 	}
+	if result, ok := client.decoyCache.take(shardID, tokenID, lenDecoy); ok {
+		return result, nil
+	}
 
-	responseInBytes, err := client.rpcServer.RandomCommitmentsAndPublicKeys(shardID, tokenID, lenDecoy)
+	return fetchRandomCommitmentV2(client.rpcServer, shardID, tokenID, lenDecoy)
+}
+
+// fetchRandomCommitmentV2 retrieves lenDecoy random coins of the given shard and token from server, in the format of
+// getRandomCommitmentV2.
+func fetchRandomCommitmentV2(server *rpc.RPCServer, shardID byte, tokenID string, lenDecoy int) (map[string]interface{}, error) {
+	responseInBytes, err := server.RandomCommitmentsAndPublicKeys(shardID, tokenID, lenDecoy)
 	if err != nil {
 		return nil, err
 	}
@@ -472,7 +709,7 @@ func (client *IncClient) initParams(privateKey string, tokenIDStr string, totalA
 
 		//fmt.Printf("Getting random commitments for %v.\n", tokenIDStr)
 		//Retrieve commitments and indices
-		kvArgs, err = client.getRandomCommitmentV2(shardID, tokenIDStr, len(coinsToSpend)*(privacy.RingSize-1))
+		kvArgs, err = client.getRandomCommitmentV2(shardID, tokenIDStr, tx_ver2.RequiredDecoyCount(len(coinsToSpend), privacy.RingSize))
 		if err != nil {
 			return nil, nil, err
 		}
@@ -494,6 +731,11 @@ func (client *IncClient) initParamsV1(txParam *TxParam, tokenIDStr string, total
 		return nil, nil, err
 	}
 
+	txParam, err = client.withPinnedInputCoins(txParam, tokenIDStr, totalAmount, 1)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	//Create sender private key from string
 	privateKey := txParam.senderPrivateKey
 
@@ -532,7 +774,11 @@ func (client *IncClient) initParamsV1(txParam *TxParam, tokenIDStr string, total
 		}
 
 		//Choose best coins for creating transactions
-		coinsToSpend, _, err = chooseBestCoinsByAmount(coinV1List, totalAmount)
+		excludedCoins, err := client.getExcludedCoins(txParam, coinV1List)
+		if err != nil {
+			return nil, nil, err
+		}
+		coinsToSpend, _, _, err = chooseCoinsWithExclusion(coinV1List, nil, excludedCoins, totalAmount, txParam.CoinSelector)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -551,6 +797,28 @@ func (client *IncClient) initParamsV1(txParam *TxParam, tokenIDStr string, total
 
 }
 
+// resolveTxVersion returns the version of the transaction to create given the requested version (-1 for either
+// version) and the OutputCoinVersion of txParam, which must be permitted by the privacy version of the network.
+func (client *IncClient) resolveTxVersion(txParam *TxParam, version int8) (int8, error) {
+	switch txParam.OutputCoinVersion {
+	case 0:
+		return version, nil
+	case 1:
+		if client.version != 1 {
+			return 0, fmt.Errorf("output coins v1 are not permitted on a network with privacy version %v", client.version)
+		}
+	case 2:
+	default:
+		return 0, fmt.Errorf("output coin version %v is invalid", txParam.OutputCoinVersion)
+	}
+
+	outputVersion := int8(txParam.OutputCoinVersion)
+	if version != -1 && version != outputVersion {
+		return 0, fmt.Errorf("output coins v%v cannot be created by a transaction v%v", outputVersion, version)
+	}
+	return outputVersion, nil
+}
+
 // initParamsV2 queries and chooses coins to spend + init random params v2.
 func (client *IncClient) initParamsV2(txParam *TxParam, tokenIDStr string, totalAmount uint64) ([]coin.PlainCoin, map[string]interface{}, error) {
 	_, err := new(common.Hash).NewHashFromStr(tokenIDStr)
@@ -568,6 +836,11 @@ func (client *IncClient) initParamsV2(txParam *TxParam, tokenIDStr string, total
 	lastByteSender := senderWallet.KeySet.PaymentAddress.Pk[len(senderWallet.KeySet.PaymentAddress.Pk)-1]
 	shardID := common.GetShardIDFromLastByte(lastByteSender)
 
+	txParam, err = client.withPinnedInputCoins(txParam, tokenIDStr, totalAmount, 2)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	var coinsToSpend []coin.PlainCoin
 	var myIndices []uint64
 	if txParam.kArgs != nil { // in case we use provided input coins to init the transaction.
@@ -603,8 +876,12 @@ func (client *IncClient) initParamsV2(txParam *TxParam, tokenIDStr string, total
 			return nil, nil, fmt.Errorf("cannot divide coin: %v", err)
 		}
 
+		excludedCoins, err := client.getExcludedCoins(txParam, coinV2List)
+		if err != nil {
+			return nil, nil, err
+		}
 		var chosenIdxList []uint64
-		coinsToSpend, chosenIdxList, err = chooseBestCoinsByAmount(coinV2List, totalAmount)
+		coinsToSpend, chosenIdxList, idxV2List, err = chooseCoinsWithExclusion(coinV2List, idxV2List, excludedCoins, totalAmount, txParam.CoinSelector)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -615,9 +892,18 @@ func (client *IncClient) initParamsV2(txParam *TxParam, tokenIDStr string, total
 		}
 	}
 
+	// the decoys are drawn from the sender's shard, so must be the input coins.
+	inputShardID, err := tx_ver2.ValidateRingShard(coinsToSpend, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if inputShardID != shardID {
+		return nil, nil, fmt.Errorf("input coins belong to shard %v, sender belongs to shard %v", inputShardID, shardID)
+	}
+
 	//Retrieve commitments and indices
 	var kvArgs = make(map[string]interface{})
-	kvArgs, err = client.getRandomCommitmentV2(shardID, tokenIDStr, len(coinsToSpend)*(privacy.RingSize-1))
+	kvArgs, err = client.getRandomCommitmentV2(shardID, tokenIDStr, tx_ver2.RequiredDecoyCount(len(coinsToSpend), privacy.RingSize))
 	if err != nil {
 		return nil, nil, err
 	}
@@ -627,24 +913,12 @@ func (client *IncClient) initParamsV2(txParam *TxParam, tokenIDStr string, total
 
 }
 
-// GetTokenFee returns the token fee per kb.
+// GetTokenFee returns the token fee per kb. The fee rate is cached by the client (see SetFeeRateCacheTTL).
 func (client *IncClient) GetTokenFee(shardID byte, tokenIDStr string) (uint64, error) {
 	if tokenIDStr == common.PRVIDStr {
 		return DefaultPRVFee, nil
 	}
-	responseInBytes, err := client.rpcServer.EstimateFeeWithEstimator(-1, shardID, 10, tokenIDStr)
-	if err != nil {
-		return 0, err
-	}
-
-	var feeEstimateResult rpc.EstimateFeeResult
-	err = rpchandler.ParseResponse(responseInBytes, &feeEstimateResult)
-	if err != nil {
-		return 0, err
-	}
-
-	return feeEstimateResult.EstimateFeeCoinPerKb, nil
-
+	return client.getFeePerKB(shardID, tokenIDStr)
 }
 
 // GetTxDetail retrieves the transaction detail from its hash.
@@ -664,18 +938,61 @@ func (client *IncClient) GetTxDetail(txHash string) (*jsonresult.TransactionDeta
 }
 
 // GetTx retrieves the transaction detail and parses it to a transaction object.
+//
+// If the inclusion verification is enabled (see SetInclusionVerification), the transaction is checked against the
+// transaction root of its block; ErrInclusionProofFailed is returned if the check fails.
 func (client *IncClient) GetTx(txHash string) (metadata.Transaction, error) {
+	tx, _, err := client.getTxWithDetail(txHash)
+	return tx, err
+}
+
+// getTxWithDetail is the same as GetTx, except that it also returns the transaction detail (e.g, its block height).
+func (client *IncClient) getTxWithDetail(txHash string) (metadata.Transaction, *jsonresult.TransactionDetail, error) {
 	txDetail, err := client.GetTxDetail(txHash)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	tx, err := jsonresult.ParseTxDetail(*txDetail)
+	if err != nil {
+		return nil, nil, err
+	}
+	if client.verifyInclusion {
+		err = client.verifyFetchedTx(txHash, tx, txDetail)
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
-	return jsonresult.ParseTxDetail(*txDetail)
+	return tx, txDetail, nil
 }
 
 // GetTxs retrieves transactions and parses them to transaction objects given their hashes.
 // By default, it will not re-calculate the hashes of the transactions. Set `hashReCheck` to true to re-check the hashes.
+//
+// If the inclusion verification is enabled (see SetInclusionVerification), the hashes are always re-checked and each
+// transaction is checked against the transaction root of its block; ErrInclusionProofFailed is returned if a check fails.
 func (client *IncClient) GetTxs(txHashList []string, hashReCheck ...bool) (map[string]metadata.Transaction, error) {
+	doubleCheck := false
+	if len(hashReCheck) > 0 {
+		doubleCheck = hashReCheck[0]
+	}
+	res, err := client.getTxs(txHashList, doubleCheck)
+	if err != nil {
+		return nil, err
+	}
+	if client.verifyInclusion {
+		err = client.verifyFetchedTxs(res)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// getTxs retrieves and parses the transactions of the given hashes, re-checking their hashes if doubleCheck is set.
+func (client *IncClient) getTxs(txHashList []string, doubleCheck bool) (map[string]metadata.Transaction, error) {
 	responseInBytes, err := client.rpcServer.GetEncodedTransactionsByHashes(txHashList)
 	if err != nil {
 		return nil, err
@@ -688,10 +1005,6 @@ func (client *IncClient) GetTxs(txHashList []string, hashReCheck ...bool) (map[s
 	}
 
 	res := make(map[string]metadata.Transaction)
-	doubleCheck := false
-	if len(hashReCheck) > 0 {
-		doubleCheck = hashReCheck[0]
-	}
 	for txHash, encodedTx := range mapRes {
 		txBytes, _, err := base58.Base58Check{}.Decode(encodedTx)
 		if err != nil {
@@ -716,6 +1029,51 @@ func (client *IncClient) GetTxs(txHashList []string, hashReCheck ...bool) (map[s
 	return res, nil
 }
 
+// GetPendingTransactions retrieves the transactions currently in the mempool, as a mapping from transaction hashes to
+// the parsed transactions.
+func (client *IncClient) GetPendingTransactions() (map[string]metadata.Transaction, error) {
+	txHashes, err := client.GetRawMemPool()
+	if err != nil {
+		return nil, err
+	}
+	if len(txHashes) == 0 {
+		return make(map[string]metadata.Transaction), nil
+	}
+
+	// pending transactions are not in a block yet, hence are never subject to the inclusion verification.
+	return client.getTxs(txHashes, false)
+}
+
+// getPendingKeyImages returns the set of base58-encoded key images spent by the transactions in the mempool.
+func (client *IncClient) getPendingKeyImages() (map[string]bool, error) {
+	pendingTxs, err := client.GetPendingTransactions()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]bool)
+	for txHash, tx := range pendingTxs {
+		keyImages, err := getListKeyImagesFromTx(tx, common.PRVIDStr)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get key images of tx %v: %v", txHash, err)
+		}
+		if tx.GetType() == common.TxCustomTokenPrivacyType || tx.GetType() == common.TxTokenConversionType {
+			tokenKeyImages, err := getListKeyImagesFromTx(tx, tx.GetTokenID().String())
+			if err != nil {
+				return nil, fmt.Errorf("cannot get key images of tx %v: %v", txHash, err)
+			}
+			for keyImage := range tokenKeyImages {
+				keyImages[keyImage] = keyImage
+			}
+		}
+		for keyImage := range keyImages {
+			res[keyImage] = true
+		}
+	}
+
+	return res, nil
+}
+
 // GetTransactionHashesByReceiver retrieves the list of all transactions received by a payment address.
 func (client *IncClient) GetTransactionHashesByReceiver(paymentAddress string) ([]string, error) {
 	responseInBytes, err := client.rpcServer.GetTxHashByReceiver(paymentAddress)
@@ -6,6 +6,7 @@ import (
 	"github.com/incognitochain/go-incognito-sdk-v2/coin"
 	"github.com/incognitochain/go-incognito-sdk-v2/common"
 	"github.com/incognitochain/go-incognito-sdk-v2/common/base58"
+	"github.com/incognitochain/go-incognito-sdk-v2/internal/safemath"
 	"github.com/incognitochain/go-incognito-sdk-v2/key"
 	"github.com/incognitochain/go-incognito-sdk-v2/metadata"
 	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler"
@@ -17,13 +18,22 @@ import (
 )
 
 // CreateRawTokenTransaction creates a token transaction with the provided version.
-// Version = -1 indicates that whichever version is accepted.
+// Version = -1 indicates that whichever version is accepted. If txParam.OutputCoinVersion is set, the version of the
+// transaction must be -1 or match it.
 //
-// It returns the base58-encoded transaction, the transaction's hash, and an error (if any).
+// It returns the base58-encoded transaction, the transaction's hash, and an error (if any). If the fee is too high
+// compared to the sent amount, a warning is logged or a *FeeExceedsAmountError is returned, depending on SetFeeGuard.
 func (client *IncClient) CreateRawTokenTransaction(txParam *TxParam, version int8) ([]byte, string, error) {
 	if txParam.txTokenParam == nil {
 		return nil, "", fmt.Errorf("TxTokenParam must not be nil")
 	}
+	if err := client.checkFeeGuard(txParam); err != nil {
+		return nil, "", err
+	}
+	version, err := client.resolveTxVersion(txParam, version)
+	if err != nil {
+		return nil, "", err
+	}
 	if version == -1 { //Try either one of the version, if possible
 		encodedTx, txHash, err := client.CreateRawTokenTransactionVer1(txParam)
 		if err != nil {
@@ -72,7 +82,10 @@ func (client *IncClient) CreateRawTokenTransactionVer1(txParam *TxParam) ([]byte
 	//Calculate the total transacted amount
 	totalAmount := uint64(0)
 	for _, amount := range txParam.txTokenParam.amountList {
-		totalAmount += amount
+		totalAmount, err = safemath.AddUint64(totalAmount, amount)
+		if err != nil {
+			return nil, "", fmt.Errorf("total amount overflows: %v", err)
+		}
 	}
 
 	//Create list of payment infos
@@ -81,7 +94,7 @@ func (client *IncClient) CreateRawTokenTransactionVer1(txParam *TxParam) ([]byte
 		uniqueReceiver := key.PaymentInfo{PaymentAddress: senderWallet.KeySet.PaymentAddress, Amount: totalAmount, Message: []byte{}}
 		tokenReceivers = []*key.PaymentInfo{&uniqueReceiver}
 	} else {
-		tokenReceivers, err = createPaymentInfos(txParam.txTokenParam.receiverList, txParam.txTokenParam.amountList)
+		tokenReceivers, err = createPaymentInfos(txParam.tokenReceivers())
 		if err != nil {
 			return nil, "", err
 		}
@@ -107,7 +120,10 @@ func (client *IncClient) CreateRawTokenTransactionVer1(txParam *TxParam) ([]byte
 
 	totalPRVAmount := prvFee
 	for _, amount := range txParam.amountList {
-		totalPRVAmount += amount
+		totalPRVAmount, err = safemath.AddUint64(totalPRVAmount, amount)
+		if err != nil {
+			return nil, "", fmt.Errorf("total PRV amount overflows: %v", err)
+		}
 	}
 
 	tokenFee := uint64(0)
@@ -128,7 +144,10 @@ func (client *IncClient) CreateRawTokenTransactionVer1(txParam *TxParam) ([]byte
 				return nil, "", err
 			}
 		}
-		totalAmount += tokenFee
+		totalAmount, err = safemath.AddUint64(totalAmount, tokenFee)
+		if err != nil {
+			return nil, "", fmt.Errorf("total amount overflows: %v", err)
+		}
 	}
 	//End init PRV fee param
 
@@ -149,7 +168,7 @@ func (client *IncClient) CreateRawTokenTransactionVer1(txParam *TxParam) ([]byte
 
 	prvReceivers := make([]*key.PaymentInfo, 0)
 	if len(txParam.receiverList) > 0 {
-		prvReceivers, err = createPaymentInfos(txParam.receiverList, txParam.amountList)
+		prvReceivers, err = createPaymentInfos(txParam.prvReceivers())
 		if err != nil {
 			return nil, "", err
 		}
@@ -201,11 +220,14 @@ func (client *IncClient) CreateRawTokenTransactionVer2(txParam *TxParam) ([]byte
 	//Calculate the total transacted amount
 	totalAmount := uint64(0)
 	for _, amount := range txParam.txTokenParam.amountList {
-		totalAmount += amount
+		totalAmount, err = safemath.AddUint64(totalAmount, amount)
+		if err != nil {
+			return nil, "", fmt.Errorf("total amount overflows: %v", err)
+		}
 	}
 
 	//Create list of payment infos
-	tokenReceivers, err := createPaymentInfos(txParam.txTokenParam.receiverList, txParam.txTokenParam.amountList)
+	tokenReceivers, err := createPaymentInfos(txParam.tokenReceivers())
 	if err != nil {
 		return nil, "", err
 	}
@@ -217,7 +239,10 @@ func (client *IncClient) CreateRawTokenTransactionVer2(txParam *TxParam) ([]byte
 
 	totalPRVAmount := prvFee
 	for _, amount := range txParam.amountList {
-		totalPRVAmount += amount
+		totalPRVAmount, err = safemath.AddUint64(totalPRVAmount, amount)
+		if err != nil {
+			return nil, "", fmt.Errorf("total PRV amount overflows: %v", err)
+		}
 	}
 
 	//Init PRV fee param
@@ -242,7 +267,7 @@ func (client *IncClient) CreateRawTokenTransactionVer2(txParam *TxParam) ([]byte
 
 	prvReceivers := make([]*key.PaymentInfo, 0)
 	if len(txParam.receiverList) > 0 {
-		prvReceivers, err = createPaymentInfos(txParam.receiverList, txParam.amountList)
+		prvReceivers, err = createPaymentInfos(txParam.prvReceivers())
 		if err != nil {
 			return nil, "", err
 		}
@@ -439,7 +464,13 @@ func (client *IncClient) CreateRawTokenTransactionWithInputCoins(txParam *TxPara
 }
 
 // SendRawTokenTx sends submits a raw token transaction to the Incognito blockchain.
+//
+// Estimation-only transactions are rejected before being sent.
 func (client *IncClient) SendRawTokenTx(encodedTx []byte) error {
+	if err := checkEstimationOnlyTx(encodedTx); err != nil {
+		return err
+	}
+
 	responseInBytes, err := client.rpcServer.SendRawTokenTx(string(encodedTx))
 	if err != nil {
 		return nil
@@ -0,0 +1,55 @@
+// Package safemath provides overflow-checked arithmetic on uint64 amounts.
+package safemath
+
+import (
+	"errors"
+	"math/bits"
+)
+
+var (
+	// ErrOverflow is returned when the result of an operation exceeds math.MaxUint64.
+	ErrOverflow = errors.New("uint64 overflow")
+
+	// ErrUnderflow is returned when the result of an operation is negative.
+	ErrUnderflow = errors.New("uint64 underflow")
+)
+
+// AddUint64 returns a + b, or ErrOverflow if the sum exceeds math.MaxUint64.
+func AddUint64(a, b uint64) (uint64, error) {
+	sum, carry := bits.Add64(a, b, 0)
+	if carry != 0 {
+		return 0, ErrOverflow
+	}
+	return sum, nil
+}
+
+// SubUint64 returns a - b, or ErrUnderflow if b > a.
+func SubUint64(a, b uint64) (uint64, error) {
+	diff, borrow := bits.Sub64(a, b, 0)
+	if borrow != 0 {
+		return 0, ErrUnderflow
+	}
+	return diff, nil
+}
+
+// MulUint64 returns a * b, or ErrOverflow if the product exceeds math.MaxUint64.
+func MulUint64(a, b uint64) (uint64, error) {
+	hi, lo := bits.Mul64(a, b)
+	if hi != 0 {
+		return 0, ErrOverflow
+	}
+	return lo, nil
+}
+
+// SumUint64 returns the sum of the given values, or ErrOverflow if it exceeds math.MaxUint64.
+func SumUint64(values ...uint64) (uint64, error) {
+	sum := uint64(0)
+	for _, v := range values {
+		var err error
+		sum, err = AddUint64(sum, v)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return sum, nil
+}
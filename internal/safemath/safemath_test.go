@@ -0,0 +1,103 @@
+package safemath
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testCase struct {
+	a, b     uint64
+	expected uint64
+	err      error
+}
+
+func TestAddUint64(t *testing.T) {
+	testCases := []testCase{
+		{0, 0, 0, nil},
+		{1, 2, 3, nil},
+		{0, math.MaxUint64, math.MaxUint64, nil},
+		{math.MaxUint64, 0, math.MaxUint64, nil},
+		{math.MaxUint64 - 1, 1, math.MaxUint64, nil},
+		{math.MaxUint64 / 2, math.MaxUint64/2 + 1, math.MaxUint64, nil},
+		{math.MaxUint64, 1, 0, ErrOverflow},
+		{1, math.MaxUint64, 0, ErrOverflow},
+		{math.MaxUint64/2 + 1, math.MaxUint64/2 + 1, 0, ErrOverflow},
+		{math.MaxUint64, math.MaxUint64, 0, ErrOverflow},
+	}
+
+	for _, tc := range testCases {
+		res, err := AddUint64(tc.a, tc.b)
+		assert.Equal(t, tc.err, err, fmt.Errorf("%v + %v", tc.a, tc.b))
+		assert.Equal(t, tc.expected, res, fmt.Errorf("%v + %v", tc.a, tc.b))
+	}
+}
+
+func TestSubUint64(t *testing.T) {
+	testCases := []testCase{
+		{0, 0, 0, nil},
+		{3, 2, 1, nil},
+		{5, 5, 0, nil},
+		{math.MaxUint64, 0, math.MaxUint64, nil},
+		{math.MaxUint64, math.MaxUint64, 0, nil},
+		{math.MaxUint64, 1, math.MaxUint64 - 1, nil},
+		{0, 1, 0, ErrUnderflow},
+		{1, 2, 0, ErrUnderflow},
+		{0, math.MaxUint64, 0, ErrUnderflow},
+		{math.MaxUint64 - 1, math.MaxUint64, 0, ErrUnderflow},
+	}
+
+	for _, tc := range testCases {
+		res, err := SubUint64(tc.a, tc.b)
+		assert.Equal(t, tc.err, err, fmt.Errorf("%v - %v", tc.a, tc.b))
+		assert.Equal(t, tc.expected, res, fmt.Errorf("%v - %v", tc.a, tc.b))
+	}
+}
+
+func TestMulUint64(t *testing.T) {
+	testCases := []testCase{
+		{0, 0, 0, nil},
+		{0, math.MaxUint64, 0, nil},
+		{math.MaxUint64, 0, 0, nil},
+		{1, math.MaxUint64, math.MaxUint64, nil},
+		{math.MaxUint64, 1, math.MaxUint64, nil},
+		{6, 7, 42, nil},
+		{math.MaxUint32, math.MaxUint32, math.MaxUint32 * math.MaxUint32, nil},
+		{1 << 32, 1<<32 - 1, 1<<64 - 1<<32, nil},
+		{math.MaxUint64 / 3, 3, math.MaxUint64, nil},
+		{1 << 32, 1 << 32, 0, ErrOverflow},
+		{math.MaxUint64/2 + 1, 2, 0, ErrOverflow},
+		{math.MaxUint64, 2, 0, ErrOverflow},
+		{math.MaxUint64, math.MaxUint64, 0, ErrOverflow},
+	}
+
+	for _, tc := range testCases {
+		res, err := MulUint64(tc.a, tc.b)
+		assert.Equal(t, tc.err, err, fmt.Errorf("%v * %v", tc.a, tc.b))
+		assert.Equal(t, tc.expected, res, fmt.Errorf("%v * %v", tc.a, tc.b))
+	}
+}
+
+func TestSumUint64(t *testing.T) {
+	testCases := []struct {
+		values   []uint64
+		expected uint64
+		err      error
+	}{
+		{nil, 0, nil},
+		{[]uint64{7}, 7, nil},
+		{[]uint64{1, 2, 3, 4}, 10, nil},
+		{[]uint64{math.MaxUint64 - 2, 1, 1}, math.MaxUint64, nil},
+		{[]uint64{math.MaxUint64 - 2, 1, 1, 1}, 0, ErrOverflow},
+		{[]uint64{math.MaxUint64, 0, 0}, math.MaxUint64, nil},
+		{[]uint64{math.MaxUint64 / 2, math.MaxUint64 / 2, 2}, 0, ErrOverflow},
+	}
+
+	for _, tc := range testCases {
+		res, err := SumUint64(tc.values...)
+		assert.Equal(t, tc.err, err, fmt.Errorf("sum of %v", tc.values))
+		assert.Equal(t, tc.expected, res, fmt.Errorf("sum of %v", tc.values))
+	}
+}
@@ -0,0 +1,99 @@
+package key
+
+import (
+	"sync"
+
+	"github.com/hashicorp/golang-lru/simplelru"
+	"github.com/incognitochain/go-incognito-sdk-v2/common"
+)
+
+// committeeKeyCacheSize is the maximum number of entries kept by each committee-key cache.
+const committeeKeyCacheSize = 2000
+
+// CacheStats holds the usage metrics of a cache.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+	Len    int
+}
+
+// HitRatio returns the ratio of lookups served from the cache, or 0 if the cache has not been looked up.
+func (s CacheStats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// stringCache is a bounded LRU cache mapping raw-byte keys to strings. It is safe for concurrent use.
+//
+// Raw keys are never stored: each key is replaced by its hash, so the memory used by an entry does not depend
+// on the size of the raw key.
+type stringCache struct {
+	mtx    *sync.Mutex
+	lru    *simplelru.LRU
+	hits   uint64
+	misses uint64
+}
+
+// newStringCache creates a stringCache with the given capacity.
+func newStringCache(size int) *stringCache {
+	l, err := simplelru.NewLRU(size, nil)
+	if err != nil {
+		panic(err)
+	}
+	return &stringCache{mtx: new(sync.Mutex), lru: l}
+}
+
+// get returns the value cached for the given key, and updates the hit/miss counters.
+func (c *stringCache) get(rawKey []byte) (string, bool) {
+	k := common.HashH(rawKey)
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	value, ok := c.lru.Get(k)
+	if !ok {
+		c.misses++
+		return "", false
+	}
+	c.hits++
+	return value.(string), true
+}
+
+// add caches a value for the given key, evicting the least recently used entry if the cache is full.
+func (c *stringCache) add(rawKey []byte, value string) {
+	k := common.HashH(rawKey)
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.lru.Add(k, value)
+}
+
+// stats returns the current metrics of the cache.
+func (c *stringCache) stats() CacheStats {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return CacheStats{Hits: c.hits, Misses: c.misses, Len: c.lru.Len()}
+}
+
+// purge removes all entries and resets the metrics of the cache.
+func (c *stringCache) purge() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.lru.Purge()
+	c.hits, c.misses = 0, 0
+}
+
+var getMiningKeyBase58Cache = newStringCache(committeeKeyCacheSize)
+var toBase58Cache = newStringCache(committeeKeyCacheSize)
+
+// GetMiningKeyBase58CacheStats returns the metrics of the cache used by CommitteePublicKey.GetMiningKeyBase58.
+func GetMiningKeyBase58CacheStats() CacheStats {
+	return getMiningKeyBase58Cache.stats()
+}
+
+// GetToBase58CacheStats returns the metrics of the cache used by CommitteePublicKey.ToBase58.
+func GetToBase58CacheStats() CacheStats {
+	return toBase58Cache.stats()
+}
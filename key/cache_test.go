@@ -0,0 +1,108 @@
+package key
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRandomCommitteeKey() CommitteePublicKey {
+	res, err := NewCommitteeKeyFromSeed(common.RandBytes(32), common.RandBytes(32))
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+func TestStringCache_Concurrency(t *testing.T) {
+	c := newStringCache(100)
+	numKeys := 150
+	keys := make([][]byte, numKeys)
+	for i := range keys {
+		keys[i] = common.RandBytes(1024)
+	}
+
+	var wg sync.WaitGroup
+	var mtx sync.Mutex
+	mismatches := make([]string, 0)
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				k := keys[common.RandInt()%numKeys]
+				expected := common.HashH(k).String()
+				if value, ok := c.get(k); ok {
+					if value != expected {
+						mtx.Lock()
+						mismatches = append(mismatches, fmt.Sprintf("expected %v, got %v", expected, value))
+						mtx.Unlock()
+					}
+					continue
+				}
+				c.add(k, expected)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 0, len(mismatches), mismatches)
+
+	stats := c.stats()
+	assert.Equal(t, uint64(16*1000), stats.Hits+stats.Misses)
+	assert.Equal(t, 100, stats.Len)
+}
+
+func TestCommitteePublicKey_CacheStats(t *testing.T) {
+	getMiningKeyBase58Cache.purge()
+	toBase58Cache.purge()
+	defer func() {
+		getMiningKeyBase58Cache.purge()
+		toBase58Cache.purge()
+	}()
+
+	numKeys := 20
+	numRounds := 5
+	committeeKeys := make([]CommitteePublicKey, numKeys)
+	expected := make([]string, numKeys)
+	for i := range committeeKeys {
+		committeeKeys[i] = newRandomCommitteeKey()
+		var err error
+		expected[i], err = committeeKeys[i].ToBase58()
+		if err != nil {
+			panic(err)
+		}
+	}
+	toBase58Cache.purge()
+
+	var wg sync.WaitGroup
+	for i := 0; i < numKeys; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for r := 0; r < numRounds; r++ {
+				encoded, err := committeeKeys[i].ToBase58()
+				assert.Equal(t, nil, err, fmt.Errorf("ToBase58 error: %v", err))
+				assert.Equal(t, expected[i], encoded)
+
+				miningKey := committeeKeys[i].GetMiningKeyBase58(common.BlsConsensus)
+				assert.NotEqual(t, "", miningKey)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// each key misses on its first lookup, and hits on the subsequent ones.
+	expectedRatio := float64(numRounds-1) / float64(numRounds)
+	for _, stats := range []CacheStats{GetToBase58CacheStats(), GetMiningKeyBase58CacheStats()} {
+		assert.Equal(t, uint64(numKeys), stats.Misses)
+		assert.Equal(t, uint64(numKeys*(numRounds-1)), stats.Hits)
+		assert.Equal(t, numKeys, stats.Len)
+		assert.Equal(t, expectedRatio, stats.HitRatio())
+	}
+
+	// the metrics of an unused cache.
+	assert.Equal(t, float64(0), CacheStats{}.HitRatio())
+}
@@ -9,7 +9,6 @@ import (
 	"github.com/incognitochain/go-incognito-sdk-v2/common"
 	"github.com/incognitochain/go-incognito-sdk-v2/common/base58"
 
-	lru "github.com/hashicorp/golang-lru"
 	"github.com/pkg/errors"
 )
 
@@ -112,17 +111,23 @@ func (pubKey *CommitteePublicKey) GetNormalKey() []byte {
 	return pubKey.IncPubKey
 }
 
-// GetMiningKey returns the mining key of a CommitteePublicKey given the consensus scheme.
+// GetMiningKey returns the JSON-encoded mining keys of a CommitteePublicKey for the given consensus scheme, together
+// with its bridge (common.BridgeConsensus) key. If schemeName is common.BridgeConsensus, only the bridge key is
+// returned.
+//
+// An error naming the missing scheme is returned if the key has no mining key for schemeName or for the bridge scheme.
 func (pubKey *CommitteePublicKey) GetMiningKey(schemeName string) ([]byte, error) {
 	allKey := map[string][]byte{}
 	var ok bool
 	allKey[schemeName], ok = pubKey.MiningPubKey[schemeName]
 	if !ok {
-		return nil, errors.New("this schemeName doesn't exist")
+		return nil, errors.Errorf("mining key for scheme %v doesn't exist", schemeName)
 	}
-	allKey[common.BridgeConsensus], ok = pubKey.MiningPubKey[common.BridgeConsensus]
-	if !ok {
-		return nil, errors.New("this lightweight schemeName doesn't exist")
+	if schemeName != common.BridgeConsensus {
+		allKey[common.BridgeConsensus], ok = pubKey.MiningPubKey[common.BridgeConsensus]
+		if !ok {
+			return nil, errors.Errorf("mining key for lightweight scheme %v doesn't exist", common.BridgeConsensus)
+		}
 	}
 	result, err := json.Marshal(allKey)
 	if err != nil {
@@ -134,17 +139,16 @@ func (pubKey *CommitteePublicKey) GetMiningKey(schemeName string) ([]byte, error
 // GetMiningKeyBase58 returns the base58-encoded mining key of a CommitteePublicKey given the consensus scheme.
 func (pubKey *CommitteePublicKey) GetMiningKeyBase58(schemeName string) string {
 	b, _ := pubKey.RawBytes()
-	key := schemeName + string(b)
-	value, exist := getMiningKeyBase58Cache.Get(key)
-	if exist {
-		return value.(string)
+	key := append([]byte(schemeName), b...)
+	if value, exist := getMiningKeyBase58Cache.get(key); exist {
+		return value
 	}
 	keyBytes, ok := pubKey.MiningPubKey[schemeName]
 	if !ok {
 		return ""
 	}
 	encodeData := base58.Base58Check{}.Encode(keyBytes, common.Base58Version)
-	getMiningKeyBase58Cache.Add(key, encodeData)
+	getMiningKeyBase58Cache.add(key, encodeData)
 	return encodeData
 }
 
@@ -164,17 +168,15 @@ func (pubKey *CommitteePublicKey) ToBase58() (string, error) {
 	}
 
 	b, _ := pubKey.RawBytes()
-	key := string(b)
-	value, exist := toBase58Cache.Get(key)
-	if exist {
-		return value.(string), nil
+	if value, exist := toBase58Cache.get(b); exist {
+		return value, nil
 	}
 	result, err := json.Marshal(pubKey)
 	if err != nil {
 		return "", err
 	}
 	encodeData := base58.Base58Check{}.Encode(result, common.Base58Version)
-	toBase58Cache.Add(key, encodeData)
+	toBase58Cache.add(b, encodeData)
 	return encodeData, nil
 }
 
@@ -213,5 +215,63 @@ func (pubKey *CommitteePublicKey) IsEqual(target CommitteePublicKey) bool {
 	return true
 }
 
-var getMiningKeyBase58Cache, _ = lru.New(2000)
-var toBase58Cache, _ = lru.New(2000)
+// committeeKeySorter sorts a list of CommitteePublicKeys by their raw bytes.
+type committeeKeySorter struct {
+	keys     []CommitteePublicKey
+	rawBytes [][]byte
+}
+
+func (s committeeKeySorter) Len() int { return len(s.keys) }
+
+func (s committeeKeySorter) Less(i, j int) bool {
+	return bytes.Compare(s.rawBytes[i], s.rawBytes[j]) < 0
+}
+
+func (s committeeKeySorter) Swap(i, j int) {
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+	s.rawBytes[i], s.rawBytes[j] = s.rawBytes[j], s.rawBytes[i]
+}
+
+// newCommitteeKeySorter returns a committeeKeySorter for keys, computing the raw bytes of each key once.
+func newCommitteeKeySorter(keys []CommitteePublicKey) committeeKeySorter {
+	rawBytes := make([][]byte, len(keys))
+	for i := range keys {
+		b, _ := keys[i].RawBytes()
+		rawBytes[i] = append([]byte{}, b...)
+	}
+	return committeeKeySorter{keys: keys, rawBytes: rawBytes}
+}
+
+// SortCommitteePublicKeys sorts a list of CommitteePublicKeys in place, in the ascending order of their RawBytes.
+// The sort is stable, so that sorting the same list always yields the same result.
+func SortCommitteePublicKeys(keys []CommitteePublicKey) {
+	sort.Stable(newCommitteeKeySorter(keys))
+}
+
+// DedupCommitteePublicKeys returns the keys sorted as in SortCommitteePublicKeys, with the keys equal (see IsEqual) to
+// a previous one removed. The input list is not modified.
+func DedupCommitteePublicKeys(keys []CommitteePublicKey) []CommitteePublicKey {
+	sorter := newCommitteeKeySorter(append([]CommitteePublicKey{}, keys...))
+	sort.Stable(sorter)
+
+	res := make([]CommitteePublicKey, 0, len(keys))
+	runStart := 0 // the index in res of the first key having the same raw bytes as the current key
+	for i, k := range sorter.keys {
+		if i == 0 || !bytes.Equal(sorter.rawBytes[i], sorter.rawBytes[i-1]) {
+			runStart = len(res)
+		}
+		// equal keys have the same raw bytes, so duplicates can only be found in the current run.
+		isDuplicate := false
+		for _, kept := range res[runStart:] {
+			if kept.IsEqual(k) && k.IsEqual(kept) {
+				isDuplicate = true
+				break
+			}
+		}
+		if !isDuplicate {
+			res = append(res, k)
+		}
+	}
+
+	return res
+}
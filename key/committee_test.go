@@ -0,0 +1,107 @@
+package key
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortCommitteePublicKeys(t *testing.T) {
+	numKeys := 50
+	keys := make([]CommitteePublicKey, numKeys)
+	for i := range keys {
+		keys[i] = newRandomCommitteeKey()
+	}
+
+	sorted1 := append([]CommitteePublicKey{}, keys...)
+	SortCommitteePublicKeys(sorted1)
+	for i := 1; i < len(sorted1); i++ {
+		prev, _ := sorted1[i-1].RawBytes()
+		cur, _ := sorted1[i].RawBytes()
+		assert.Equal(t, true, bytes.Compare(prev, cur) <= 0, fmt.Errorf("keys %v and %v are not sorted", i-1, i))
+	}
+
+	// sorting a shuffled copy gives the same result.
+	sorted2 := append([]CommitteePublicKey{}, keys...)
+	for i := range sorted2 {
+		j := common.RandInt() % len(sorted2)
+		sorted2[i], sorted2[j] = sorted2[j], sorted2[i]
+	}
+	SortCommitteePublicKeys(sorted2)
+	assert.Equal(t, sorted1, sorted2)
+}
+
+func TestDedupCommitteePublicKeys(t *testing.T) {
+	numKeys := 20
+	keys := make([]CommitteePublicKey, 0)
+	unique := make([]CommitteePublicKey, 0)
+	for i := 0; i < numKeys; i++ {
+		k := newRandomCommitteeKey()
+		unique = append(unique, k)
+		for j := 0; j <= i%3; j++ {
+			// a deep copy of k is a duplicate.
+			dup := CommitteePublicKey{
+				IncPubKey:    append([]byte{}, k.IncPubKey...),
+				MiningPubKey: make(map[string][]byte),
+			}
+			for scheme, v := range k.MiningPubKey {
+				dup.MiningPubKey[scheme] = append([]byte{}, v...)
+			}
+			keys = append(keys, dup)
+		}
+	}
+	// a key having the same IncPubKey but a different mining key is not a duplicate.
+	other := newRandomCommitteeKey()
+	other.IncPubKey = unique[0].IncPubKey
+	keys = append(keys, other)
+	unique = append(unique, other)
+	input := append([]CommitteePublicKey{}, keys...)
+
+	res := DedupCommitteePublicKeys(keys)
+	assert.Equal(t, input, keys) // the input is not modified
+	SortCommitteePublicKeys(unique)
+	assert.Equal(t, len(unique), len(res))
+	for i := range unique {
+		assert.Equal(t, true, unique[i].IsEqual(res[i]), fmt.Errorf("key %v mismatch", i))
+	}
+
+	assert.Equal(t, 0, len(DedupCommitteePublicKeys(nil)))
+}
+
+func TestCommitteePublicKey_GetMiningKey(t *testing.T) {
+	fullKey := newRandomCommitteeKey()
+	blsKey := fullKey.MiningPubKey[common.BlsConsensus]
+	bridgeKey := fullKey.MiningPubKey[common.BridgeConsensus]
+
+	// a full dual-scheme key.
+	res, err := fullKey.GetMiningKey(common.BlsConsensus)
+	assert.Equal(t, nil, err, fmt.Errorf("GetMiningKey error: %v", err))
+	expected, _ := json.Marshal(map[string][]byte{common.BlsConsensus: blsKey, common.BridgeConsensus: bridgeKey})
+	assert.Equal(t, expected, res)
+	res, err = fullKey.GetMiningKey(common.BridgeConsensus)
+	assert.Equal(t, nil, err, fmt.Errorf("GetMiningKey error: %v", err))
+	expected, _ = json.Marshal(map[string][]byte{common.BridgeConsensus: bridgeKey})
+	assert.Equal(t, expected, res)
+
+	// a BLS-only key.
+	blsOnlyKey := CommitteePublicKey{IncPubKey: fullKey.IncPubKey, MiningPubKey: map[string][]byte{common.BlsConsensus: blsKey}}
+	_, err = blsOnlyKey.GetMiningKey(common.BlsConsensus)
+	assert.NotEqual(t, nil, err)
+	assert.Contains(t, err.Error(), common.BridgeConsensus)
+	_, err = blsOnlyKey.GetMiningKey(common.BridgeConsensus)
+	assert.NotEqual(t, nil, err)
+	assert.Contains(t, err.Error(), common.BridgeConsensus)
+
+	// a bridge-only key.
+	bridgeOnlyKey := CommitteePublicKey{IncPubKey: fullKey.IncPubKey, MiningPubKey: map[string][]byte{common.BridgeConsensus: bridgeKey}}
+	res, err = bridgeOnlyKey.GetMiningKey(common.BridgeConsensus)
+	assert.Equal(t, nil, err, fmt.Errorf("GetMiningKey error: %v", err))
+	assert.Equal(t, expected, res)
+	_, err = bridgeOnlyKey.GetMiningKey(common.BlsConsensus)
+	assert.NotEqual(t, nil, err)
+	assert.Contains(t, err.Error(), common.BlsConsensus)
+}
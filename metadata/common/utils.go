@@ -376,6 +376,12 @@ func NoInputHasOutput(metaType int) bool {
 	return false
 }
 
+// HasMetaInfo checks if the transaction types allowed for metaType are known.
+func HasMetaInfo(metaType int) bool {
+	_, ok := metaInfoMap[metaType]
+	return ok
+}
+
 func IsAvailableMetaInTxType(metaType int, txType string) bool {
 	if info, ok := metaInfoMap[metaType]; ok {
 		_, ok := info.TxType[txType]
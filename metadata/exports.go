@@ -35,6 +35,7 @@ var NewMetadataBaseWithSignature = metadataCommon.NewMetadataBaseWithSignature
 // var ValidatePortalExternalAddress = metadataCommon.ValidatePortalExternalAddress
 // var NewMetadataTxError = metadataCommon.NewMetadataTxError
 var IsAvailableMetaInTxType = metadataCommon.IsAvailableMetaInTxType
+var HasMetaInfo = metadataCommon.HasMetaInfo
 var NoInputNoOutput = metadataCommon.NoInputNoOutput
 var NoInputHasOutput = metadataCommon.NoInputHasOutput
 var IsPortalRelayingMetaType = metadataCommon.IsPortalRelayingMetaType
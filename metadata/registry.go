@@ -0,0 +1,224 @@
+package metadata
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	metadataCommon "github.com/incognitochain/go-incognito-sdk-v2/metadata/common"
+	metadataPdexv3 "github.com/incognitochain/go-incognito-sdk-v2/metadata/pdexv3"
+)
+
+// metadataRegistryEntry holds how to decode a metadata type.
+type metadataRegistryEntry struct {
+	factory  func() Metadata
+	name     string
+	category MetadataCategory
+}
+
+var (
+	metadataRegistryMtx = new(sync.RWMutex)
+	metadataRegistry    = make(map[int]metadataRegistryEntry)
+)
+
+// MetadataCategory is the category of a metadata type.
+type MetadataCategory string
+
+// categories of the metadata types known to the SDK.
+const (
+	MetadataCategoryBridge   MetadataCategory = "bridge"
+	MetadataCategoryPDex     MetadataCategory = "pdex"
+	MetadataCategoryStake    MetadataCategory = "stake"
+	MetadataCategoryReward   MetadataCategory = "reward"
+	MetadataCategoryTransfer MetadataCategory = "transfer"
+
+	// MetadataCategoryOther is the category of metadata types registered via RegisterMetadata by the caller.
+	MetadataCategoryOther MetadataCategory = "other"
+)
+
+// MetadataTypeInfo describes a registered metadata type.
+type MetadataTypeInfo struct {
+	ID       int
+	Name     string
+	Category MetadataCategory
+}
+
+// RegisterMetadata teaches the SDK how to decode the metadata type typeID: factory returns a new (empty) instance of the
+// metadata, into which the JSON-encoded metadata is unmarshalled, and name is a human-readable name of the type.
+// Registering an already-registered typeID replaces the previous registration, including its category: a type
+// registered via this function always has the category MetadataCategoryOther.
+//
+// All metadata types known to the SDK are pre-registered. This function is meant for metadata types introduced
+// on-chain after the SDK release; once registered, ParseMetadata (and therefore transaction deserialization) is able
+// to decode them. It is safe for concurrent use.
+func RegisterMetadata(typeID int, factory func() Metadata, name string) {
+	registerMetadata(typeID, factory, name, MetadataCategoryOther)
+}
+
+// registerMetadata registers the metadata type typeID with the given category.
+func registerMetadata(typeID int, factory func() Metadata, name string, category MetadataCategory) {
+	if factory == nil {
+		panic(fmt.Sprintf("nil factory for metadata type %v", typeID))
+	}
+
+	metadataRegistryMtx.Lock()
+	defer metadataRegistryMtx.Unlock()
+	metadataRegistry[typeID] = metadataRegistryEntry{factory: factory, name: name, category: category}
+}
+
+// UnregisterMetadata removes the registration of the metadata type typeID, if any. Metadata of this type can no longer
+// be decoded afterwards. It is safe for concurrent use.
+func UnregisterMetadata(typeID int) {
+	metadataRegistryMtx.Lock()
+	defer metadataRegistryMtx.Unlock()
+	delete(metadataRegistry, typeID)
+}
+
+// NewMetadataByType returns a new (empty) instance of the metadata type typeID, and whether the type is registered.
+func NewMetadataByType(typeID int) (Metadata, bool) {
+	metadataRegistryMtx.RLock()
+	entry, ok := metadataRegistry[typeID]
+	metadataRegistryMtx.RUnlock()
+	if !ok {
+		return nil, false
+	}
+
+	return entry.factory(), true
+}
+
+// MetadataTypeName returns the registered name of the metadata type typeID, or "Unknown(<typeID>)" if the type is not
+// registered.
+func MetadataTypeName(typeID int) string {
+	metadataRegistryMtx.RLock()
+	defer metadataRegistryMtx.RUnlock()
+	if entry, ok := metadataRegistry[typeID]; ok {
+		return entry.name
+	}
+
+	return fmt.Sprintf("Unknown(%v)", typeID)
+}
+
+// RegisteredMetadataTypes returns the sorted list of registered metadata types.
+func RegisteredMetadataTypes() []int {
+	metadataRegistryMtx.RLock()
+	defer metadataRegistryMtx.RUnlock()
+
+	res := make([]int, 0, len(metadataRegistry))
+	for typeID := range metadataRegistry {
+		res = append(res, typeID)
+	}
+	sort.Ints(res)
+
+	return res
+}
+
+// ListMetadataTypes returns the information of all registered metadata types, sorted by their IDs.
+// Types registered by the caller (i.e, not known to the SDK) have the category MetadataCategoryOther.
+func ListMetadataTypes() []MetadataTypeInfo {
+	metadataRegistryMtx.RLock()
+	defer metadataRegistryMtx.RUnlock()
+
+	res := make([]MetadataTypeInfo, 0, len(metadataRegistry))
+	for typeID, entry := range metadataRegistry {
+		res = append(res, MetadataTypeInfo{ID: typeID, Name: entry.name, Category: entry.category})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].ID < res[j].ID
+	})
+
+	return res
+}
+
+// registerKnownMetadataType registers a metadata type known to the SDK together with its category.
+func registerKnownMetadataType(typeID int, factory func() Metadata, name string, category MetadataCategory) {
+	registerMetadata(typeID, factory, name, category)
+}
+
+// registerKnownMetadata registers all metadata types known to the SDK.
+func registerKnownMetadata() {
+	registerKnownMetadataType(InitTokenRequestMeta, func() Metadata { return &InitTokenRequest{} }, "InitTokenRequest", MetadataCategoryTransfer)
+	registerKnownMetadataType(InitTokenResponseMeta, func() Metadata { return &InitTokenResponse{} }, "InitTokenResponse", MetadataCategoryTransfer)
+	registerKnownMetadataType(IssuingRequestMeta, func() Metadata { return &IssuingRequest{} }, "IssuingRequest", MetadataCategoryBridge)
+	registerKnownMetadataType(IssuingResponseMeta, func() Metadata { return &IssuingResponse{} }, "IssuingResponse", MetadataCategoryBridge)
+	registerKnownMetadataType(ContractingRequestMeta, func() Metadata { return &ContractingRequest{} }, "ContractingRequest", MetadataCategoryBridge)
+	registerKnownMetadataType(IssuingETHRequestMeta, func() Metadata { return &IssuingEVMRequest{} }, "IssuingETHRequest", MetadataCategoryBridge)
+	registerKnownMetadataType(IssuingBSCRequestMeta, func() Metadata { return &IssuingEVMRequest{} }, "IssuingBSCRequest", MetadataCategoryBridge)
+	registerKnownMetadataType(IssuingPRVERC20RequestMeta, func() Metadata { return &IssuingEVMRequest{} }, "IssuingPRVERC20Request", MetadataCategoryBridge)
+	registerKnownMetadataType(IssuingPRVBEP20RequestMeta, func() Metadata { return &IssuingEVMRequest{} }, "IssuingPRVBEP20Request", MetadataCategoryBridge)
+	registerKnownMetadataType(IssuingETHResponseMeta, func() Metadata { return &IssuingEVMResponse{} }, "IssuingETHResponse", MetadataCategoryBridge)
+	registerKnownMetadataType(IssuingBSCResponseMeta, func() Metadata { return &IssuingEVMResponse{} }, "IssuingBSCResponse", MetadataCategoryBridge)
+	registerKnownMetadataType(IssuingPRVERC20ResponseMeta, func() Metadata { return &IssuingEVMResponse{} }, "IssuingPRVERC20Response", MetadataCategoryBridge)
+	registerKnownMetadataType(IssuingPRVBEP20ResponseMeta, func() Metadata { return &IssuingEVMResponse{} }, "IssuingPRVBEP20Response", MetadataCategoryBridge)
+	registerKnownMetadataType(BurningRequestMeta, func() Metadata { return &BurningRequest{} }, "BurningRequest", MetadataCategoryBridge)
+	registerKnownMetadataType(BurningForDepositToSCRequestMeta, func() Metadata { return &BurningRequest{} }, "BurningForDepositToSCRequest", MetadataCategoryBridge)
+	registerKnownMetadataType(BurningRequestMetaV2, func() Metadata { return &BurningRequest{} }, "BurningRequestMetaV2", MetadataCategoryBridge)
+	registerKnownMetadataType(BurningForDepositToSCRequestMetaV2, func() Metadata { return &BurningRequest{} }, "BurningForDepositToSCRequestMetaV2", MetadataCategoryBridge)
+	registerKnownMetadataType(BurningPBSCRequestMeta, func() Metadata { return &BurningRequest{} }, "BurningPBSCRequest", MetadataCategoryBridge)
+	registerKnownMetadataType(BurningPBSCForDepositToSCRequestMeta, func() Metadata { return &BurningRequest{} }, "BurningPBSCForDepositToSCRequest", MetadataCategoryBridge)
+	registerKnownMetadataType(BurningPRVBEP20RequestMeta, func() Metadata { return &BurningRequest{} }, "BurningPRVBEP20Request", MetadataCategoryBridge)
+	registerKnownMetadataType(BurningPRVERC20RequestMeta, func() Metadata { return &BurningRequest{} }, "BurningPRVERC20Request", MetadataCategoryBridge)
+	registerKnownMetadataType(IssuingPLGRequestMeta, func() Metadata { return &IssuingEVMRequest{} }, "IssuingPLGRequest", MetadataCategoryBridge)
+	registerKnownMetadataType(IssuingPLGResponseMeta, func() Metadata { return &IssuingEVMResponse{} }, "IssuingPLGResponse", MetadataCategoryBridge)
+	registerKnownMetadataType(BurningPLGRequestMeta, func() Metadata { return &BurningRequest{} }, "BurningPLGRequest", MetadataCategoryBridge)
+	registerKnownMetadataType(BurningPLGForDepositToSCRequestMeta, func() Metadata { return &BurningRequest{} }, "BurningPLGForDepositToSCRequest", MetadataCategoryBridge)
+	registerKnownMetadataType(IssuingFantomRequestMeta, func() Metadata { return &IssuingEVMRequest{} }, "IssuingFantomRequest", MetadataCategoryBridge)
+	registerKnownMetadataType(IssuingFantomResponseMeta, func() Metadata { return &IssuingEVMResponse{} }, "IssuingFantomResponse", MetadataCategoryBridge)
+	registerKnownMetadataType(BurningFantomRequestMeta, func() Metadata { return &BurningRequest{} }, "BurningFantomRequest", MetadataCategoryBridge)
+	registerKnownMetadataType(BurningFantomForDepositToSCRequestMeta, func() Metadata { return &BurningRequest{} }, "BurningFantomForDepositToSCRequest", MetadataCategoryBridge)
+	registerKnownMetadataType(ShardStakingMeta, func() Metadata { return &StakingMetadata{} }, "ShardStaking", MetadataCategoryStake)
+	registerKnownMetadataType(BeaconStakingMeta, func() Metadata { return &StakingMetadata{} }, "BeaconStaking", MetadataCategoryStake)
+	registerKnownMetadataType(ReturnStakingMeta, func() Metadata { return &ReturnStakingMetadata{} }, "ReturnStaking", MetadataCategoryStake)
+	registerKnownMetadataType(WithDrawRewardRequestMeta, func() Metadata { return &WithDrawRewardRequest{} }, "WithDrawRewardRequest", MetadataCategoryReward)
+	registerKnownMetadataType(WithDrawRewardResponseMeta, func() Metadata { return &WithDrawRewardResponse{} }, "WithDrawRewardResponse", MetadataCategoryReward)
+	registerKnownMetadataType(UnStakingMeta, func() Metadata { return &UnStakingMetadata{} }, "UnStaking", MetadataCategoryStake)
+	registerKnownMetadataType(StopAutoStakingMeta, func() Metadata { return &StopAutoStakingMetadata{} }, "StopAutoStaking", MetadataCategoryStake)
+	registerKnownMetadataType(PDEContributionMeta, func() Metadata { return &PDEContribution{} }, "PDEContribution", MetadataCategoryPDex)
+	registerKnownMetadataType(PDEPRVRequiredContributionRequestMeta, func() Metadata { return &PDEContribution{} }, "PDEPRVRequiredContributionRequest", MetadataCategoryPDex)
+	registerKnownMetadataType(PDETradeRequestMeta, func() Metadata { return &PDETradeRequest{} }, "PDETradeRequest", MetadataCategoryPDex)
+	registerKnownMetadataType(PDETradeResponseMeta, func() Metadata { return &PDETradeResponse{} }, "PDETradeResponse", MetadataCategoryPDex)
+	registerKnownMetadataType(PDECrossPoolTradeRequestMeta, func() Metadata { return &PDECrossPoolTradeRequest{} }, "PDECrossPoolTradeRequest", MetadataCategoryPDex)
+	registerKnownMetadataType(PDECrossPoolTradeResponseMeta, func() Metadata { return &PDECrossPoolTradeResponse{} }, "PDECrossPoolTradeResponse", MetadataCategoryPDex)
+	registerKnownMetadataType(PDEWithdrawalRequestMeta, func() Metadata { return &PDEWithdrawalRequest{} }, "PDEWithdrawalRequest", MetadataCategoryPDex)
+	registerKnownMetadataType(PDEWithdrawalResponseMeta, func() Metadata { return &PDEWithdrawalResponse{} }, "PDEWithdrawalResponse", MetadataCategoryPDex)
+	registerKnownMetadataType(PDEFeeWithdrawalRequestMeta, func() Metadata { return &PDEFeeWithdrawalRequest{} }, "PDEFeeWithdrawalRequest", MetadataCategoryPDex)
+	registerKnownMetadataType(PDEFeeWithdrawalResponseMeta, func() Metadata { return &PDEFeeWithdrawalResponse{} }, "PDEFeeWithdrawalResponse", MetadataCategoryPDex)
+	registerKnownMetadataType(PDEContributionResponseMeta, func() Metadata { return &PDEContributionResponse{} }, "PDEContributionResponse", MetadataCategoryPDex)
+	registerKnownMetadataType(RelayingBNBHeaderMeta, func() Metadata { return &RelayingHeader{} }, "RelayingBNBHeader", MetadataCategoryBridge)
+	registerKnownMetadataType(RelayingBTCHeaderMeta, func() Metadata { return &RelayingHeader{} }, "RelayingBTCHeader", MetadataCategoryBridge)
+	registerKnownMetadataType(metadataCommon.PortalV4ShieldingRequestMeta, func() Metadata { return &PortalShieldingRequest{} }, "PortalV4ShieldingRequest", MetadataCategoryBridge)
+	registerKnownMetadataType(metadataCommon.PortalV4ShieldingResponseMeta, func() Metadata { return &PortalShieldingResponse{} }, "PortalV4ShieldingResponse", MetadataCategoryBridge)
+	registerKnownMetadataType(metadataCommon.PortalV4UnshieldingRequestMeta, func() Metadata { return &PortalUnshieldRequest{} }, "PortalV4UnshieldingRequest", MetadataCategoryBridge)
+	registerKnownMetadataType(metadataCommon.PortalV4UnshieldingResponseMeta, func() Metadata { return &PortalUnshieldResponse{} }, "PortalV4UnshieldingResponse", MetadataCategoryBridge)
+	registerKnownMetadataType(metadataCommon.PortalV4FeeReplacementRequestMeta, func() Metadata { return &PortalReplacementFeeRequest{} }, "PortalV4FeeReplacementRequest", MetadataCategoryBridge)
+	registerKnownMetadataType(metadataCommon.PortalV4SubmitConfirmedTxMeta, func() Metadata { return &PortalSubmitConfirmedTxRequest{} }, "PortalV4SubmitConfirmedTx", MetadataCategoryBridge)
+	registerKnownMetadataType(metadataCommon.PortalV4ConvertVaultRequestMeta, func() Metadata { return &PortalConvertVaultRequest{} }, "PortalV4ConvertVaultRequest", MetadataCategoryBridge)
+	registerKnownMetadataType(metadataCommon.Pdexv3ModifyParamsMeta, func() Metadata { return &metadataPdexv3.ParamsModifyingRequest{} }, "Pdexv3ModifyParams", MetadataCategoryPDex)
+	registerKnownMetadataType(metadataCommon.Pdexv3AddLiquidityRequestMeta, func() Metadata { return &metadataPdexv3.AddLiquidityRequest{} }, "Pdexv3AddLiquidityRequest", MetadataCategoryPDex)
+	registerKnownMetadataType(metadataCommon.Pdexv3AddLiquidityResponseMeta, func() Metadata { return &metadataPdexv3.AddLiquidityResponse{} }, "Pdexv3AddLiquidityResponse", MetadataCategoryPDex)
+	registerKnownMetadataType(metadataCommon.Pdexv3WithdrawLiquidityRequestMeta, func() Metadata { return &metadataPdexv3.WithdrawLiquidityRequest{} }, "Pdexv3WithdrawLiquidityRequest", MetadataCategoryPDex)
+	registerKnownMetadataType(metadataCommon.Pdexv3WithdrawLiquidityResponseMeta, func() Metadata { return &metadataPdexv3.WithdrawLiquidityResponse{} }, "Pdexv3WithdrawLiquidityResponse", MetadataCategoryPDex)
+	registerKnownMetadataType(metadataCommon.Pdexv3TradeRequestMeta, func() Metadata { return &metadataPdexv3.TradeRequest{} }, "Pdexv3TradeRequest", MetadataCategoryPDex)
+	registerKnownMetadataType(metadataCommon.Pdexv3TradeResponseMeta, func() Metadata { return &metadataPdexv3.TradeResponse{} }, "Pdexv3TradeResponse", MetadataCategoryPDex)
+	registerKnownMetadataType(metadataCommon.Pdexv3AddOrderRequestMeta, func() Metadata { return &metadataPdexv3.AddOrderRequest{} }, "Pdexv3AddOrderRequest", MetadataCategoryPDex)
+	registerKnownMetadataType(metadataCommon.Pdexv3AddOrderResponseMeta, func() Metadata { return &metadataPdexv3.AddOrderResponse{} }, "Pdexv3AddOrderResponse", MetadataCategoryPDex)
+	registerKnownMetadataType(metadataCommon.Pdexv3UserMintNftRequestMeta, func() Metadata { return &metadataPdexv3.UserMintNftRequest{} }, "Pdexv3UserMintNftRequest", MetadataCategoryPDex)
+	registerKnownMetadataType(metadataCommon.Pdexv3UserMintNftResponseMeta, func() Metadata { return &metadataPdexv3.UserMintNftResponse{} }, "Pdexv3UserMintNftResponse", MetadataCategoryPDex)
+	registerKnownMetadataType(metadataCommon.Pdexv3MintNftResponseMeta, func() Metadata { return &metadataPdexv3.MintNftResponse{} }, "Pdexv3MintNftResponse", MetadataCategoryPDex)
+	registerKnownMetadataType(metadataCommon.Pdexv3WithdrawOrderRequestMeta, func() Metadata { return &metadataPdexv3.WithdrawOrderRequest{} }, "Pdexv3WithdrawOrderRequest", MetadataCategoryPDex)
+	registerKnownMetadataType(metadataCommon.Pdexv3WithdrawOrderResponseMeta, func() Metadata { return &metadataPdexv3.WithdrawOrderResponse{} }, "Pdexv3WithdrawOrderResponse", MetadataCategoryPDex)
+	registerKnownMetadataType(metadataCommon.Pdexv3StakingRequestMeta, func() Metadata { return &metadataPdexv3.StakingRequest{} }, "Pdexv3StakingRequest", MetadataCategoryPDex)
+	registerKnownMetadataType(metadataCommon.Pdexv3StakingResponseMeta, func() Metadata { return &metadataPdexv3.StakingResponse{} }, "Pdexv3StakingResponse", MetadataCategoryPDex)
+	registerKnownMetadataType(metadataCommon.Pdexv3UnstakingRequestMeta, func() Metadata { return &metadataPdexv3.UnstakingRequest{} }, "Pdexv3UnstakingRequest", MetadataCategoryPDex)
+	registerKnownMetadataType(metadataCommon.Pdexv3UnstakingResponseMeta, func() Metadata { return &metadataPdexv3.UnstakingResponse{} }, "Pdexv3UnstakingResponse", MetadataCategoryPDex)
+	registerKnownMetadataType(metadataCommon.Pdexv3WithdrawLPFeeRequestMeta, func() Metadata { return &metadataPdexv3.WithdrawalLPFeeRequest{} }, "Pdexv3WithdrawLPFeeRequest", MetadataCategoryPDex)
+	registerKnownMetadataType(metadataCommon.Pdexv3WithdrawLPFeeResponseMeta, func() Metadata { return &metadataPdexv3.WithdrawalLPFeeResponse{} }, "Pdexv3WithdrawLPFeeResponse", MetadataCategoryPDex)
+	registerKnownMetadataType(metadataCommon.Pdexv3WithdrawProtocolFeeRequestMeta, func() Metadata { return &metadataPdexv3.WithdrawalProtocolFeeRequest{} }, "Pdexv3WithdrawProtocolFeeRequest", MetadataCategoryPDex)
+	registerKnownMetadataType(metadataCommon.Pdexv3WithdrawProtocolFeeResponseMeta, func() Metadata { return &metadataPdexv3.WithdrawalProtocolFeeResponse{} }, "Pdexv3WithdrawProtocolFeeResponse", MetadataCategoryPDex)
+	registerKnownMetadataType(metadataCommon.Pdexv3MintPDEXGenesisMeta, func() Metadata { return &metadataPdexv3.MintPDEXGenesisResponse{} }, "Pdexv3MintPDEXGenesis", MetadataCategoryPDex)
+	registerKnownMetadataType(metadataCommon.Pdexv3WithdrawStakingRewardRequestMeta, func() Metadata { return &metadataPdexv3.WithdrawalStakingRewardRequest{} }, "Pdexv3WithdrawStakingRewardRequest", MetadataCategoryPDex)
+	registerKnownMetadataType(metadataCommon.Pdexv3WithdrawStakingRewardResponseMeta, func() Metadata { return &metadataPdexv3.WithdrawalStakingRewardResponse{} }, "Pdexv3WithdrawStakingRewardResponse", MetadataCategoryPDex)
+}
+
+func init() {
+	registerKnownMetadata()
+}
@@ -0,0 +1,122 @@
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	metadataCommon "github.com/incognitochain/go-incognito-sdk-v2/metadata/common"
+	"github.com/stretchr/testify/assert"
+)
+
+const testCustomMetadataType = 10001
+
+type testCustomMetadata struct {
+	metadataCommon.MetadataBase
+	Receiver string
+	Amount   uint64
+}
+
+func TestRegisterMetadata(t *testing.T) {
+	// known types are pre-registered
+	knownTypes := map[int]interface{}{
+		IssuingRequestMeta:                           &IssuingRequest{},
+		BurningRequestMetaV2:                         &BurningRequest{},
+		ShardStakingMeta:                             &StakingMetadata{},
+		WithDrawRewardRequestMeta:                    &WithDrawRewardRequest{},
+		metadataCommon.Pdexv3TradeRequestMeta:        nil,
+		metadataCommon.PortalV4ShieldingRequestMeta:  &PortalShieldingRequest{},
+		metadataCommon.Pdexv3AddLiquidityRequestMeta: nil,
+	}
+	for typeID, expected := range knownTypes {
+		md, ok := NewMetadataByType(typeID)
+		assert.Equal(t, true, ok, fmt.Errorf("metadata type %v is not registered", typeID))
+		if expected != nil {
+			assert.IsType(t, expected, md)
+		}
+		assert.NotContains(t, MetadataTypeName(typeID), "Unknown")
+	}
+	assert.Equal(t, "IssuingRequest", MetadataTypeName(IssuingRequestMeta))
+	assert.Equal(t, "Pdexv3TradeRequest", MetadataTypeName(metadataCommon.Pdexv3TradeRequestMeta))
+
+	// an unknown type cannot be parsed
+	_, ok := NewMetadataByType(testCustomMetadataType)
+	assert.Equal(t, false, ok)
+	assert.Equal(t, fmt.Sprintf("Unknown(%v)", testCustomMetadataType), MetadataTypeName(testCustomMetadataType))
+	mdBytes, err := json.Marshal(testCustomMetadata{
+		MetadataBase: *metadataCommon.NewMetadataBase(testCustomMetadataType),
+		Receiver:     "someone",
+		Amount:       1000,
+	})
+	assert.Equal(t, nil, err)
+	_, err = ParseMetadata(mdBytes)
+	assert.NotEqual(t, nil, err)
+
+	// once registered, it can
+	RegisterMetadata(testCustomMetadataType, func() Metadata { return &testCustomMetadata{} }, "TestCustomMetadata")
+	defer UnregisterMetadata(testCustomMetadataType)
+	assert.Equal(t, "TestCustomMetadata", MetadataTypeName(testCustomMetadataType))
+	assert.Contains(t, RegisteredMetadataTypes(), testCustomMetadataType)
+
+	md, err := ParseMetadata(mdBytes)
+	assert.Equal(t, nil, err, fmt.Errorf("ParseMetadata error: %v", err))
+	customMd, ok := md.(*testCustomMetadata)
+	assert.Equal(t, true, ok, fmt.Errorf("expected *testCustomMetadata, got %T", md))
+	assert.Equal(t, testCustomMetadataType, customMd.GetType())
+	assert.Equal(t, "someone", customMd.Receiver)
+	assert.Equal(t, uint64(1000), customMd.Amount)
+
+	// and unregistered again
+	UnregisterMetadata(testCustomMetadataType)
+	_, ok = NewMetadataByType(testCustomMetadataType)
+	assert.Equal(t, false, ok)
+	assert.NotContains(t, RegisteredMetadataTypes(), testCustomMetadataType)
+}
+
+func TestListMetadataTypes(t *testing.T) {
+	types := ListMetadataTypes()
+	assert.Equal(t, len(RegisteredMetadataTypes()), len(types))
+
+	typeInfos := make(map[int]MetadataTypeInfo)
+	for i, info := range types {
+		if i > 0 {
+			assert.Less(t, types[i-1].ID, info.ID)
+		}
+		assert.Equal(t, MetadataTypeName(info.ID), info.Name)
+		typeInfos[info.ID] = info
+	}
+
+	// EVM issuing/burning types
+	evmTypes := []int{
+		IssuingETHRequestMeta, IssuingETHResponseMeta, IssuingBSCRequestMeta, IssuingBSCResponseMeta,
+		IssuingPLGRequestMeta, IssuingPLGResponseMeta, IssuingFantomRequestMeta, IssuingFantomResponseMeta,
+		IssuingPRVERC20RequestMeta, IssuingPRVBEP20RequestMeta,
+		BurningRequestMetaV2, BurningForDepositToSCRequestMetaV2, BurningPBSCRequestMeta, BurningPBSCForDepositToSCRequestMeta,
+		BurningPLGRequestMeta, BurningPLGForDepositToSCRequestMeta, BurningFantomRequestMeta, BurningFantomForDepositToSCRequestMeta,
+		BurningPRVERC20RequestMeta, BurningPRVBEP20RequestMeta,
+	}
+	for _, typeID := range evmTypes {
+		info, ok := typeInfos[typeID]
+		assert.Equal(t, true, ok, fmt.Errorf("metadata type %v not listed", typeID))
+		assert.Equal(t, MetadataCategoryBridge, info.Category, fmt.Errorf("invalid category of %v", info.Name))
+	}
+
+	assert.Equal(t, MetadataCategoryStake, typeInfos[ShardStakingMeta].Category)
+	assert.Equal(t, MetadataCategoryReward, typeInfos[WithDrawRewardRequestMeta].Category)
+	assert.Equal(t, MetadataCategoryPDex, typeInfos[metadataCommon.Pdexv3TradeRequestMeta].Category)
+	assert.Equal(t, MetadataCategoryTransfer, typeInfos[InitTokenRequestMeta].Category)
+
+	// a known type overridden by the caller is no longer listed with its original category.
+	defer registerKnownMetadataType(ShardStakingMeta, func() Metadata { return &StakingMetadata{} }, "ShardStaking", MetadataCategoryStake)
+	RegisterMetadata(ShardStakingMeta, func() Metadata { return &testCustomMetadata{} }, "CustomShardStaking")
+	RegisterMetadata(testCustomMetadataType, func() Metadata { return &testCustomMetadata{} }, "TestCustomMetadata")
+	defer UnregisterMetadata(testCustomMetadataType)
+	for _, info := range ListMetadataTypes() {
+		switch info.ID {
+		case ShardStakingMeta:
+			assert.Equal(t, MetadataTypeInfo{ID: ShardStakingMeta, Name: "CustomShardStaking", Category: MetadataCategoryOther}, info)
+		case testCustomMetadataType:
+			assert.Equal(t, MetadataTypeInfo{ID: testCustomMetadataType, Name: "TestCustomMetadata", Category: MetadataCategoryOther}, info)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package metadata
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	metadataCommon "github.com/incognitochain/go-incognito-sdk-v2/metadata/common"
+	metadataPdexv3 "github.com/incognitochain/go-incognito-sdk-v2/metadata/pdexv3"
+)
+
+// ErrNotATrade indicates that a metadata (or a transaction) is not a pDEX trade request.
+var ErrNotATrade = fmt.Errorf("not a pDEX trade request")
+
+// TradeRequestInfo holds the parameters of a pDEX trade request, regardless of the version of its metadata.
+type TradeRequestInfo struct {
+	// MetadataType is the type of the trade metadata (PDETradeRequestMeta, PDECrossPoolTradeRequestMeta or
+	// Pdexv3TradeRequestMeta).
+	MetadataType int
+
+	// SellTokenID is the tokenID being sold.
+	SellTokenID string
+
+	// BuyTokenID is the tokenID being bought.
+	BuyTokenID string
+
+	// SellAmount is the amount of SellTokenID being sold.
+	SellAmount uint64
+
+	// MinAcceptableAmount is the minimum amount of BuyTokenID the trader accepts to receive.
+	MinAcceptableAmount uint64
+
+	// TradingFee is the fee paid to the pDEX.
+	TradingFee uint64
+
+	// TradingPath is the list of pool pairs the trade goes through. It is only available for pDEX v3 trades.
+	TradingPath []string
+}
+
+// GetTradeRequestInfo extracts the trade parameters of a pDEX trade metadata of any version.
+// It returns ErrNotATrade if md is not a trade request.
+func GetTradeRequestInfo(md Metadata) (*TradeRequestInfo, error) {
+	if md == nil {
+		return nil, ErrNotATrade
+	}
+
+	switch req := md.(type) {
+	case *PDETradeRequest:
+		return &TradeRequestInfo{
+			MetadataType:        req.Type,
+			SellTokenID:         req.TokenIDToSellStr,
+			BuyTokenID:          req.TokenIDToBuyStr,
+			SellAmount:          req.SellAmount,
+			MinAcceptableAmount: req.MinAcceptableAmount,
+			TradingFee:          req.TradingFee,
+		}, nil
+	case *PDECrossPoolTradeRequest:
+		return &TradeRequestInfo{
+			MetadataType:        req.Type,
+			SellTokenID:         req.TokenIDToSellStr,
+			BuyTokenID:          req.TokenIDToBuyStr,
+			SellAmount:          req.SellAmount,
+			MinAcceptableAmount: req.MinAcceptableAmount,
+			TradingFee:          req.TradingFee,
+		}, nil
+	case *metadataPdexv3.TradeRequest:
+		if len(req.TradePath) == 0 {
+			return nil, fmt.Errorf("empty trading path")
+		}
+		buyTokenID, err := getBuyTokenFromTradePath(req.TokenToSell.String(), req.TradePath)
+		if err != nil {
+			return nil, err
+		}
+		return &TradeRequestInfo{
+			MetadataType:        req.Type,
+			SellTokenID:         req.TokenToSell.String(),
+			BuyTokenID:          buyTokenID,
+			SellAmount:          req.SellAmount,
+			MinAcceptableAmount: req.MinAcceptableAmount,
+			TradingFee:          req.TradingFee,
+			TradingPath:         append([]string{}, req.TradePath...),
+		}, nil
+	}
+
+	switch md.GetType() {
+	case PDETradeRequestMeta, PDECrossPoolTradeRequestMeta, metadataCommon.Pdexv3TradeRequestMeta:
+		return nil, fmt.Errorf("unexpected type %T of trade metadata %v", md, md.GetType())
+	}
+	return nil, ErrNotATrade
+}
+
+// getBuyTokenFromTradePath follows a pDEX v3 trading path, starting from the sell token, and returns the token
+// received at the end. Each pool pair ID has the form "<tokenID0>-<tokenID1>-<hash>".
+func getBuyTokenFromTradePath(sellTokenID string, tradePath []string) (string, error) {
+	current := sellTokenID
+	for _, poolPairID := range tradePath {
+		tokenIDs := strings.Split(poolPairID, "-")
+		if len(tokenIDs) != 3 {
+			return "", fmt.Errorf("invalid pool pair ID %v", poolPairID)
+		}
+		switch current {
+		case tokenIDs[0]:
+			current = tokenIDs[1]
+		case tokenIDs[1]:
+			current = tokenIDs[0]
+		default:
+			return "", fmt.Errorf("pool pair %v does not trade token %v", poolPairID, current)
+		}
+	}
+	if current == sellTokenID {
+		return "", fmt.Errorf("trading path %v ends with the sell token %v", tradePath, sellTokenID)
+	}
+	if _, err := new(common.Hash).NewHashFromStr(current); err != nil {
+		return "", fmt.Errorf("invalid buy token %v: %v", current, err)
+	}
+
+	return current, nil
+}
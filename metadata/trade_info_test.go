@@ -0,0 +1,80 @@
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/coin"
+	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	metadataCommon "github.com/incognitochain/go-incognito-sdk-v2/metadata/common"
+	metadataPdexv3 "github.com/incognitochain/go-incognito-sdk-v2/metadata/pdexv3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTradeRequestInfo(t *testing.T) {
+	tokenA := common.HashH([]byte("tokenA")).String()
+	tokenB := common.HashH([]byte("tokenB")).String()
+	tokenC := common.HashH([]byte("tokenC")).String()
+
+	// pDEX v1
+	v1, err := NewPDETradeRequest(tokenB, tokenA, 1000, 900, 10, "trader", "", PDETradeRequestMeta)
+	assert.Equal(t, nil, err)
+	// pDEX v2
+	v2, err := NewPDECrossPoolTradeRequest(tokenB, tokenA, 1000, 900, 10, "trader", "", "subTrader", "", PDECrossPoolTradeRequestMeta)
+	assert.Equal(t, nil, err)
+	// pDEX v3, trading A -> PRV -> C -> B
+	tokenAHash, _ := new(common.Hash).NewHashFromStr(tokenA)
+	tradePath := []string{
+		common.PRVIDStr + "-" + tokenA + "-" + common.HashH([]byte("pool0")).String(),
+		common.PRVIDStr + "-" + tokenC + "-" + common.HashH([]byte("pool1")).String(),
+		tokenB + "-" + tokenC + "-" + common.HashH([]byte("pool2")).String(),
+	}
+	v3, err := metadataPdexv3.NewTradeRequest(tradePath, *tokenAHash, 1000, 900, 10,
+		map[common.Hash]coin.OTAReceiver{}, metadataCommon.Pdexv3TradeRequestMeta)
+	assert.Equal(t, nil, err)
+
+	for _, md := range []Metadata{v1, v2, v3} {
+		// the parsed metadata must give the same result
+		jsb, err := json.Marshal(md)
+		assert.Equal(t, nil, err)
+		parsedMd, err := ParseMetadata(jsb)
+		assert.Equal(t, nil, err, fmt.Errorf("ParseMetadata error: %v", err))
+
+		for _, tmpMd := range []Metadata{md, parsedMd} {
+			info, err := GetTradeRequestInfo(tmpMd)
+			assert.Equal(t, nil, err, fmt.Errorf("GetTradeRequestInfo(%v) error: %v", md.GetType(), err))
+			assert.Equal(t, md.GetType(), info.MetadataType)
+			assert.Equal(t, tokenA, info.SellTokenID)
+			assert.Equal(t, tokenB, info.BuyTokenID)
+			assert.Equal(t, uint64(1000), info.SellAmount)
+			assert.Equal(t, uint64(900), info.MinAcceptableAmount)
+			assert.Equal(t, uint64(10), info.TradingFee)
+			if md.GetType() == metadataCommon.Pdexv3TradeRequestMeta {
+				assert.Equal(t, tradePath, info.TradingPath)
+			} else {
+				assert.Equal(t, 0, len(info.TradingPath))
+			}
+		}
+	}
+
+	// not a trade
+	for _, md := range []Metadata{nil, NewMetadataBase(WithDrawRewardRequestMeta)} {
+		_, err = GetTradeRequestInfo(md)
+		assert.Equal(t, ErrNotATrade, err)
+	}
+
+	// invalid trading paths
+	invalidPaths := [][]string{
+		{},
+		{"abc"},
+		{tokenB + "-" + tokenC + "-" + common.HashH([]byte("pool2")).String()},
+		{common.PRVIDStr + "-" + tokenA + "-" + common.HashH([]byte("pool0")).String(), common.PRVIDStr + "-" + tokenA + "-" + common.HashH([]byte("pool0")).String()},
+	}
+	for _, path := range invalidPaths {
+		v3.TradePath = path
+		_, err = GetTradeRequestInfo(v3)
+		assert.NotEqual(t, nil, err, fmt.Errorf("expected an error for path %v", path))
+		assert.NotEqual(t, ErrNotATrade, err)
+	}
+}
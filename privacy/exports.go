@@ -83,6 +83,8 @@ func ArrayScalarToBytes(arr *[]*crypto.Scalar) ([]byte, error) {
 
 // ProveV2 returns a ProofV2 based on the given input coins, output coins, shared secrets, etc.
 // It is usually used in constructing a transaction of version 2.
-func ProveV2(inputCoins []coin.PlainCoin, outputCoins []*coin.CoinV2, sharedSecrets []*crypto.Point, hasPrivacy bool, paymentInfo []*key.PaymentInfo) (*ProofV2, error) {
-	return v2.Prove(inputCoins, outputCoins, sharedSecrets, hasPrivacy, paymentInfo)
+//
+// If skipRangeProof is set to true, the returned proof is for estimation only and must not be submitted.
+func ProveV2(inputCoins []coin.PlainCoin, outputCoins []*coin.CoinV2, sharedSecrets []*crypto.Point, hasPrivacy bool, paymentInfo []*key.PaymentInfo, skipRangeProof ...bool) (*ProofV2, error) {
+	return v2.Prove(inputCoins, outputCoins, sharedSecrets, hasPrivacy, paymentInfo, skipRangeProof...)
 }
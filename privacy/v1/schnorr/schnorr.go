@@ -140,3 +140,22 @@ func (privateKey SchnorrPrivateKey) Sign(data []byte) (*SchnSignature, error) {
 
 	return signature, nil
 }
+
+// Verify checks if a SchnSignature is a valid signature of the given data w.r.t a SchnorrPublicKey.
+func (publicKey SchnorrPublicKey) Verify(signature *SchnSignature, data []byte) bool {
+	if signature == nil || signature.e == nil || signature.z1 == nil {
+		return false
+	}
+
+	// rv = e*PK + z1*G (+ z2*H)
+	rv := new(crypto.Point).ScalarMult(publicKey.publicKey, signature.e)
+	rv.Add(rv, new(crypto.Point).ScalarMult(publicKey.g, signature.z1))
+	if signature.z2 != nil {
+		rv.Add(rv, new(crypto.Point).ScalarMult(publicKey.h, signature.z2))
+	}
+
+	msg := append(rv.ToBytesS(), data...)
+	tmpE := crypto.HashToScalar(msg)
+
+	return crypto.IsScalarEqual(tmpE, signature.e)
+}
@@ -110,6 +110,37 @@ func (proof RangeProof) Bytes() []byte {
 	return res
 }
 
+// NewPlaceholderRangeProof returns a RangeProof which has the same size as a real RangeProof for the given commitments,
+// but proves nothing. All of its points are the identity point, and all of its scalars are zero.
+//
+// It is only used to estimate the size of a transaction without paying the cost of proving. Such a RangeProof never
+// passes the verification.
+func NewPlaceholderRangeProof(commitments []*crypto.Point) *RangeProof {
+	proof := new(RangeProof)
+	proof.Init()
+	proof.cmsValue = commitments
+
+	numRounds := 0
+	for n := utils.MaxExp * roundUpPowTwo(len(commitments)); n > 1; n /= 2 {
+		numRounds++
+	}
+	for i := 0; i < numRounds; i++ {
+		proof.innerProductProof.l = append(proof.innerProductProof.l, new(crypto.Point).Identity())
+		proof.innerProductProof.r = append(proof.innerProductProof.r, new(crypto.Point).Identity())
+	}
+
+	return proof
+}
+
+// IsPlaceholder checks if a RangeProof is a placeholder created by NewPlaceholderRangeProof.
+func (proof RangeProof) IsPlaceholder() bool {
+	if proof.IsNil() {
+		return false
+	}
+
+	return proof.a.IsIdentity() && proof.s.IsIdentity() && proof.t1.IsIdentity() && proof.t2.IsIdentity()
+}
+
 // GetCommitments returns the commitments of a RangeProof.
 func (proof RangeProof) GetCommitments() []*crypto.Point { return proof.cmsValue }
 
@@ -404,3 +435,96 @@ func (wit Witness) Prove() (*RangeProof, error) {
 
 	return proof, nil
 }
+
+// Verify checks a (non-CA) RangeProof, i.e, that every committed value lies in [0, 2^utils.MaxExp). It returns false
+// with an error describing the first failed check.
+func (proof RangeProof) Verify() (bool, error) {
+	if proof.IsNil() {
+		return false, fmt.Errorf("range proof is nil")
+	}
+	if proof.IsPlaceholder() {
+		return false, fmt.Errorf("range proof is a placeholder")
+	}
+	numValue := len(proof.cmsValue)
+	if numValue == 0 || numValue > utils.MaxOutputCoin {
+		return false, fmt.Errorf("invalid number of commitments %v", numValue)
+	}
+	numValuePad := roundUpPowTwo(numValue)
+	maxExp := utils.MaxExp
+	N := maxExp * numValuePad
+
+	aggParam := setAggregateParams(N)
+
+	cmsValue := make([]*crypto.Point, numValuePad)
+	initChal := aggParam.cs.ToBytesS()
+	for i := 0; i < numValuePad; i++ {
+		if i >= numValue {
+			cmsValue[i] = new(crypto.Point).Identity()
+			continue
+		}
+		if proof.cmsValue[i] == nil {
+			return false, fmt.Errorf("commitment %v is nil", i)
+		}
+		cmsValue[i] = proof.cmsValue[i]
+		if proof.version >= 2 {
+			initChal = append(initChal, proof.cmsValue[i].ToBytesS()...)
+		}
+	}
+
+	// the same challenges as in Prove
+	y := generateChallenge(initChal, []*crypto.Point{proof.a, proof.s})
+	z := generateChallenge(y.ToBytesS(), []*crypto.Point{proof.a, proof.s})
+	zSquare := new(crypto.Scalar).Mul(z, z)
+	x := generateChallenge(z.ToBytesS(), []*crypto.Point{proof.t1, proof.t2})
+	xSquare := new(crypto.Scalar).Mul(x, x)
+
+	// tHat = t(x): G^tHat * H^tauX = V^(z^2 * z^j) * G^delta(y, z) * T1^x * T2^(x^2)
+	yVector := powerVector(y, N)
+	deltaYZ, err := computeDeltaYZ(z, zSquare, yVector, N)
+	if err != nil {
+		return false, err
+	}
+	LHS := crypto.PedCom.CommitAtIndex(proof.tHat, proof.tauX, crypto.PedersenValueIndex)
+	RHS := new(crypto.Point).AddPedersen(x, proof.t1, xSquare, proof.t2)
+	RHS.Add(RHS, new(crypto.Point).ScalarMult(crypto.PedCom.G[crypto.PedersenValueIndex], deltaYZ))
+	expVector := vectorMulScalar(powerVector(z, numValuePad), zSquare)
+	RHS.Add(RHS, new(crypto.Point).MultiScalarMult(expVector, cmsValue))
+	if !crypto.IsPointEqual(LHS, RHS) {
+		return false, fmt.Errorf("invalid range proof: tHat mismatch")
+	}
+
+	// the point of the inner-product argument must be derived from A, S:
+	// P = A + x*S - mu*h - z*<1, g> + <z*y^N + z^2*z^j*2^n, H'> + tHat*u'
+	HPrime := computeHPrime(y, N, aggParam.h)
+	uPrime := new(crypto.Point).ScalarMult(aggParam.u, crypto.HashToScalar(x.ToBytesS()))
+	twoVectorN := powerVector(new(crypto.Scalar).FromUint64(2), maxExp)
+	zNeg := new(crypto.Scalar).Sub(new(crypto.Scalar).FromUint64(0), z)
+	gExps := make([]*crypto.Scalar, N)
+	hExps := make([]*crypto.Scalar, N)
+	zTmp := new(crypto.Scalar).Set(z)
+	for j := 0; j < numValuePad; j++ {
+		zTmp.Mul(zTmp, z)
+		for i := 0; i < maxExp; i++ {
+			k := j*maxExp + i
+			gExps[k] = zNeg
+			hExps[k] = new(crypto.Scalar).Mul(z, yVector[k])
+			hExps[k].Add(hExps[k], new(crypto.Scalar).Mul(zTmp, twoVectorN[i]))
+		}
+	}
+	expectedP, err := encodeVectors(gExps, hExps, aggParam.g, HPrime)
+	if err != nil {
+		return false, err
+	}
+	expectedP.Add(expectedP, new(crypto.Point).AddPedersen(new(crypto.Scalar).FromUint64(1), proof.a, x, proof.s))
+	expectedP.Sub(expectedP, new(crypto.Point).ScalarMult(crypto.HBase, proof.mu))
+	expectedP.Add(expectedP, new(crypto.Point).ScalarMult(uPrime, proof.tHat))
+	if !crypto.IsPointEqual(expectedP, proof.innerProductProof.p) {
+		return false, fmt.Errorf("invalid range proof: inner-product point mismatch")
+	}
+
+	if !proof.innerProductProof.Verify(aggParam.g, HPrime, uPrime, x.ToBytesS()) {
+		return false, fmt.Errorf("invalid range proof: inner-product argument failed")
+	}
+
+	return true, nil
+}
@@ -0,0 +1,69 @@
+package bulletproofs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRangeProof(t *testing.T, values []uint64) *RangeProof {
+	rands := make([]*crypto.Scalar, len(values))
+	for i := range rands {
+		rands[i] = crypto.RandomScalar()
+	}
+	wit := new(Witness)
+	wit.Set(values, rands)
+	proof, err := wit.Prove()
+	assert.Equal(t, nil, err, fmt.Errorf("Prove error: %v", err))
+	return proof
+}
+
+func TestRangeProof_Verify(t *testing.T) {
+	for _, values := range [][]uint64{{0}, {1000, 1<<64 - 1}, {1, 2, 3}} {
+		proof := newTestRangeProof(t, values)
+		isValid, err := proof.Verify()
+		assert.Equal(t, nil, err, fmt.Errorf("Verify error: %v", err))
+		assert.Equal(t, true, isValid)
+
+		// the proof survives serialization.
+		tmpProof := new(RangeProof)
+		err = tmpProof.SetBytes(proof.Bytes())
+		assert.Equal(t, nil, err, fmt.Errorf("SetBytes error: %v", err))
+		isValid, err = tmpProof.Verify()
+		assert.Equal(t, nil, err, fmt.Errorf("Verify error: %v", err))
+		assert.Equal(t, true, isValid)
+	}
+
+	// the proof does not hold for other commitments.
+	proof := newTestRangeProof(t, []uint64{1000, 2000})
+	otherProof := newTestRangeProof(t, []uint64{1000, 2000})
+	proof.cmsValue[1] = otherProof.cmsValue[1]
+	isValid, err := proof.Verify()
+	assert.NotEqual(t, nil, err)
+	assert.Equal(t, false, isValid)
+
+	// tampered proofs.
+	for i, tamper := range []func(proof *RangeProof){
+		func(proof *RangeProof) { proof.tHat.Add(proof.tHat, new(crypto.Scalar).FromUint64(1)) },
+		func(proof *RangeProof) { proof.mu.Add(proof.mu, new(crypto.Scalar).FromUint64(1)) },
+		func(proof *RangeProof) { proof.a = crypto.RandomPoint() },
+		func(proof *RangeProof) {
+			proof.innerProductProof.a.Add(proof.innerProductProof.a, new(crypto.Scalar).FromUint64(1))
+		},
+		func(proof *RangeProof) { proof.innerProductProof.l[0] = crypto.RandomPoint() },
+		func(proof *RangeProof) { proof.innerProductProof.l = proof.innerProductProof.l[1:] },
+	} {
+		proof := newTestRangeProof(t, []uint64{1000, 2000})
+		tamper(proof)
+		isValid, err := proof.Verify()
+		assert.NotEqual(t, nil, err, fmt.Errorf("tampered proof %v", i))
+		assert.Equal(t, false, isValid, fmt.Errorf("tampered proof %v", i))
+	}
+
+	// placeholder proofs are not valid.
+	isValid, err = NewPlaceholderRangeProof(proof.cmsValue).Verify()
+	assert.NotEqual(t, nil, err)
+	assert.Equal(t, false, isValid)
+}
@@ -209,3 +209,61 @@ func (wit InnerProductWitness) Prove(GParam []*crypto.Point, HParam []*crypto.Po
 
 	return proof, nil
 }
+
+// Verify checks an InnerProductProof against the given generators, i.e, that the prover knows two vectors a, b such
+// that proof.p = <a, GParam> + <b, HParam> + <a, b> * uParam. hashCache must be the one passed to Prove.
+func (proof InnerProductProof) Verify(GParam []*crypto.Point, HParam []*crypto.Point, uParam *crypto.Point, hashCache []byte) bool {
+	N := len(GParam)
+	if N == 0 || len(HParam) != N || proof.p == nil || proof.a == nil || proof.b == nil {
+		return false
+	}
+	numRounds := 0
+	for n := N; n > 1; n /= 2 {
+		if n%2 != 0 {
+			return false
+		}
+		numRounds++
+	}
+	if len(proof.l) != numRounds || len(proof.r) != numRounds {
+		return false
+	}
+
+	p := new(crypto.Point).Set(proof.p)
+	G := make([]*crypto.Point, N)
+	H := make([]*crypto.Point, N)
+	for i := range G {
+		G[i] = new(crypto.Point).Set(GParam[i])
+		H[i] = new(crypto.Point).Set(HParam[i])
+	}
+
+	for i := 0; i < numRounds; i++ {
+		nPrime := N / 2
+		x := generateChallenge(hashCache, []*crypto.Point{proof.l[i], proof.r[i]})
+		hashCache = new(crypto.Scalar).Set(x).ToBytesS()
+
+		xInverse := new(crypto.Scalar).Invert(x)
+		xSquare := new(crypto.Scalar).Mul(x, x)
+		xSquareInverse := new(crypto.Scalar).Mul(xInverse, xInverse)
+
+		// the same folding as in Prove
+		GPrime := make([]*crypto.Point, nPrime)
+		HPrime := make([]*crypto.Point, nPrime)
+		for j := range GPrime {
+			GPrime[j] = new(crypto.Point).AddPedersen(xInverse, G[j], x, G[j+nPrime])
+			HPrime[j] = new(crypto.Point).AddPedersen(x, H[j], xInverse, H[j+nPrime])
+		}
+		PPrime := new(crypto.Point).AddPedersen(xSquare, proof.l[i], xSquareInverse, proof.r[i])
+		PPrime.Add(PPrime, p)
+
+		p = PPrime
+		G = GPrime
+		H = HPrime
+		N = nPrime
+	}
+
+	c := new(crypto.Scalar).Mul(proof.a, proof.b)
+	rightPoint := new(crypto.Point).AddPedersen(proof.a, G[0], proof.b, H[0])
+	rightPoint.Add(rightPoint, new(crypto.Point).ScalarMult(uParam, c))
+
+	return crypto.IsPointEqual(rightPoint, p)
+}
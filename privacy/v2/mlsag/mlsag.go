@@ -1,9 +1,11 @@
 package mlsag
 
 import (
+	"bytes"
 	"fmt"
 	"github.com/incognitochain/go-incognito-sdk-v2/common"
 	"github.com/incognitochain/go-incognito-sdk-v2/crypto"
+	C25519 "github.com/incognitochain/go-incognito-sdk-v2/crypto/curve25519"
 )
 
 // Ring represents a ring of public keys used in the MLSAG signature scheme.
@@ -142,6 +144,59 @@ func (ml *Mlsag) Sign(message []byte) (*Sig, error) {
 	}, nil
 }
 
+// Verify checks if sig is a valid MLSAG signature of the given message on the Ring K.
+// The key images of sig must have been set (see Sig.SetKeyImages).
+func Verify(sig *Sig, K *Ring, message []byte) (bool, error) {
+	if sig == nil || K == nil {
+		return false, fmt.Errorf("cannot mlsag verify: nil signature or ring")
+	}
+	if len(message) != common.HashSize {
+		return false, fmt.Errorf("cannot mlsag verify the message because its length is not 32, maybe it has not been hashed")
+	}
+	message32byte := [32]byte{}
+	copy(message32byte[:], message)
+
+	if !verifyKeyImages(sig.keyImages) {
+		return false, nil
+	}
+	return verifyRing(sig, K, message32byte)
+}
+
+// verifyKeyImages checks that all key images are non-nil and lie in the prime-order subgroup.
+func verifyKeyImages(keyImages []*crypto.Point) bool {
+	curveOrder := new(crypto.Scalar).SetKeyUnsafe(&C25519.L)
+	for _, keyImage := range keyImages {
+		if keyImage == nil {
+			return false
+		}
+		if !new(crypto.Point).ScalarMult(keyImage, curveOrder).IsIdentity() {
+			return false
+		}
+	}
+	return true
+}
+
+// verifyRing re-computes the challenges of sig around the Ring K, and checks that they close the loop.
+func verifyRing(sig *Sig, K *Ring, message [common.HashSize]byte) (bool, error) {
+	if sig.c == nil {
+		return false, fmt.Errorf("mlsag signature has no challenge")
+	}
+	if len(K.keys) != len(sig.r) {
+		return false, fmt.Errorf("malformed ring: ring size %v, signature size %v", len(K.keys), len(sig.r))
+	}
+
+	c := sig.c
+	for i := 0; i < len(sig.r); i += 1 {
+		nextC, err := calculateNextC(message, sig.r[i], c, K.keys[i], sig.keyImages)
+		if err != nil {
+			return false, err
+		}
+		c = nextC
+	}
+
+	return bytes.Equal(c.ToBytesS(), sig.c.ToBytesS()), nil
+}
+
 // parsePublicKey parses public key from private key.
 func parsePublicKey(privateKey *crypto.Scalar, isLast bool) *crypto.Point {
 	// isLast will commit to random base G
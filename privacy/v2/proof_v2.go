@@ -331,8 +331,17 @@ func (proof *ProofV2) IsConfidentialAsset() (bool, error) {
 	return false, fmt.Errorf("error : TX contains both confidential asset & non-CA coins")
 }
 
+// IsEstimationOnly checks if the proof was created without range proofs (i.e, for estimation purposes only).
+// Such a proof must never be submitted to the network.
+func (proof *ProofV2) IsEstimationOnly() bool {
+	return proof.rangeProof != nil && proof.rangeProof.IsPlaceholder()
+}
+
 // Prove returns a ProofV2 based on the given input coins, output coins, shared secrets, etc.
-func Prove(inputCoins []coin.PlainCoin, outputCoins []*coin.CoinV2, sharedSecrets []*crypto.Point, hasConfidentialAsset bool, paymentInfo []*key.PaymentInfo) (*ProofV2, error) {
+//
+// If skipRangeProof is set to true, the range proof is replaced by a placeholder of the same size. The resulting proof
+// is structurally valid but can only be used for size/fee estimation.
+func Prove(inputCoins []coin.PlainCoin, outputCoins []*coin.CoinV2, sharedSecrets []*crypto.Point, hasConfidentialAsset bool, paymentInfo []*key.PaymentInfo, skipRangeProof ...bool) (*ProofV2, error) {
 	var err error
 
 	proof := new(ProofV2)
@@ -355,7 +364,13 @@ func Prove(inputCoins []coin.PlainCoin, outputCoins []*coin.CoinV2, sharedSecret
 
 	wit := new(bulletproofs.Witness)
 	wit.Set(outputValues, outputRands)
-	if hasConfidentialAsset {
+	if len(skipRangeProof) > 0 && skipRangeProof[0] {
+		outputCommitments := make([]*crypto.Point, n)
+		for i := 0; i < n; i += 1 {
+			outputCommitments[i] = outputCoins[i].GetCommitment()
+		}
+		proof.rangeProof = bulletproofs.NewPlaceholderRangeProof(outputCommitments)
+	} else if hasConfidentialAsset {
 		blinders := make([]*crypto.Scalar, len(sharedSecrets))
 		for i := range sharedSecrets {
 			if sharedSecrets[i] == nil {
@@ -10,12 +10,18 @@ import (
 )
 
 // RPCError represents an error that is used as a part of a JSON-RPC JsonResponse
-// object.
+// object. It is also returned by RPC queries failing with a non-200 HTTP status, in which case HTTPStatus is set.
 type RPCError struct {
 	Code       int    `json:"Code,omitempty"`
 	Message    string `json:"Message,omitempty"`
 	StackTrace string `json:"StackTrace"`
 	Err        error  `json:"Err"`
+
+	// Method is the name of the RPC method which failed, if known.
+	Method string `json:"-"`
+
+	// HTTPStatus is the HTTP status code of a failed query, or 0 if the remote node returned a JSON-RPC error.
+	HTTPStatus int `json:"-"`
 }
 
 // JsonRequest represents a JSON-RPC request.
@@ -47,13 +53,16 @@ func OldParseResponse(respondInBytes []byte) (*JsonResponse, error) {
 	}
 
 	if respond.Error != nil {
-		return nil, fmt.Errorf("RPC returns an error: %v", respond.Error)
+		respond.Error.Method = respond.Method
+		return nil, respond.Error
 	}
 
 	return &respond, nil
 }
 
 // ParseResponse parses a JSON-RPC response to val.
+//
+// If the response carries a JSON-RPC error, the returned error is the corresponding *RPCError (see AsRPCError).
 func ParseResponse(respondInBytes []byte, val interface{}) error {
 	var respond JsonResponse
 	err := json.Unmarshal(respondInBytes, &respond)
@@ -66,7 +75,8 @@ func ParseResponse(respondInBytes []byte, val interface{}) error {
 	}
 
 	if respond.Error != nil {
-		return fmt.Errorf("RPC returns an error: %v", respond.Error)
+		respond.Error.Method = respond.Method
+		return respond.Error
 	}
 
 	if val == nil {
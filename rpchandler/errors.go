@@ -0,0 +1,95 @@
+package rpchandler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// UnexpectedErrorCode is the generic error code returned by Incognito nodes, e.g. when a requested object
+	// (a burn proof, a transaction, etc.) cannot be found.
+	UnexpectedErrorCode = -1
+
+	// InvalidParamsErrorCode is the JSON-RPC error code returned when the parameters of a query are invalid.
+	InvalidParamsErrorCode = -32602
+
+	// MethodNotFoundErrorCode is the JSON-RPC error code returned when the requested method does not exist.
+	MethodNotFoundErrorCode = -32601
+
+	// LimitExceededErrorCode is the JSON-RPC error code returned by rate-limiting nodes and gateways.
+	LimitExceededErrorCode = -32005
+)
+
+// Error implements the error interface for an RPCError.
+func (e *RPCError) Error() string {
+	res := "RPC returns an error"
+	if e.Method != "" {
+		res += fmt.Sprintf(" (method %v)", e.Method)
+	}
+	if e.HTTPStatus != 0 {
+		return fmt.Sprintf("%v: HTTP status %v", res, e.Message)
+	}
+	msg := e.Message
+	if msg == "" && e.Err != nil {
+		msg = e.Err.Error()
+	}
+	res = fmt.Sprintf("%v: code %v, message %v", res, e.Code, msg)
+	if e.StackTrace != "" {
+		res += fmt.Sprintf(", stack trace %v", e.StackTrace)
+	}
+	return res
+}
+
+// AsRPCError returns the RPCError carried by err, if any. Errors wrapped with github.com/pkg/errors are unwrapped.
+func AsRPCError(err error) (*RPCError, bool) {
+	if err == nil {
+		return nil, false
+	}
+	rpcErr, ok := errors.Cause(err).(*RPCError)
+	return rpcErr, ok
+}
+
+// IsMethodNotFound checks if err is an RPCError telling that the remote node does not support the requested method.
+func IsMethodNotFound(err error) bool {
+	rpcErr, ok := AsRPCError(err)
+	return ok && (rpcErr.Code == MethodNotFoundErrorCode || rpcErr.HTTPStatus == http.StatusNotFound)
+}
+
+// IsRateLimited checks if err is an RPCError telling that the remote node (or a gateway in front of it) rejected the
+// query because too many queries were sent.
+func IsRateLimited(err error) bool {
+	rpcErr, ok := AsRPCError(err)
+	return ok && (rpcErr.Code == LimitExceededErrorCode || rpcErr.HTTPStatus == http.StatusTooManyRequests)
+}
+
+// IsTransient checks if err is a failure that may not happen again if the same query is retried later, i.e, a
+// transport error (a timeout, a refused or reset connection, a truncated response), a rate limit, or a 5xx HTTP
+// status. Application errors returned by the remote node, and queries aborted by their context, are not transient.
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	cause := errors.Cause(err)
+	if cause == context.Canceled || cause == context.DeadlineExceeded {
+		return false
+	}
+	if urlErr, ok := cause.(*url.Error); ok {
+		if urlErr.Err == context.Canceled || urlErr.Err == context.DeadlineExceeded {
+			return false
+		}
+	}
+	if rpcErr, ok := cause.(*RPCError); ok {
+		return rpcErr.HTTPStatus >= http.StatusInternalServerError || IsRateLimited(rpcErr)
+	}
+	if cause == io.ErrUnexpectedEOF {
+		return true
+	}
+	_, ok := cause.(net.Error)
+	return ok
+}
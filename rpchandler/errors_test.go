@@ -0,0 +1,79 @@
+package rpchandler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseResponse_RPCError(t *testing.T) {
+	resp := []byte(`{"Id":1,"Result":null,"Error":{"Code":-32601,"Message":"Method not found","StackTrace":"trace"},"Method":"foo","Jsonrpc":"1.0"}`)
+	err := ParseResponse(resp, nil)
+	assert.NotEqual(t, nil, err)
+
+	rpcErr, ok := AsRPCError(err)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, MethodNotFoundErrorCode, rpcErr.Code)
+	assert.Equal(t, "Method not found", rpcErr.Message)
+	assert.Equal(t, "foo", rpcErr.Method)
+	assert.Contains(t, err.Error(), "Method not found")
+	assert.Equal(t, true, IsMethodNotFound(err))
+	assert.Equal(t, false, IsRateLimited(err))
+	assert.Equal(t, false, IsTransient(err))
+
+	_, err = OldParseResponse(resp)
+	assert.Equal(t, true, IsMethodNotFound(err))
+
+	var res int
+	err = ParseResponse([]byte(`{"Id":1,"Result":10,"Error":null}`), &res)
+	assert.Equal(t, nil, err, fmt.Errorf("ParseResponse error: %v", err))
+	assert.Equal(t, 10, res)
+	_, ok = AsRPCError(err)
+	assert.Equal(t, false, ok)
+}
+
+type testTimeoutError struct{}
+
+func (testTimeoutError) Error() string   { return "i/o timeout" }
+func (testTimeoutError) Timeout() bool   { return true }
+func (testTimeoutError) Temporary() bool { return true }
+
+func TestRPCErrorHelpers(t *testing.T) {
+	rateLimited := &RPCError{Code: LimitExceededErrorCode, Message: "limit exceeded"}
+	tooManyRequests := &RPCError{Message: "429 Too Many Requests", HTTPStatus: 429}
+	unavailable := &RPCError{Message: "503 Service Unavailable", HTTPStatus: 503}
+	notFound := &RPCError{Message: "404 Not Found", HTTPStatus: 404}
+	appErr := &RPCError{Code: -1000, Message: "reject transaction"}
+
+	tcs := []struct {
+		err            error
+		methodNotFound bool
+		rateLimited    bool
+		transient      bool
+	}{
+		{rateLimited, false, true, true},
+		{errors.Wrap(tooManyRequests, "wrapped"), false, true, true},
+		{unavailable, false, false, true},
+		{notFound, true, false, false},
+		{appErr, false, false, false},
+		{testTimeoutError{}, false, false, true},
+		{&url.Error{Op: "Post", URL: "http://127.0.0.1:1", Err: &net.OpError{Op: "dial", Err: fmt.Errorf("connection refused")}}, false, false, true},
+		{&url.Error{Op: "Post", URL: "http://127.0.0.1:1", Err: context.Canceled}, false, false, false},
+		{errors.Wrap(io.ErrUnexpectedEOF, "read body"), false, false, true},
+		{&RPCError{Message: "500 Internal Server Error", HTTPStatus: 500}, false, false, true},
+		{errors.Wrap(context.DeadlineExceeded, "aborted"), false, false, false},
+		{fmt.Errorf("some error"), false, false, false},
+		{nil, false, false, false},
+	}
+	for i, tc := range tcs {
+		assert.Equal(t, tc.methodNotFound, IsMethodNotFound(tc.err), fmt.Sprintf("test case %v", i))
+		assert.Equal(t, tc.rateLimited, IsRateLimited(tc.err), fmt.Sprintf("test case %v", i))
+		assert.Equal(t, tc.transient, IsTransient(tc.err), fmt.Sprintf("test case %v", i))
+	}
+}
@@ -48,4 +48,5 @@ type BeaconBestState struct {
 	CommitteeEngineVersion                 uint                        `json:"CommitteeEngineVersion"`
 	NumberOfMissingSignature               map[string]MissingSignature `json:"MissingSignature"`        // lock sync.RWMutex
 	MissingSignaturePenalty                map[string]Penalty          `json:"MissingSignaturePenalty"` // lock sync.RWMutex
+	TriggeredFeature                       map[string]uint64           `json:"TriggeredFeature"`        // feature name => beacon height at which it was triggered
 }
@@ -0,0 +1,18 @@
+package jsonresult
+
+// BeaconBlockResult describes a beacon block returned by the `retrievebeaconblockbyheight` RPC.
+type BeaconBlockResult struct {
+	Hash              string     `json:"Hash"`
+	Height            uint64     `json:"Height"`
+	BlockProducer     string     `json:"BlockProducer"`
+	ValidationData    string     `json:"ValidationData"`
+	Confirmations     int64      `json:"Confirmations"`
+	Time              int64      `json:"Time"`
+	Version           int        `json:"Version"`
+	Epoch             uint64     `json:"Epoch"`
+	Round             int        `json:"Round"`
+	Size              uint64     `json:"Size"`
+	PreviousBlockHash string     `json:"PreviousBlockHash"`
+	NextBlockHash     string     `json:"NextBlockHash"`
+	Instructions      [][]string `json:"Instructions"`
+}
@@ -1,5 +1,15 @@
 package jsonresult
 
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/internal/safemath"
+	"github.com/incognitochain/go-incognito-sdk-v2/key"
+)
+
 // InstructionProof describes the proof of a instruction in the beacon chain.
 type InstructionProof struct {
 	Instruction  string // Hex-encoded swap inst
@@ -20,3 +30,180 @@ type InstructionProof struct {
 	BridgeSigs           []string
 	BridgeSigIndices     []int `json:"BridgeSigIdxs"`
 }
+
+// BurnProofArgs holds the arguments of an InstructionProof in the layout expected by the Incognito vault contracts
+// on EVM networks. Each two-element array holds the beacon part at index 0, and the bridge part at index 1.
+type BurnProofArgs struct {
+	Instruction []byte
+	Heights     [2]*big.Int
+
+	InstPaths       [2][][32]byte
+	InstPathIsLefts [2][]bool
+	InstRoots       [2][32]byte
+	BlkData         [2][32]byte
+	SigIndices      [2][]*big.Int
+	SigVs           [2][]uint8
+	SigRs           [2][][32]byte
+	SigSs           [2][][32]byte
+}
+
+// ToContractArgs unpacks an InstructionProof into the ABI-ready arguments of a vault contract. Unlike a plain
+// conversion, it returns an error if any of the fields is malformed (e.g, not hex-encoded, of wrong length, or with
+// inconsistent numbers of elements).
+func (p *InstructionProof) ToContractArgs() (*BurnProofArgs, error) {
+	inst, err := decodeProofHex(p.Instruction)
+	if err != nil {
+		return nil, fmt.Errorf("invalid instruction: %v", err)
+	}
+	if len(inst) == 0 {
+		return nil, fmt.Errorf("empty instruction")
+	}
+
+	res := &BurnProofArgs{Instruction: inst}
+	parts := [2]struct {
+		name       string
+		height     string
+		path       []string
+		pathIsLeft []bool
+		root       string
+		blkData    string
+		sigs       []string
+		sigIndices []int
+	}{
+		{"beacon", p.BeaconHeight, p.BeaconInstPath, p.BeaconInstPathIsLeft, p.BeaconInstRoot, p.BeaconBlkData, p.BeaconSigs, p.BeaconSigIndices},
+		{"bridge", p.BridgeHeight, p.BridgeInstPath, p.BridgeInstPathIsLeft, p.BridgeInstRoot, p.BridgeBlkData, p.BridgeSigs, p.BridgeSigIndices},
+	}
+	for i, part := range parts {
+		height, ok := new(big.Int).SetString(strings.TrimPrefix(part.height, "0x"), 16)
+		if !ok {
+			return nil, fmt.Errorf("invalid %v height %v", part.name, part.height)
+		}
+		res.Heights[i] = height
+
+		if len(part.path) != len(part.pathIsLeft) {
+			return nil, fmt.Errorf("%v path has %v nodes but %v directions", part.name, len(part.path), len(part.pathIsLeft))
+		}
+		res.InstPaths[i] = make([][32]byte, len(part.path))
+		for j, node := range part.path {
+			res.InstPaths[i][j], err = decodeProofHex32(node)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %v path node #%v: %v", part.name, j, err)
+			}
+		}
+		res.InstPathIsLefts[i] = append([]bool{}, part.pathIsLeft...)
+
+		res.InstRoots[i], err = decodeProofHex32(part.root)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %v instruction root: %v", part.name, err)
+		}
+		res.BlkData[i], err = decodeProofHex32(part.blkData)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %v block data: %v", part.name, err)
+		}
+
+		if len(part.sigs) != len(part.sigIndices) {
+			return nil, fmt.Errorf("%v proof has %v signatures but %v signer indices", part.name, len(part.sigs), len(part.sigIndices))
+		}
+		res.SigIndices[i] = make([]*big.Int, len(part.sigIndices))
+		res.SigVs[i] = make([]uint8, len(part.sigs))
+		res.SigRs[i] = make([][32]byte, len(part.sigs))
+		res.SigSs[i] = make([][32]byte, len(part.sigs))
+		for j, sig := range part.sigs {
+			v, r, s, err := key.DecodeECDSASig(sig)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %v signature #%v: %v", part.name, j, err)
+			}
+			res.SigVs[i][j] = v
+			copy(res.SigRs[i][j][:], r)
+			copy(res.SigSs[i][j][:], s)
+			res.SigIndices[i][j] = big.NewInt(int64(part.sigIndices[j]))
+		}
+	}
+
+	return res, nil
+}
+
+// decodeProofHex decodes a hex-encoded field of an InstructionProof, with or without the "0x" prefix.
+func decodeProofHex(s string) ([]byte, error) {
+	return hex.DecodeString(strings.TrimPrefix(s, "0x"))
+}
+
+// decodeProofHex32 decodes a hex-encoded 32-byte field of an InstructionProof.
+func decodeProofHex32(s string) ([32]byte, error) {
+	var res [32]byte
+	b, err := decodeProofHex(s)
+	if err != nil {
+		return res, err
+	}
+	if len(b) != len(res) {
+		return res, fmt.Errorf("expected %v bytes, got %v", len(res), len(b))
+	}
+	copy(res[:], b)
+
+	return res, nil
+}
+
+// UnifiedShieldStatus is the status code of a shielding request into a unified token (i.e, via the bridge aggregator).
+type UnifiedShieldStatus int
+
+const (
+	// UnifiedShieldPending indicates that the shielding request has not been processed by the beacon chain yet.
+	// This value is never reported by the node, it is used when the node has no status for the request.
+	UnifiedShieldPending UnifiedShieldStatus = -1
+
+	// UnifiedShieldRejected indicates that the shielding request has been rejected.
+	UnifiedShieldRejected UnifiedShieldStatus = 0
+
+	// UnifiedShieldAccepted indicates that the shielding request has been accepted.
+	UnifiedShieldAccepted UnifiedShieldStatus = 1
+)
+
+// String returns the human-readable name of a UnifiedShieldStatus.
+func (s UnifiedShieldStatus) String() string {
+	switch s {
+	case UnifiedShieldPending:
+		return "pending"
+	case UnifiedShieldRejected:
+		return "rejected"
+	case UnifiedShieldAccepted:
+		return "accepted"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(s))
+	}
+}
+
+// UnifiedShieldData describes a shielded amount of a unified shielding request.
+type UnifiedShieldData struct {
+	// Amount is the shielded amount (in unified token) of an external deposit.
+	Amount uint64 `json:"Amount"`
+
+	// Reward is the additional amount (in unified token) rewarded to the shielder by the bridge aggregator.
+	Reward uint64 `json:"Reward"`
+}
+
+// UnifiedShieldStatusDetail is the typed form of the status payload of a unified shielding request.
+type UnifiedShieldStatusDetail struct {
+	// Status is the status of the shielding request.
+	Status UnifiedShieldStatus `json:"Status"`
+
+	// Data holds the shielded amounts, one for each deposit proof of the request. It is empty unless accepted.
+	Data []UnifiedShieldData `json:"Data,omitempty"`
+
+	// ErrorCode is the reason of a rejection, as reported by the node.
+	ErrorCode int `json:"ErrorCode,omitempty"`
+}
+
+// MintedAmount returns the total amount of unified token minted for the shielding request, including rewards. It
+// returns an error if the total exceeds math.MaxUint64.
+func (d UnifiedShieldStatusDetail) MintedAmount() (uint64, error) {
+	res := uint64(0)
+	for _, data := range d.Data {
+		var err error
+		res, err = safemath.SumUint64(res, data.Amount, data.Reward)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return res, nil
+}
@@ -1,6 +1,7 @@
 package jsonresult
 
 import (
+	"fmt"
 	"math/big"
 
 	"github.com/incognitochain/go-incognito-sdk-v2/common"
@@ -62,7 +63,10 @@ func (s CurrentPdexState) Clone() *CurrentPdexState {
 		}
 	}
 
-	params := s.Params.Clone()
+	var params *Pdexv3Params
+	if s.Params != nil {
+		params = s.Params.Clone()
+	}
 
 	var stakingPoolStates map[string]*Pdexv3StakingPoolState
 	if s.StakingPoolStates != nil {
@@ -306,6 +310,9 @@ func (o Pdexv3Order) Clone() *Pdexv3Order {
 	}
 }
 
+// BPS is the denominator of rates expressed in basis points (e.g, a fee rate of 30 BPS is 0.3%).
+const BPS = 10000
+
 type Pdexv3Params struct {
 	DefaultFeeRateBPS               uint            // the default value if fee rate is not specific in FeeRateBPS (default 0.3% ~ 30 BPS)
 	FeeRateBPS                      map[string]uint // map: pool ID -> fee rate (0.1% ~ 10 BPS)
@@ -322,6 +329,15 @@ type Pdexv3Params struct {
 	OrderMiningRewardRatioBPS       map[string]uint // map: pool ID -> ratio of LOP rewards compare with LP rewards (0.1% ~ 10 BPS)
 }
 
+// GetFeeRateBPS returns the trading fee rate (in BPS) of the given pool: the pool-specific rate in FeeRateBPS if there
+// is one, or DefaultFeeRateBPS otherwise.
+func (p Pdexv3Params) GetFeeRateBPS(poolID string) uint {
+	if feeRate, ok := p.FeeRateBPS[poolID]; ok {
+		return feeRate
+	}
+	return p.DefaultFeeRateBPS
+}
+
 // Clone returns a cloned version of a Pdexv3Params.
 func (p Pdexv3Params) Clone() *Pdexv3Params {
 	result := &Pdexv3Params{}
@@ -423,6 +439,59 @@ type DEXTradeStatus struct {
 	TokenToBuy string `json:"TokenToBuy"`
 }
 
+// TradeStatus is the status code of a pDEX v3 trade as reported by the beacon chain.
+type TradeStatus int
+
+const (
+	// TradeRefunded indicates that the trade request has been refunded.
+	TradeRefunded TradeStatus = 0
+
+	// TradeAccepted indicates that the trade request has been accepted.
+	TradeAccepted TradeStatus = 1
+)
+
+// String returns the human-readable name of a TradeStatus.
+func (s TradeStatus) String() string {
+	switch s {
+	case TradeRefunded:
+		return "refunded"
+	case TradeAccepted:
+		return "accepted"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(s))
+	}
+}
+
+// TradeStatusDetail is the typed form of the full status payload of a pDEX v3 trade.
+type TradeStatusDetail struct {
+	// Status is the status of the trade.
+	Status TradeStatus `json:"Status"`
+
+	// ReceivedAmount is the amount of TokenToBuy received by the trader (0 if the trade is refunded).
+	ReceivedAmount uint64 `json:"BuyAmount"`
+
+	// TokenToBuy is the buying tokenId.
+	TokenToBuy string `json:"TokenToBuy"`
+
+	// RefundAmount is the amount returned to the trader if the trade is refunded.
+	// It is only filled if the node includes it in the status payload.
+	RefundAmount uint64 `json:"RefundAmount,omitempty"`
+
+	// BeaconHeight is the beacon height at which the trade was processed.
+	// It is only filled if the node includes it in the status payload.
+	BeaconHeight uint64 `json:"BeaconHeight,omitempty"`
+}
+
+// IsAccepted checks if the trade has been accepted.
+func (d TradeStatusDetail) IsAccepted() bool {
+	return d.Status == TradeAccepted
+}
+
+// IsRefunded checks if the trade has been refunded.
+func (d TradeStatusDetail) IsRefunded() bool {
+	return d.Status == TradeRefunded
+}
+
 // DEXAddLiquidityStatus represents the status of a pDEX v3 liquidity contribution.
 type DEXAddLiquidityStatus struct {
 	// Status represents the status of the transaction, and should be understood as follows:
@@ -0,0 +1,20 @@
+package jsonresult
+
+// ShardBlockResult describes a shard block returned by the `retrieveblock` RPC with verbosity "1".
+type ShardBlockResult struct {
+	Hash              string   `json:"Hash"`
+	ShardID           byte     `json:"ShardID"`
+	Height            uint64   `json:"Height"`
+	Confirmations     int64    `json:"Confirmations"`
+	Version           int      `json:"Version"`
+	TxRoot            string   `json:"TxRoot"`
+	Time              int64    `json:"Time"`
+	PreviousBlockHash string   `json:"PreviousBlockHash"`
+	NextBlockHash     string   `json:"NextBlockHash"`
+	TxHashes          []string `json:"TxHashes"`
+	BlockProducer     string   `json:"BlockProducer"`
+	ValidationData    string   `json:"ValidationData"`
+	BeaconHeight      uint64   `json:"BeaconHeight"`
+	BeaconBlockHash   string   `json:"BeaconBlockHash"`
+	Epoch             uint64   `json:"Epoch"`
+}
@@ -176,9 +176,14 @@ const (
 	createAndSendTxWithIssuingETHReq   = "createandsendtxwithissuingethreq"
 	createAndSendTxWithIssuingETHReqV2 = "createandsendtxwithissuingethreqv2"
 	checkETHHashIssued                 = "checkethhashissued"
+	checkBSCHashIssued                 = "checkbschashissued"
+	checkPLGHashIssued                 = "checkplghashissued"
+	checkFTMHashIssued                 = "checkftmhashissued"
 	getAllBridgeTokens                 = "getallbridgetokens"
 	getETHHeaderByHash                 = "getethheaderbyhash"
 	getBridgeReqWithStatus             = "getbridgereqwithstatus"
+	getBridgeAggState                  = "bridgeagg_getState"
+	getBridgeAggShieldStatus           = "bridgeaggGetStatusShield"
 
 	// Incognito -> Ethereum bridge
 	getBeaconSwapProof       = "getbeaconswapproof"
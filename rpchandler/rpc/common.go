@@ -2,9 +2,11 @@ package rpc
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler"
+	"github.com/pkg/errors"
 	"io/ioutil"
 	"log"
 	"net/http"
@@ -14,25 +16,75 @@ import (
 // RPCServer represents a RPC host server.
 type RPCServer struct {
 	url string
+
+	// the context bound to queries sent by the server; nil stands for context.Background()
+	ctx context.Context
+
+	// the policy used to retry failed read-only queries; nil stands for DefaultRetryPolicy
+	retryPolicy *RetryPolicy
+
+	// the endpoints of a server created by NewRPCServerWithEndpoints; nil if the server only targets url
+	endpoints *endpointPool
 }
 
-// NewRPCServer creates a new RPCServer pointing to the given url.
-func NewRPCServer(url string) *RPCServer {
-	return &RPCServer{url: url}
+// NewRPCServer creates a new RPCServer pointing to the given url. Failed read-only queries are retried according to
+// DefaultRetryPolicy, unless the WithRetryPolicy option is given.
+func NewRPCServer(url string, opts ...RPCServerOption) *RPCServer {
+	server := &RPCServer{url: url}
+	for _, opt := range opts {
+		opt(server)
+	}
+	return server
 }
 
-// GetURL returns the url of a RPCServer.
+// GetURL returns the url of a RPCServer. For a server created by NewRPCServerWithEndpoints, it is the url of the
+// endpoint which answered last.
 func (server *RPCServer) GetURL() string {
+	if server.endpoints != nil {
+		return server.endpoints.lastGoodURL()
+	}
 	return server.url
 }
 
-// InitToURL points a RPCServer to a given url.
+// InitToURL points a RPCServer to a given url. The other endpoints of the server, if any, are dropped.
 func (server *RPCServer) InitToURL(url string) *RPCServer {
 	server.url = url
+	server.endpoints = nil
 	return server
 }
 
+// WithContext returns a shallow copy of a RPCServer whose queries are bound to the given context: an in-flight HTTP
+// request is aborted as soon as ctx is done, and the query returns an error wrapping ctx.Err().
+func (server *RPCServer) WithContext(ctx context.Context) *RPCServer {
+	if ctx == nil {
+		panic("nil context")
+	}
+	if server == nil {
+		return nil
+	}
+	res := *server
+	res.ctx = ctx
+	return &res
+}
+
+// Context returns the context bound to a RPCServer (see WithContext), or context.Background() if there is none.
+func (server *RPCServer) Context() context.Context {
+	if server == nil || server.ctx == nil {
+		return context.Background()
+	}
+	return server.ctx
+}
+
+// SendQueryWithContext is the same as SendQuery, except that the query is aborted when ctx is done.
+func (server *RPCServer) SendQueryWithContext(ctx context.Context, method string, params []interface{}) ([]byte, error) {
+	return server.WithContext(ctx).SendQuery(method, params)
+}
+
 // SendQuery sends a query to the remote server given the method and parameters.
+//
+// A query failing with a non-200 HTTP status returns an *rpchandler.RPCError carrying the status and the method.
+// Read-only queries failing with a transport error or a 5xx HTTP status are retried according to the retry policy
+// of the server (see WithRetryPolicy).
 func (server *RPCServer) SendQuery(method string, params []interface{}) ([]byte, error) {
 	if params == nil {
 		params = make([]interface{}, 0)
@@ -44,26 +96,48 @@ func (server *RPCServer) SendQuery(method string, params []interface{}) ([]byte,
 		return nil, err
 	}
 
-	return server.SendPostRequestWithQuery(string(query))
+	res, err := server.sendWithRetry(method, string(query))
+	if rpcErr, ok := err.(*rpchandler.RPCError); ok {
+		rpcErr.Method = method
+	}
+	return res, err
 }
 
-// SendPostRequestWithQuery sends a query to the remote server using the POST method.
+// SendPostRequestWithQuery sends a query to the remote server using the POST method. For a server created by
+// NewRPCServerWithEndpoints, the query fails over to the next endpoints if needed.
 func (server *RPCServer) SendPostRequestWithQuery(query string) ([]byte, error) {
 	if server == nil || len(server.url) == 0 {
 		return []byte{}, fmt.Errorf("server has not been set")
 	}
+	if server.endpoints != nil {
+		return server.endpoints.send(query, server.sendPostRequestToURL)
+	}
+	return server.sendPostRequestToURL(server.url, query)
+}
+
+// sendPostRequestToURL sends a query to the given url using the POST method.
+func (server *RPCServer) sendPostRequestToURL(url, query string) ([]byte, error) {
 	//fmt.Printf("Request: %v\n", query)
 	var jsonStr = []byte(query)
-	req, _ := http.NewRequest("POST", server.url, bytes.NewBuffer(jsonStr))
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonStr))
+	if err != nil {
+		return []byte{}, err
+	}
+	ctx := server.Context()
+	req = req.WithContext(ctx)
 	req.Header.Set("Content-Type", "application/json")
 	client := &http.Client{}
 	client.Timeout = 10 * time.Minute
 	resp, err := client.Do(req)
 	if err != nil {
+		if ctx.Err() != nil {
+			return []byte{}, errors.Wrapf(ctx.Err(), "request to %v aborted", url)
+		}
 		log.Printf("DoReq %v error: %v\n", query, err)
 		return []byte{}, err
 	} else if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("%v", resp.Status)
+		_ = resp.Body.Close()
+		return nil, &rpchandler.RPCError{Message: resp.Status, HTTPStatus: resp.StatusCode}
 	} else {
 		defer func() {
 			err := resp.Body.Close()
@@ -74,6 +148,9 @@ func (server *RPCServer) SendPostRequestWithQuery(query string) ([]byte, error)
 
 		body, err := ioutil.ReadAll(resp.Body)
 		if err != nil {
+			if ctx.Err() != nil {
+				return []byte{}, errors.Wrapf(ctx.Err(), "request to %v aborted", url)
+			}
 			log.Printf("ReadAll %v\n", err)
 			return []byte{}, err
 		}
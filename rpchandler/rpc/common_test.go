@@ -0,0 +1,201 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCServer_SendQuery_HTTPError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "slow down", http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+
+	_, err := NewRPCServer(ts.URL).SendQuery(getBeaconBestState, nil)
+	assert.NotEqual(t, nil, err)
+	rpcErr, ok := rpchandler.AsRPCError(err)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, http.StatusTooManyRequests, rpcErr.HTTPStatus)
+	assert.Equal(t, getBeaconBestState, rpcErr.Method)
+	assert.Equal(t, true, rpchandler.IsRateLimited(err))
+	assert.Equal(t, true, rpchandler.IsTransient(err))
+}
+
+func TestRPCServer_SendQuery_Retry(t *testing.T) {
+	numFailures := 2
+	numCalls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		numCalls++
+		if numCalls <= numFailures {
+			http.Error(w, "bad gateway", http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`{"Result":1,"Error":null}`))
+	}))
+	defer ts.Close()
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond}
+
+	// a read-only query is retried until it succeeds.
+	server := NewRPCServer(ts.URL, WithRetryPolicy(policy))
+	res, err := server.SendQuery(getBeaconBestState, nil)
+	assert.Equal(t, nil, err, fmt.Errorf("SendQuery error: %v", err))
+	assert.Equal(t, 3, numCalls)
+	var val int
+	err = rpchandler.ParseResponse(res, &val)
+	assert.Equal(t, nil, err, fmt.Errorf("ParseResponse error: %v", err))
+	assert.Equal(t, 1, val)
+
+	// the last error is returned when all attempts fail.
+	numCalls = 0
+	numFailures = 5
+	_, err = server.SendQuery(getBeaconBestState, nil)
+	assert.Equal(t, http.StatusBadGateway, err.(*rpchandler.RPCError).HTTPStatus)
+	assert.Equal(t, 3, numCalls)
+
+	// queries changing the state of the node are not retried.
+	numCalls = 0
+	_, err = server.SendQuery(sendRawTransaction, nil)
+	assert.NotEqual(t, nil, err)
+	assert.Equal(t, 1, numCalls)
+
+	// retrying can be disabled.
+	numCalls = 0
+	_, err = NewRPCServer(ts.URL, WithRetryPolicy(NoRetryPolicy)).SendQuery(getBeaconBestState, nil)
+	assert.NotEqual(t, nil, err)
+	assert.Equal(t, 1, numCalls)
+
+	// retrying stops at the deadline of the context.
+	numCalls = 0
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	slowPolicy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second}
+	_, err = NewRPCServer(ts.URL, WithRetryPolicy(slowPolicy)).SendQueryWithContext(ctx, getBeaconBestState, nil)
+	assert.NotEqual(t, nil, err)
+	assert.Equal(t, 1, numCalls)
+}
+
+func TestRPCServer_SendQuery_NoRetryOnApplicationError(t *testing.T) {
+	numCalls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		numCalls++
+		_, _ = w.Write([]byte(`{"Result":null,"Error":{"Code":-1000,"Message":"invalid params"}}`))
+	}))
+	defer ts.Close()
+
+	res, err := NewRPCServer(ts.URL).SendQuery(getBeaconBestState, nil)
+	assert.Equal(t, nil, err, fmt.Errorf("SendQuery error: %v", err))
+	assert.Equal(t, 1, numCalls)
+	err = rpchandler.ParseResponse(res, nil)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestIsReadOnlyMethod(t *testing.T) {
+	for _, method := range []string{getBeaconBestState, "pdexv3_getState", "eth_getTransactionReceipt", getBridgeAggShieldStatus,
+		listOutputCoins} {
+		assert.Equal(t, true, isReadOnlyMethod(method), method)
+	}
+	for _, method := range []string{sendRawTransaction, "pdexv3_txTrade", "eth_sendRawTransaction", getAndSendTxsFromFile,
+		hashToIdenticon, "listtokens", ""} {
+		assert.Equal(t, false, isReadOnlyMethod(method), method)
+	}
+}
+
+// newCountingServer is for testing purposes ONLY.
+func newCountingServer(status int, numCalls *int, mtx *sync.Mutex) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mtx.Lock()
+		*numCalls++
+		mtx.Unlock()
+		if status != http.StatusOK {
+			http.Error(w, http.StatusText(status), status)
+			return
+		}
+		_, _ = w.Write([]byte(`{"Result":1,"Error":null}`))
+	}))
+}
+
+func TestNewRPCServerWithEndpoints_PrimaryWithFallback(t *testing.T) {
+	mtx := new(sync.Mutex)
+	var numDeadCalls, numBadCalls, numGoodCalls int
+	dead := newCountingServer(http.StatusOK, &numDeadCalls, mtx)
+	dead.Close()
+	bad := newCountingServer(http.StatusBadGateway, &numBadCalls, mtx)
+	defer bad.Close()
+	good := newCountingServer(http.StatusOK, &numGoodCalls, mtx)
+	defer good.Close()
+
+	cooldown := 200 * time.Millisecond
+	server := NewRPCServerWithEndpoints([]string{dead.URL, bad.URL, good.URL},
+		WithRetryPolicy(NoRetryPolicy), WithEndpointCooldown(cooldown))
+	assert.Equal(t, dead.URL, server.GetURL())
+
+	// the query fails over to the last endpoint.
+	_, err := server.SendQuery(getBeaconBestState, nil)
+	assert.Equal(t, nil, err, fmt.Errorf("SendQuery error: %v", err))
+	assert.Equal(t, 1, numBadCalls)
+	assert.Equal(t, 1, numGoodCalls)
+	assert.Equal(t, good.URL, server.GetURL())
+
+	// the failing endpoints are quarantined.
+	_, err = server.WithContext(context.Background()).SendQuery(getBeaconBestState, nil)
+	assert.Equal(t, nil, err, fmt.Errorf("SendQuery error: %v", err))
+	assert.Equal(t, 1, numBadCalls)
+	assert.Equal(t, 2, numGoodCalls)
+
+	// after the cooldown, the endpoint which answered last is still tried first.
+	time.Sleep(cooldown)
+	_, err = server.SendQuery(getBeaconBestState, nil)
+	assert.Equal(t, nil, err, fmt.Errorf("SendQuery error: %v", err))
+	assert.Equal(t, 1, numBadCalls)
+	assert.Equal(t, 3, numGoodCalls)
+
+	// the other endpoints are tried again, in order, when it fails.
+	good.Close()
+	_, err = server.SendQuery(getBeaconBestState, nil)
+	assert.NotEqual(t, nil, err)
+	assert.Equal(t, 2, numBadCalls)
+	assert.Equal(t, 3, numGoodCalls)
+
+	// a query aborted by its context does not fail over.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = NewRPCServerWithEndpoints([]string{good.URL, bad.URL}).SendQueryWithContext(ctx, getBeaconBestState, nil)
+	assert.NotEqual(t, nil, err)
+	assert.Equal(t, 2, numBadCalls)
+
+	// all endpoints failing returns the last error.
+	_, err = NewRPCServerWithEndpoints([]string{dead.URL, bad.URL}, WithRetryPolicy(NoRetryPolicy)).SendQuery(getBeaconBestState, nil)
+	assert.Equal(t, true, rpchandler.IsTransient(err))
+
+	_, err = NewRPCServerWithEndpoints(nil).SendQuery(getBeaconBestState, nil)
+	assert.NotEqual(t, nil, err)
+}
+
+func TestNewRPCServerWithEndpoints_RoundRobin(t *testing.T) {
+	mtx := new(sync.Mutex)
+	numCalls := make([]int, 3)
+	urls := make([]string, 0)
+	for i := range numCalls {
+		ts := newCountingServer(http.StatusOK, &numCalls[i], mtx)
+		defer ts.Close()
+		urls = append(urls, ts.URL)
+	}
+
+	server := NewRPCServerWithEndpoints(urls, WithFailoverStrategy(RoundRobin))
+	numRounds := 4
+	for i := 0; i < numRounds*len(urls); i++ {
+		_, err := server.SendQuery(getBeaconBestState, nil)
+		assert.Equal(t, nil, err, fmt.Errorf("SendQuery error: %v", err))
+	}
+	for i := range numCalls {
+		assert.Equal(t, numRounds, numCalls[i])
+	}
+}
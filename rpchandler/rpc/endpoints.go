@@ -0,0 +1,162 @@
+package rpc
+
+import (
+	"sync"
+	"time"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler"
+)
+
+// FailoverStrategy decides the order in which the endpoints of a RPCServer created by NewRPCServerWithEndpoints are
+// tried.
+type FailoverStrategy int
+
+const (
+	// PrimaryWithFallback sends each query to the endpoint which answered last (initially, the first one in the
+	// list), falling back to the next healthy ones if it fails.
+	PrimaryWithFallback FailoverStrategy = iota
+
+	// RoundRobin spreads queries over the healthy endpoints in turn, falling back to the next ones if one fails.
+	RoundRobin
+)
+
+// DefaultEndpointCooldown is the default duration during which an endpoint which failed to answer a query is
+// quarantined, i.e, only tried after all healthy endpoints.
+const DefaultEndpointCooldown = 30 * time.Second
+
+// endpointPool keeps the health of the endpoints of a RPCServer. It is shared by the copies of the server returned by
+// WithContext.
+type endpointPool struct {
+	mtx        *sync.Mutex
+	urls       []string
+	strategy   FailoverStrategy
+	cooldown   time.Duration
+	deadUntil  []time.Time
+	lastGood   int
+	nextInTurn int
+}
+
+// WithFailoverStrategy sets the FailoverStrategy of a RPCServer created by NewRPCServerWithEndpoints. The default
+// strategy is PrimaryWithFallback.
+func WithFailoverStrategy(strategy FailoverStrategy) RPCServerOption {
+	return func(server *RPCServer) {
+		if server.endpoints != nil {
+			server.endpoints.strategy = strategy
+		}
+	}
+}
+
+// WithEndpointCooldown sets how long an endpoint of a RPCServer created by NewRPCServerWithEndpoints is quarantined
+// after failing. The default cooldown is DefaultEndpointCooldown.
+func WithEndpointCooldown(cooldown time.Duration) RPCServerOption {
+	return func(server *RPCServer) {
+		if server.endpoints != nil {
+			server.endpoints.cooldown = cooldown
+		}
+	}
+}
+
+// NewRPCServerWithEndpoints creates a new RPCServer sending queries to an ordered list of endpoints. When an endpoint
+// cannot be reached (or answers with a 5xx HTTP status), the query is sent to the next one, and the failing endpoint
+// is quarantined for a cooldown period: it is only tried again after all healthy endpoints. The endpoint which
+// answered last is remembered, returned by GetURL, and tried first for the next query.
+//
+// Since an endpoint may fail after having processed a query, callers must make sure that the queries they send can
+// be processed twice (e.g, submitting the same signed transaction twice is harmless).
+func NewRPCServerWithEndpoints(urls []string, opts ...RPCServerOption) *RPCServer {
+	if len(urls) == 0 {
+		return NewRPCServer("", opts...)
+	}
+
+	server := &RPCServer{
+		url: urls[0],
+		endpoints: &endpointPool{
+			mtx:       new(sync.Mutex),
+			urls:      append([]string{}, urls...),
+			cooldown:  DefaultEndpointCooldown,
+			deadUntil: make([]time.Time, len(urls)),
+		},
+	}
+	for _, opt := range opts {
+		opt(server)
+	}
+	return server
+}
+
+// order returns the indices of the endpoints in the order they should be tried for the next query: healthy endpoints
+// first, starting from the endpoint which answered last (or the next one in turn for RoundRobin), followed by
+// quarantined ones in the order of their recovery.
+func (pool *endpointPool) order() []int {
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+
+	start := pool.lastGood
+	if pool.strategy == RoundRobin {
+		start = pool.nextInTurn
+		pool.nextInTurn = (pool.nextInTurn + 1) % len(pool.urls)
+	}
+
+	now := time.Now()
+	healthy := make([]int, 0, len(pool.urls))
+	quarantined := make([]int, 0)
+	for i := 0; i < len(pool.urls); i++ {
+		idx := (start + i) % len(pool.urls)
+		if now.Before(pool.deadUntil[idx]) {
+			quarantined = append(quarantined, idx)
+		} else {
+			healthy = append(healthy, idx)
+		}
+	}
+	for i := 1; i < len(quarantined); i++ {
+		for j := i; j > 0 && pool.deadUntil[quarantined[j]].Before(pool.deadUntil[quarantined[j-1]]); j-- {
+			quarantined[j], quarantined[j-1] = quarantined[j-1], quarantined[j]
+		}
+	}
+
+	return append(healthy, quarantined...)
+}
+
+// markGood records that the endpoint at idx answered a query.
+func (pool *endpointPool) markGood(idx int) {
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+
+	pool.deadUntil[idx] = time.Time{}
+	pool.lastGood = idx
+}
+
+// markDead quarantines the endpoint at idx.
+func (pool *endpointPool) markDead(idx int) {
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+
+	pool.deadUntil[idx] = time.Now().Add(pool.cooldown)
+}
+
+// lastGoodURL returns the url of the endpoint which answered last (the first endpoint if none has answered yet).
+func (pool *endpointPool) lastGoodURL() string {
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+
+	return pool.urls[pool.lastGood]
+}
+
+// send sends a query to the endpoints of the pool until one of them answers, using sendToURL. The query is not
+// sent to the other endpoints if it fails with an error which is not transient (see rpchandler.IsTransient), e.g,
+// its context is done.
+func (pool *endpointPool) send(query string, sendToURL func(url, query string) ([]byte, error)) ([]byte, error) {
+	var res []byte
+	var err error
+	for _, idx := range pool.order() {
+		res, err = sendToURL(pool.urls[idx], query)
+		if err == nil {
+			pool.markGood(idx)
+			return res, nil
+		}
+		if !rpchandler.IsTransient(err) {
+			return res, err
+		}
+		pool.markDead(idx)
+	}
+	return res, err
+}
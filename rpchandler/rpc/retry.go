@@ -0,0 +1,166 @@
+package rpc
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/rpchandler"
+)
+
+// RetryPolicy describes how a RPCServer retries a read-only query failing with a transient error (see
+// rpchandler.IsTransient), e.g, a connection reset, a rate limit or a 5xx HTTP status. Application-level JSON-RPC
+// errors are never retried.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times a query is sent. A value less than 2 disables retrying.
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry. The delay doubles after each retry, up to MaxDelay, and a random
+	// jitter of up to half the delay is subtracted from it.
+	BaseDelay time.Duration
+
+	// MaxDelay is the maximum delay between two attempts. A non-positive value means no limit.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy is the retry policy of a RPCServer created without the WithRetryPolicy option.
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 3, BaseDelay: 200 * time.Millisecond, MaxDelay: 2 * time.Second}
+
+// NoRetryPolicy disables retrying.
+var NoRetryPolicy = RetryPolicy{MaxAttempts: 1}
+
+// RPCServerOption is an option of NewRPCServer.
+type RPCServerOption func(server *RPCServer)
+
+// WithRetryPolicy sets the retry policy of a RPCServer. Use NoRetryPolicy to disable retrying.
+func WithRetryPolicy(policy RetryPolicy) RPCServerOption {
+	return func(server *RPCServer) {
+		server.retryPolicy = &policy
+	}
+}
+
+// readOnlyMethods lists the RPC methods which do not change the state of the remote node, and can therefore be sent
+// several times. A method missing from this list is never retried.
+var readOnlyMethods = map[string]bool{
+	getNetworkInfo: true, getConnectionCount: true, getAllConnectedPeers: true, getAllPeers: true, getNodeRole: true,
+	getInOutMessages: true, getInOutMessageCount: true, estimateFee: true, estimateFeeV2: true,
+	estimateFeeWithEstimator: true, getActiveShards: true, getMaxShardsNumber: true, getMiningInfo: true,
+	getSyncStats: true, getRawMempool: true, getNumberOfTxsInMempool: true, getMempoolEntry: true,
+	getBeaconPoolState: true, getShardPoolState: true, getShardPoolLatestValidHeight: true, getNextCrossShard: true,
+	getShardToBeaconPoolStateV2: true, getCrossShardPoolStateV2: true, getShardPoolStateV2: true,
+	getBeaconPoolStateV2: true, getLatestBackup: true, getBestBlock: true, getBestBlockHash: true, getBlocks: true,
+	retrieveBlock: true, retrieveBlockByHeight: true, retrieveBeaconBlock: true, retrieveBeaconBlockByHeight: true,
+	getBlockChainInfo: true, getBlockCount: true, getBlockHash: true, listOutputCoins: true,
+	listOutputCoinsFromCache: true, listOutputTokens: true, getMempoolInfo: true, getPendingTxsInBlockgen: true,
+	getCandidateList: true, getCommitteeList: true, canPubkeyStake: true, getTotalTransaction: true,
+	listUnspentCustomToken: true, getBalanceCustomToken: true, getTransactionByHash: true,
+	getEncodedTransactionsByHashes: true, gettransactionhashbyreceiver: true, gettransactionhashbyreceiverv2: true,
+	gettransactionbyreceiver: true, gettransactionbyreceiverv2: true, gettransactionbyserialnumber: true,
+	gettransactionbypublickey: true, listCustomToken: true, listPrivacyCustomToken: true,
+	listPrivacyCustomTokenIDs: true, getPrivacyCustomToken: true, listPrivacyCustomTokenByShard: true,
+	getBalancePrivacyCustomToken: true, listUnspentOutputTokens: true, checkHashValue: true,
+	getListCustomTokenBalance: true, getListPrivacyCustomTokenBalance: true, getBlockHeader: true,
+	getCrossShardBlock: true, randomCommitments: true, hasSerialNumbers: true, hasSerialNumbersInMempool: true,
+	hasSnDerivators: true, listSnDerivators: true, listSerialNumbers: true, listCommitments: true,
+	listCommitmentIndices: true, decryptoutputcoinbykeyoftransaction: true, randomCommitmentsAndPublicKeys: true,
+	getOTACoinLength: true, getOTACoinsByIndices: true, handleGetConsensusInfoV3: true, getAutoStakingByHeight: true,
+	getCommitteeState: true, convertPaymentAddress: true, getCommitteeStateByShard: true, getSlashingCommittee: true,
+	getSlashingCommitteeDetail: true, getRewardAmountByEpoch: true, getShardBestState: true,
+	getShardBestStateDetail: true, getBeaconBestState: true, getBeaconBestStateDetail: true, listAccounts: true,
+	getAccount: true, getAddressesByAccount: true, listUnspentOutputCoins: true,
+	listUnspentOutputCoinsFromCache: true, getBalance: true, getBalanceByPrivatekey: true,
+	getBalanceByPaymentAddress: true, getReceivedByAccount: true, getKeySubmissionInfo: true,
+	getPublicKeyFromPaymentAddress: true, getStackingAmount: true, generateTokenID: true, checkETHHashIssued: true,
+	checkBSCHashIssued: true, checkPLGHashIssued: true, checkFTMHashIssued: true, getAllBridgeTokens: true,
+	getETHHeaderByHash: true, getBridgeReqWithStatus: true, getBridgeAggState: true, getBridgeAggShieldStatus: true,
+	getBeaconSwapProof: true, getLatestBeaconSwapProof: true, getBridgeSwapProof: true, getLatestBridgeSwapProof: true,
+	getBurnProof: true, getBSCBurnProof: true, getPRVERC20BurnProof: true, getPRVBEP20BurnProof: true,
+	getPLGBurnProof: true, getFTMBurnProof: true, getRewardAmount: true, getRewardAmountByPublicKey: true,
+	listRewardAmount: true, getChainMiningStatus: true, getPublickeyMining: true, getPublicKeyRole: true,
+	getRoleByValidatorKey: true, getIncognitoPublicKeyRole: true, getMinerRewardFromMiningKey: true,
+	getProducersBlackList: true, getProducersBlackListDetail: true, getPDEState: true, getPDEContributionStatus: true,
+	getPDEContributionStatusV2: true, getPDETradeStatus: true, getPDEWithdrawalStatus: true,
+	getPDEFeeWithdrawalStatus: true, convertPDEPrices: true, extractPDEInstsFromBeaconBlock: true, getPdexv3State: true,
+	getPdexv3ParamsModifyingStatus: true, getPdexv3ContributionStatus: true, getPdexv3WithdrawLiquidityStatus: true,
+	getPdexv3MintNftStatus: true, pdexv3GetTradeStatus: true, pdexv3GetAddOrderStatus: true,
+	pdexv3GetWithdrawOrderStatus: true, pdexv3GetStakingStatus: true, pdexv3GetUnstakingStatus: true,
+	getPdexv3EstimatedLPValue: true, getPdexv3EstimatedLPPoolReward: true, getPdexv3WithdrawalLPFeeStatus: true,
+	getPdexv3WithdrawalProtocolFeeStatus: true, getPdexv3EstimatedStakingReward: true,
+	getPdexv3EstimatedStakingPoolReward: true, getPdexv3WithdrawalStakingRewardStatus: true, getBurningAddress: true,
+	getPortalV4State: true, getPortalV4Params: true, getPortalShieldingRequestStatus: true,
+	getPortalUnShieldingRequestStatus: true, getPortalBatchUnShieldingRequestStatus: true,
+	getSignedRawTransactionByBatchID: true, getPortalReplacementFeeStatus: true, getPortalSubmitConfirmedTx: true,
+	getSignedRawReplaceFeeTransaction: true, getPortalConvertVaultTxStatus: true, getRelayingBNBHeaderState: true,
+	getRelayingBNBHeaderByBlockHeight: true, getBTCRelayingBestState: true, getBTCBlockByHash: true,
+	getLatestBNBHeaderBlockHeight: true, getBurnProofForDepositToSC: true, getBeaconPoolInfo: true,
+	getShardPoolInfo: true, getCrossShardPoolInfo: true, getAllView: true, getAllViewDetail: true,
+	getRewardFeature: true, getTotalStaker: true, getValKeyState: true, getAllTradesInMemPool: true,
+	getAllTradesByAddress: true, "bridgeaggGetBurnProof": true,
+	// EVM methods
+	"eth_blockNumber": true, "eth_call": true, "eth_chainId": true, "eth_gasPrice": true, "eth_getBalance": true,
+	"eth_getBlockByHash": true, "eth_getBlockByNumber": true, "eth_getTransactionByHash": true,
+	"eth_getTransactionCount": true, "eth_getTransactionReceipt": true,
+}
+
+// isReadOnlyMethod checks if an RPC method is safe to retry.
+func isReadOnlyMethod(method string) bool {
+	return readOnlyMethods[method]
+}
+
+// delay returns the (jittered) delay before the given retry, starting from 0.
+func (policy RetryPolicy) delay(retry int) time.Duration {
+	d := policy.BaseDelay
+	for i := 0; i < retry; i++ {
+		if policy.MaxDelay > 0 && d >= policy.MaxDelay {
+			break
+		}
+		d *= 2
+	}
+	if policy.MaxDelay > 0 && d > policy.MaxDelay {
+		d = policy.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d - time.Duration(rand.Int63n(int64(d)/2+1))
+}
+
+// getRetryPolicy returns the retry policy of a RPCServer.
+func (server *RPCServer) getRetryPolicy() RetryPolicy {
+	if server.retryPolicy == nil {
+		return DefaultRetryPolicy
+	}
+	return *server.retryPolicy
+}
+
+// sendWithRetry sends a query for the given method, retrying it according to the retry policy of the server if the
+// method is read-only. It stops retrying when the context of the server is done, or when its deadline would be
+// exceeded before the next attempt.
+func (server *RPCServer) sendWithRetry(method, query string) ([]byte, error) {
+	if server == nil || len(server.url) == 0 {
+		return server.SendPostRequestWithQuery(query)
+	}
+	policy := server.getRetryPolicy()
+	if !isReadOnlyMethod(method) {
+		policy.MaxAttempts = 1
+	}
+	ctx := server.Context()
+
+	for attempt := 1; ; attempt++ {
+		res, err := server.SendPostRequestWithQuery(query)
+		if err == nil || attempt >= policy.MaxAttempts || !rpchandler.IsTransient(err) {
+			return res, err
+		}
+
+		d := policy.delay(attempt - 1)
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(d).After(deadline) {
+			return res, err
+		}
+		timer := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return res, err
+		case <-timer.C:
+		}
+	}
+}
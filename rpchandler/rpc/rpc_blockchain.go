@@ -24,6 +24,24 @@ func (server *RPCServer) RetrieveBlock(blockHash string, verbosity string) ([]by
 	return server.SendQuery(retrieveBlock, params)
 }
 
+// RetrieveBlockByHeight returns the detail of the shard blocks at the given height.
+func (server *RPCServer) RetrieveBlockByHeight(shardID byte, height uint64, verbosity string) ([]byte, error) {
+	params := make([]interface{}, 0)
+	params = append(params, height)
+	params = append(params, shardID)
+	params = append(params, verbosity)
+
+	return server.SendQuery(retrieveBlockByHeight, params)
+}
+
+// RetrieveBeaconBlockByHeight returns the detail of the beacon blocks at the given height.
+func (server *RPCServer) RetrieveBeaconBlockByHeight(beaconHeight uint64) ([]byte, error) {
+	params := make([]interface{}, 0)
+	params = append(params, beaconHeight)
+
+	return server.SendQuery(retrieveBeaconBlockByHeight, params)
+}
+
 // GetShardBestState returns the best state of a shard chain.
 func (server *RPCServer) GetShardBestState(shardID byte) ([]byte, error) {
 	params := make([]interface{}, 0)
@@ -28,6 +28,25 @@ var EVMBurningMetadata = map[int]int{
 	FTMNetworkID: metadata.BurningFantomRequestMeta,
 }
 
+// PRVPeggingIssuingMetadata keeps track of PRV pegging issuing metadata types based on the EVM networkIDs.
+// PRV pegging is only available on the networks listed here.
+var PRVPeggingIssuingMetadata = map[int]int{
+	ETHNetworkID: metadata.IssuingPRVERC20RequestMeta,
+	BSCNetworkID: metadata.IssuingPRVBEP20RequestMeta,
+}
+
+// PRVPeggingBurningMetadata keeps track of PRV pegging burning metadata types based on the EVM networkIDs.
+// PRV pegging is only available on the networks listed here.
+var PRVPeggingBurningMetadata = map[int]int{
+	ETHNetworkID: metadata.BurningPRVERC20RequestMeta,
+	BSCNetworkID: metadata.BurningPRVBEP20RequestMeta,
+}
+
+var burnPRVPeggingProofRPCMethod = map[int]string{
+	ETHNetworkID: getPRVERC20BurnProof,
+	BSCNetworkID: getPRVBEP20BurnProof,
+}
+
 var burnProofRPCMethod = map[int]string{
 	ETHNetworkID: getBurnProof,
 	BSCNetworkID: getBSCBurnProof,
@@ -35,6 +54,13 @@ var burnProofRPCMethod = map[int]string{
 	FTMNetworkID: getFTMBurnProof,
 }
 
+var checkHashIssuedRPCMethod = map[int]string{
+	ETHNetworkID: checkETHHashIssued,
+	BSCNetworkID: checkBSCHashIssued,
+	PLGNetworkID: checkPLGHashIssued,
+	FTMNetworkID: checkFTMHashIssued,
+}
+
 // EVMNetworkNotFoundError returns an error indicating that the given EVM networkID is not supported.
 func EVMNetworkNotFoundError(evmNetworkID int) error {
 	return fmt.Errorf("EVMNetworkID %v not supported", evmNetworkID)
@@ -81,21 +107,54 @@ func (server *RPCServer) GetBurnProofForSC(txHash string) ([]byte, error) {
 }
 
 // GetBurnPRVPeggingProof retrieves the burning prv pegging proof of a transaction.
+// evmNetworkID can be one of the following:
+//	- ETHNetworkID: the Ethereum network
+//	- BSCNetworkID: the Binance Smart Chain network
+// PRV pegging is not available on the other EVM networks (see PRVPeggingBurningMetadata).
+// If set empty, evmNetworkID defaults to ETHNetworkID. NOTE that only the first value of evmNetworkID is used.
 func (server *RPCServer) GetBurnPRVPeggingProof(txHash string, evmNetworkIDs ...int) ([]byte, error) {
-	method := getPRVERC20BurnProof
+	networkID := ETHNetworkID
 	if len(evmNetworkIDs) > 0 {
-		switch evmNetworkIDs[0] {
-		case BSCNetworkID:
-			method = getPRVBEP20BurnProof
-		case PLGNetworkID, FTMNetworkID:
-			return nil, EVMNetworkNotFoundError(evmNetworkIDs[0])
-		}
+		networkID = evmNetworkIDs[0]
+	}
+
+	method, ok := burnPRVPeggingProofRPCMethod[networkID]
+	if !ok {
+		return nil, EVMNetworkNotFoundError(networkID)
 	}
 	params := make([]interface{}, 0)
 	params = append(params, txHash)
 	return server.SendQuery(method, params)
 }
 
+// CheckEVMHashIssued checks if an EVM deposit (identified by its block hash and transaction index) has been used
+// to mint tokens on the Incognito network.
+// evmNetworkID can be one of the following:
+//	- ETHNetworkID: the Ethereum network
+//	- BSCNetworkID: the Binance Smart Chain network
+//	- PLGNetworkID: the Polygon network
+//	- FTMNetworkID: the Fantom network
+// If set empty, evmNetworkID defaults to ETHNetworkID. NOTE that only the first value of evmNetworkID is used.
+func (server *RPCServer) CheckEVMHashIssued(blockHash string, txIdx uint, evmNetworkID ...int) ([]byte, error) {
+	networkID := ETHNetworkID
+	if len(evmNetworkID) > 0 {
+		networkID = evmNetworkID[0]
+	}
+
+	if _, ok := checkHashIssuedRPCMethod[networkID]; !ok {
+		return nil, EVMNetworkNotFoundError(networkID)
+	}
+	method := checkHashIssuedRPCMethod[networkID]
+
+	tmpParams := make(map[string]interface{})
+	tmpParams["BlockHash"] = blockHash
+	tmpParams["TxIndex"] = txIdx
+
+	params := make([]interface{}, 0)
+	params = append(params, tmpParams)
+	return server.SendQuery(method, params)
+}
+
 // CheckShieldStatus checks the status of a decentralized shielding transaction.
 func (server *RPCServer) CheckShieldStatus(txHash string) ([]byte, error) {
 	tmpParams := make(map[string]interface{})
@@ -106,7 +165,29 @@ func (server *RPCServer) CheckShieldStatus(txHash string) ([]byte, error) {
 	return server.SendQuery(getBridgeReqWithStatus, params)
 }
 
+// CheckUnifiedShieldStatus checks the status of a shielding transaction into a unified token (i.e, via the bridge
+// aggregator).
+func (server *RPCServer) CheckUnifiedShieldStatus(txHash string) ([]byte, error) {
+	tmpParams := make(map[string]interface{})
+	tmpParams["TxReqID"] = txHash
+
+	params := make([]interface{}, 0)
+	params = append(params, tmpParams)
+	return server.SendQuery(getBridgeAggShieldStatus, params)
+}
+
 // GetAllBridgeTokens retrieves the list of bridge tokens in the network.
 func (server *RPCServer) GetAllBridgeTokens() ([]byte, error) {
 	return server.SendQuery(getAllBridgeTokens, nil)
 }
+
+// GetBridgeAggState retrieves the state of the bridge aggregator at the given beacon height.
+// If the beacon height is set to 0, it returns the latest state.
+func (server *RPCServer) GetBridgeAggState(beaconHeight uint64) ([]byte, error) {
+	tmpParams := make(map[string]interface{})
+	tmpParams["BeaconHeight"] = beaconHeight
+
+	params := make([]interface{}, 0)
+	params = append(params, tmpParams)
+	return server.SendQuery(getBridgeAggState, params)
+}
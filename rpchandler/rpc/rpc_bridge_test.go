@@ -0,0 +1,60 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/metadata"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCServer_GetBurnPRVPeggingProof(t *testing.T) {
+	var method string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		method = req.Method
+		_, _ = w.Write([]byte(`{"Result":{},"Error":null}`))
+	}))
+	defer ts.Close()
+	server := NewRPCServer(ts.URL)
+
+	expectedMethods := map[int]string{
+		ETHNetworkID: "getprverc20burnproof",
+		BSCNetworkID: "getprvbep20burnproof",
+	}
+	for networkID, expectedMethod := range expectedMethods {
+		method = ""
+		_, err := server.GetBurnPRVPeggingProof("txHash", networkID)
+		assert.Equal(t, nil, err, fmt.Errorf("GetBurnPRVPeggingProof error: %v", err))
+		assert.Equal(t, expectedMethod, method)
+	}
+	method = ""
+	_, err := server.GetBurnPRVPeggingProof("txHash")
+	assert.Equal(t, nil, err, fmt.Errorf("GetBurnPRVPeggingProof error: %v", err))
+	assert.Equal(t, expectedMethods[ETHNetworkID], method)
+
+	// PRV pegging is not available on Polygon and Fantom.
+	for _, networkID := range []int{PLGNetworkID, FTMNetworkID, FTMNetworkID + 1} {
+		method = ""
+		_, err = server.GetBurnPRVPeggingProof("txHash", networkID)
+		assert.Equal(t, EVMNetworkNotFoundError(networkID), err)
+		assert.Equal(t, "", method)
+	}
+
+	// every network supporting PRV pegging has a burn proof method and both metadata types.
+	assert.Equal(t, metadata.BurningPRVERC20RequestMeta, PRVPeggingBurningMetadata[ETHNetworkID])
+	assert.Equal(t, metadata.BurningPRVBEP20RequestMeta, PRVPeggingBurningMetadata[BSCNetworkID])
+	assert.Equal(t, len(burnPRVPeggingProofRPCMethod), len(PRVPeggingBurningMetadata))
+	for networkID := range PRVPeggingBurningMetadata {
+		_, ok := PRVPeggingIssuingMetadata[networkID]
+		assert.Equal(t, true, ok)
+		_, ok = burnPRVPeggingProofRPCMethod[networkID]
+		assert.Equal(t, true, ok)
+	}
+}
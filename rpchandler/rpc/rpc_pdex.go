@@ -6,6 +6,10 @@ type ConvertedPrice struct {
 	ToTokenIDStr   string
 	Amount         uint64
 	Price          uint64
+
+	// PoolID is the ID of the pool used to price the conversion. It is only set when the price is calculated
+	// on the client side.
+	PoolID string `json:",omitempty"`
 }
 
 // CheckTradeStatus retrieves the status of a trading transaction.
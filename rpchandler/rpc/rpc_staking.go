@@ -26,3 +26,12 @@ func (server *RPCServer) GetMiningInfo() ([]byte, error) {
 func (server *RPCServer) GetSyncStats() ([]byte, error) {
 	return server.SendQuery(getSyncStats, nil)
 }
+
+// GetRewardAmountByEpoch returns the PRV reward pool of a shard at the given epoch.
+func (server *RPCServer) GetRewardAmountByEpoch(shardID byte, epoch uint64) ([]byte, error) {
+	params := make([]interface{}, 0)
+	params = append(params, shardID)
+	params = append(params, epoch)
+
+	return server.SendQuery(getRewardAmountByEpoch, params)
+}
@@ -63,6 +63,9 @@ func ValidateTxParams(params *TxPrivacyInitParams) error {
 	if len(params.PaymentInfo) > 254 {
 		return fmt.Errorf("number of outputs (%v) is too large", len(params.PaymentInfo))
 	}
+	if _, err := params.GetRingSize(); err != nil {
+		return err
+	}
 	if params.TokenID == nil {
 		// using default PRV
 		params.TokenID = &common.Hash{}
@@ -89,3 +92,20 @@ func SignNoPrivacy(privateKey *key.PrivateKey, hashedMessage []byte) (signatureB
 	sigPubKey = sigKey.GetPublicKey().GetPublicKey().ToBytesS()
 	return signatureBytes, sigPubKey, nil
 }
+
+// VerifySigNoPrivacy verifies a Schnorr signature created by SignNoPrivacy.
+func VerifySigNoPrivacy(sig []byte, sigPubKey []byte, hashedMessage []byte) (bool, error) {
+	pubKeyPoint, err := new(crypto.Point).FromBytesS(sigPubKey)
+	if err != nil {
+		return false, fmt.Errorf("cannot parse sigPubKey: %v", err)
+	}
+	verifyKey := new(privacy.SchnorrPublicKey)
+	verifyKey.Set(pubKeyPoint)
+
+	signature := new(privacy.SchnorrSignature)
+	if err = signature.SetBytes(sig); err != nil {
+		return false, fmt.Errorf("cannot parse signature: %v", err)
+	}
+
+	return verifyKey.Verify(signature, hashedMessage), nil
+}
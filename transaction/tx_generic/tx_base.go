@@ -49,6 +49,7 @@ type TxPrivacyInitParams struct {
 	MetaData    metadata.Metadata
 	Info        []byte // 512 bytes
 	KvArgs      map[string]interface{}
+	RingSize    int // default is 0 -> use privacy.RingSize
 }
 
 // NewTxPrivacyInitParams creates a new TxPrivacyInitParams based on the given inputs.
@@ -79,6 +80,19 @@ func NewTxPrivacyInitParams(
 	return params
 }
 
+// GetRingSize returns the size of the MLSAG ring used to sign a TxPrivacyInitParams, i.e, the number of coins
+// (including the real one) each input coin is hidden among. It defaults to privacy.RingSize if RingSize is not set.
+func (param *TxPrivacyInitParams) GetRingSize() (int, error) {
+	if param.RingSize == 0 {
+		return privacy.RingSize, nil
+	}
+	if param.RingSize < 2 {
+		return 0, fmt.Errorf("invalid ring size %v: must be at least 2", param.RingSize)
+	}
+
+	return param.RingSize, nil
+}
+
 // GetSenderShard returns the shardID of the sender of a TxPrivacyInitParams.
 func (param *TxPrivacyInitParams) GetSenderShard() byte {
 	pubKey := new(crypto.Point).ScalarMultBase(new(crypto.Scalar).FromBytesS(*param.SenderSK))
@@ -278,6 +292,12 @@ func (tx TxBase) GetTokenID() *common.Hash { return &common.PRVCoinID }
 // GetMetadata returns the metadata of a TxBase.
 func (tx TxBase) GetMetadata() metadata.Metadata { return tx.Metadata }
 
+// GetTradeRequest returns the parameters of the pDEX trade requested by a TxBase. It returns metadata.ErrNotATrade
+// if the TxBase does not carry a trade metadata.
+func (tx TxBase) GetTradeRequest() (*metadata.TradeRequestInfo, error) {
+	return metadata.GetTradeRequestInfo(tx.Metadata)
+}
+
 // GetPrivateKey returns the sigPrivateKey of a TxBase.
 func (tx TxBase) GetPrivateKey() []byte {
 	return tx.sigPrivateKey
@@ -255,6 +255,12 @@ func (txToken TxTokenBase) String() string {
 	return record
 }
 
+// GetTradeRequest returns the parameters of the pDEX trade requested by a TxTokenBase. It returns metadata.ErrNotATrade
+// if the TxTokenBase does not carry a trade metadata.
+func (txToken TxTokenBase) GetTradeRequest() (*metadata.TradeRequestInfo, error) {
+	return metadata.GetTradeRequestInfo(txToken.GetMetadata())
+}
+
 // Hash calculates the hash of a TxTokenBase.
 func (txToken *TxTokenBase) Hash() *common.Hash {
 	if txToken.cachedHash != nil {
@@ -0,0 +1,97 @@
+package tx_ver2
+
+import (
+	"fmt"
+	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"github.com/incognitochain/go-incognito-sdk-v2/crypto"
+	"github.com/incognitochain/go-incognito-sdk-v2/metadata"
+	"sort"
+)
+
+// DecoyResolver retrieves the on-chain PRV coins referenced by the MLSAG rings of transactions.
+type DecoyResolver interface {
+	// ResolveCoins returns the public keys and the commitments of the PRV coins of the given indices in a shard,
+	// in the same order as indices.
+	ResolveCoins(shardID byte, indices []uint64) (publicKeys []*crypto.Point, commitments []*crypto.Point, err error)
+}
+
+// VerifyBlockTransactions verifies the signatures of a list of PRV transactions ver 2 (e.g, the PRV transactions of a
+// block) and returns whether each of them is valid. The ring members of all transactions are resolved with a single
+// call to the resolver per shard, so that coins shared between rings are looked up only once.
+//
+// Non-privacy transactions are verified with Tx.VerifySig, the others as in VerifyTxVer2. A malformed transaction is
+// reported as invalid. Other kinds of transactions (e.g, token transactions, whose rings also need the asset tags of
+// their members, or transactions ver 1) are not supported: an error is returned if txs contains any, and callers
+// verifying a whole block must filter them out first. An error is also returned if the resolver fails.
+func VerifyBlockTransactions(txs []metadata.Transaction, resolver DecoyResolver) ([]bool, error) {
+	txVer2s := make([]*Tx, len(txs))
+	indicesByShard := make(map[byte]map[uint64]bool)
+	for i, tmpTx := range txs {
+		tx, ok := tmpTx.(*Tx)
+		if !ok || tx == nil {
+			return nil, fmt.Errorf("transaction %v is a %T: only PRV transactions ver 2 are supported", i, tmpTx)
+		}
+		txVer2s[i] = tx
+		if tx.IsNonPrivacy() {
+			continue
+		}
+
+		sigPubKey := new(SigPubKey)
+		if err := sigPubKey.SetBytes(tx.SigPubKey); err != nil {
+			continue // reported as invalid below
+		}
+		shardID := common.GetShardIDFromLastByte(tx.PubKeyLastByteSender)
+		if indicesByShard[shardID] == nil {
+			indicesByShard[shardID] = make(map[uint64]bool)
+		}
+		for _, row := range sigPubKey.Indexes {
+			for _, index := range row {
+				if index.IsUint64() {
+					indicesByShard[shardID][index.Uint64()] = true
+				}
+			}
+		}
+	}
+
+	ringCoinsByShard := make(map[byte]map[uint64][2]*crypto.Point)
+	for shardID, indexSet := range indicesByShard {
+		indices := make([]uint64, 0, len(indexSet))
+		for index := range indexSet {
+			indices = append(indices, index)
+		}
+		sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+
+		publicKeys, commitments, err := resolver.ResolveCoins(shardID, indices)
+		if err != nil {
+			return nil, fmt.Errorf("cannot resolve the ring coins of shard %v: %v", shardID, err)
+		}
+		if len(publicKeys) != len(indices) || len(commitments) != len(indices) {
+			return nil, fmt.Errorf("resolver returned %v public keys and %v commitments for %v indices of shard %v",
+				len(publicKeys), len(commitments), len(indices), shardID)
+		}
+
+		ringCoins := make(map[uint64][2]*crypto.Point)
+		for i, index := range indices {
+			if publicKeys[i] == nil || commitments[i] == nil {
+				return nil, fmt.Errorf("resolver returned no public key or commitment for coin %v of shard %v", index, shardID)
+			}
+			ringCoins[index] = [2]*crypto.Point{publicKeys[i], commitments[i]}
+		}
+		ringCoinsByShard[shardID] = ringCoins
+	}
+
+	res := make([]bool, len(txVer2s))
+	for i, tx := range txVer2s {
+		var isValid bool
+		var err error
+		if tx.IsNonPrivacy() {
+			isValid, err = tx.VerifySig()
+		} else {
+			shardID := common.GetShardIDFromLastByte(tx.PubKeyLastByteSender)
+			isValid, err = verifyTxVer2WithRingCoins(tx, ringCoinsByShard[shardID])
+		}
+		res[i] = err == nil && isValid
+	}
+
+	return res, nil
+}
@@ -0,0 +1,113 @@
+package tx_ver2
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/metadata"
+	"github.com/incognitochain/go-incognito-sdk-v2/privacy/v2/mlsag"
+)
+
+// TxExplanation is a human-readable summary of a Tx, as shown by a blockchain explorer.
+type TxExplanation struct {
+	// TxHash is the ID of the transaction.
+	TxHash string
+
+	// Version is the version of the transaction.
+	Version int8
+
+	// Type is the type of the transaction (e.g, common.TxNormalType, common.TxRewardType).
+	Type string
+
+	// Fee is the PRV fee paid by the transaction.
+	Fee uint64
+
+	// FeePerKB is the effective PRV fee rate of the transaction (see Tx.FeePerKB).
+	FeePerKB uint64
+
+	// NumKeyImages is the number of input coins (i.e, key images) spent by the transaction.
+	NumKeyImages int
+
+	// OutputOTAs is the list of base58-encoded one-time public keys of the output coins.
+	OutputOTAs []string
+
+	// RingSize is the size of the MLSAG ring of the transaction. It is 0 for a non-privacy transaction.
+	RingSize int
+
+	// MetadataType is the type of the metadata attached to the transaction, or 0 if there is none.
+	MetadataType int
+
+	// MetadataTypeName is the registered name of MetadataType (see metadata.MetadataTypeName), or empty if the
+	// transaction has no metadata.
+	MetadataTypeName string
+
+	// PrivacyLevel is the privacy classification of the transaction.
+	PrivacyLevel PrivacyLevel
+}
+
+// String pretty-prints a TxExplanation.
+func (e TxExplanation) String() string {
+	metadataStr := "none"
+	if e.MetadataTypeName != "" {
+		metadataStr = fmt.Sprintf("%v (%v)", e.MetadataTypeName, e.MetadataType)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("TxHash:        %v\n", e.TxHash))
+	sb.WriteString(fmt.Sprintf("Version:       %v\n", e.Version))
+	sb.WriteString(fmt.Sprintf("Type:          %v\n", e.Type))
+	sb.WriteString(fmt.Sprintf("Fee:           %v\n", e.Fee))
+	sb.WriteString(fmt.Sprintf("FeePerKB:      %v\n", e.FeePerKB))
+	sb.WriteString(fmt.Sprintf("PrivacyLevel:  %v\n", e.PrivacyLevel))
+	sb.WriteString(fmt.Sprintf("RingSize:      %v\n", e.RingSize))
+	sb.WriteString(fmt.Sprintf("KeyImages:     %v\n", e.NumKeyImages))
+	sb.WriteString(fmt.Sprintf("Metadata:      %v\n", metadataStr))
+	sb.WriteString(fmt.Sprintf("OutputOTAs:    %v\n", len(e.OutputOTAs)))
+	for i, ota := range e.OutputOTAs {
+		sb.WriteString(fmt.Sprintf("  [%v] %v\n", i, ota))
+	}
+
+	return sb.String()
+}
+
+// Explain returns a human-readable summary of a Tx, which consolidates its main attributes (fee, inputs, outputs,
+// ring size, metadata and privacy level) into a single view.
+func (tx *Tx) Explain() (*TxExplanation, error) {
+	txHash := tx.Hash()
+	if txHash == nil {
+		return nil, fmt.Errorf("cannot hash tx")
+	}
+	feePerKB, err := tx.FeePerKB()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &TxExplanation{
+		TxHash:       txHash.String(),
+		Version:      tx.Version,
+		Type:         tx.Type,
+		Fee:          tx.Fee,
+		FeePerKB:     feePerKB,
+		OutputOTAs:   tx.AllOutputOTAs(),
+		PrivacyLevel: tx.PrivacyLevel(),
+	}
+
+	if tx.Proof != nil {
+		res.NumKeyImages = len(tx.Proof.GetInputCoins())
+	}
+
+	if res.PrivacyLevel == PrivacyLevelPrivate {
+		sig, err := new(mlsag.Sig).FromBytes(tx.Sig)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse the MLSAG signature of tx %v: %v", res.TxHash, err)
+		}
+		res.RingSize = len(sig.GetR())
+	}
+
+	if tx.Metadata != nil {
+		res.MetadataType = tx.Metadata.GetType()
+		res.MetadataTypeName = metadata.MetadataTypeName(res.MetadataType)
+	}
+
+	return res, nil
+}
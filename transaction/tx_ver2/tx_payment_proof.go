@@ -0,0 +1,203 @@
+package tx_ver2
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/incognitochain/go-incognito-sdk-v2/coin"
+	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"github.com/incognitochain/go-incognito-sdk-v2/crypto"
+	"github.com/incognitochain/go-incognito-sdk-v2/key"
+	"github.com/incognitochain/go-incognito-sdk-v2/wallet"
+)
+
+// PaymentProof is an off-chain receipt proving that an output coin of a transaction pays a recipient a given amount.
+// It is created by the sender of the transaction (see Tx.ProveSentTo) and checked with VerifyPaymentProof.
+//
+// A PaymentProof reveals the shared randoms of one output coin, which let anyone holding it link the coin to its
+// recipient and learn its amount. It reveals nothing about the other output coins, and does not allow spending the coin.
+type PaymentProof struct {
+	// TxHash is the hash of the transaction containing the output coin.
+	TxHash string
+
+	// OutputIndex is the position of the output coin among the output coins of the transaction.
+	OutputIndex int
+
+	// OutputCoin is the serialized output coin, as it appears on the chain.
+	OutputCoin []byte
+
+	// SharedOTARandom is the random used to derive the one-time address of the coin.
+	SharedOTARandom []byte
+
+	// SharedConcealRandom is the random used to conceal the amount of the coin.
+	SharedConcealRandom []byte
+}
+
+// outputSecrets keeps the shared randoms of the output coins of a Tx, which are erased from the coins when the Tx is
+// proved.
+type outputSecrets struct {
+	senderPublicKey      []byte
+	sharedRandoms        []*crypto.Scalar
+	sharedConcealRandoms []*crypto.Scalar
+}
+
+func newOutputSecrets(senderSK *key.PrivateKey, outputCoins []*coin.CoinV2) *outputSecrets {
+	if senderSK == nil {
+		return nil
+	}
+	res := &outputSecrets{
+		senderPublicKey: new(crypto.Point).ScalarMultBase(new(crypto.Scalar).FromBytesS(*senderSK)).ToBytesS(),
+	}
+	for _, outputCoin := range outputCoins {
+		res.sharedRandoms = append(res.sharedRandoms, outputCoin.GetSharedRandom())
+		res.sharedConcealRandoms = append(res.sharedConcealRandoms, outputCoin.GetSharedConcealRandom())
+	}
+
+	return res
+}
+
+// ProveSentTo creates a PaymentProof that an output coin of a Tx pays the given base58-encoded payment address, given
+// the base58-encoded private key of the sender.
+//
+// The shared randoms of the output coins are not part of the transaction and cannot be recovered from the private key;
+// they are only kept in memory by the Tx returned when creating the transaction. Therefore, the proof can only be
+// created on that Tx (e.g, before it is serialized), not on a Tx decoded from its bytes or retrieved from the network.
+func (tx *Tx) ProveSentTo(privateKey, recipientAddress string) (*PaymentProof, error) {
+	w, err := wallet.Base58CheckDeserialize(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot deserialize private key %v: %v", privateKey, err)
+	}
+	if len(w.KeySet.PrivateKey) == 0 {
+		return nil, fmt.Errorf("%v is not a private key", privateKey)
+	}
+	recipient, err := parseRecipientAddress(recipientAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := tx.outputSecrets
+	if secrets == nil {
+		return nil, fmt.Errorf("the shared randoms of tx %v are unknown: it was not created in this process", tx.Hash().String())
+	}
+	if !bytes.Equal(secrets.senderPublicKey, w.KeySet.PaymentAddress.Pk) {
+		return nil, fmt.Errorf("tx %v was not created with the given private key", tx.Hash().String())
+	}
+
+	outputCoins, err := tx.GetReceiverData()
+	if err != nil {
+		return nil, err
+	}
+	if len(outputCoins) != len(secrets.sharedRandoms) {
+		return nil, fmt.Errorf("expected %v output coins, got %v", len(secrets.sharedRandoms), len(outputCoins))
+	}
+	for i, outputCoin := range outputCoins {
+		c, ok := outputCoin.(*coin.CoinV2)
+		if !ok || secrets.sharedRandoms[i] == nil || secrets.sharedConcealRandoms[i] == nil {
+			continue
+		}
+		if belongs, err := isPaidTo(c, secrets.sharedRandoms[i], recipient); err != nil || !belongs {
+			continue
+		}
+
+		return &PaymentProof{
+			TxHash:              tx.Hash().String(),
+			OutputIndex:         i,
+			OutputCoin:          c.Bytes(),
+			SharedOTARandom:     secrets.sharedRandoms[i].ToBytesS(),
+			SharedConcealRandom: secrets.sharedConcealRandoms[i].ToBytesS(),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("tx %v has no output coin paying %v", tx.Hash().String(), recipientAddress)
+}
+
+// VerifyPaymentProof checks if a PaymentProof shows that the transaction with the given hash pays amount to the
+// given base58-encoded payment address. It returns false if the proof is well-formed but does not match the claim.
+//
+// VerifyPaymentProof only checks the proof against its OutputCoin; it does not query the network. The verifier should
+// also make sure that OutputCoin is indeed the output coin at position OutputIndex of the confirmed transaction TxHash
+// (e.g, by comparing its public key with Tx.AllOutputOTAs).
+func VerifyPaymentProof(proof *PaymentProof, txHash, recipientAddress string, amount uint64) (bool, error) {
+	if proof == nil {
+		return false, fmt.Errorf("payment proof is nil")
+	}
+	recipient, err := parseRecipientAddress(recipientAddress)
+	if err != nil {
+		return false, err
+	}
+	if len(proof.SharedOTARandom) != crypto.Ed25519KeySize || len(proof.SharedConcealRandom) != crypto.Ed25519KeySize {
+		return false, fmt.Errorf("invalid shared randoms")
+	}
+	sharedRandom := new(crypto.Scalar).FromBytesS(proof.SharedOTARandom)
+	sharedConcealRandom := new(crypto.Scalar).FromBytesS(proof.SharedConcealRandom)
+	c := new(coin.CoinV2)
+	if err = c.SetBytes(proof.OutputCoin); err != nil {
+		return false, fmt.Errorf("cannot parse output coin: %v", err)
+	}
+
+	if proof.TxHash != txHash {
+		return false, nil
+	}
+
+	concealRandomPoint, otaRandomPoint, _, err := c.GetTxRandomDetail()
+	if err != nil {
+		return false, err
+	}
+	if !crypto.IsPointEqual(otaRandomPoint, new(crypto.Point).ScalarMultBase(sharedRandom)) ||
+		!crypto.IsPointEqual(concealRandomPoint, new(crypto.Point).ScalarMultBase(sharedConcealRandom)) {
+		return false, nil
+	}
+
+	belongs, err := isPaidTo(c, sharedRandom, recipient)
+	if err != nil || !belongs {
+		return false, err
+	}
+
+	// decrypt the amount the same way the recipient does with its private view key (see CoinV2.Decrypt).
+	rK := new(crypto.Point).ScalarMult(recipient.GetPublicView(), sharedConcealRandom)
+	hash := crypto.HashToScalar(rK.ToBytesS())
+	hash = crypto.HashToScalar(hash.ToBytesS())
+	randomness := new(crypto.Scalar).Sub(c.GetRandomness(), hash)
+	hash = crypto.HashToScalar(hash.ToBytesS())
+	value := new(crypto.Scalar).Sub(c.GetAmount(), hash)
+
+	if !crypto.IsScalarEqual(value, new(crypto.Scalar).FromUint64(amount)) {
+		return false, nil
+	}
+	commitment := crypto.PedCom.CommitAtIndex(value, randomness, crypto.PedersenValueIndex)
+	if c.GetAssetTag() != nil {
+		commitment, err = coin.ComputeCommitmentCA(c.GetAssetTag(), randomness, value)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return crypto.IsPointEqual(commitment, c.GetCommitment()), nil
+}
+
+// parseRecipientAddress parses a base58-encoded payment address which supports one-time addresses.
+func parseRecipientAddress(recipientAddress string) (*key.PaymentAddress, error) {
+	w, err := wallet.Base58CheckDeserialize(recipientAddress)
+	if err != nil {
+		return nil, fmt.Errorf("cannot deserialize payment address %v: %v", recipientAddress, err)
+	}
+	recipient := w.KeySet.PaymentAddress
+	if recipient.GetOTAPublicKey() == nil || recipient.GetPublicSpend() == nil || recipient.GetPublicView() == nil {
+		return nil, fmt.Errorf("%v is not a payment address v2", recipientAddress)
+	}
+
+	return &recipient, nil
+}
+
+// isPaidTo checks if the one-time address of c, derived with the given shared random, belongs to recipient.
+func isPaidTo(c *coin.CoinV2, sharedRandom *crypto.Scalar, recipient *key.PaymentAddress) (bool, error) {
+	_, _, index, err := c.GetTxRandomDetail()
+	if err != nil {
+		return false, err
+	}
+	rK := new(crypto.Point).ScalarMult(recipient.GetOTAPublicKey(), sharedRandom)
+	hash := crypto.HashToScalar(append(rK.ToBytesS(), common.Uint32ToBytes(index)...))
+	expectedPublicKey := new(crypto.Point).Add(new(crypto.Point).ScalarMultBase(hash), recipient.GetPublicSpend())
+
+	return crypto.IsPointEqual(expectedPublicKey, c.GetPublicKey()), nil
+}
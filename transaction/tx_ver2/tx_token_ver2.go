@@ -89,6 +89,12 @@ func (txToken TxToken) GetTokenID() *common.Hash { return &txToken.TokenData.Pro
 // GetMetadata returns the metadata of a TxToken.
 func (txToken TxToken) GetMetadata() metadata.Metadata { return txToken.Tx.Metadata }
 
+// GetTradeRequest returns the parameters of the pDEX trade requested by a TxToken. It returns metadata.ErrNotATrade
+// if the TxToken does not carry a trade metadata.
+func (txToken TxToken) GetTradeRequest() (*metadata.TradeRequestInfo, error) {
+	return metadata.GetTradeRequestInfo(txToken.Tx.Metadata)
+}
+
 // GetMetadataType returns the metadata type of a TxToken.
 func (txToken TxToken) GetMetadataType() int {
 	if txToken.Tx.Metadata != nil {
@@ -216,6 +222,11 @@ func (txToken TxToken) IsPrivacy() bool {
 	return txToken.Tx.Proof.IsPrivacy()
 }
 
+// IsEstimationOnly checks if either the PRV or the token part of a TxToken was created without range proofs.
+func (txToken TxToken) IsEstimationOnly() bool {
+	return isEstimationOnlyProof(txToken.Tx.Proof) || isEstimationOnlyProof(txToken.TokenData.Proof)
+}
+
 // ListSerialNumbersHashH returns the hash list of all serial numbers in a TxToken.
 func (txToken TxToken) ListSerialNumbersHashH() []common.Hash {
 	result := make([]common.Hash, 0)
@@ -276,7 +287,7 @@ func (txToken TxToken) GetPrivateKey() []byte {
 	return txToken.Tx.GetPrivateKey()
 }
 
-// Hash calculates the hash of a TxToken.
+// Hash calculates the hash of a TxToken. Hash().String() is the transaction ID reported by the Incognito network.
 func (txToken *TxToken) Hash() *common.Hash {
 	firstHash := txToken.Tx.Hash()
 	secondHash, err := txToken.TokenData.Hash()
@@ -437,7 +448,21 @@ func (txToken *TxToken) Init(txTokenParams interface{}) error {
 	if txSize > common.MaxTxSize {
 		return utils.NewTransactionErr(utils.ExceedSizeTx, nil, strconv.Itoa(int(txSize)))
 	}
-	return nil
+
+	return txToken.ValidateTypeConsistency()
+}
+
+// ValidateTypeConsistency cross-checks the declared type of a TxToken against its metadata. It returns an error if
+// the type is not a token type, or if the PRV sub-transaction (which carries the type and the metadata of the TxToken)
+// fails Tx.ValidateTypeConsistency, e.g. the metadata type is not allowed in a token transaction.
+func (txToken *TxToken) ValidateTypeConsistency() error {
+	switch txToken.Tx.Type {
+	case common.TxCustomTokenPrivacyType, common.TxTokenConversionType:
+	default:
+		return fmt.Errorf("transaction type %q is not a token type", txToken.Tx.Type)
+	}
+
+	return txToken.Tx.ValidateTypeConsistency()
 }
 
 // CalculateTxValue calculates total output values.
@@ -1,10 +1,13 @@
 package tx_ver2
 
 import (
+	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"github.com/incognitochain/go-incognito-sdk-v2/coin"
 	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"github.com/incognitochain/go-incognito-sdk-v2/common/base58"
 	"github.com/incognitochain/go-incognito-sdk-v2/crypto"
 	"github.com/incognitochain/go-incognito-sdk-v2/key"
 	"github.com/incognitochain/go-incognito-sdk-v2/metadata"
@@ -94,6 +97,60 @@ func (sigPub *SigPubKey) SetBytes(b []byte) error {
 	return nil
 }
 
+// sigPubKeyJSON is the JSON-representation of a SigPubKey, in which each index is a decimal string.
+type sigPubKeyJSON struct {
+	Indexes [][]string
+}
+
+// MarshalJSON returns the JSON-representation of a SigPubKey, in which the indices are given row by row as decimal
+// strings, e.g, {"Indexes":[["12","345"],["6","78"]]}. As in Bytes, Indexes must be a non-empty rectangular array.
+func (sigPub SigPubKey) MarshalJSON() ([]byte, error) {
+	if _, err := sigPub.Bytes(); err != nil {
+		return nil, err
+	}
+
+	res := sigPubKeyJSON{Indexes: make([][]string, len(sigPub.Indexes))}
+	for i, row := range sigPub.Indexes {
+		res.Indexes[i] = make([]string, len(row))
+		for j, index := range row {
+			if index == nil {
+				return nil, fmt.Errorf("TxSigPublicKeyVer2.MarshalJSON: index[%v][%v] is nil", i, j)
+			}
+			res.Indexes[i][j] = index.String()
+		}
+	}
+
+	return json.Marshal(res)
+}
+
+// UnmarshalJSON recovers a SigPubKey from its JSON-representation (see MarshalJSON). The recovered SigPubKey satisfies
+// the same constraints as Bytes, so that it round-trips through Bytes and SetBytes.
+func (sigPub *SigPubKey) UnmarshalJSON(data []byte) error {
+	var tmp sigPubKeyJSON
+	if err := json.Unmarshal(data, &tmp); err != nil {
+		return err
+	}
+
+	indexes := make([][]*big.Int, len(tmp.Indexes))
+	for i, row := range tmp.Indexes {
+		indexes[i] = make([]*big.Int, len(row))
+		for j, indexStr := range row {
+			index, ok := new(big.Int).SetString(indexStr, 10)
+			if !ok || index.Sign() < 0 {
+				return fmt.Errorf("TxSigPublicKeyVer2.UnmarshalJSON: invalid index[%v][%v] %v", i, j, indexStr)
+			}
+			indexes[i][j] = index
+		}
+	}
+	res := SigPubKey{Indexes: indexes}
+	if _, err := res.Bytes(); err != nil {
+		return err
+	}
+
+	*sigPub = res
+	return nil
+}
+
 // Tx implements a PRV transaction v2. It is a embedded TxBase with some overridden functions.
 // A transaction v2 is mainly composed of
 //	- OTA: different output coins have different public key, even if they belong to the same user.
@@ -102,6 +159,9 @@ func (sigPub *SigPubKey) SetBytes(b []byte) error {
 // By default, a transaction v2 is private, meaning that most of the stuff is hidden to public observers.
 type Tx struct {
 	tx_generic.TxBase
+
+	// the secrets of the output coins, only known when the Tx is created in this process (see ProveSentTo)
+	outputSecrets *outputSecrets
 }
 
 // GetReceiverData returns a list of output coins of a Tx.
@@ -114,6 +174,361 @@ func (tx *Tx) GetReceiverData() ([]coin.Coin, error) {
 	return nil, nil
 }
 
+// GetNonBurnReceiverData is the same as GetReceiverData, except that output coins sent to the burning address are
+// discarded (as in ListOTAHashH), so that they are not counted as received coins.
+func (tx *Tx) GetNonBurnReceiverData() ([]coin.Coin, error) {
+	outputCoins, err := tx.GetReceiverData()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]coin.Coin, 0)
+	for _, outputCoin := range outputCoins {
+		if wallet.IsPublicKeyBurningAddress(outputCoin.GetPublicKey().ToBytesS()) {
+			continue
+		}
+		res = append(res, outputCoin)
+	}
+
+	return res, nil
+}
+
+// AllOutputOTAs returns the base58-encoded one-time public keys of all output coins of a Tx, in order. Unlike
+// ListOTAHashH and GetNonBurnReceiverData, output coins sent to the burning address are included, so that the result
+// can be matched against a watch list of OTAs.
+func (tx *Tx) AllOutputOTAs() []string {
+	res := make([]string, 0)
+	if tx.Proof == nil {
+		return res
+	}
+	for _, outputCoin := range tx.Proof.GetOutputCoins() {
+		res = append(res, base58.Base58Check{}.Encode(outputCoin.GetPublicKey().ToBytesS(), common.ZeroByte))
+	}
+
+	return res
+}
+
+// ErrNoChange indicates that a transaction has no output coin sent back to its sender.
+var ErrNoChange = fmt.Errorf("no change output found")
+
+// FindOwnChange returns the decrypted change coin of a Tx given the base58-encoded private key of its sender, together
+// with the position of the coin among the output coins of the Tx. This lets a wallet learn its new UTXO right after
+// sending, without rescanning.
+//
+// The change is appended as the last payment of a transaction, so if several output coins belong to the sender (e.g,
+// when sending to oneself), the last one is returned. ErrNoChange is returned if no output coin belongs to the sender,
+// i.e, the inputs exactly covered the sent amount and the fee.
+func (tx *Tx) FindOwnChange(privateKey string) (coin.PlainCoin, uint64, error) {
+	w, err := wallet.Base58CheckDeserialize(privateKey)
+	if err != nil {
+		return nil, 0, fmt.Errorf("cannot deserialize private key %v: %v", privateKey, err)
+	}
+	if len(w.KeySet.PrivateKey) == 0 {
+		return nil, 0, fmt.Errorf("%v is not a private key", privateKey)
+	}
+
+	outputCoins, err := tx.GetReceiverData()
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := len(outputCoins) - 1; i >= 0; i-- {
+		c, ok := outputCoins[i].(*coin.CoinV2)
+		if !ok {
+			continue
+		}
+		if belongs, _ := c.DoesCoinBelongToKeySet(&w.KeySet); !belongs {
+			continue
+		}
+		plainCoin, err := c.Decrypt(&w.KeySet)
+		if err != nil {
+			return nil, 0, fmt.Errorf("cannot decrypt change output %v: %v", i, err)
+		}
+		return plainCoin, uint64(i), nil
+	}
+
+	return nil, 0, ErrNoChange
+}
+
+// IsNonPrivacy checks if a Tx is a non-privacy transaction with no input coins (e.g, a reward transaction, or
+// the PRV transaction of a token transaction paying fees in pToken). Such a transaction has no MLSAG ring; it is
+// signed with a Schnorr signature instead.
+func (tx *Tx) IsNonPrivacy() bool {
+	if tx.Proof == nil {
+		return true
+	}
+	return len(tx.Proof.GetInputCoins()) == 0
+}
+
+// PrivacyLevel classifies the privacy of a Tx.
+type PrivacyLevel int
+
+const (
+	// PrivacyLevelPrivate indicates a private transaction: its input coins are hidden in an MLSAG ring.
+	PrivacyLevelPrivate PrivacyLevel = iota
+
+	// PrivacyLevelNonPrivateReward indicates a non-privacy transaction minting output coins without any input
+	// (e.g, a reward transaction).
+	PrivacyLevelNonPrivateReward
+
+	// PrivacyLevelNonPrivatePTokenFee indicates a non-privacy transaction with neither input nor output coins, i.e,
+	// the PRV transaction of a token transaction paying fees in pToken.
+	PrivacyLevelNonPrivatePTokenFee
+)
+
+// String returns a human-readable label of a PrivacyLevel.
+func (level PrivacyLevel) String() string {
+	switch level {
+	case PrivacyLevelPrivate:
+		return "private"
+	case PrivacyLevelNonPrivateReward:
+		return "non-private reward"
+	case PrivacyLevelNonPrivatePTokenFee:
+		return "non-private pToken fee"
+	default:
+		return fmt.Sprintf("unknown privacy level %d", int(level))
+	}
+}
+
+// PrivacyLevel returns the privacy classification of a Tx. A Tx is private if it spends input coins through an MLSAG
+// ring; otherwise (see IsNonPrivacy), it is a reward-like transaction if it has output coins, or the PRV
+// transaction of a pToken-fee transaction if it has none.
+func (tx *Tx) PrivacyLevel() PrivacyLevel {
+	if !tx.IsNonPrivacy() {
+		return PrivacyLevelPrivate
+	}
+	if tx.Proof != nil && len(tx.Proof.GetOutputCoins()) > 0 {
+		return PrivacyLevelNonPrivateReward
+	}
+	return PrivacyLevelNonPrivatePTokenFee
+}
+
+// EffectiveRingSize returns the effective anonymity set of a private Tx, i.e, the number of rows of its MLSAG ring
+// which may still be the real one. A row is ruled out as soon as one of its coins is known to be spent by another
+// transaction, as reported by isSpent given the index of the coin. Each coin index is checked at most once.
+//
+// Note that the real input coins are spent by the Tx itself: isSpent should only report coins spent elsewhere,
+// otherwise the real row is ruled out as well.
+func (tx *Tx) EffectiveRingSize(isSpent func(index uint64) (bool, error)) (int, error) {
+	if tx.IsNonPrivacy() {
+		return 0, fmt.Errorf("tx %v has no ring", tx.Hash().String())
+	}
+
+	sigPubKey := new(SigPubKey)
+	if err := sigPubKey.SetBytes(tx.SigPubKey); err != nil {
+		return 0, fmt.Errorf("cannot parse the SigPubKey of tx %v: %v", tx.Hash().String(), err)
+	}
+
+	spent := make(map[uint64]bool)
+	res := 0
+	for _, rowIndexes := range sigPubKey.Indexes {
+		isPlausible := true
+		for _, index := range rowIndexes {
+			if !index.IsUint64() {
+				return 0, fmt.Errorf("invalid ring index %v", index)
+			}
+			idx := index.Uint64()
+			isSpentCoin, ok := spent[idx]
+			if !ok {
+				var err error
+				isSpentCoin, err = isSpent(idx)
+				if err != nil {
+					return 0, fmt.Errorf("cannot check coin of index %v: %v", idx, err)
+				}
+				spent[idx] = isSpentCoin
+			}
+			if isSpentCoin {
+				isPlausible = false
+				break
+			}
+		}
+		if isPlausible {
+			res++
+		}
+	}
+
+	return res, nil
+}
+
+// IsEstimationOnly checks if a Tx was created without range proofs (see utils.EstimationOnly).
+// Such a transaction is only useful for size/fee estimation and will be rejected by the network.
+func (tx *Tx) IsEstimationOnly() bool {
+	return isEstimationOnlyProof(tx.Proof)
+}
+
+// VerifySig verifies the signature of a Tx.
+//
+// Only non-privacy transactions (see IsNonPrivacy) can be verified locally. Verifying the MLSAG signature of other
+// transactions requires the decoys of the ring to be retrieved from the blockchain, which is not supported.
+func (tx *Tx) VerifySig() (bool, error) {
+	if !tx.IsNonPrivacy() {
+		return false, fmt.Errorf("cannot verify the MLSAG signature of tx %v without its ring", tx.Hash().String())
+	}
+	if len(tx.Sig) == 0 || len(tx.SigPubKey) == 0 {
+		return false, fmt.Errorf("tx %v has not been signed", tx.Hash().String())
+	}
+
+	hashedMessage := tx.Hash()
+	if tx.Proof == nil {
+		// a transaction without proof is signed in TxBase.IsNonPrivacyNonInput, over the hash of its TxBase
+		hashedMessage = tx.TxBase.Hash()
+	}
+
+	return tx_generic.VerifySigNoPrivacy(tx.Sig, tx.SigPubKey, hashedMessage[:])
+}
+
+// VerifyTxVer2 verifies the MLSAG signature of a (non-CA) PRV transaction without any private key. The ring is
+// reconstructed from the indices stored in the SigPubKey of the transaction: each index is looked up in kvArgs, which
+// must map coin indices to the on-chain coins as three lists of the same length:
+//	- utils.CommitmentIndices ([]uint64): the indices of the coins;
+//	- utils.PublicKeys ([]*crypto.Point): the public keys of the coins;
+//	- utils.Commitments ([]*crypto.Point): the commitments of the coins.
+//
+// The last column of each row of the ring is recomputed as the sum of the commitments of the row minus the sum of
+// the output commitments and the fee commitment; the signature is only valid if one of them is a commitment to zero.
+// The bulletproof of the transaction is also verified, so that no output commits to a negative (i.e, overflowing)
+// amount, which would otherwise let such a commitment to zero hide an inflation.
+//
+// Non-privacy transactions are verified using VerifySig.
+func VerifyTxVer2(tx *Tx, kvArgs map[string]interface{}) (bool, error) {
+	if tx == nil {
+		return false, fmt.Errorf("tx is nil")
+	}
+	if tx.IsNonPrivacy() {
+		return tx.VerifySig()
+	}
+
+	ringCoins, err := parseRingCoinsForVerification(kvArgs)
+	if err != nil {
+		return false, err
+	}
+
+	return verifyTxVer2WithRingCoins(tx, ringCoins)
+}
+
+// verifyTxVer2WithRingCoins verifies the MLSAG signature of a private, non-CA Tx given the public keys and
+// commitments of the coins referenced by its ring (see VerifyTxVer2).
+func verifyTxVer2WithRingCoins(tx *Tx, ringCoins map[uint64][2]*crypto.Point) (bool, error) {
+	if len(tx.Sig) == 0 || len(tx.SigPubKey) == 0 {
+		return false, fmt.Errorf("tx %v has not been signed", tx.Hash().String())
+	}
+	if proofV2, ok := tx.Proof.(*privacy.ProofV2); ok {
+		if isCA, err := proofV2.IsConfidentialAsset(); err != nil || isCA {
+			return false, fmt.Errorf("cannot verify tx %v: only non-CA transactions are supported", tx.Hash().String())
+		}
+	}
+
+	sigPubKey := new(SigPubKey)
+	if err := sigPubKey.SetBytes(tx.SigPubKey); err != nil {
+		return false, fmt.Errorf("cannot parse the SigPubKey of tx %v: %v", tx.Hash().String(), err)
+	}
+
+	inputCoins := tx.Proof.GetInputCoins()
+	sumOutputsWithFee := tx_generic.CalculateSumOutputsWithFee(tx.Proof.GetOutputCoins(), tx.Fee)
+	ring := make([][]*crypto.Point, len(sigPubKey.Indexes))
+	for i, rowIndexes := range sigPubKey.Indexes {
+		if len(rowIndexes) != len(inputCoins) {
+			return false, fmt.Errorf("ring row %v has %v members, expected %v", i, len(rowIndexes), len(inputCoins))
+		}
+		commitmentToZero := new(crypto.Point).Identity()
+		commitmentToZero.Sub(commitmentToZero, sumOutputsWithFee)
+		row := make([]*crypto.Point, 0, len(rowIndexes)+1)
+		for _, index := range rowIndexes {
+			if !index.IsUint64() {
+				return false, fmt.Errorf("invalid ring index %v", index)
+			}
+			c, ok := ringCoins[index.Uint64()]
+			if !ok {
+				return false, fmt.Errorf("coin of index %v not found", index)
+			}
+			row = append(row, c[0])
+			commitmentToZero.Add(commitmentToZero, c[1])
+		}
+		ring[i] = append(row, commitmentToZero)
+	}
+
+	sig, err := new(mlsag.Sig).FromBytes(tx.Sig)
+	if err != nil {
+		return false, fmt.Errorf("cannot parse the signature of tx %v: %v", tx.Hash().String(), err)
+	}
+	keyImages := make([]*crypto.Point, 0, len(inputCoins)+1)
+	for i, inputCoin := range inputCoins {
+		if inputCoin == nil || inputCoin.GetKeyImage() == nil {
+			return false, fmt.Errorf("input coin %v has no key image", i)
+		}
+		keyImages = append(keyImages, inputCoin.GetKeyImage())
+	}
+	// the key image of the last column is not used
+	keyImages = append(keyImages, new(crypto.Point).Identity())
+	sig.SetKeyImages(keyImages)
+
+	isValid, err := mlsag.Verify(sig, mlsag.NewRing(ring), tx.Hash()[:])
+	if err != nil || !isValid {
+		return isValid, err
+	}
+
+	return verifyTxRangeProof(tx), nil
+}
+
+// verifyTxRangeProof checks that the bulletproof of a private, non-CA Tx is valid and commits to its output coins.
+func verifyTxRangeProof(tx *Tx) bool {
+	proofV2, ok := tx.Proof.(*privacy.ProofV2)
+	if !ok {
+		return false
+	}
+	rangeProof, ok := proofV2.GetRangeProof().(*privacy.RangeProofV2)
+	if !ok || rangeProof == nil {
+		return false
+	}
+	outputCoins := proofV2.GetOutputCoins()
+	cmsValue := rangeProof.GetCommitments()
+	if len(cmsValue) != len(outputCoins) {
+		return false
+	}
+	for i, outputCoin := range outputCoins {
+		if outputCoin == nil || outputCoin.GetCommitment() == nil || cmsValue[i] == nil ||
+			!crypto.IsPointEqual(outputCoin.GetCommitment(), cmsValue[i]) {
+			return false
+		}
+	}
+
+	// the error only tells which check failed.
+	isValid, _ := rangeProof.Verify()
+	return isValid
+}
+
+// parseRingCoinsForVerification returns the map from coin indices to the public keys and commitments given in kvArgs.
+func parseRingCoinsForVerification(kvArgs map[string]interface{}) (map[uint64][2]*crypto.Point, error) {
+	if kvArgs == nil {
+		return nil, fmt.Errorf("kvArgs is nil: need more params to proceed")
+	}
+	indices, ok := kvArgs[utils.CommitmentIndices].([]uint64)
+	if !ok {
+		return nil, fmt.Errorf("cannot parse commitment indices: %v", kvArgs[utils.CommitmentIndices])
+	}
+	publicKeys, ok := kvArgs[utils.PublicKeys].([]*crypto.Point)
+	if !ok {
+		return nil, fmt.Errorf("cannot parse public keys: %v", kvArgs[utils.PublicKeys])
+	}
+	commitments, ok := kvArgs[utils.Commitments].([]*crypto.Point)
+	if !ok {
+		return nil, fmt.Errorf("cannot parse commitments: %v", kvArgs[utils.Commitments])
+	}
+	if len(publicKeys) != len(indices) || len(commitments) != len(indices) {
+		return nil, fmt.Errorf("length mismatch: %v indices, %v public keys, %v commitments",
+			len(indices), len(publicKeys), len(commitments))
+	}
+
+	res := make(map[uint64][2]*crypto.Point)
+	for i, index := range indices {
+		if publicKeys[i] == nil || commitments[i] == nil {
+			return nil, fmt.Errorf("coin of index %v has no public key or commitment", index)
+		}
+		res[index] = [2]*crypto.Point{publicKeys[i], commitments[i]}
+	}
+
+	return res, nil
+}
+
 // GetTxMintData returns the minting data of a Tx.
 func (tx Tx) GetTxMintData() (bool, coin.Coin, *common.Hash, error) {
 	return tx_generic.GetTxMintData(&tx, &common.PRVCoinID)
@@ -139,6 +554,91 @@ func (tx Tx) GetTxActualSize() uint64 {
 	return uint64(math.Ceil(float64(len(jsb)) / 1024))
 }
 
+// EstimateActualSize returns the size of a Tx in kb, as GetTxActualSize does, but without marshalling the whole Tx
+// to JSON: the size of the JSON encoding is computed from the byte lengths of its components (proof, signature,
+// SigPubKey, info) and the JSON encoding of its metadata only. This makes it suitable for sizing many candidate
+// transactions.
+//
+// The tolerance is zero: the estimated number of bytes is the exact length of the JSON encoding of the Tx, so the
+// result always equals GetTxActualSize and never under-reports against common.MaxTxSize. GetTxActualSize remains the
+// reference; if a component cannot be encoded, EstimateActualSize falls back to it.
+func (tx Tx) EstimateActualSize() uint64 {
+	size, ok := tx.estimateJSONSize()
+	if !ok {
+		return tx.GetTxActualSize()
+	}
+	return uint64(math.Ceil(float64(size) / 1024))
+}
+
+// estimateJSONSize returns the length (in bytes) of the JSON encoding of a Tx, computed from its components (see
+// EstimateActualSize). It returns false if a component cannot be encoded.
+func (tx Tx) estimateJSONSize() (int, bool) {
+	// the keys of the JSON encoding of a Tx, in order.
+	keys := []string{"Version", "Type", "LockTime", "Fee", "Info", "SigPubKey", "Sig", "Proof",
+		"PubKeyLastByteSender", "Metadata"}
+	size := 2 + len(keys) - 1 // the braces and the commas
+	for _, key := range keys {
+		size += len(key) + 3 // the quotes and the colon
+	}
+
+	typeBytes, err := json.Marshal(tx.Type)
+	if err != nil {
+		return 0, false
+	}
+	size += len(strconv.FormatInt(int64(tx.Version), 10))
+	size += len(typeBytes)
+	size += len(strconv.FormatInt(tx.LockTime, 10))
+	size += len(strconv.FormatUint(tx.Fee, 10))
+	size += jsonBytesSize(tx.Info)
+	size += jsonBytesSize(tx.SigPubKey)
+	size += jsonBytesSize(tx.Sig)
+	size += len(strconv.FormatUint(uint64(tx.PubKeyLastByteSender), 10))
+
+	switch proof := tx.Proof.(type) {
+	case nil:
+		size += len("null")
+	case *privacy.ProofV2:
+		if proof == nil {
+			size += len("null")
+		} else {
+			size += base64.StdEncoding.EncodedLen(len(proof.Bytes())) + 2
+		}
+	default:
+		return 0, false
+	}
+
+	if tx.Metadata == nil {
+		size += len("null")
+	} else {
+		mdBytes, err := json.Marshal(tx.Metadata)
+		if err != nil {
+			return 0, false
+		}
+		size += len(mdBytes)
+	}
+
+	return size, true
+}
+
+// jsonBytesSize returns the length of the JSON encoding of a byte slice, i.e, a base64-encoded string, or null.
+func jsonBytesSize(b []byte) int {
+	if b == nil {
+		return len("null")
+	}
+	return base64.StdEncoding.EncodedLen(len(b)) + 2
+}
+
+// FeePerKB returns the effective PRV fee rate (in nano PRV per kb) of a Tx, i.e, its fee divided by its actual size.
+// It returns an error if the size of the Tx cannot be determined.
+func (tx Tx) FeePerKB() (uint64, error) {
+	txSize := tx.GetTxActualSize()
+	if txSize == 0 {
+		return 0, fmt.Errorf("cannot get the actual size of tx %v", tx.Hash().String())
+	}
+
+	return tx.Fee / txSize, nil
+}
+
 // ListOTAHashH returns the hash list of all OTA keys in a Tx.
 func (tx Tx) ListOTAHashH() []common.Hash {
 	result := make([]common.Hash, 0)
@@ -158,7 +658,8 @@ func (tx Tx) ListOTAHashH() []common.Hash {
 	return result
 }
 
-// Hash calculates the hash of a Tx.
+// Hash calculates the hash of a Tx. Hash().String() is the transaction ID reported by the Incognito network; it does
+// not depend on the signature, and is the same before and after a JSON round-trip of the transaction.
 func (tx Tx) Hash() *common.Hash {
 	// leave out signature & its public key when hashing tx
 	tx.Sig = []byte{}
@@ -172,6 +673,76 @@ func (tx Tx) Hash() *common.Hash {
 	return &hash
 }
 
+// EqualTo checks if a Tx is semantically equal to another Tx, i.e, they have the same Hash. Since the hash excludes
+// the signature and its public key, two transactions that differ only in their signatures are equal.
+func (tx *Tx) EqualTo(other *Tx) bool {
+	if tx == nil || other == nil {
+		return tx == other
+	}
+	txHash, otherHash := tx.Hash(), other.Hash()
+	if txHash == nil || otherHash == nil {
+		return false
+	}
+
+	return txHash.IsEqual(otherHash)
+}
+
+// DeepEqual is a strict version of EqualTo, which checks if all fields of two transactions (including the signature
+// and its public key) are equal. The proofs are compared by their bytes, and the metadata by their types and hashes.
+func (tx *Tx) DeepEqual(other *Tx) bool {
+	if tx == nil || other == nil {
+		return tx == other
+	}
+	if tx.Version != other.Version || tx.Type != other.Type || tx.LockTime != other.LockTime ||
+		tx.Fee != other.Fee || tx.PubKeyLastByteSender != other.PubKeyLastByteSender {
+		return false
+	}
+	if !bytes.Equal(tx.Info, other.Info) || !bytes.Equal(tx.SigPubKey, other.SigPubKey) || !bytes.Equal(tx.Sig, other.Sig) {
+		return false
+	}
+
+	if (tx.Proof == nil) != (other.Proof == nil) {
+		return false
+	}
+	if tx.Proof != nil && !bytes.Equal(tx.Proof.Bytes(), other.Proof.Bytes()) {
+		return false
+	}
+
+	if (tx.Metadata == nil) != (other.Metadata == nil) {
+		return false
+	}
+	if tx.Metadata != nil {
+		if tx.Metadata.GetType() != other.Metadata.GetType() {
+			return false
+		}
+		mdHash, otherMdHash := tx.Metadata.Hash(), other.Metadata.Hash()
+		if mdHash == nil || otherMdHash == nil || !mdHash.IsEqual(otherMdHash) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// MessageToSign returns the exact message signed by a (PRV) Tx, i.e, the bytes passed to the MLSAG (or Schnorr, for
+// non-private transactions) signer. It is the SHA3-256 hash of the JSON-encoded transaction whose Sig and SigPubKey
+// are set to empty byte slices, which is also Hash()[:].
+//
+// The message is stable across JSON round-trips of the signed transaction. Note that the inner PRV transaction of a
+// TxToken signs HashH(Hash() || tokenDataHash) instead; see TxToken. A transaction without proof is signed over the
+// hash of its TxBase.
+func (tx *Tx) MessageToSign() ([]byte, error) {
+	hash := tx.Hash()
+	if tx.Proof == nil {
+		hash = tx.TxBase.Hash()
+	}
+	if hash == nil {
+		return nil, fmt.Errorf("cannot hash tx")
+	}
+
+	return hash[:], nil
+}
+
 // HashWithoutMetadataSig calculates the hash of a Tx with out adding the signature of its metadata.
 func (tx Tx) HashWithoutMetadataSig() *common.Hash {
 	md := tx.GetMetadata()
@@ -237,15 +808,55 @@ func (tx *Tx) Init(txParams interface{}) error {
 		return utils.NewTransactionErr(utils.ExceedSizeTx, nil, strconv.Itoa(int(txSize)))
 	}
 
+	return tx.ValidateTypeConsistency()
+}
+
+// ValidateTypeConsistency cross-checks the declared type of a Tx against its proof and its metadata. It returns an
+// error if
+//	- the type is not a known transaction type;
+//	- the type is a PRV type but the proof carries token outputs (i.e., output coins with an asset tag);
+//	- the metadata type is not allowed in a transaction of this type.
+//
+// Metadata types whose allowed transaction types are unknown to the SDK are not checked.
+func (tx *Tx) ValidateTypeConsistency() error {
+	switch tx.Type {
+	case common.TxNormalType, common.TxRewardType, common.TxReturnStakingType, common.TxConversionType:
+		if tx.Proof != nil {
+			for i, c := range tx.Proof.GetOutputCoins() {
+				if c == nil {
+					continue
+				}
+				if cv2, ok := c.(*coin.CoinV2); ok && cv2.GetAssetTag() != nil {
+					return fmt.Errorf("transaction type %v is a PRV type but output coin %v carries token data", tx.Type, i)
+				}
+			}
+		}
+	case common.TxCustomTokenPrivacyType, common.TxTokenConversionType:
+	default:
+		return fmt.Errorf("unknown transaction type %q", tx.Type)
+	}
+
+	if tx.Metadata != nil {
+		mdType := tx.Metadata.GetType()
+		if metadata.HasMetaInfo(mdType) && !metadata.IsAvailableMetaInTxType(mdType, tx.Type) {
+			return fmt.Errorf("metadata type %v is not allowed in a transaction of type %v", mdType, tx.Type)
+		}
+	}
+
 	return nil
 }
 
 // InitTxSalary creates a PRV salary transaction to an OTA address.
+//
+// The salary coin must be a plain PRV coin whose amount is in the range [1, utils.MaxSalaryAmount].
 func (tx *Tx) InitTxSalary(otaCoin *coin.CoinV2, privateKey *key.PrivateKey, metaData metadata.Metadata) error {
 	tokenID := &common.Hash{}
 	if err := tokenID.SetBytes(common.PRVCoinID[:]); err != nil {
 		return utils.NewTransactionErr(utils.TokenIDInvalidError, err, tokenID.String())
 	}
+	if err := validateSalaryCoin(otaCoin, tokenID); err != nil {
+		return err
+	}
 
 	tx.Version = utils.TxVersion2Number
 	tx.Type = common.TxRewardType
@@ -279,7 +890,10 @@ func (tx *Tx) signOnMessage(inp []coin.PlainCoin, out []*coin.CoinV2, params *tx
 	if tx.Sig != nil {
 		return utils.NewTransactionErr(utils.UnexpectedError, fmt.Errorf("input transaction must be an unsigned one"))
 	}
-	ringSize := privacy.RingSize
+	ringSize, err := params.GetRingSize()
+	if err != nil {
+		return utils.NewTransactionErr(utils.SignTxError, err)
+	}
 
 	// Generate Ring
 	piBig, piErr := common.RandBigIntMaxRange(big.NewInt(int64(ringSize)))
@@ -343,7 +957,12 @@ func (tx *Tx) prove(params *tx_generic.TxPrivacyInitParams) error {
 	// inputCoins is plainCoin because it may have coinV1 with coinV2
 	inputCoins := params.InputCoins
 
-	tx.Proof, err = privacy.ProveV2(inputCoins, outputCoins, nil, false, params.PaymentInfo)
+	// the shared randoms are erased from the output coins once the proof is created, keep them for ProveSentTo.
+	if !isEstimationOnlyParams(params) {
+		tx.outputSecrets = newOutputSecrets(params.SenderSK, outputCoins)
+	}
+
+	tx.Proof, err = privacy.ProveV2(inputCoins, outputCoins, nil, false, params.PaymentInfo, isEstimationOnlyParams(params))
 	if err != nil {
 		return err
 	}
@@ -358,7 +977,29 @@ func (tx *Tx) prove(params *tx_generic.TxPrivacyInitParams) error {
 	return err
 }
 
+// RequiredDecoyCount returns the number of decoys needed to build the MLSAG ring of a transaction with numInputs
+// input coins and the given ring size: every row of the ring except the real one holds numInputs decoys.
+func RequiredDecoyCount(numInputs, ringSize int) int {
+	if numInputs <= 0 || ringSize <= 1 {
+		return 0
+	}
+	return numInputs * (ringSize - 1)
+}
+
+// RequiredDecoyData returns the number of decoy commitment indices, commitments, public keys and asset tags that must
+// be provided (via the utils.CommitmentIndices, utils.Commitments, utils.PublicKeys and utils.AssetTags kvArgs) to
+// create a transaction with numInputs input coins and the given ring size. Asset tags are only required for
+// confidential-asset (i.e, token) transactions.
+func RequiredDecoyData(numInputs, ringSize int, isConfidentialAsset bool) (numIndices, numCommitments, numPublicKeys, numAssetTags int) {
+	numDecoys := RequiredDecoyCount(numInputs, ringSize)
+	if isConfidentialAsset {
+		numAssetTags = numDecoys
+	}
+	return numDecoys, numDecoys, numDecoys, numAssetTags
+}
+
 func parseParamsForRing(kvArgs map[string]interface{}, lenInput, ringSize int) (cmtIndices []uint64, myIndices []uint64, commitments []*crypto.Point, publicKeys []*crypto.Point, assetTags []*crypto.Point, err error) {
+	numDecoys := RequiredDecoyCount(lenInput, ringSize)
 	if kvArgs == nil {
 		fmt.Println("kvArgs is nil: need more params to proceed")
 		return nil, nil, nil, nil, nil, fmt.Errorf("kvArgs is nil: need more params to proceed")
@@ -374,8 +1015,8 @@ func parseParamsForRing(kvArgs map[string]interface{}, lenInput, ringSize int) (
 	if !ok {
 		return nil, nil, nil, nil, nil, fmt.Errorf("cannot parse commitment indices: %v", tmp)
 	}
-	if len(cmtIndices) < lenInput*(ringSize-1) {
-		return nil, nil, nil, nil, nil, fmt.Errorf("not enough decoy commitment indices: have %v, need at least %v (%v input coins)", len(cmtIndices), lenInput*(ringSize-1), lenInput)
+	if len(cmtIndices) < numDecoys {
+		return nil, nil, nil, nil, nil, fmt.Errorf("not enough decoy commitment indices: have %v, need at least %v (%v input coins, ring size %v)", len(cmtIndices), numDecoys, lenInput, ringSize)
 	}
 
 	//Get list of decoy commitments.
@@ -388,8 +1029,8 @@ func parseParamsForRing(kvArgs map[string]interface{}, lenInput, ringSize int) (
 	if !ok {
 		return nil, nil, nil, nil, nil, fmt.Errorf("cannot parse decoy commitment indices: %v", tmp)
 	}
-	if len(commitments) < lenInput*(ringSize-1) {
-		return nil, nil, nil, nil, nil, fmt.Errorf("not enough decoy commitments: have %v, need at least %v (%v input coins)", len(commitments), lenInput*(ringSize-1), lenInput)
+	if len(commitments) < numDecoys {
+		return nil, nil, nil, nil, nil, fmt.Errorf("not enough decoy commitments: have %v, need at least %v (%v input coins, ring size %v)", len(commitments), numDecoys, lenInput, ringSize)
 	}
 
 	//Get list of decoy public keys
@@ -402,8 +1043,8 @@ func parseParamsForRing(kvArgs map[string]interface{}, lenInput, ringSize int) (
 	if !ok {
 		return nil, nil, nil, nil, nil, fmt.Errorf("cannot parse decoy public keys: %v", tmp)
 	}
-	if len(publicKeys) < lenInput*(ringSize-1) {
-		return nil, nil, nil, nil, nil, fmt.Errorf("not enough decoy public keys: have %v, need at least %v (%v input coins)", len(publicKeys), lenInput*(ringSize-1), lenInput)
+	if len(publicKeys) < numDecoys {
+		return nil, nil, nil, nil, nil, fmt.Errorf("not enough decoy public keys: have %v, need at least %v (%v input coins, ring size %v)", len(publicKeys), numDecoys, lenInput, ringSize)
 	}
 
 	//Get list of decoy asset tags
@@ -433,6 +1074,48 @@ func parseParamsForRing(kvArgs map[string]interface{}, lenInput, ringSize int) (
 	return
 }
 
+// ValidateRingShard checks that all input coins belong to the same shard, and that all the given decoy public keys
+// (if any) belong to that shard as well. It returns the shard of the inputs.
+//
+// MLSAG rings are verified against the output coins of a single shard, mixing coins of different shards results in
+// an invalid transaction.
+func ValidateRingShard(inputCoins []coin.PlainCoin, decoyPublicKeys []*crypto.Point) (byte, error) {
+	if len(inputCoins) == 0 {
+		return 0, utils.NewTransactionErr(utils.CrossShardInputError, fmt.Errorf("no input coin"))
+	}
+
+	var shardID byte
+	for i, inputCoin := range inputCoins {
+		if inputCoin == nil {
+			return 0, utils.NewTransactionErr(utils.CrossShardInputError, fmt.Errorf("input coin %v is nil", i))
+		}
+		coinShard, err := inputCoin.GetShardID()
+		if err != nil {
+			return 0, utils.NewTransactionErr(utils.GetShardIDByPublicKeyError, err)
+		}
+		if i == 0 {
+			shardID = coinShard
+		} else if coinShard != shardID {
+			return 0, utils.NewTransactionErr(utils.CrossShardInputError,
+				fmt.Errorf("input coin %v belongs to shard %v, input coin 0 belongs to shard %v", i, coinShard, shardID))
+		}
+	}
+
+	for i, pk := range decoyPublicKeys {
+		if pk == nil {
+			return 0, utils.NewTransactionErr(utils.CrossShardInputError, fmt.Errorf("decoy public key %v is nil", i))
+		}
+		pkBytes := pk.ToBytesS()
+		decoyShard := common.GetShardIDFromLastByte(pkBytes[len(pkBytes)-1])
+		if decoyShard != shardID {
+			return 0, utils.NewTransactionErr(utils.CrossShardInputError,
+				fmt.Errorf("decoy %v belongs to shard %v, input coins belong to shard %v", i, decoyShard, shardID))
+		}
+	}
+
+	return shardID, nil
+}
+
 func generateMLSAGRingWithIndexes(inputCoins []coin.PlainCoin, outputCoins []*coin.CoinV2, params *tx_generic.TxPrivacyInitParams, pi int, ringSize int) (*mlsag.Ring, [][]*big.Int, *crypto.Point, error) {
 	lenInput := len(inputCoins)
 	kvArgs := params.KvArgs
@@ -442,6 +1125,9 @@ func generateMLSAGRingWithIndexes(inputCoins []coin.PlainCoin, outputCoins []*co
 	if err != nil {
 		return nil, nil, nil, err
 	}
+	if _, err = ValidateRingShard(inputCoins, publicKeys); err != nil {
+		return nil, nil, nil, err
+	}
 
 	outputCoinsAsGeneric := make([]coin.Coin, len(outputCoins))
 	for i := 0; i < len(outputCoins); i++ {
@@ -485,13 +1171,7 @@ func generateMLSAGRingWithIndexes(inputCoins []coin.PlainCoin, outputCoins []*co
 }
 
 func createPrivateKeyMlsag(inputCoins []coin.PlainCoin, outputCoins []*coin.CoinV2, senderSK *key.PrivateKey, commitmentToZero *crypto.Point) ([]*crypto.Scalar, error) {
-	sumRand := new(crypto.Scalar).FromUint64(0)
-	for _, in := range inputCoins {
-		sumRand.Add(sumRand, in.GetRandomness())
-	}
-	for _, out := range outputCoins {
-		sumRand.Sub(sumRand, out.GetRandomness())
-	}
+	sumRand := sumRandomnessOfCoins(inputCoins, outputCoins)
 
 	privateKeyMlsag := make([]*crypto.Scalar, len(inputCoins)+1)
 	for i := 0; i < len(inputCoins); i += 1 {
@@ -509,3 +1189,89 @@ func createPrivateKeyMlsag(inputCoins []coin.PlainCoin, outputCoins []*coin.Coin
 	privateKeyMlsag[len(inputCoins)] = sumRand
 	return privateKeyMlsag, nil
 }
+
+// sumRandomnessOfCoins returns the sum of the randomness of the input coins minus that of the output coins.
+func sumRandomnessOfCoins(inputCoins []coin.PlainCoin, outputCoins []*coin.CoinV2) *crypto.Scalar {
+	sumRand := new(crypto.Scalar).FromUint64(0)
+	for _, in := range inputCoins {
+		sumRand.Add(sumRand, in.GetRandomness())
+	}
+	for _, out := range outputCoins {
+		sumRand.Sub(sumRand, out.GetRandomness())
+	}
+	return sumRand
+}
+
+// VerifyCommitmentToZero performs the balance check done when signing a (non-CA) transaction: it checks if `claimed`,
+// i.e, the sum of the input commitments minus the sum of the output commitments and the fee commitment (the last
+// column of the MLSAG ring), is a commitment to zero under the randomness of the given coins. If so, the values of the
+// inputs and the outputs (with fee) are balanced.
+//
+// The input coins must be decrypted coins owned by senderSK; an error is returned otherwise.
+func VerifyCommitmentToZero(inputCoins []coin.PlainCoin, outputCoins []*coin.CoinV2, senderSK key.PrivateKey, claimed *crypto.Point) (bool, error) {
+	if claimed == nil {
+		return false, fmt.Errorf("claimed commitment to zero is nil")
+	}
+	for i, inputCoin := range inputCoins {
+		if inputCoin == nil || inputCoin.GetRandomness() == nil || inputCoin.GetPublicKey() == nil {
+			return false, fmt.Errorf("input coin %v is not a decrypted coin", i)
+		}
+		privateKey, err := inputCoin.ParsePrivateKeyOfCoin(senderSK)
+		if err != nil {
+			return false, fmt.Errorf("cannot parse the private key of input coin %v: %v", i, err)
+		}
+		if !crypto.IsPointEqual(new(crypto.Point).ScalarMultBase(privateKey), inputCoin.GetPublicKey()) {
+			return false, fmt.Errorf("input coin %v does not belong to the sender", i)
+		}
+	}
+	for i, outputCoin := range outputCoins {
+		if outputCoin == nil || outputCoin.GetRandomness() == nil {
+			return false, fmt.Errorf("output coin %v has no randomness", i)
+		}
+	}
+
+	sumRand := sumRandomnessOfCoins(inputCoins, outputCoins)
+	commitmentToZeroRecomputed := new(crypto.Point).ScalarMult(crypto.PedCom.G[crypto.PedersenRandomnessIndex], sumRand)
+
+	return crypto.IsPointEqual(commitmentToZeroRecomputed, claimed), nil
+}
+
+// isEstimationOnlyParams checks if the given parameters request an estimation-only transaction.
+func isEstimationOnlyParams(params *tx_generic.TxPrivacyInitParams) bool {
+	if params == nil || params.KvArgs == nil {
+		return false
+	}
+	estimationOnly, ok := params.KvArgs[utils.EstimationOnly].(bool)
+	return ok && estimationOnly
+}
+
+// isEstimationOnlyProof checks if the given proof is a ProofV2 without range proofs.
+func isEstimationOnlyProof(proof privacy.Proof) bool {
+	if proof == nil {
+		return false
+	}
+	proofV2, ok := proof.(*privacy.ProofV2)
+	return ok && proofV2.IsEstimationOnly()
+}
+
+// validateSalaryCoin checks if a salary coin is a plain coin of the given (PRV) tokenID with a sane amount.
+func validateSalaryCoin(otaCoin *coin.CoinV2, prvTokenID *common.Hash) error {
+	if otaCoin == nil {
+		return utils.NewTransactionErr(utils.UnexpectedError, fmt.Errorf("salary coin is nil"))
+	}
+	if assetTag := otaCoin.GetAssetTag(); assetTag != nil && !crypto.IsPointEqual(assetTag, crypto.HashToPoint(prvTokenID[:])) {
+		return utils.NewTransactionErr(utils.TokenIDInvalidError,
+			fmt.Errorf("salary coin must be a PRV coin, got asset tag %v", assetTag.String()), assetTag.String())
+	}
+	if otaCoin.IsEncrypted() {
+		return utils.NewTransactionErr(utils.InvalidSalaryAmountError, fmt.Errorf("salary coin must not be encrypted"), "encrypted")
+	}
+
+	amount := otaCoin.GetValue()
+	if amount == 0 || amount > utils.MaxSalaryAmount {
+		return utils.NewTransactionErr(utils.InvalidSalaryAmountError,
+			fmt.Errorf("salary amount %v not in range [1, %v]", amount, utils.MaxSalaryAmount), amount)
+	}
+
+	return nil
+}
@@ -135,8 +135,12 @@ func generateMlsagRingWithIndexesCA(inputCoins []coin.PlainCoin, outputCoins []*
 	if err != nil {
 		return nil, nil, nil, utils.NewTransactionErr(utils.UnexpectedError, fmt.Errorf("parseParamsForRing error: %v", err))
 	}
-	if len(assetTags) < len(inputCoins)*(ringSize-1) {
-		return nil, nil, nil, fmt.Errorf("not enough decoy asset tags: have %v, need at least %v (%v input coins)", len(assetTags), len(inputCoins)*(ringSize-1), len(inputCoins))
+	_, _, _, numAssetTags := RequiredDecoyData(len(inputCoins), ringSize, true)
+	if len(assetTags) < numAssetTags {
+		return nil, nil, nil, fmt.Errorf("not enough decoy asset tags: have %v, need at least %v (%v input coins, ring size %v)", len(assetTags), numAssetTags, len(inputCoins), ringSize)
+	}
+	if _, err = ValidateRingShard(inputCoins, publicKeys); err != nil {
+		return nil, nil, nil, err
 	}
 
 	outputCoinsAsGeneric := make([]coin.Coin, len(outputCoins))
@@ -244,7 +248,7 @@ func (tx *Tx) proveCA(params *tx_generic.TxPrivacyInitParams) (bool, error) {
 
 	// inputCoins is plainCoin because it may have coinV1 with coinV2
 	inputCoins := params.InputCoins
-	tx.Proof, err = privacy.ProveV2(inputCoins, outputCoins, sharedSecrets, true, params.PaymentInfo)
+	tx.Proof, err = privacy.ProveV2(inputCoins, outputCoins, sharedSecrets, true, params.PaymentInfo, isEstimationOnlyParams(params))
 	if err != nil {
 		log.Printf("Error in privacy_v2.Prove, error %v ", err)
 		return false, err
@@ -258,7 +262,10 @@ func (tx *Tx) signCA(inp []coin.PlainCoin, out []*coin.CoinV2, outputSharedSecre
 	if tx.Sig != nil {
 		return utils.NewTransactionErr(utils.UnexpectedError, fmt.Errorf("input transaction must be an unsigned one"))
 	}
-	ringSize := privacy.RingSize
+	ringSize, err := params.GetRingSize()
+	if err != nil {
+		return utils.NewTransactionErr(utils.SignTxError, err)
+	}
 
 	// Generate Ring
 	piBig, piErr := common.RandBigIntMaxRange(big.NewInt(int64(ringSize)))
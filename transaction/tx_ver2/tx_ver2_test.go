@@ -0,0 +1,1559 @@
+package tx_ver2
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/incognitochain/go-incognito-sdk-v2/coin"
+	"github.com/incognitochain/go-incognito-sdk-v2/common"
+	"github.com/incognitochain/go-incognito-sdk-v2/common/base58"
+	"github.com/incognitochain/go-incognito-sdk-v2/crypto"
+	"github.com/incognitochain/go-incognito-sdk-v2/key"
+	"github.com/incognitochain/go-incognito-sdk-v2/metadata"
+	"github.com/incognitochain/go-incognito-sdk-v2/privacy"
+	"github.com/incognitochain/go-incognito-sdk-v2/privacy/v2/mlsag"
+	"github.com/incognitochain/go-incognito-sdk-v2/transaction/tx_generic"
+	"github.com/incognitochain/go-incognito-sdk-v2/transaction/tx_ver1"
+	"github.com/incognitochain/go-incognito-sdk-v2/transaction/utils"
+	"github.com/incognitochain/go-incognito-sdk-v2/wallet"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var numTests = 10
+
+func newRandomKeySet() *key.KeySet {
+	return new(key.KeySet).GenerateKey(common.RandBytes(32))
+}
+
+func TestTx_VerifySig_RewardTx(t *testing.T) {
+	for i := 0; i < numTests; i++ {
+		signer := newRandomKeySet()
+		receiver := newRandomKeySet()
+
+		paymentInfo := key.InitPaymentInfo(receiver.PaymentAddress, common.RandUint64()%1000000+1, []byte{})
+		otaCoin, err := coin.NewCoinFromPaymentInfo(coin.NewMintCoinParams(paymentInfo))
+		assert.Equal(t, nil, err, fmt.Errorf("NewCoinFromPaymentInfo error: %v", err))
+
+		tx := new(Tx)
+		err = tx.InitTxSalary(otaCoin, &signer.PrivateKey, nil)
+		assert.Equal(t, nil, err, fmt.Errorf("InitTxSalary error: %v", err))
+		assert.Equal(t, true, tx.IsNonPrivacy())
+
+		isValid, err := tx.VerifySig()
+		assert.Equal(t, nil, err, fmt.Errorf("VerifySig error: %v", err))
+		assert.Equal(t, true, isValid)
+
+		// the signature must remain valid after a JSON round-trip
+		jsb, err := json.Marshal(tx)
+		assert.Equal(t, nil, err)
+		tx1 := new(Tx)
+		err = json.Unmarshal(jsb, tx1)
+		assert.Equal(t, nil, err)
+		isValid, err = tx1.VerifySig()
+		assert.Equal(t, nil, err, fmt.Errorf("VerifySig error: %v", err))
+		assert.Equal(t, true, isValid)
+
+		// tampering with the transaction invalidates the signature
+		tx1.LockTime++
+		isValid, err = tx1.VerifySig()
+		assert.Equal(t, nil, err, fmt.Errorf("VerifySig error: %v", err))
+		assert.Equal(t, false, isValid)
+	}
+}
+
+func TestTx_VerifySig_PTokenFeeTx(t *testing.T) {
+	for i := 0; i < numTests; i++ {
+		signer := newRandomKeySet()
+
+		params := tx_generic.NewTxPrivacyInitParams(&signer.PrivateKey, []*key.PaymentInfo{}, []coin.PlainCoin{},
+			0, false, nil, nil, nil, nil)
+		tx := new(Tx)
+		err := tx.Init(params)
+		assert.Equal(t, nil, err, fmt.Errorf("Init error: %v", err))
+		assert.Equal(t, true, tx.IsNonPrivacy())
+
+		isValid, err := tx.VerifySig()
+		assert.Equal(t, nil, err, fmt.Errorf("VerifySig error: %v", err))
+		assert.Equal(t, true, isValid)
+
+		// a signature from another key is rejected
+		tx.SigPubKey = newRandomKeySet().PaymentAddress.Pk
+		isValid, err = tx.VerifySig()
+		assert.Equal(t, nil, err, fmt.Errorf("VerifySig error: %v", err))
+		assert.Equal(t, false, isValid)
+	}
+}
+
+func TestTx_ConcurrentBuild(t *testing.T) {
+	numWorkers := 8
+	signer := newRandomKeySet()
+	receiver := newRandomKeySet()
+
+	// every worker signs the same payment with the same key, so any collision can only come from shared randomness.
+	txs := make([]*Tx, numWorkers*numTests)
+	errs := make([]error, numWorkers*numTests)
+	var wg sync.WaitGroup
+	for w := 0; w < numWorkers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < numTests; i++ {
+				paymentInfo := key.InitPaymentInfo(receiver.PaymentAddress, 1000, []byte{})
+				otaCoin, err := coin.NewCoinFromPaymentInfo(coin.NewMintCoinParams(paymentInfo))
+				if err != nil {
+					errs[w*numTests+i] = err
+					continue
+				}
+				tx := new(Tx)
+				errs[w*numTests+i] = tx.InitTxSalary(otaCoin, &signer.PrivateKey, nil)
+				txs[w*numTests+i] = tx
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	hashes := make(map[string]bool)
+	publicKeys := make(map[string]bool)
+	for i, tx := range txs {
+		assert.Equal(t, nil, errs[i], fmt.Errorf("InitTxSalary error: %v", errs[i]))
+		if errs[i] != nil {
+			continue
+		}
+
+		isValid, err := tx.VerifySig()
+		assert.Equal(t, nil, err, fmt.Errorf("VerifySig error: %v", err))
+		assert.Equal(t, true, isValid)
+
+		txHash := tx.Hash().String()
+		assert.Equal(t, false, hashes[txHash], fmt.Errorf("duplicate tx %v", txHash))
+		hashes[txHash] = true
+
+		outCoin := tx.GetProof().GetOutputCoins()[0]
+		pkStr := outCoin.GetPublicKey().String()
+		assert.Equal(t, false, publicKeys[pkStr], fmt.Errorf("duplicate output public key %v", pkStr))
+		publicKeys[pkStr] = true
+	}
+
+	// ring-signed private transactions of the same sender, which also exercise the bulletproofs and MLSAG signing.
+	sender, err := wallet.GenRandomWalletForShardID(byte(common.RandInt() % common.MaxShardNumber))
+	if err != nil {
+		panic(err)
+	}
+	numPrivateTxs := 1
+	type privateTxResult struct {
+		tx             *Tx
+		allIndices     []uint64
+		allPublicKeys  []*crypto.Point
+		allCommitments []*crypto.Point
+	}
+	results := make([]privateTxResult, numWorkers*numPrivateTxs)
+	for w := 0; w < numWorkers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < numPrivateTxs; i++ {
+				j := w*numPrivateTxs + i
+				// each transaction references its own range of on-chain coins.
+				tx, allIndices, allPublicKeys, allCommitments := newTestPrivateTxFrom(t, sender, 1500, 2, uint64(j*1000))
+				results[j] = privateTxResult{tx, allIndices, allPublicKeys, allCommitments}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	keyImages := make(map[string]bool)
+	for _, res := range results {
+		if res.tx == nil || res.tx.GetProof() == nil {
+			t.Fatalf("private transaction not created")
+		}
+		isValid, err := VerifyTxVer2(res.tx, map[string]interface{}{
+			utils.CommitmentIndices: res.allIndices,
+			utils.PublicKeys:        res.allPublicKeys,
+			utils.Commitments:       res.allCommitments,
+		})
+		assert.Equal(t, nil, err, fmt.Errorf("VerifyTxVer2 error: %v", err))
+		assert.Equal(t, true, isValid)
+
+		txHash := res.tx.Hash().String()
+		assert.Equal(t, false, hashes[txHash], fmt.Errorf("duplicate tx %v", txHash))
+		hashes[txHash] = true
+
+		for _, outCoin := range res.tx.GetProof().GetOutputCoins() {
+			pkStr := outCoin.GetPublicKey().String()
+			assert.Equal(t, false, publicKeys[pkStr], fmt.Errorf("duplicate output public key %v", pkStr))
+			publicKeys[pkStr] = true
+		}
+		for _, inCoin := range res.tx.GetProof().GetInputCoins() {
+			kiStr := inCoin.GetKeyImage().String()
+			assert.Equal(t, false, keyImages[kiStr], fmt.Errorf("duplicate key image %v", kiStr))
+			keyImages[kiStr] = true
+		}
+	}
+}
+
+func TestTx_InitTxSalary_Validation(t *testing.T) {
+	signer := newRandomKeySet()
+	receiver := newRandomKeySet()
+
+	// an amount above the maximum is rejected
+	paymentInfo := key.InitPaymentInfo(receiver.PaymentAddress, utils.MaxSalaryAmount+1, []byte{})
+	otaCoin, err := coin.NewCoinFromPaymentInfo(coin.NewMintCoinParams(paymentInfo))
+	assert.Equal(t, nil, err, fmt.Errorf("NewCoinFromPaymentInfo error: %v", err))
+	err = new(Tx).InitTxSalary(otaCoin, &signer.PrivateKey, nil)
+	assert.NotEqual(t, nil, err)
+	assert.Equal(t, utils.ErrCodeMessage[utils.InvalidSalaryAmountError].Code, err.(*utils.TransactionError).Code)
+
+	// the maximum amount itself is fine
+	paymentInfo = key.InitPaymentInfo(receiver.PaymentAddress, utils.MaxSalaryAmount, []byte{})
+	otaCoin, err = coin.NewCoinFromPaymentInfo(coin.NewMintCoinParams(paymentInfo))
+	assert.Equal(t, nil, err, fmt.Errorf("NewCoinFromPaymentInfo error: %v", err))
+	err = new(Tx).InitTxSalary(otaCoin, &signer.PrivateKey, nil)
+	assert.Equal(t, nil, err, fmt.Errorf("InitTxSalary error: %v", err))
+
+	// a zero amount is rejected
+	paymentInfo = key.InitPaymentInfo(receiver.PaymentAddress, 0, []byte{})
+	otaCoin, err = coin.NewCoinFromPaymentInfo(coin.NewMintCoinParams(paymentInfo))
+	assert.Equal(t, nil, err, fmt.Errorf("NewCoinFromPaymentInfo error: %v", err))
+	err = new(Tx).InitTxSalary(otaCoin, &signer.PrivateKey, nil)
+	assert.NotEqual(t, nil, err)
+
+	// a non-PRV coin is rejected
+	paymentInfo = key.InitPaymentInfo(receiver.PaymentAddress, 1000, []byte{})
+	otaCoin, err = coin.NewCoinFromPaymentInfo(coin.NewMintCoinParams(paymentInfo))
+	assert.Equal(t, nil, err, fmt.Errorf("NewCoinFromPaymentInfo error: %v", err))
+	err = otaCoin.SetPlainTokenID(&common.Hash{1})
+	assert.Equal(t, nil, err)
+	err = new(Tx).InitTxSalary(otaCoin, &signer.PrivateKey, nil)
+	assert.NotEqual(t, nil, err)
+	assert.Equal(t, utils.ErrCodeMessage[utils.TokenIDInvalidError].Code, err.(*utils.TransactionError).Code)
+
+	// a PRV coin with an explicit PRV asset tag is accepted
+	otaCoin, err = coin.NewCoinFromPaymentInfo(coin.NewMintCoinParams(paymentInfo))
+	assert.Equal(t, nil, err, fmt.Errorf("NewCoinFromPaymentInfo error: %v", err))
+	err = otaCoin.SetPlainTokenID(&common.PRVCoinID)
+	assert.Equal(t, nil, err)
+	err = new(Tx).InitTxSalary(otaCoin, &signer.PrivateKey, nil)
+	assert.Equal(t, nil, err, fmt.Errorf("InitTxSalary error: %v", err))
+}
+
+func TestTx_FeePerKB(t *testing.T) {
+	signer := newRandomKeySet()
+	receiver := newRandomKeySet()
+
+	paymentInfo := key.InitPaymentInfo(receiver.PaymentAddress, 1000, make([]byte, 3000))
+	otaCoin, err := coin.NewCoinFromPaymentInfo(coin.NewMintCoinParams(paymentInfo))
+	assert.Equal(t, nil, err, fmt.Errorf("NewCoinFromPaymentInfo error: %v", err))
+	tx := new(Tx)
+	err = tx.InitTxSalary(otaCoin, &signer.PrivateKey, nil)
+	assert.Equal(t, nil, err, fmt.Errorf("InitTxSalary error: %v", err))
+
+	txSize := tx.GetTxActualSize()
+	assert.Equal(t, true, txSize > 1, fmt.Errorf("unexpected tx size %v", txSize))
+
+	feePerKB, err := tx.FeePerKB()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, uint64(0), feePerKB)
+
+	tx.Fee = 100 * txSize
+	feePerKB, err = tx.FeePerKB()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, uint64(100), feePerKB)
+
+	tx.Fee = 100*txSize + txSize - 1
+	feePerKB, err = tx.FeePerKB()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, uint64(100), feePerKB)
+}
+
+func TestTx_GetNonBurnReceiverData(t *testing.T) {
+	burningWallet, err := wallet.Base58CheckDeserialize(common.BurningAddress2)
+	if err != nil {
+		panic(err)
+	}
+
+	for i := 0; i < numTests; i++ {
+		receiver := newRandomKeySet()
+		sender := newRandomKeySet()
+
+		// the tx sends a part to a receiver, burns another part, and returns the change to the sender
+		outputCoins := make([]coin.Coin, 0)
+		for _, addr := range []key.PaymentAddress{receiver.PaymentAddress, burningWallet.KeySet.PaymentAddress, sender.PaymentAddress} {
+			paymentInfo := key.InitPaymentInfo(addr, common.RandUint64()%1000000+1, []byte{})
+			outCoin, err := coin.NewCoinFromPaymentInfo(coin.NewTransferCoinParams(paymentInfo))
+			assert.Equal(t, nil, err, fmt.Errorf("NewCoinFromPaymentInfo error: %v", err))
+			outputCoins = append(outputCoins, outCoin)
+		}
+		proof := new(privacy.ProofV2)
+		proof.Init()
+		err = proof.SetOutputCoins(outputCoins)
+		assert.Equal(t, nil, err)
+		tx := &Tx{}
+		tx.Proof = proof
+
+		allCoins, err := tx.GetReceiverData()
+		assert.Equal(t, nil, err)
+		assert.Equal(t, 3, len(allCoins))
+
+		nonBurnCoins, err := tx.GetNonBurnReceiverData()
+		assert.Equal(t, nil, err)
+		assert.Equal(t, 2, len(nonBurnCoins))
+		assert.Equal(t, outputCoins[0].GetPublicKey().ToBytesS(), nonBurnCoins[0].GetPublicKey().ToBytesS())
+		assert.Equal(t, outputCoins[2].GetPublicKey().ToBytesS(), nonBurnCoins[1].GetPublicKey().ToBytesS())
+		assert.Equal(t, len(tx.ListOTAHashH()), len(nonBurnCoins))
+	}
+
+	// a tx without output coins
+	nonBurnCoins, err := new(Tx).GetNonBurnReceiverData()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(nonBurnCoins))
+}
+
+func TestTx_AllOutputOTAs(t *testing.T) {
+	burningWallet, err := wallet.Base58CheckDeserialize(common.BurningAddress2)
+	if err != nil {
+		panic(err)
+	}
+
+	for i := 0; i < numTests; i++ {
+		receiver := newRandomKeySet()
+		sender := newRandomKeySet()
+
+		// mixed outputs: a payment, a burn, and the change
+		outputCoins := make([]coin.Coin, 0)
+		expectedOTAs := make([]string, 0)
+		for _, addr := range []key.PaymentAddress{receiver.PaymentAddress, burningWallet.KeySet.PaymentAddress, sender.PaymentAddress} {
+			paymentInfo := key.InitPaymentInfo(addr, common.RandUint64()%1000000+1, []byte{})
+			outCoin, err := coin.NewCoinFromPaymentInfo(coin.NewTransferCoinParams(paymentInfo))
+			assert.Equal(t, nil, err, fmt.Errorf("NewCoinFromPaymentInfo error: %v", err))
+			outputCoins = append(outputCoins, outCoin)
+			expectedOTAs = append(expectedOTAs, base58.Base58Check{}.Encode(outCoin.GetPublicKey().ToBytesS(), common.ZeroByte))
+		}
+		proof := new(privacy.ProofV2)
+		proof.Init()
+		err = proof.SetOutputCoins(outputCoins)
+		assert.Equal(t, nil, err)
+		tx := &Tx{}
+		tx.Proof = proof
+
+		otas := tx.AllOutputOTAs()
+		assert.Equal(t, expectedOTAs, otas)
+		assert.Equal(t, len(tx.ListOTAHashH())+1, len(otas))
+	}
+
+	// a tx without output coins
+	assert.Equal(t, 0, len(new(Tx).AllOutputOTAs()))
+}
+
+func TestVerifyCommitmentToZero(t *testing.T) {
+	newCoins := func(addr key.PaymentAddress, amounts ...uint64) []*coin.CoinV2 {
+		res := make([]*coin.CoinV2, 0)
+		for _, amount := range amounts {
+			paymentInfo := key.InitPaymentInfo(addr, amount, []byte{})
+			c, err := coin.NewCoinFromPaymentInfo(coin.NewTransferCoinParams(paymentInfo))
+			if err != nil {
+				panic(err)
+			}
+			res = append(res, c)
+		}
+		return res
+	}
+	claimedCommitmentToZero := func(inputCoins []coin.PlainCoin, outputCoins []*coin.CoinV2, fee uint64) *crypto.Point {
+		outputCoinsAsGeneric := make([]coin.Coin, 0)
+		for _, c := range outputCoins {
+			outputCoinsAsGeneric = append(outputCoinsAsGeneric, c)
+		}
+		res := new(crypto.Point).Identity()
+		for _, c := range inputCoins {
+			res.Add(res, c.GetCommitment())
+		}
+		return res.Sub(res, tx_generic.CalculateSumOutputsWithFee(outputCoinsAsGeneric, fee))
+	}
+
+	for i := 0; i < numTests; i++ {
+		sender := newRandomKeySet()
+		receiver := newRandomKeySet()
+		fee := uint64(100)
+
+		inputCoins := make([]coin.PlainCoin, 0)
+		for _, c := range newCoins(sender.PaymentAddress, 1000, 2000, 3000) {
+			inputCoins = append(inputCoins, c)
+		}
+
+		// balanced: 6000 = 4000 + 1900 + 100
+		outputCoins := append(newCoins(receiver.PaymentAddress, 4000), newCoins(sender.PaymentAddress, 1900)...)
+		isValid, err := VerifyCommitmentToZero(inputCoins, outputCoins, sender.PrivateKey, claimedCommitmentToZero(inputCoins, outputCoins, fee))
+		assert.Equal(t, nil, err, fmt.Errorf("VerifyCommitmentToZero error: %v", err))
+		assert.Equal(t, true, isValid)
+
+		// unbalanced: 6000 < 4000 + 2000 + 100
+		outputCoins = append(newCoins(receiver.PaymentAddress, 4000), newCoins(sender.PaymentAddress, 2000)...)
+		isValid, err = VerifyCommitmentToZero(inputCoins, outputCoins, sender.PrivateKey, claimedCommitmentToZero(inputCoins, outputCoins, fee))
+		assert.Equal(t, nil, err, fmt.Errorf("VerifyCommitmentToZero error: %v", err))
+		assert.Equal(t, false, isValid)
+
+		// the input coins do not belong to the given key
+		_, err = VerifyCommitmentToZero(inputCoins, outputCoins, receiver.PrivateKey, claimedCommitmentToZero(inputCoins, outputCoins, fee))
+		assert.NotEqual(t, nil, err)
+	}
+}
+
+func TestRequiredDecoyCount(t *testing.T) {
+	testCases := []struct {
+		numInputs, ringSize, expected int
+	}{
+		{1, 8, 7},
+		{2, 8, 14},
+		{30, 8, 210},
+		{5, 2, 5},
+		{3, 1, 0},
+		{0, 8, 0},
+		{-1, 8, 0},
+		{4, 0, 0},
+	}
+
+	for _, tc := range testCases {
+		numDecoys := RequiredDecoyCount(tc.numInputs, tc.ringSize)
+		assert.Equal(t, tc.expected, numDecoys, fmt.Errorf("inputs %v, ring size %v", tc.numInputs, tc.ringSize))
+
+		numIndices, numCommitments, numPublicKeys, numAssetTags := RequiredDecoyData(tc.numInputs, tc.ringSize, false)
+		assert.Equal(t, tc.expected, numIndices)
+		assert.Equal(t, tc.expected, numCommitments)
+		assert.Equal(t, tc.expected, numPublicKeys)
+		assert.Equal(t, 0, numAssetTags)
+
+		_, _, _, numAssetTags = RequiredDecoyData(tc.numInputs, tc.ringSize, true)
+		assert.Equal(t, tc.expected, numAssetTags)
+	}
+}
+
+func TestValidateRingShard(t *testing.T) {
+	newCoinsOfShard := func(shardID byte, n int) []*coin.CoinV2 {
+		w, err := wallet.GenRandomWalletForShardID(shardID)
+		if err != nil {
+			panic(err)
+		}
+		res := make([]*coin.CoinV2, 0)
+		for i := 0; i < n; i++ {
+			paymentInfo := key.InitPaymentInfo(w.KeySet.PaymentAddress, 1000, []byte{})
+			c, err := coin.NewCoinFromPaymentInfo(coin.NewTransferCoinParams(paymentInfo))
+			if err != nil {
+				panic(err)
+			}
+			res = append(res, c)
+		}
+		return res
+	}
+	newRingParams := func(inputCoins []coin.PlainCoin, decoys []*coin.CoinV2) map[string]interface{} {
+		cmtIndices := make([]uint64, 0)
+		commitments := make([]*crypto.Point, 0)
+		publicKeys := make([]*crypto.Point, 0)
+		for i, decoy := range decoys {
+			cmtIndices = append(cmtIndices, uint64(i))
+			commitments = append(commitments, decoy.GetCommitment())
+			publicKeys = append(publicKeys, decoy.GetPublicKey())
+		}
+		myIndices := make([]uint64, len(inputCoins))
+
+		return map[string]interface{}{
+			utils.CommitmentIndices: cmtIndices,
+			utils.Commitments:       commitments,
+			utils.PublicKeys:        publicKeys,
+			utils.AssetTags:         []*crypto.Point{},
+			utils.MyIndices:         myIndices,
+		}
+	}
+	toPlainCoins := func(coins []*coin.CoinV2) []coin.PlainCoin {
+		res := make([]coin.PlainCoin, 0)
+		for _, c := range coins {
+			res = append(res, c)
+		}
+		return res
+	}
+	isCrossShardErr := func(err error) bool {
+		txErr, ok := err.(*utils.TransactionError)
+		return ok && txErr.Code == utils.ErrCodeMessage[utils.CrossShardInputError].Code
+	}
+
+	for i := 0; i < numTests; i++ {
+		shardID := byte(common.RandInt() % common.MaxShardNumber)
+		otherShardID := byte((int(shardID) + 1) % common.MaxShardNumber)
+		numInputs := 1 + common.RandInt()%3
+		numDecoys := RequiredDecoyCount(numInputs, privacy.RingSize)
+
+		inputCoins := toPlainCoins(newCoinsOfShard(shardID, numInputs))
+		decoys := newCoinsOfShard(shardID, numDecoys)
+		publicKeys := newRingParams(inputCoins, decoys)[utils.PublicKeys].([]*crypto.Point)
+
+		// same shard
+		gotShardID, err := ValidateRingShard(inputCoins, publicKeys)
+		assert.Equal(t, nil, err, fmt.Errorf("ValidateRingShard error: %v", err))
+		assert.Equal(t, shardID, gotShardID)
+
+		// input coins from two shards
+		mixedInputs := append(toPlainCoins(newCoinsOfShard(otherShardID, 1)), inputCoins...)
+		_, err = ValidateRingShard(mixedInputs, nil)
+		assert.Equal(t, true, isCrossShardErr(err), fmt.Errorf("expected a cross-shard error, got %v", err))
+
+		// a decoy from another shard
+		mixedDecoys := append(newCoinsOfShard(otherShardID, 1), decoys[1:]...)
+		kvArgs := newRingParams(inputCoins, mixedDecoys)
+		_, err = ValidateRingShard(inputCoins, kvArgs[utils.PublicKeys].([]*crypto.Point))
+		assert.Equal(t, true, isCrossShardErr(err), fmt.Errorf("expected a cross-shard error, got %v", err))
+
+		// the build path rejects the mixed ring
+		params := &tx_generic.TxPrivacyInitParams{KvArgs: kvArgs}
+		_, _, _, err = generateMLSAGRingWithIndexes(inputCoins, []*coin.CoinV2{}, params, 0, privacy.RingSize)
+		assert.Equal(t, true, isCrossShardErr(err), fmt.Errorf("expected a cross-shard error, got %v", err))
+	}
+}
+
+// testCustomMetadata is a metadata type unknown to the SDK.
+type testCustomMetadata struct {
+	metadata.MetadataBase
+	Note string
+}
+
+func TestTx_UnmarshalJSON_RegisteredMetadata(t *testing.T) {
+	const customMetadataType = 10002
+
+	signer := newRandomKeySet()
+	paymentInfo := key.InitPaymentInfo(signer.PaymentAddress, common.RandUint64()%1000000+1, []byte{})
+	otaCoin, err := coin.NewCoinFromPaymentInfo(coin.NewMintCoinParams(paymentInfo))
+	assert.Equal(t, nil, err, fmt.Errorf("NewCoinFromPaymentInfo error: %v", err))
+
+	md := &testCustomMetadata{MetadataBase: *metadata.NewMetadataBase(customMetadataType), Note: "hello"}
+	tx := new(Tx)
+	err = tx.InitTxSalary(otaCoin, &signer.PrivateKey, md)
+	assert.Equal(t, nil, err, fmt.Errorf("InitTxSalary error: %v", err))
+	jsb, err := json.Marshal(tx)
+	assert.Equal(t, nil, err)
+
+	// the metadata type is unknown
+	err = json.Unmarshal(jsb, new(Tx))
+	assert.NotEqual(t, nil, err)
+
+	// teach the SDK about the new type
+	metadata.RegisterMetadata(customMetadataType, func() metadata.Metadata { return &testCustomMetadata{} }, "TestCustomMetadata")
+	defer metadata.UnregisterMetadata(customMetadataType)
+	tx1 := new(Tx)
+	err = json.Unmarshal(jsb, tx1)
+	assert.Equal(t, nil, err, fmt.Errorf("UnmarshalJSON error: %v", err))
+	assert.Equal(t, customMetadataType, tx1.GetMetadataType())
+	md1, ok := tx1.GetMetadata().(*testCustomMetadata)
+	assert.Equal(t, true, ok, fmt.Errorf("expected *testCustomMetadata, got %T", tx1.GetMetadata()))
+	assert.Equal(t, "hello", md1.Note)
+
+	isValid, err := tx1.VerifySig()
+	assert.Equal(t, nil, err, fmt.Errorf("VerifySig error: %v", err))
+	assert.Equal(t, true, isValid)
+}
+
+func TestTx_MessageToSign(t *testing.T) {
+	for i := 0; i < numTests; i++ {
+		signer := newRandomKeySet()
+		receiver := newRandomKeySet()
+
+		paymentInfo := key.InitPaymentInfo(receiver.PaymentAddress, common.RandUint64()%1000000+1, []byte{})
+		otaCoin, err := coin.NewCoinFromPaymentInfo(coin.NewMintCoinParams(paymentInfo))
+		assert.Equal(t, nil, err, fmt.Errorf("NewCoinFromPaymentInfo error: %v", err))
+
+		tx := new(Tx)
+		err = tx.InitTxSalary(otaCoin, &signer.PrivateKey, nil)
+		assert.Equal(t, nil, err, fmt.Errorf("InitTxSalary error: %v", err))
+
+		msg, err := tx.MessageToSign()
+		assert.Equal(t, nil, err)
+		assert.Equal(t, tx.Hash()[:], msg)
+
+		// the signature is over the message
+		isValid, err := tx_generic.VerifySigNoPrivacy(tx.Sig, tx.SigPubKey, msg)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, true, isValid)
+
+		// the message does not depend on the signature
+		unsignedTx := *tx
+		unsignedTx.Sig, unsignedTx.SigPubKey = nil, nil
+		unsignedMsg, err := unsignedTx.MessageToSign()
+		assert.Equal(t, nil, err)
+		assert.Equal(t, msg, unsignedMsg)
+
+		// and is stable across JSON round-trips
+		jsb, err := json.Marshal(tx)
+		assert.Equal(t, nil, err)
+		tx1 := new(Tx)
+		err = json.Unmarshal(jsb, tx1)
+		assert.Equal(t, nil, err)
+		msg1, err := tx1.MessageToSign()
+		assert.Equal(t, nil, err)
+		assert.Equal(t, msg, msg1)
+	}
+
+	// a private transaction, signed with MLSAG
+	tx, allIndices, allPublicKeys, allCommitments := newTestPrivateTx(t, byte(common.RandInt()%common.MaxShardNumber), 2, 0)
+	msg, err := tx.MessageToSign()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, tx.Hash()[:], msg)
+
+	// the MLSAG signature is over the message
+	isValid, err := VerifyTxVer2(tx, map[string]interface{}{
+		utils.CommitmentIndices: allIndices,
+		utils.PublicKeys:        allPublicKeys,
+		utils.Commitments:       allCommitments,
+	})
+	assert.Equal(t, nil, err, fmt.Errorf("VerifyTxVer2 error: %v", err))
+	assert.Equal(t, true, isValid)
+
+	// the message does not depend on the signature nor on the ring indices (SigPubKey)
+	unsignedTx := *tx
+	unsignedTx.Sig, unsignedTx.SigPubKey = nil, nil
+	unsignedMsg, err := unsignedTx.MessageToSign()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, msg, unsignedMsg)
+
+	// and is stable across JSON round-trips
+	jsb, err := json.Marshal(tx)
+	assert.Equal(t, nil, err)
+	tx1 := new(Tx)
+	err = json.Unmarshal(jsb, tx1)
+	assert.Equal(t, nil, err)
+	msg1, err := tx1.MessageToSign()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, msg, msg1)
+}
+
+func TestTx_GetTradeRequest(t *testing.T) {
+	sellTokenID := common.HashH([]byte("sell")).String()
+	buyTokenID := common.HashH([]byte("buy")).String()
+	md, err := metadata.NewPDETradeRequest(buyTokenID, sellTokenID, 1000, 900, 10, "trader", "", metadata.PDETradeRequestMeta)
+	assert.Equal(t, nil, err)
+
+	// a PRV transaction
+	tx := new(Tx)
+	_, err = tx.GetTradeRequest()
+	assert.Equal(t, metadata.ErrNotATrade, err)
+	tx.SetMetadata(md)
+	info, err := tx.GetTradeRequest()
+	assert.Equal(t, nil, err, fmt.Errorf("GetTradeRequest error: %v", err))
+	assert.Equal(t, sellTokenID, info.SellTokenID)
+	assert.Equal(t, buyTokenID, info.BuyTokenID)
+	assert.Equal(t, uint64(1000), info.SellAmount)
+
+	// a token transaction
+	txToken := new(TxToken)
+	_, err = txToken.GetTradeRequest()
+	assert.Equal(t, metadata.ErrNotATrade, err)
+	txToken.Tx.SetMetadata(md)
+	info, err = txToken.GetTradeRequest()
+	assert.Equal(t, nil, err, fmt.Errorf("GetTradeRequest error: %v", err))
+	assert.Equal(t, uint64(900), info.MinAcceptableAmount)
+}
+
+func TestTx_PrivacyLevel(t *testing.T) {
+	signer := newRandomKeySet()
+	receiver := newRandomKeySet()
+
+	// a private transfer spends input coins
+	inputCoins := make([]coin.PlainCoin, 0)
+	outputCoins := make([]coin.Coin, 0)
+	for _, addr := range []key.PaymentAddress{signer.PaymentAddress, receiver.PaymentAddress} {
+		paymentInfo := key.InitPaymentInfo(addr, common.RandUint64()%1000000+1, []byte{})
+		c, err := coin.NewCoinFromPaymentInfo(coin.NewTransferCoinParams(paymentInfo))
+		assert.Equal(t, nil, err, fmt.Errorf("NewCoinFromPaymentInfo error: %v", err))
+		inputCoins = append(inputCoins, c)
+		outputCoins = append(outputCoins, c)
+	}
+	proof := new(privacy.ProofV2)
+	proof.Init()
+	assert.Equal(t, nil, proof.SetInputCoins(inputCoins[:1]))
+	assert.Equal(t, nil, proof.SetOutputCoins(outputCoins[1:]))
+	privateTx := &Tx{}
+	privateTx.Proof = proof
+	assert.Equal(t, PrivacyLevelPrivate, privateTx.PrivacyLevel())
+
+	// a reward transaction only mints output coins
+	paymentInfo := key.InitPaymentInfo(receiver.PaymentAddress, 1000, []byte{})
+	otaCoin, err := coin.NewCoinFromPaymentInfo(coin.NewMintCoinParams(paymentInfo))
+	assert.Equal(t, nil, err, fmt.Errorf("NewCoinFromPaymentInfo error: %v", err))
+	rewardTx := new(Tx)
+	err = rewardTx.InitTxSalary(otaCoin, &signer.PrivateKey, nil)
+	assert.Equal(t, nil, err, fmt.Errorf("InitTxSalary error: %v", err))
+	assert.Equal(t, PrivacyLevelNonPrivateReward, rewardTx.PrivacyLevel())
+
+	// the PRV transaction of a pToken-fee transaction has neither input nor output coins
+	params := tx_generic.NewTxPrivacyInitParams(&signer.PrivateKey, []*key.PaymentInfo{}, []coin.PlainCoin{},
+		0, false, nil, nil, nil, nil)
+	pTokenFeeTx := new(Tx)
+	err = pTokenFeeTx.Init(params)
+	assert.Equal(t, nil, err, fmt.Errorf("Init error: %v", err))
+	assert.Equal(t, PrivacyLevelNonPrivatePTokenFee, pTokenFeeTx.PrivacyLevel())
+
+	// the classification survives JSON round-trips
+	for _, tx := range []*Tx{rewardTx, pTokenFeeTx} {
+		jsb, err := json.Marshal(tx)
+		assert.Equal(t, nil, err)
+		tx1 := new(Tx)
+		err = json.Unmarshal(jsb, tx1)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, tx.PrivacyLevel(), tx1.PrivacyLevel())
+	}
+
+	assert.Equal(t, "private", PrivacyLevelPrivate.String())
+	assert.Equal(t, "non-private reward", PrivacyLevelNonPrivateReward.String())
+	assert.Equal(t, "non-private pToken fee", PrivacyLevelNonPrivatePTokenFee.String())
+}
+
+func TestTx_Explain(t *testing.T) {
+	signer := newRandomKeySet()
+	receiver := newRandomKeySet()
+	numInputs := 2
+	ringSize := privacy.RingSize
+
+	// a sample private trade transaction: 2 input coins, 2 output coins and a full MLSAG signature.
+	inputCoins := make([]coin.PlainCoin, 0)
+	for i := 0; i < numInputs; i++ {
+		paymentInfo := key.InitPaymentInfo(signer.PaymentAddress, 1000, []byte{})
+		c, err := coin.NewCoinFromPaymentInfo(coin.NewTransferCoinParams(paymentInfo))
+		assert.Equal(t, nil, err, fmt.Errorf("NewCoinFromPaymentInfo error: %v", err))
+		inputCoins = append(inputCoins, c)
+	}
+	outputCoins := make([]coin.Coin, 0)
+	for _, addr := range []key.PaymentAddress{signer.PaymentAddress, receiver.PaymentAddress} {
+		paymentInfo := key.InitPaymentInfo(addr, 900, []byte{})
+		c, err := coin.NewCoinFromPaymentInfo(coin.NewTransferCoinParams(paymentInfo))
+		assert.Equal(t, nil, err, fmt.Errorf("NewCoinFromPaymentInfo error: %v", err))
+		outputCoins = append(outputCoins, c)
+	}
+	proof := new(privacy.ProofV2)
+	proof.Init()
+	assert.Equal(t, nil, proof.SetInputCoins(inputCoins))
+	assert.Equal(t, nil, proof.SetOutputCoins(outputCoins))
+
+	r := make([][]*crypto.Scalar, ringSize)
+	for i := range r {
+		r[i] = make([]*crypto.Scalar, numInputs+1)
+		for j := range r[i] {
+			r[i][j] = crypto.RandomScalar()
+		}
+	}
+	sig := new(mlsag.Sig)
+	sig.SetC(crypto.RandomScalar())
+	sig.SetR(r)
+	sigBytes, err := sig.ToBytes()
+	assert.Equal(t, nil, err)
+
+	md, err := metadata.NewPDETradeRequest(common.PRVIDStr, common.HashH([]byte("sell")).String(),
+		1000, 900, 10, "trader", "", metadata.PDETradeRequestMeta)
+	assert.Equal(t, nil, err)
+
+	tx := new(Tx)
+	tx.Version = utils.TxVersion2Number
+	tx.Type = common.TxNormalType
+	tx.Fee = 200
+	tx.Proof = proof
+	tx.Sig = sigBytes
+	tx.SetMetadata(md)
+
+	explanation, err := tx.Explain()
+	assert.Equal(t, nil, err, fmt.Errorf("Explain error: %v", err))
+	assert.Equal(t, tx.Hash().String(), explanation.TxHash)
+	assert.Equal(t, int8(utils.TxVersion2Number), explanation.Version)
+	assert.Equal(t, common.TxNormalType, explanation.Type)
+	assert.Equal(t, uint64(200), explanation.Fee)
+	assert.NotEqual(t, uint64(0), explanation.FeePerKB)
+	assert.Equal(t, numInputs, explanation.NumKeyImages)
+	assert.Equal(t, len(outputCoins), len(explanation.OutputOTAs))
+	for i, c := range outputCoins {
+		assert.Equal(t, base58.Base58Check{}.Encode(c.GetPublicKey().ToBytesS(), common.ZeroByte), explanation.OutputOTAs[i])
+	}
+	assert.Equal(t, ringSize, explanation.RingSize)
+	assert.Equal(t, metadata.PDETradeRequestMeta, explanation.MetadataType)
+	assert.Equal(t, "PDETradeRequest", explanation.MetadataTypeName)
+	assert.Equal(t, PrivacyLevelPrivate, explanation.PrivacyLevel)
+
+	str := explanation.String()
+	for _, s := range append([]string{explanation.TxHash, "PDETradeRequest", PrivacyLevelPrivate.String()}, explanation.OutputOTAs...) {
+		assert.Equal(t, true, strings.Contains(str, s), fmt.Errorf("%v not found in %v", s, str))
+	}
+
+	// a reward transaction has no ring.
+	paymentInfo := key.InitPaymentInfo(receiver.PaymentAddress, 1000, []byte{})
+	otaCoin, err := coin.NewCoinFromPaymentInfo(coin.NewMintCoinParams(paymentInfo))
+	assert.Equal(t, nil, err, fmt.Errorf("NewCoinFromPaymentInfo error: %v", err))
+	rewardTx := new(Tx)
+	err = rewardTx.InitTxSalary(otaCoin, &signer.PrivateKey, nil)
+	assert.Equal(t, nil, err, fmt.Errorf("InitTxSalary error: %v", err))
+	explanation, err = rewardTx.Explain()
+	assert.Equal(t, nil, err, fmt.Errorf("Explain error: %v", err))
+	assert.Equal(t, 0, explanation.RingSize)
+	assert.Equal(t, 0, explanation.NumKeyImages)
+	assert.Equal(t, 1, len(explanation.OutputOTAs))
+	assert.Equal(t, "", explanation.MetadataTypeName)
+	assert.Equal(t, PrivacyLevelNonPrivateReward, explanation.PrivacyLevel)
+
+	// a private transaction with a broken signature.
+	tx.Sig = []byte{1, 2, 3}
+	_, err = tx.Explain()
+	assert.NotEqual(t, nil, err)
+}
+
+func TestTx_EqualTo(t *testing.T) {
+	for i := 0; i < numTests; i++ {
+		signer := newRandomKeySet()
+		paymentInfo := key.InitPaymentInfo(newRandomKeySet().PaymentAddress, 1000, []byte{})
+		otaCoin, err := coin.NewCoinFromPaymentInfo(coin.NewMintCoinParams(paymentInfo))
+		assert.Equal(t, nil, err, fmt.Errorf("NewCoinFromPaymentInfo error: %v", err))
+		tx := new(Tx)
+		err = tx.InitTxSalary(otaCoin, &signer.PrivateKey, nil)
+		assert.Equal(t, nil, err, fmt.Errorf("InitTxSalary error: %v", err))
+
+		// a JSON round-trip yields an identical transaction
+		jsb, err := json.Marshal(tx)
+		assert.Equal(t, nil, err)
+		tx1 := new(Tx)
+		err = json.Unmarshal(jsb, tx1)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, true, tx.EqualTo(tx1))
+		assert.Equal(t, true, tx.DeepEqual(tx1))
+
+		// the transactions differ only in their signatures
+		tx2 := new(Tx)
+		err = json.Unmarshal(jsb, tx2)
+		assert.Equal(t, nil, err)
+		tx2.Sig = common.RandBytes(len(tx.Sig))
+		assert.Equal(t, true, tx.EqualTo(tx2))
+		assert.Equal(t, false, tx.DeepEqual(tx2))
+
+		// the transactions have different contents
+		tx2.LockTime++
+		assert.Equal(t, false, tx.EqualTo(tx2))
+		assert.Equal(t, false, tx.DeepEqual(tx2))
+
+		// the transactions differ only in their info, proof or metadata
+		tx3 := new(Tx)
+		err = json.Unmarshal(jsb, tx3)
+		assert.Equal(t, nil, err)
+		tx3.Info = []byte("info")
+		assert.Equal(t, false, tx.DeepEqual(tx3))
+		tx3.Info = tx.Info
+		tx3.Proof = nil
+		assert.Equal(t, false, tx.DeepEqual(tx3))
+		assert.Equal(t, false, tx3.DeepEqual(tx))
+		err = json.Unmarshal(jsb, tx3)
+		assert.Equal(t, nil, err)
+		md, err := metadata.NewUnStakingMetadata("committeePublicKey")
+		assert.Equal(t, nil, err)
+		tx3.SetMetadata(md)
+		assert.Equal(t, false, tx.DeepEqual(tx3))
+		tx1.SetMetadata(md)
+		assert.Equal(t, true, tx1.DeepEqual(tx3))
+
+		var nilTx *Tx
+		assert.Equal(t, false, tx.EqualTo(nil))
+		assert.Equal(t, false, nilTx.DeepEqual(tx))
+		assert.Equal(t, true, nilTx.EqualTo(nil))
+	}
+}
+
+func TestSigPubKey_JSON(t *testing.T) {
+	for i := 0; i < numTests; i++ {
+		numRows := common.RandInt()%10 + 1
+		numCols := common.RandInt()%5 + 1
+		sigPub := SigPubKey{Indexes: make([][]*big.Int, numRows)}
+		for r := 0; r < numRows; r++ {
+			sigPub.Indexes[r] = make([]*big.Int, numCols)
+			for c := 0; c < numCols; c++ {
+				sigPub.Indexes[r][c] = new(big.Int).SetUint64(common.RandUint64())
+			}
+		}
+		sigPub.Indexes[0][0] = big.NewInt(0)
+
+		jsb, err := json.Marshal(sigPub)
+		assert.Equal(t, nil, err, fmt.Errorf("MarshalJSON error: %v", err))
+		assert.Equal(t, true, strings.Contains(string(jsb), fmt.Sprintf("\"%v\"", sigPub.Indexes[numRows-1][numCols-1].String())))
+
+		recovered := new(SigPubKey)
+		err = json.Unmarshal(jsb, recovered)
+		assert.Equal(t, nil, err, fmt.Errorf("UnmarshalJSON error: %v", err))
+		assert.Equal(t, numRows, len(recovered.Indexes))
+		for r := 0; r < numRows; r++ {
+			assert.Equal(t, numCols, len(recovered.Indexes[r]))
+			for c := 0; c < numCols; c++ {
+				assert.Equal(t, 0, sigPub.Indexes[r][c].Cmp(recovered.Indexes[r][c]))
+			}
+		}
+
+		// the recovered SigPubKey round-trips through Bytes and SetBytes.
+		expectedBytes, err := sigPub.Bytes()
+		assert.Equal(t, nil, err)
+		recoveredBytes, err := recovered.Bytes()
+		assert.Equal(t, nil, err)
+		assert.Equal(t, expectedBytes, recoveredBytes)
+		fromBytes := new(SigPubKey)
+		err = fromBytes.SetBytes(recoveredBytes)
+		assert.Equal(t, nil, err)
+		jsb1, err := json.Marshal(fromBytes)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, string(jsb), string(jsb1))
+	}
+
+	assert.Equal(t, `{"Indexes":[["1","2"],["30","400"]]}`, func() string {
+		jsb, _ := json.Marshal(SigPubKey{Indexes: [][]*big.Int{{big.NewInt(1), big.NewInt(2)}, {big.NewInt(30), big.NewInt(400)}}})
+		return string(jsb)
+	}())
+
+	// invalid SigPubKeys.
+	_, err := json.Marshal(SigPubKey{})
+	assert.NotEqual(t, nil, err)
+	_, err = json.Marshal(SigPubKey{Indexes: [][]*big.Int{{big.NewInt(1), big.NewInt(2)}, {big.NewInt(3)}}})
+	assert.NotEqual(t, nil, err)
+	invalidJSONs := []string{
+		`{"Indexes":[]}`,
+		`{"Indexes":[["1","2"],["3"]]}`,
+		`{"Indexes":[["1","-2"]]}`,
+		`{"Indexes":[["1","0x2"]]}`,
+		`{"Indexes":[[1,2]]}`,
+	}
+	for _, s := range invalidJSONs {
+		err = json.Unmarshal([]byte(s), new(SigPubKey))
+		assert.NotEqual(t, nil, err, fmt.Errorf("expected an error for %v", s))
+	}
+}
+
+func TestTx_CustomRingSize(t *testing.T) {
+	shardID := byte(common.RandInt() % common.MaxShardNumber)
+	newWalletOfShard := func() *wallet.KeyWallet {
+		w, err := wallet.GenRandomWalletForShardID(shardID)
+		if err != nil {
+			panic(err)
+		}
+		return w
+	}
+	newRingParams := func(inputCoins []coin.PlainCoin, numDecoys int) map[string]interface{} {
+		cmtIndices := make([]uint64, 0)
+		commitments := make([]*crypto.Point, 0)
+		publicKeys := make([]*crypto.Point, 0)
+		for i := 0; i < numDecoys; i++ {
+			paymentInfo := key.InitPaymentInfo(newWalletOfShard().KeySet.PaymentAddress, 1000, []byte{})
+			decoy, err := coin.NewCoinFromPaymentInfo(coin.NewTransferCoinParams(paymentInfo))
+			if err != nil {
+				panic(err)
+			}
+			cmtIndices = append(cmtIndices, uint64(i+1))
+			commitments = append(commitments, decoy.GetCommitment())
+			publicKeys = append(publicKeys, decoy.GetPublicKey())
+		}
+
+		return map[string]interface{}{
+			utils.CommitmentIndices: cmtIndices,
+			utils.Commitments:       commitments,
+			utils.PublicKeys:        publicKeys,
+			utils.AssetTags:         []*crypto.Point{},
+			utils.MyIndices:         make([]uint64, len(inputCoins)),
+		}
+	}
+
+	signer := newWalletOfShard()
+	receiver := newWalletOfShard()
+	numInputs := 2
+	inputCoins := make([]coin.PlainCoin, 0)
+	for i := 0; i < numInputs; i++ {
+		paymentInfo := key.InitPaymentInfo(signer.KeySet.PaymentAddress, 1000, []byte{})
+		c, err := coin.NewCoinFromPaymentInfo(coin.NewTransferCoinParams(paymentInfo))
+		assert.Equal(t, nil, err, fmt.Errorf("NewCoinFromPaymentInfo error: %v", err))
+		plainCoin, err := c.Decrypt(&signer.KeySet)
+		assert.Equal(t, nil, err, fmt.Errorf("Decrypt error: %v", err))
+		inputCoins = append(inputCoins, plainCoin)
+	}
+	newParams := func(ringSize, numDecoys int) *tx_generic.TxPrivacyInitParams {
+		paymentInfo := []*key.PaymentInfo{key.InitPaymentInfo(receiver.KeySet.PaymentAddress, 1500, []byte{})}
+		params := tx_generic.NewTxPrivacyInitParams(&signer.KeySet.PrivateKey, paymentInfo, inputCoins, 100, true,
+			nil, nil, nil, newRingParams(inputCoins, numDecoys))
+		params.RingSize = ringSize
+		return params
+	}
+
+	for _, ringSize := range []int{2, 4, privacy.RingSize + 3} {
+		tx := new(Tx)
+		err := tx.Init(newParams(ringSize, RequiredDecoyCount(numInputs, ringSize)))
+		assert.Equal(t, nil, err, fmt.Errorf("Init with ring size %v error: %v", ringSize, err))
+		if err != nil {
+			continue
+		}
+
+		explanation, err := tx.Explain()
+		assert.Equal(t, nil, err, fmt.Errorf("Explain error: %v", err))
+		assert.Equal(t, ringSize, explanation.RingSize)
+
+		sigPubKey := new(SigPubKey)
+		err = sigPubKey.SetBytes(tx.SigPubKey)
+		assert.Equal(t, nil, err, fmt.Errorf("SigPubKey.SetBytes error: %v", err))
+		assert.Equal(t, ringSize, len(sigPubKey.Indexes))
+	}
+
+	// the default ring size
+	params := newParams(0, 0)
+	ringSize, err := params.GetRingSize()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, privacy.RingSize, ringSize)
+
+	// invalid ring sizes
+	for _, ringSize := range []int{1, -1} {
+		err = new(Tx).Init(newParams(ringSize, RequiredDecoyCount(numInputs, privacy.RingSize)))
+		assert.NotEqual(t, nil, err, fmt.Errorf("expected an error for ring size %v", ringSize))
+	}
+
+	// not enough decoys for the requested ring size
+	err = new(Tx).Init(newParams(6, RequiredDecoyCount(numInputs, 4)))
+	assert.NotEqual(t, nil, err)
+	assert.Equal(t, true, strings.Contains(err.Error(), "not enough decoy"), err)
+}
+
+func TestTx_ValidateTypeConsistency(t *testing.T) {
+	receiver := newRandomKeySet()
+	newProof := func(withAssetTag bool) privacy.Proof {
+		paymentInfo := key.InitPaymentInfo(receiver.PaymentAddress, 1000, []byte{})
+		c, err := coin.NewCoinFromPaymentInfo(coin.NewTransferCoinParams(paymentInfo))
+		assert.Equal(t, nil, err, fmt.Errorf("NewCoinFromPaymentInfo error: %v", err))
+		if withAssetTag {
+			c.SetAssetTag(crypto.RandomPoint())
+		}
+		proof := new(privacy.ProofV2)
+		proof.Init()
+		assert.Equal(t, nil, proof.SetOutputCoins([]coin.Coin{c}))
+		return proof
+	}
+	tradeMd, err := metadata.NewPDETradeRequest(common.PRVIDStr, common.HashH([]byte("sell")).String(),
+		1000, 900, 10, "trader", "", metadata.PDETradeRequestMeta)
+	assert.Equal(t, nil, err)
+	unStakingMd, err := metadata.NewUnStakingMetadata("committeePublicKey")
+	assert.Equal(t, nil, err)
+
+	testCases := []struct {
+		name      string
+		txType    string
+		assetTag  bool
+		md        metadata.Metadata
+		expectErr bool
+	}{
+		{"PRV transfer", common.TxNormalType, false, nil, false},
+		{"PRV trade", common.TxNormalType, false, tradeMd, false},
+		{"token transfer", common.TxCustomTokenPrivacyType, true, nil, false},
+		{"unknown type", "x", false, nil, true},
+		{"PRV type with token outputs", common.TxNormalType, true, nil, true},
+		{"reward type with token outputs", common.TxRewardType, true, nil, true},
+		{"token type with PRV-only metadata", common.TxCustomTokenPrivacyType, true, unStakingMd, true},
+	}
+	for _, tc := range testCases {
+		tx := new(Tx)
+		tx.Version = utils.TxVersion2Number
+		tx.Type = tc.txType
+		tx.Proof = newProof(tc.assetTag)
+		tx.SetMetadata(tc.md)
+
+		err := tx.ValidateTypeConsistency()
+		assert.Equal(t, tc.expectErr, err != nil, fmt.Errorf("%v: unexpected result %v", tc.name, err))
+	}
+}
+
+func TestTxToken_ValidateTypeConsistency(t *testing.T) {
+	shardID := byte(common.RandInt() % common.MaxShardNumber)
+	newWalletOfShard := func() *wallet.KeyWallet {
+		w, err := wallet.GenRandomWalletForShardID(shardID)
+		if err != nil {
+			panic(err)
+		}
+		return w
+	}
+	tokenID := common.HashH([]byte("token"))
+	newCoin := func(addr key.PaymentAddress, isToken bool) *coin.CoinV2 {
+		paymentInfo := key.InitPaymentInfo(addr, 1000, []byte{})
+		if isToken {
+			c, _, err := createUniqueOTACoinCA(coin.NewTransferCoinParams(paymentInfo, shardID), &tokenID)
+			assert.Equal(t, nil, err, fmt.Errorf("createUniqueOTACoinCA error: %v", err))
+			return c
+		}
+		c, err := coin.NewCoinFromPaymentInfo(coin.NewTransferCoinParams(paymentInfo))
+		assert.Equal(t, nil, err, fmt.Errorf("NewCoinFromPaymentInfo error: %v", err))
+		return c
+	}
+	newRingParams := func(isToken bool) map[string]interface{} {
+		cmtIndices := make([]uint64, 0)
+		commitments := make([]*crypto.Point, 0)
+		publicKeys := make([]*crypto.Point, 0)
+		assetTags := make([]*crypto.Point, 0)
+		for i := 0; i < RequiredDecoyCount(1, privacy.RingSize); i++ {
+			decoy := newCoin(newWalletOfShard().KeySet.PaymentAddress, isToken)
+			cmtIndices = append(cmtIndices, uint64(i+1))
+			commitments = append(commitments, decoy.GetCommitment())
+			publicKeys = append(publicKeys, decoy.GetPublicKey())
+			if isToken {
+				assetTags = append(assetTags, decoy.GetAssetTag())
+			}
+		}
+
+		return map[string]interface{}{
+			utils.CommitmentIndices: cmtIndices,
+			utils.Commitments:       commitments,
+			utils.PublicKeys:        publicKeys,
+			utils.AssetTags:         assetTags,
+			utils.MyIndices:         make([]uint64, 1),
+		}
+	}
+
+	signer := newWalletOfShard()
+	receiver := newWalletOfShard()
+	newTxToken := func(md metadata.Metadata) (*TxToken, error) {
+		prvCoin, err := newCoin(signer.KeySet.PaymentAddress, false).Decrypt(&signer.KeySet)
+		assert.Equal(t, nil, err, fmt.Errorf("Decrypt error: %v", err))
+		tokenCoin, err := newCoin(signer.KeySet.PaymentAddress, true).Decrypt(&signer.KeySet)
+		assert.Equal(t, nil, err, fmt.Errorf("Decrypt error: %v", err))
+
+		tokenReceivers := []*key.PaymentInfo{
+			key.InitPaymentInfo(receiver.KeySet.PaymentAddress, 600, []byte{}),
+			key.InitPaymentInfo(signer.KeySet.PaymentAddress, 400, []byte{}),
+		}
+		tokenParam := tx_generic.NewTokenParam(tokenID.String(), "", "", 600, utils.CustomTokenTransfer,
+			tokenReceivers, []coin.PlainCoin{tokenCoin}, false, 0, newRingParams(true))
+		prvReceivers := []*key.PaymentInfo{key.InitPaymentInfo(signer.KeySet.PaymentAddress, 900, []byte{})}
+		params := tx_generic.NewTxTokenParams(&signer.KeySet.PrivateKey, prvReceivers, []coin.PlainCoin{prvCoin}, 100,
+			tokenParam, md, true, true, shardID, nil, newRingParams(false))
+
+		txToken := new(TxToken)
+		return txToken, txToken.Init(params)
+	}
+
+	// a token transfer
+	txToken, err := newTxToken(nil)
+	assert.Equal(t, nil, err, fmt.Errorf("Init error: %v", err))
+	assert.Equal(t, nil, txToken.ValidateTypeConsistency())
+
+	// a token transaction with a PRV-only metadata is rejected by Init.
+	unStakingMd, err := metadata.NewUnStakingMetadata("committeePublicKey")
+	assert.Equal(t, nil, err)
+	_, err = newTxToken(unStakingMd)
+	assert.NotEqual(t, nil, err)
+
+	// a token transaction with a PRV type
+	txToken.Tx.Type = common.TxNormalType
+	assert.NotEqual(t, nil, txToken.ValidateTypeConsistency())
+}
+
+// newTestPrivateTx creates a private PRV transaction of a sender of the given shard, with numInputs input coins and
+// the default ring size. It returns the transaction along with the indices, public keys and commitments of all the
+// on-chain coins referenced by its ring, which are numbered from firstIndex.
+func newTestPrivateTx(t *testing.T, shardID byte, numInputs int, firstIndex uint64) (*Tx, []uint64, []*crypto.Point, []*crypto.Point) {
+	signer, err := wallet.GenRandomWalletForShardID(shardID)
+	if err != nil {
+		panic(err)
+	}
+	return newTestPrivateTxFrom(t, signer, 1500, numInputs, firstIndex)
+}
+
+// newTestPrivateTxFrom is the same as newTestPrivateTx, except that the transaction is signed by signer and sends
+// sendAmount to a random receiver. Each input coin is worth 1000 and the fee is 100.
+func newTestPrivateTxFrom(t *testing.T, signer *wallet.KeyWallet, sendAmount uint64, numInputs int, firstIndex uint64) (*Tx, []uint64, []*crypto.Point, []*crypto.Point) {
+	shardID := common.GetShardIDFromLastByte(signer.KeySet.PaymentAddress.Pk[len(signer.KeySet.PaymentAddress.Pk)-1])
+	receiver, err := wallet.GenRandomWalletForShardID(shardID)
+	if err != nil {
+		panic(err)
+	}
+	return newTestPrivateTxTo(t, signer, receiver, sendAmount, numInputs, firstIndex)
+}
+
+// newTestPrivateTxTo is the same as newTestPrivateTxFrom, except that sendAmount is sent to receiver.
+func newTestPrivateTxTo(t *testing.T, signer, receiver *wallet.KeyWallet, sendAmount uint64, numInputs int, firstIndex uint64) (*Tx, []uint64, []*crypto.Point, []*crypto.Point) {
+	shardID := common.GetShardIDFromLastByte(signer.KeySet.PaymentAddress.Pk[len(signer.KeySet.PaymentAddress.Pk)-1])
+	newWalletOfShard := func() *wallet.KeyWallet {
+		w, err := wallet.GenRandomWalletForShardID(shardID)
+		if err != nil {
+			panic(err)
+		}
+		return w
+	}
+	ringSize := privacy.RingSize
+
+	// the "on-chain" coins: the input coins of the signer, followed by the decoys.
+	inputCoins := make([]coin.PlainCoin, 0)
+	myIndices := make([]uint64, 0)
+	allIndices := make([]uint64, 0)
+	allPublicKeys := make([]*crypto.Point, 0)
+	allCommitments := make([]*crypto.Point, 0)
+	for i := 0; i < numInputs; i++ {
+		paymentInfo := key.InitPaymentInfo(signer.KeySet.PaymentAddress, 1000, []byte{})
+		c, err := coin.NewCoinFromPaymentInfo(coin.NewTransferCoinParams(paymentInfo))
+		assert.Equal(t, nil, err, fmt.Errorf("NewCoinFromPaymentInfo error: %v", err))
+		plainCoin, err := c.Decrypt(&signer.KeySet)
+		assert.Equal(t, nil, err, fmt.Errorf("Decrypt error: %v", err))
+		inputCoins = append(inputCoins, plainCoin)
+		myIndices = append(myIndices, firstIndex+uint64(i))
+		allIndices = append(allIndices, firstIndex+uint64(i))
+		allPublicKeys = append(allPublicKeys, c.GetPublicKey())
+		allCommitments = append(allCommitments, c.GetCommitment())
+	}
+	numDecoys := RequiredDecoyCount(numInputs, ringSize)
+	for i := 0; i < numDecoys; i++ {
+		paymentInfo := key.InitPaymentInfo(newWalletOfShard().KeySet.PaymentAddress, 1000, []byte{})
+		decoy, err := coin.NewCoinFromPaymentInfo(coin.NewTransferCoinParams(paymentInfo))
+		assert.Equal(t, nil, err, fmt.Errorf("NewCoinFromPaymentInfo error: %v", err))
+		allIndices = append(allIndices, firstIndex+uint64(numInputs+i))
+		allPublicKeys = append(allPublicKeys, decoy.GetPublicKey())
+		allCommitments = append(allCommitments, decoy.GetCommitment())
+	}
+
+	paymentInfo := []*key.PaymentInfo{key.InitPaymentInfo(receiver.KeySet.PaymentAddress, sendAmount, []byte{})}
+	params := tx_generic.NewTxPrivacyInitParams(&signer.KeySet.PrivateKey, paymentInfo, inputCoins, 100, true,
+		nil, nil, nil, map[string]interface{}{
+			utils.CommitmentIndices: allIndices[numInputs:],
+			utils.Commitments:       allCommitments[numInputs:],
+			utils.PublicKeys:        allPublicKeys[numInputs:],
+			utils.AssetTags:         []*crypto.Point{},
+			utils.MyIndices:         myIndices,
+		})
+	tx := new(Tx)
+	err := tx.Init(params)
+	assert.Equal(t, nil, err, fmt.Errorf("Init error: %v", err))
+
+	return tx, allIndices, allPublicKeys, allCommitments
+}
+
+func TestVerifyTxVer2(t *testing.T) {
+	numInputs := 2
+	tx, allIndices, allPublicKeys, allCommitments := newTestPrivateTx(t, byte(common.RandInt()%common.MaxShardNumber), numInputs, 1000)
+
+	// the verifier only knows the public data of the coins.
+	kvArgs := map[string]interface{}{
+		utils.CommitmentIndices: allIndices,
+		utils.PublicKeys:        allPublicKeys,
+		utils.Commitments:       allCommitments,
+	}
+	isValid, err := VerifyTxVer2(tx, kvArgs)
+	assert.Equal(t, nil, err, fmt.Errorf("VerifyTxVer2 error: %v", err))
+	assert.Equal(t, true, isValid)
+
+	// a wrong commitment breaks the commitment to zero of the real row.
+	wrongCommitments := append([]*crypto.Point{}, allCommitments...)
+	wrongCommitments[0] = crypto.RandomPoint()
+	isValid, err = VerifyTxVer2(tx, map[string]interface{}{
+		utils.CommitmentIndices: allIndices,
+		utils.PublicKeys:        allPublicKeys,
+		utils.Commitments:       wrongCommitments,
+	})
+	assert.Equal(t, nil, err, fmt.Errorf("VerifyTxVer2 error: %v", err))
+	assert.Equal(t, false, isValid)
+
+	// a modified fee changes both the message and the last column.
+	tx.Fee++
+	isValid, err = VerifyTxVer2(tx, kvArgs)
+	assert.Equal(t, nil, err, fmt.Errorf("VerifyTxVer2 error: %v", err))
+	assert.Equal(t, false, isValid)
+	tx.Fee--
+
+	// missing ring members.
+	_, err = VerifyTxVer2(tx, map[string]interface{}{
+		utils.CommitmentIndices: allIndices[numInputs:],
+		utils.PublicKeys:        allPublicKeys[numInputs:],
+		utils.Commitments:       allCommitments[numInputs:],
+	})
+	assert.NotEqual(t, nil, err)
+}
+
+// mapDecoyResolver is a DecoyResolver backed by in-memory maps.
+type mapDecoyResolver struct {
+	publicKeys  map[byte]map[uint64]*crypto.Point
+	commitments map[byte]map[uint64]*crypto.Point
+	numCalls    int
+}
+
+func (r *mapDecoyResolver) add(shardID byte, indices []uint64, publicKeys, commitments []*crypto.Point) {
+	if r.publicKeys[shardID] == nil {
+		r.publicKeys[shardID] = make(map[uint64]*crypto.Point)
+		r.commitments[shardID] = make(map[uint64]*crypto.Point)
+	}
+	for i, index := range indices {
+		r.publicKeys[shardID][index] = publicKeys[i]
+		r.commitments[shardID][index] = commitments[i]
+	}
+}
+
+func (r *mapDecoyResolver) ResolveCoins(shardID byte, indices []uint64) ([]*crypto.Point, []*crypto.Point, error) {
+	r.numCalls++
+	publicKeys := make([]*crypto.Point, 0)
+	commitments := make([]*crypto.Point, 0)
+	for _, index := range indices {
+		pk, ok := r.publicKeys[shardID][index]
+		if !ok {
+			return nil, nil, fmt.Errorf("coin %v of shard %v not found", index, shardID)
+		}
+		publicKeys = append(publicKeys, pk)
+		commitments = append(commitments, r.commitments[shardID][index])
+	}
+	return publicKeys, commitments, nil
+}
+
+func TestVerifyBlockTransactions(t *testing.T) {
+	oldMaxShardNumber := common.MaxShardNumber
+	common.MaxShardNumber = 8
+	defer func() { common.MaxShardNumber = oldMaxShardNumber }()
+
+	resolver := &mapDecoyResolver{
+		publicKeys:  make(map[byte]map[uint64]*crypto.Point),
+		commitments: make(map[byte]map[uint64]*crypto.Point),
+	}
+	txs := make([]metadata.Transaction, 0)
+	expected := make([]bool, 0)
+
+	// private transactions of two shards; the second one of each shard is tampered.
+	for i, shardID := range []byte{1, 1, 2, 2} {
+		tx, indices, publicKeys, commitments := newTestPrivateTx(t, shardID, 2+i%2, uint64(100*i))
+		resolver.add(shardID, indices, publicKeys, commitments)
+		isValid := true
+		if i%2 == 1 {
+			tx.Fee++
+			isValid = false
+		}
+		txs = append(txs, tx)
+		expected = append(expected, isValid)
+	}
+
+	// a reward transaction.
+	receiver := newRandomKeySet()
+	signer := newRandomKeySet()
+	otaCoin, err := coin.NewCoinFromPaymentInfo(coin.NewMintCoinParams(key.InitPaymentInfo(receiver.PaymentAddress, 1000, []byte{})))
+	assert.Equal(t, nil, err, fmt.Errorf("NewCoinFromPaymentInfo error: %v", err))
+	rewardTx := new(Tx)
+	err = rewardTx.InitTxSalary(otaCoin, &signer.PrivateKey, nil)
+	assert.Equal(t, nil, err, fmt.Errorf("InitTxSalary error: %v", err))
+	txs = append(txs, rewardTx)
+	expected = append(expected, true)
+
+	// a malformed transaction.
+	txs = append(txs, &Tx{TxBase: tx_generic.TxBase{Sig: []byte{1}, SigPubKey: []byte{2}}})
+	expected = append(expected, false)
+
+	res, err := VerifyBlockTransactions(txs, resolver)
+	assert.Equal(t, nil, err, fmt.Errorf("VerifyBlockTransactions error: %v", err))
+	assert.Equal(t, expected, res)
+	assert.Equal(t, 2, resolver.numCalls) // one lookup per shard
+
+	// unresolvable ring members.
+	_, err = VerifyBlockTransactions(txs, &mapDecoyResolver{})
+	assert.NotEqual(t, nil, err)
+
+	// token and ver-1 transactions are not supported.
+	for _, unsupportedTx := range []metadata.Transaction{new(TxToken), new(tx_ver1.Tx), (*Tx)(nil)} {
+		resolver.numCalls = 0
+		res, err = VerifyBlockTransactions(append(append([]metadata.Transaction{}, txs...), unsupportedTx), resolver)
+		assert.NotEqual(t, nil, err, fmt.Errorf("expected an error for %T", unsupportedTx))
+		assert.Equal(t, 0, len(res))
+		assert.Equal(t, 0, resolver.numCalls)
+	}
+
+	// no transactions
+	res, err = VerifyBlockTransactions(nil, &mapDecoyResolver{})
+	assert.Equal(t, nil, err, fmt.Errorf("VerifyBlockTransactions error: %v", err))
+	assert.Equal(t, 0, len(res))
+}
+
+func TestTx_FindOwnChange(t *testing.T) {
+	for i := 0; i < 5; i++ {
+		signer, err := wallet.GenRandomWalletForShardID(byte(common.RandInt() % common.MaxShardNumber))
+		if err != nil {
+			panic(err)
+		}
+		privateKey := signer.Base58CheckSerialize(wallet.PrivateKeyType)
+
+		// 2 inputs of 1000, 1500 sent and a fee of 100: the change is 400.
+		tx, _, _, _ := newTestPrivateTxFrom(t, signer, 1500, 2, 1000)
+		change, index, err := tx.FindOwnChange(privateKey)
+		assert.Equal(t, nil, err, fmt.Errorf("FindOwnChange error: %v", err))
+		assert.Equal(t, uint64(400), change.GetValue())
+		outputCoins := tx.GetProof().GetOutputCoins()
+		assert.Equal(t, uint64(len(outputCoins)-1), index)
+		assert.Equal(t, outputCoins[index].GetPublicKey().ToBytesS(), change.GetPublicKey().ToBytesS())
+		assert.NotEqual(t, nil, change.GetKeyImage())
+
+		// another key does not own the change.
+		other, err := wallet.GenRandomWalletForShardID(byte(common.RandInt() % common.MaxShardNumber))
+		if err != nil {
+			panic(err)
+		}
+		_, _, err = tx.FindOwnChange(other.Base58CheckSerialize(wallet.PrivateKeyType))
+		assert.Equal(t, ErrNoChange, err)
+
+		// the inputs exactly cover the sent amount and the fee.
+		tx, _, _, _ = newTestPrivateTxFrom(t, signer, 1900, 2, 1000)
+		_, _, err = tx.FindOwnChange(privateKey)
+		assert.Equal(t, ErrNoChange, err)
+
+		_, _, err = tx.FindOwnChange(signer.Base58CheckSerialize(wallet.PaymentAddressType))
+		assert.NotEqual(t, nil, err)
+	}
+}
+
+func TestTx_EffectiveRingSize(t *testing.T) {
+	numInputs := 2
+	tx, allIndices, _, _ := newTestPrivateTx(t, byte(common.RandInt()%common.MaxShardNumber), numInputs, 1000)
+	myIndices := make(map[uint64]bool)
+	for _, idx := range allIndices[:numInputs] {
+		myIndices[idx] = true
+	}
+
+	sigPubKey := new(SigPubKey)
+	err := sigPubKey.SetBytes(tx.SigPubKey)
+	assert.Equal(t, nil, err, fmt.Errorf("SetBytes error: %v", err))
+	ringSize := len(sigPubKey.Indexes)
+	assert.Equal(t, privacy.RingSize, ringSize)
+
+	// no decoy is spent.
+	effectiveSize, err := tx.EffectiveRingSize(func(uint64) (bool, error) { return false, nil })
+	assert.Equal(t, nil, err, fmt.Errorf("EffectiveRingSize error: %v", err))
+	assert.Equal(t, ringSize, effectiveSize)
+
+	// half of the decoy rows are spent.
+	spentIndices := make(map[uint64]bool)
+	numSpentRows := 0
+	for _, row := range sigPubKey.Indexes {
+		if myIndices[row[0].Uint64()] {
+			continue
+		}
+		if numSpentRows < (ringSize-1)/2 {
+			for _, index := range row {
+				spentIndices[index.Uint64()] = true
+			}
+			numSpentRows++
+		}
+	}
+	numChecks := 0
+	effectiveSize, err = tx.EffectiveRingSize(func(index uint64) (bool, error) {
+		numChecks++
+		return spentIndices[index], nil
+	})
+	assert.Equal(t, nil, err, fmt.Errorf("EffectiveRingSize error: %v", err))
+	assert.Equal(t, ringSize-numSpentRows, effectiveSize)
+	assert.Equal(t, true, numChecks <= len(allIndices))
+
+	// a single spent coin rules out its whole row.
+	decoyIndex := allIndices[numInputs]
+	effectiveSize, err = tx.EffectiveRingSize(func(index uint64) (bool, error) { return index == decoyIndex, nil })
+	assert.Equal(t, nil, err, fmt.Errorf("EffectiveRingSize error: %v", err))
+	assert.Equal(t, ringSize-1, effectiveSize)
+
+	_, err = tx.EffectiveRingSize(func(uint64) (bool, error) { return false, fmt.Errorf("rpc error") })
+	assert.NotEqual(t, nil, err)
+}
+
+func TestTx_EstimateActualSize(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		tx, _, _, _ := newTestPrivateTx(t, byte(common.RandInt()%common.MaxShardNumber), 2+i%3, 1000)
+		switch i % 3 {
+		case 1:
+			tx.Info = common.RandBytes(common.RandInt() % 512)
+		case 2:
+			md, err := metadata.NewUnStakingMetadata("<committeePublicKey>")
+			assert.Equal(t, nil, err, fmt.Errorf("NewUnStakingMetadata error: %v", err))
+			tx.Metadata = md
+		}
+
+		jsb, err := json.Marshal(tx)
+		assert.Equal(t, nil, err, fmt.Errorf("Marshal error: %v", err))
+		size, ok := tx.estimateJSONSize()
+		assert.Equal(t, true, ok)
+		assert.Equal(t, len(jsb), size)
+		assert.Equal(t, tx.GetTxActualSize(), tx.EstimateActualSize())
+	}
+
+	// an unsigned Tx without proof.
+	tx := new(Tx)
+	tx.Version = utils.TxVersion2Number
+	tx.Type = common.TxNormalType
+	jsb, err := json.Marshal(tx)
+	assert.Equal(t, nil, err, fmt.Errorf("Marshal error: %v", err))
+	size, ok := tx.estimateJSONSize()
+	assert.Equal(t, true, ok)
+	assert.Equal(t, len(jsb), size)
+}
+
+func TestTx_ProveSentTo(t *testing.T) {
+	for i := 0; i < 5; i++ {
+		signer, err := wallet.GenRandomWalletForShardID(byte(common.RandInt() % common.MaxShardNumber))
+		if err != nil {
+			panic(err)
+		}
+		receiver, err := wallet.GenRandomWalletForShardID(byte(common.RandInt() % common.MaxShardNumber))
+		if err != nil {
+			panic(err)
+		}
+		other, err := wallet.GenRandomWalletForShardID(byte(common.RandInt() % common.MaxShardNumber))
+		if err != nil {
+			panic(err)
+		}
+		privateKey := signer.Base58CheckSerialize(wallet.PrivateKeyType)
+		receiverAddress := receiver.Base58CheckSerialize(wallet.PaymentAddressType)
+		otherAddress := other.Base58CheckSerialize(wallet.PaymentAddressType)
+
+		// 2 inputs of 1000, 1500 sent and a fee of 100: the change is 400.
+		tx, _, _, _ := newTestPrivateTxTo(t, signer, receiver, 1500, 2, 1000)
+		txHash := tx.Hash().String()
+
+		proof, err := tx.ProveSentTo(privateKey, receiverAddress)
+		assert.Equal(t, nil, err, fmt.Errorf("ProveSentTo error: %v", err))
+		outputCoins := tx.GetProof().GetOutputCoins()
+		assert.Equal(t, outputCoins[proof.OutputIndex].Bytes(), proof.OutputCoin)
+
+		// the proof survives a JSON round-trip.
+		proofBytes, err := json.Marshal(proof)
+		assert.Equal(t, nil, err)
+		decodedProof := new(PaymentProof)
+		err = json.Unmarshal(proofBytes, decodedProof)
+		assert.Equal(t, nil, err)
+
+		isValid, err := VerifyPaymentProof(decodedProof, txHash, receiverAddress, 1500)
+		assert.Equal(t, nil, err, fmt.Errorf("VerifyPaymentProof error: %v", err))
+		assert.Equal(t, true, isValid)
+
+		// wrong amount, recipient or transaction.
+		isValid, err = VerifyPaymentProof(proof, txHash, receiverAddress, 1501)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, false, isValid)
+		isValid, err = VerifyPaymentProof(proof, txHash, otherAddress, 1500)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, false, isValid)
+		isValid, err = VerifyPaymentProof(proof, common.HashH([]byte("other")).String(), receiverAddress, 1500)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, false, isValid)
+
+		// the change is also provable.
+		changeProof, err := tx.ProveSentTo(privateKey, signer.Base58CheckSerialize(wallet.PaymentAddressType))
+		assert.Equal(t, nil, err, fmt.Errorf("ProveSentTo error: %v", err))
+		isValid, err = VerifyPaymentProof(changeProof, txHash, signer.Base58CheckSerialize(wallet.PaymentAddressType), 400)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, true, isValid)
+
+		// a forged shared random does not verify.
+		forgedProof := *proof
+		forgedProof.SharedOTARandom = crypto.RandomScalar().ToBytesS()
+		isValid, err = VerifyPaymentProof(&forgedProof, txHash, receiverAddress, 1500)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, false, isValid)
+
+		// the transaction does not pay other.
+		_, err = tx.ProveSentTo(privateKey, otherAddress)
+		assert.NotEqual(t, nil, err)
+
+		// only the sender can create proofs.
+		_, err = tx.ProveSentTo(other.Base58CheckSerialize(wallet.PrivateKeyType), receiverAddress)
+		assert.NotEqual(t, nil, err)
+
+		// the shared randoms are lost once the transaction is serialized.
+		txBytes, err := json.Marshal(tx)
+		assert.Equal(t, nil, err)
+		decodedTx := new(Tx)
+		err = json.Unmarshal(txBytes, decodedTx)
+		assert.Equal(t, nil, err)
+		_, err = decodedTx.ProveSentTo(privateKey, receiverAddress)
+		assert.NotEqual(t, nil, err)
+	}
+}
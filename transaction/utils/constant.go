@@ -15,6 +15,7 @@ const (
 	MyIndices         = "myCommitmentIndices"
 	PublicKeys        = "publicKeys"
 	AssetTags         = "assetTags"
+	EstimationOnly    = "estimationOnly"
 )
 
 const (
@@ -38,3 +39,7 @@ const (
 	MaxSizeUint32 = (1 << 32) - 1
 	MaxSizeByte   = (1 << 8) - 1
 )
+
+// MaxSalaryAmount is the maximum amount (in nano PRV) a salary transaction is allowed to mint (1,000,000 PRV by
+// default). Tooling that legitimately mints larger rewards can raise it.
+var MaxSalaryAmount = uint64(1000000 * 1e9)
@@ -73,6 +73,8 @@ const (
 	GetCommitmentsInDatabaseError
 	InvalidPaymentAddressError
 	OnetimeAddressAlreadyExists
+	InvalidSalaryAmountError
+	CrossShardInputError
 )
 
 // ErrCodeMessage represents all error messages of the transaction package.
@@ -124,10 +126,12 @@ var ErrCodeMessage = map[int]struct {
 	BatchTxProofVerifyFailError:                   {-1040, "Can not verify proof of batch txs %s"},
 	VerifyOneOutOfManyProofFailedErr:              {-1041, "Verify one out of many proof failed"},
 	GetShardIDByPublicKeyError:                    {-1042, "Cannot get shard id from public key of input coin"},
+	CrossShardInputError:                          {-1043, "Input coins and decoys must belong to the same shard: %+v"},
 
 	// for PRV
 	InvalidSanityDataPRVError:  {-2000, "Invalid sanity data for PRV"},
 	InvalidDoubleSpendPRVError: {-2001, "Double spend PRV in blockchain"},
+	InvalidSalaryAmountError:   {-2002, "Invalid salary amount: %+v"},
 
 	// for privacy token
 	InvalidSanityDataPrivacyTokenError:  {-3000, "Invalid sanity data for privacy Token"},
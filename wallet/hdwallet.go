@@ -186,7 +186,12 @@ func (w *KeyWallet) Serialize(keyType byte, isNewCheckSum bool) ([]byte, error)
 // in the standard Incognito base58 encoding.
 // It returns the encoding string of the key.
 func (w *KeyWallet) Base58CheckSerialize(keyType byte) string {
-	isNewEncoding := common.AddressVersion == 1
+	return w.Base58CheckSerializeWithEncoding(keyType, common.AddressVersion == 1)
+}
+
+// Base58CheckSerializeWithEncoding is the same as Base58CheckSerialize, except that the encoding is given explicitly
+// instead of being read from common.AddressVersion. It is safe to use concurrently with different encodings.
+func (w *KeyWallet) Base58CheckSerializeWithEncoding(keyType byte, isNewEncoding bool) string {
 	serializedKey, err := w.Serialize(keyType, isNewEncoding) //Must use the new checksum from now on
 	if err != nil {
 		return ""
@@ -26,6 +26,16 @@ func NewMnemonicFromEntropy(entropy []byte) (string, error) {
 	return bip39.NewMnemonic(entropy)
 }
 
+// NewEntropyFromMnemonic recovers the entropy encoded in a mnemonic string.
+// The mnemonic checksum is validated against the BIP39 standard.
+func NewEntropyFromMnemonic(mnemonic string) ([]byte, error) {
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return nil, fmt.Errorf("mnemonic is invalid")
+	}
+
+	return bip39.EntropyFromMnemonic(mnemonic)
+}
+
 // NewSeedFromMnemonic creates a hashed seed output given a provided mnemonic string.
 // The mnemonic is validated against the BIP39 standard before the seed is generated.
 func NewSeedFromMnemonic(mnemonic string) ([]byte, error) {
@@ -89,7 +99,7 @@ func GetPaymentAddressV1(addr string, isNewEncoding bool) (string, error) {
 	newWallet.KeySet.PaymentAddress.OTAPublic = nil
 
 	if isNewEncoding {
-		addrV1 := newWallet.Base58CheckSerialize(PaymentAddressType)
+		addrV1 := newWallet.Base58CheckSerializeWithEncoding(PaymentAddressType, true)
 		if len(addrV1) == 0 {
 			return "", fmt.Errorf("cannot decode new payment address: %v", addr)
 		}
@@ -110,6 +120,57 @@ func GetPaymentAddressV1(addr string, isNewEncoding bool) (string, error) {
 	}
 }
 
+// IsNewEncodingForAddressVersion returns the key encoding used for an address version, which is either
+//	- 1: payment address of version 1 (old encoding, without the public OTA key), as used by privacy-v1 networks, or
+//	- 2: payment address of version 2 (new encoding).
+//
+// Address version v corresponds to common.AddressVersion v-1.
+func IsNewEncodingForAddressVersion(version int) (bool, error) {
+	switch version {
+	case 1:
+		return false, nil
+	case 2:
+		return true, nil
+	default:
+		return false, fmt.Errorf("address version %v not supported", version)
+	}
+}
+
+// ConvertPaymentAddressVersion re-encodes a payment address to the given address version (see
+// IsNewEncodingForAddressVersion), for display purposes. The underlying public spend key and public view key are kept
+// unchanged.
+//	- To version 1: the public OTA key is removed, and the old encoding is used.
+//	- To version 2: the address must already contain a public OTA key, since it cannot be derived from a payment
+//	address ver 1 (only from the private key).
+func ConvertPaymentAddressVersion(addr string, toVersion int) (string, error) {
+	w, err := Base58CheckDeserialize(addr)
+	if err != nil {
+		return "", err
+	}
+	if len(w.KeySet.PaymentAddress.Pk) == 0 || len(w.KeySet.PaymentAddress.Tk) == 0 {
+		return "", fmt.Errorf("something must be wrong with the provided payment address: %v", addr)
+	}
+
+	isNewEncoding, err := IsNewEncodingForAddressVersion(toVersion)
+	if err != nil {
+		return "", err
+	}
+	if isNewEncoding && len(w.KeySet.PaymentAddress.OTAPublic) == 0 {
+		return "", fmt.Errorf("cannot convert %v to version 2: the public OTA key cannot be derived from a payment address ver 1", addr)
+	}
+	// the old encoding never includes the public OTA key.
+	res := w.Base58CheckSerializeWithEncoding(PaymentAddressType, isNewEncoding)
+	if res == "" {
+		return "", fmt.Errorf("cannot encode the payment address %v", addr)
+	}
+
+	if _, err = ComparePaymentAddresses(addr, res); err != nil {
+		return "", fmt.Errorf("converted payment address mismatch: %v", err)
+	}
+
+	return res, nil
+}
+
 // ComparePaymentAddresses checks if two payment addresses are generated from the same private key.
 //
 // Just need to compare PKs and TKs.
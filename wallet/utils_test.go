@@ -252,6 +252,47 @@ func TestGetPaymentAddressV1(t *testing.T) {
 	}
 }
 
+func TestConvertPaymentAddressVersion(t *testing.T) {
+	for i := 0; i < numTests; i++ {
+		privateKey := common.RandBytes(common.PrivateKeySize)
+		keySet := new(key.KeySet)
+		err := keySet.InitFromPrivateKeyByte(privateKey)
+		assert.Equal(t, nil, err, "initKeySet returns an error: %v\n", err)
+
+		w := new(KeyWallet)
+		w.KeySet = *keySet
+		addrV2 := w.Base58CheckSerialize(PaymentAddressType)
+
+		// v2 -> v1
+		addrV1, err := ConvertPaymentAddressVersion(addrV2, 1)
+		assert.Equal(t, nil, err, "ConvertPaymentAddressVersion returns an error: %v\n", err)
+		v1Wallet, err := Base58CheckDeserialize(addrV1)
+		assert.Equal(t, nil, err, "deserialize returns an error: %v\n", err)
+		assert.Equal(t, keySet.PaymentAddress.Pk, v1Wallet.KeySet.PaymentAddress.Pk)
+		assert.Equal(t, keySet.PaymentAddress.Tk, v1Wallet.KeySet.PaymentAddress.Tk)
+		assert.Equal(t, 0, len(v1Wallet.KeySet.PaymentAddress.OTAPublic))
+
+		// the same numbering as the other address-version functions: version 1 uses the old encoding.
+		assert.Equal(t, w.Base58CheckSerializeWithEncoding(PaymentAddressType, false), addrV1)
+		assert.Equal(t, w.Base58CheckSerializeWithEncoding(PaymentAddressType, true), addrV2)
+
+		// v1 -> v1 and v2 -> v2 keep the address
+		tmpAddr, err := ConvertPaymentAddressVersion(addrV1, 1)
+		assert.Equal(t, nil, err, "ConvertPaymentAddressVersion returns an error: %v\n", err)
+		assert.Equal(t, addrV1, tmpAddr)
+		tmpAddr, err = ConvertPaymentAddressVersion(addrV2, 2)
+		assert.Equal(t, nil, err, "ConvertPaymentAddressVersion returns an error: %v\n", err)
+		assert.Equal(t, addrV2, tmpAddr)
+
+		// v1 -> v2 is not possible without the public OTA key
+		_, err = ConvertPaymentAddressVersion(addrV1, 2)
+		assert.NotEqual(t, nil, err)
+
+		_, err = ConvertPaymentAddressVersion(addrV2, 3)
+		assert.NotEqual(t, nil, err)
+	}
+}
+
 func TestComparePaymentAddresses(t *testing.T) {
 	for i := 0; i < numTests; i++ {
 		privateKey := common.RandBytes(common.PrivateKeySize)